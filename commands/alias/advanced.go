@@ -0,0 +1,432 @@
+package alias
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+
+	dg "github.com/bwmarrin/discordgo"
+)
+
+var Advanced = advanced{}
+
+type advanced struct{}
+
+func (advanced) Type() core.CommandType {
+	return core.Advanced
+}
+
+func (advanced) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advanced) Names() []string {
+	return []string{
+		"alias",
+	}
+}
+
+func (advanced) Description() string {
+	return "Add, delete or list aliases of built-in commands."
+}
+
+func (c advanced) UsageArgs() string {
+	return c.Children().Usage()
+}
+
+func (advanced) Category() core.CommandCategory {
+	return core.CommandCategoryModerators
+}
+
+func (advanced) Examples() []string {
+	return nil
+}
+
+func (advanced) Parent() core.CommandStatic {
+	return nil
+}
+
+func (advanced) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		AdvancedAdd,
+		AdvancedDelete,
+		AdvancedList,
+	}
+}
+
+func (advanced) Init() error {
+	return nil
+}
+
+func (advanced) Run(m *core.Message) (any, error, error) {
+	return m.Usage(), core.ErrMissingArgs, nil
+}
+
+/////////
+//     //
+// add //
+//     //
+/////////
+
+var AdvancedAdd = advancedAdd{}
+
+type advancedAdd struct{}
+
+func (c advancedAdd) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedAdd) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedAdd) Names() []string {
+	return core.AliasesAdd
+}
+
+func (advancedAdd) Description() string {
+	return "Add an alias that runs a command along with any fixed arguments."
+}
+
+func (advancedAdd) UsageArgs() string {
+	return "<alias> <command...>"
+}
+
+func (c advancedAdd) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedAdd) Examples() []string {
+	return []string{
+		"!sr $audio play",
+		"!tz !time zone",
+	}
+}
+
+func (advancedAdd) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedAdd) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedAdd) Init() error {
+	return nil
+}
+
+func (c advancedAdd) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 2 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return c.discord(m)
+	default:
+		return c.text(m)
+	}
+}
+
+func (c advancedAdd) discord(m *core.Message) (*dg.MessageEmbed, error, error) {
+	trigger, command, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, usrErr, err
+	}
+
+	trigger = discord.PlaceInBackticks(trigger)
+	command = discord.PlaceInBackticks(command)
+
+	embed := &dg.MessageEmbed{
+		Description: c.err(usrErr, trigger, command),
+	}
+
+	return embed, usrErr, nil
+}
+
+func (c advancedAdd) text(m *core.Message) (string, error, error) {
+	trigger, command, usrErr, err := c.core(m)
+	if err != nil {
+		return "", usrErr, err
+	}
+
+	trigger = fmt.Sprintf("'%s'", trigger)
+	command = fmt.Sprintf("'%s'", command)
+
+	return c.err(usrErr, trigger, command), usrErr, nil
+}
+
+func (advancedAdd) err(usrErr error, trigger, command string) string {
+	switch usrErr {
+	case nil:
+		return fmt.Sprintf("Alias %s now runs %s.", trigger, command)
+	case ErrExists:
+		return fmt.Sprintf("Alias %s already exists.", trigger)
+	case ErrNoPrefix:
+		return fmt.Sprintf("Alias %s must begin with one of the prefixes.", trigger)
+	case ErrBuiltinCommand:
+		return fmt.Sprintf("Alias %s already exists as a built-in command.", trigger)
+	case ErrCustomCommand:
+		return fmt.Sprintf("Alias %s already exists as a custom command.", trigger)
+	case ErrCommandNotFound:
+		return fmt.Sprintf("Couldn't find a command that you can use in %s.", command)
+	default:
+		return "Something went wrong..."
+	}
+}
+
+func (advancedAdd) core(m *core.Message) (string, string, error, error) {
+	trigger := m.Command.Args[0]
+	command := strings.Join(m.Command.Args[1:], " ")
+
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	usrErr, err := Add(m, here, trigger, command)
+	return trigger, command, usrErr, err
+}
+
+////////////
+//        //
+// delete //
+//        //
+////////////
+
+var AdvancedDelete = advancedDelete{}
+
+type advancedDelete struct{}
+
+func (c advancedDelete) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedDelete) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedDelete) Names() []string {
+	return core.AliasesDelete
+}
+
+func (advancedDelete) Description() string {
+	return "Delete an alias."
+}
+
+func (advancedDelete) UsageArgs() string {
+	return "<alias>"
+}
+
+func (c advancedDelete) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedDelete) Examples() []string {
+	return []string{
+		"!sr",
+	}
+}
+
+func (advancedDelete) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedDelete) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedDelete) Init() error {
+	return nil
+}
+
+func (c advancedDelete) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return c.discord(m)
+	default:
+		return c.text(m)
+	}
+}
+
+func (c advancedDelete) discord(m *core.Message) (*dg.MessageEmbed, error, error) {
+	trigger, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, usrErr, err
+	}
+
+	trigger = discord.PlaceInBackticks(trigger)
+
+	embed := &dg.MessageEmbed{
+		Description: c.err(usrErr, trigger),
+	}
+
+	return embed, usrErr, nil
+}
+
+func (c advancedDelete) text(m *core.Message) (string, error, error) {
+	trigger, usrErr, err := c.core(m)
+	if err != nil {
+		return "", usrErr, err
+	}
+
+	trigger = fmt.Sprintf("'%s'", trigger)
+
+	return c.err(usrErr, trigger), usrErr, nil
+}
+
+func (advancedDelete) err(usrErr error, trigger string) string {
+	switch usrErr {
+	case nil:
+		return fmt.Sprintf("Alias %s has been deleted.", trigger)
+	case ErrNotFound:
+		return fmt.Sprintf("Alias %s doesn't exist.", trigger)
+	default:
+		return "Something went wrong..."
+	}
+}
+
+func (advancedDelete) core(m *core.Message) (string, error, error) {
+	trigger := m.Command.Args[0]
+
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	usrErr, err := Delete(here, trigger)
+	return trigger, usrErr, err
+}
+
+//////////
+//      //
+// list //
+//      //
+//////////
+
+var AdvancedList = advancedList{}
+
+type advancedList struct{}
+
+func (c advancedList) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedList) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedList) Names() []string {
+	return core.AliasesList
+}
+
+func (advancedList) Description() string {
+	return "List the aliases."
+}
+
+func (advancedList) UsageArgs() string {
+	return ""
+}
+
+func (c advancedList) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedList) Examples() []string {
+	return nil
+}
+
+func (advancedList) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedList) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedList) Init() error {
+	return nil
+}
+
+func (c advancedList) Run(m *core.Message) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return c.discord(m)
+	default:
+		return c.text(m)
+	}
+}
+
+func (c advancedList) discord(m *core.Message) (*dg.MessageEmbed, error, error) {
+	aliases, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(aliases) == 0 {
+		return &dg.MessageEmbed{Description: "No aliases have been added."}, nil, nil
+	}
+
+	var lines []string
+	for _, a := range aliases {
+		lines = append(lines, fmt.Sprintf("%s → %s", discord.PlaceInBackticks(a[0]), discord.PlaceInBackticks(a[1])))
+	}
+
+	embed := &dg.MessageEmbed{
+		Title:       "Aliases",
+		Description: strings.Join(lines, "\n"),
+	}
+
+	return embed, nil, nil
+}
+
+func (c advancedList) text(m *core.Message) (string, error, error) {
+	aliases, err := c.core(m)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(aliases) == 0 {
+		return "No aliases have been added.", nil, nil
+	}
+
+	var pairs []string
+	for _, a := range aliases {
+		pairs = append(pairs, fmt.Sprintf("%s → %s", a[0], a[1]))
+	}
+
+	return fmt.Sprintf("Aliases: %s", strings.Join(pairs, ", ")), nil, nil
+}
+
+// core returns the aliases' triggers and the commands they run, the commands
+// are shown with one of the place's current prefixes.
+func (advancedList) core(m *core.Message) ([][2]string, error) {
+	prefixes, _, err := m.Prefixes()
+	if err != nil {
+		return nil, err
+	}
+
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return nil, err
+	}
+
+	aliases, err := List(here)
+	if err != nil {
+		return nil, err
+	}
+
+	var pairs [][2]string
+	for _, a := range aliases {
+		prefix, _ := core.TypePrefix(prefixes, a.Type)
+		pairs = append(pairs, [2]string{a.Trigger, prefix + a.Command})
+	}
+
+	return pairs, nil
+}
@@ -0,0 +1,116 @@
+package alias
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/janitorjeff/jeff-bot/commands/custom-command"
+	"github.com/janitorjeff/jeff-bot/core"
+)
+
+var (
+	ErrExists          = errors.New("alias already exists")
+	ErrNotFound        = errors.New("alias was not found")
+	ErrNoPrefix        = errors.New("alias doesn't begin with a prefix")
+	ErrBuiltinCommand  = errors.New("alias collides with a built-in command")
+	ErrCustomCommand   = errors.New("alias collides with a custom command")
+	ErrCommandNotFound = errors.New("command was not found")
+)
+
+// resolve finds the command that the text refers to, the text must begin with
+// one of the place's prefixes. The command is matched the same way it would be
+// if m's author typed it out, so an alias can't be made for a command that
+// they aren't permitted to use. Returns the command's type and the text
+// without the prefix.
+func resolve(m *core.Message, place int64, text string) (core.CommandType, string, error, error) {
+	prefixes, _, err := core.PlacePrefixes(place)
+	if err != nil {
+		return 0, "", nil, err
+	}
+
+	prefix, rest, ok := core.MatchPrefix(prefixes, strings.TrimSpace(text))
+	if !ok {
+		return 0, "", ErrCommandNotFound, nil
+	}
+	if prefix.Type == core.Admin && !m.Author.BotAdmin() {
+		return 0, "", ErrCommandNotFound, nil
+	}
+
+	fields := strings.Fields(rest)
+	if _, _, err := core.Commands.Match(prefix.Type, m, fields); err != nil {
+		return 0, "", ErrCommandNotFound, nil
+	}
+
+	return prefix.Type, strings.Join(fields, " "), nil, nil
+}
+
+// Add creates an alias in the specified place that runs command, which is
+// the command's path followed by any fixed arguments, including the prefix.
+// The trigger must begin with one of the place's prefixes so that normal
+// messages aren't mistaken for it, and can't collide with a built-in command,
+// a custom command or another alias.
+func Add(m *core.Message, place int64, trigger, command string) (error, error) {
+	prefixes, _, err := core.PlacePrefixes(place)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, ok := core.MatchPrefix(prefixes, trigger); !ok {
+		return ErrNoPrefix, nil
+	}
+
+	_, exists, err := core.PlaceAlias(place, trigger)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return ErrExists, nil
+	}
+
+	builtin, err := custom_command.IsBuiltin(place, trigger)
+	if err != nil {
+		return nil, err
+	}
+	if builtin {
+		return ErrBuiltinCommand, nil
+	}
+
+	custom, err := custom_command.Exists(place, trigger)
+	if err != nil {
+		return nil, err
+	}
+	if custom {
+		return ErrCustomCommand, nil
+	}
+
+	t, command, usrErr, err := resolve(m, place, command)
+	if usrErr != nil || err != nil {
+		return usrErr, err
+	}
+
+	return nil, dbAdd(place, trigger, t, command)
+}
+
+// Delete removes the alias from the specified place.
+func Delete(place int64, trigger string) (error, error) {
+	_, exists, err := core.PlaceAlias(place, trigger)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return ErrNotFound, nil
+	}
+	return nil, dbDelete(place, trigger)
+}
+
+// List returns the place's aliases sorted by their trigger.
+func List(place int64) ([]core.Alias, error) {
+	aliases, err := core.PlaceAliases(place)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(aliases, func(i, j int) bool {
+		return aliases[i].Trigger < aliases[j].Trigger
+	})
+	return aliases, nil
+}
@@ -0,0 +1,148 @@
+package alias_test
+
+import (
+	"log"
+	"os"
+	"testing"
+
+	"github.com/janitorjeff/jeff-bot/commands/alias"
+	"github.com/janitorjeff/jeff-bot/commands/custom-command"
+	"github.com/janitorjeff/jeff-bot/commands/nick"
+	"github.com/janitorjeff/jeff-bot/core"
+	_ "github.com/janitorjeff/jeff-bot/internal/testing_init"
+	"github.com/janitorjeff/jeff-bot/internal/testkit"
+
+	"github.com/rs/zerolog"
+)
+
+var (
+	msg    *core.Message
+	place  int64
+	person int64
+)
+
+const (
+	trigger1 = "!n"
+	trigger2 = "!name"
+)
+
+func addAlias(trigger, command string) (error, error) {
+	return alias.Add(msg, place, trigger, command)
+}
+
+func TestAdd(t *testing.T) {
+	if usrErr, err := addAlias(trigger1, "$nick set"); usrErr != nil || err != nil {
+		t.Fatalf("failed to add alias '%s': usrErr = %v, err = %v", trigger1, usrErr, err)
+	}
+
+	if usrErr, err := addAlias(trigger1, "$nick set"); usrErr != alias.ErrExists || err != nil {
+		t.Fatalf("expected Exists user error, got: usrErr = %v, err = %v", usrErr, err)
+	}
+
+	if usrErr, err := addAlias("n", "$nick set"); usrErr != alias.ErrNoPrefix || err != nil {
+		t.Fatalf("expected NoPrefix user error, got: usrErr = %v, err = %v", usrErr, err)
+	}
+
+	if usrErr, err := addAlias("$nick", "$nick set"); usrErr != alias.ErrBuiltinCommand || err != nil {
+		t.Fatalf("expected BuiltinCommand user error, got: usrErr = %v, err = %v", usrErr, err)
+	}
+
+	if usrErr, err := custom_command.Add(place, person, "!custom", "response"); usrErr != nil || err != nil {
+		t.Fatalf("failed to add custom command: usrErr = %v, err = %v", usrErr, err)
+	}
+	if usrErr, err := addAlias("!custom", "$nick set"); usrErr != alias.ErrCustomCommand || err != nil {
+		t.Fatalf("expected CustomCommand user error, got: usrErr = %v, err = %v", usrErr, err)
+	}
+	if usrErr, err := custom_command.Add(place, person, trigger1, "response"); usrErr != custom_command.ErrAliasExists || err != nil {
+		t.Fatalf("expected AliasExists user error, got: usrErr = %v, err = %v", usrErr, err)
+	}
+
+	if usrErr, err := addAlias(trigger2, "$unknown"); usrErr != alias.ErrCommandNotFound || err != nil {
+		t.Fatalf("expected CommandNotFound user error, got: usrErr = %v, err = %v", usrErr, err)
+	}
+
+	if usrErr, err := addAlias(trigger2, "$nick"); usrErr != nil || err != nil {
+		t.Fatalf("failed to add alias '%s': usrErr = %v, err = %v", trigger2, usrErr, err)
+	}
+}
+
+func TestList(t *testing.T) {
+	aliases, err := alias.List(place)
+	if err != nil {
+		t.Fatalf("failed to get list of aliases: %v", err)
+	}
+	if len(aliases) != 2 {
+		t.Fatalf("unexpected len of list of aliases, expected 2 got %d, %v", len(aliases), aliases)
+	}
+	if a := aliases[0]; a.Trigger != trigger1 || a.Type != core.Advanced || a.Command != "nick set" {
+		t.Fatalf("unexpected alias %v", a)
+	}
+	if a := aliases[1]; a.Trigger != trigger2 || a.Command != "nick" {
+		t.Fatalf("unexpected alias %v", a)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	msg.Raw = trigger1 + "  janitor  jeff"
+
+	m, err := msg.CommandParse()
+	if err != nil {
+		t.Fatalf("failed to parse alias: %v", err)
+	}
+	if m.Command.CommandStatic != nick.AdvancedSet {
+		t.Fatalf("expected alias to match '%s', got '%s'", core.Format(nick.AdvancedSet, "$"), core.Format(m.Command.CommandStatic, "$"))
+	}
+	if args := m.RawArgs(0); args != "janitor  jeff" {
+		t.Fatalf("expected arguments to be kept as is, got '%s'", args)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	if usrErr, err := alias.Delete(place, trigger1); usrErr != nil || err != nil {
+		t.Fatalf("failed to delete alias '%s': usrErr = %v, err = %v", trigger1, usrErr, err)
+	}
+
+	if usrErr, err := alias.Delete(place, trigger1); usrErr != alias.ErrNotFound || err != nil {
+		t.Fatalf("expected NotFound user error, got: usrErr = %v, err = %v", usrErr, err)
+	}
+
+	aliases, err := alias.List(place)
+	if err != nil {
+		t.Fatalf("failed to get list of aliases: %v", err)
+	}
+	if len(aliases) != 1 || aliases[0].Trigger != trigger2 {
+		t.Fatalf("expected only alias '%s' to be left, got %v", trigger2, aliases)
+	}
+}
+
+func TestMain(m *testing.M) {
+	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+
+	core.Prefixes.Add(core.Normal, "!")
+	core.Prefixes.Add(core.Advanced, "$")
+
+	// nick doesn't require any permission checks which would make calls to
+	// the frontend's API
+	core.Commands = &core.CommandsStatic{
+		nick.Advanced,
+	}
+
+	tdb := testkit.NewTestDB()
+	msg = &testkit.NewTestMessage().DiscordRandom().Message
+
+	var err error
+
+	place, err = msg.Here.ScopeLogical()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	person, err = msg.Author.Scope()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	code := m.Run()
+	tdb.Delete()
+	os.Exit(code)
+}
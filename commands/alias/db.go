@@ -0,0 +1,51 @@
+package alias
+
+import (
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/rs/zerolog/log"
+)
+
+func dbAdd(place int64, trigger string, t core.CommandType, command string) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		INSERT INTO aliases(place, trigger, type, command)
+		VALUES ($1, $2, $3, $4)`, place, trigger, t, command)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Str("trigger", trigger).
+		Int("type", int(t)).
+		Str("command", command).
+		Msg("added alias")
+
+	if err != nil {
+		return err
+	}
+	return core.PlaceAliasesInvalidate(place)
+}
+
+func dbDelete(place int64, trigger string) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		DELETE FROM aliases
+		WHERE place = $1 and trigger = $2`, place, trigger)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Str("trigger", trigger).
+		Msg("deleted alias")
+
+	if err != nil {
+		return err
+	}
+	return core.PlaceAliasesInvalidate(place)
+}
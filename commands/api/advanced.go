@@ -0,0 +1,231 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+	"github.com/janitorjeff/jeff-bot/frontends/twitch"
+
+	dg "github.com/bwmarrin/discordgo"
+)
+
+func render(m *core.Message, resp string, usrErr error) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return &dg.MessageEmbed{Description: resp}, usrErr, nil
+	default:
+		return resp, usrErr, nil
+	}
+}
+
+var Advanced = advanced{}
+
+type advanced struct{}
+
+func (advanced) Type() core.CommandType {
+	return core.Advanced
+}
+
+func (advanced) Permitted(m *core.Message) bool {
+	return true
+}
+
+func (advanced) Names() []string {
+	return []string{
+		"api",
+	}
+}
+
+func (advanced) Description() string {
+	return "Manage the place from outside of chat using the REST API."
+}
+
+func (c advanced) UsageArgs() string {
+	return c.Children().Usage()
+}
+
+func (advanced) Category() core.CommandCategory {
+	return core.CommandCategoryOther
+}
+
+func (advanced) Examples() []string {
+	return nil
+}
+
+func (advanced) Parent() core.CommandStatic {
+	return nil
+}
+
+func (advanced) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		AdvancedToken,
+		AdvancedRevoke,
+	}
+}
+
+func (advanced) Init() error {
+	return nil
+}
+
+func (advanced) Run(m *core.Message) (any, error, error) {
+	return m.Usage(), core.ErrMissingArgs, nil
+}
+
+///////////
+//       //
+// token //
+//       //
+///////////
+
+var AdvancedToken = advancedToken{}
+
+type advancedToken struct{}
+
+func (c advancedToken) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedToken) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedToken) Names() []string {
+	return []string{
+		"token",
+	}
+}
+
+func (advancedToken) Description() string {
+	return "Get an API token, it has the same permissions you have right now."
+}
+
+func (advancedToken) UsageArgs() string {
+	return ""
+}
+
+func (c advancedToken) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedToken) Examples() []string {
+	return nil
+}
+
+func (advancedToken) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedToken) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedToken) Init() error {
+	return nil
+}
+
+func (c advancedToken) Run(m *core.Message) (any, error, error) {
+	// Anyone in chat could see the token, so on twitch the broadcaster logs
+	// in instead and gets it in their browser.
+	if m.Frontend.Type() == twitch.Frontend.Type() {
+		return render(m, "Log in at "+LoginURL()+" to get a token for your channel.", nil)
+	}
+	if m.Frontend.Type() != discord.Frontend.Type() {
+		return render(m, "Tokens can't be sent privately here.", nil)
+	}
+
+	token, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	text := fmt.Sprintf("Your API token, send it in the Authorization header as `Bearer <token>`:\n||%s||\nAnyone with it can act as you, use `api revoke` if it leaks.", token)
+	if err := discord.DM(m.Author.ID(), text); err != nil {
+		return nil, nil, err
+	}
+	return render(m, "Sent you a token in a DM.", nil)
+}
+
+func (advancedToken) core(m *core.Message) (string, error) {
+	author, err := m.Author.Scope()
+	if err != nil {
+		return "", err
+	}
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", err
+	}
+	return Issue(author, here)
+}
+
+////////////
+//        //
+// revoke //
+//        //
+////////////
+
+var AdvancedRevoke = advancedRevoke{}
+
+type advancedRevoke struct{}
+
+func (c advancedRevoke) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedRevoke) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedRevoke) Names() []string {
+	return []string{
+		"revoke",
+	}
+}
+
+func (advancedRevoke) Description() string {
+	return "Revoke all of the API tokens you've been given in this place."
+}
+
+func (advancedRevoke) UsageArgs() string {
+	return ""
+}
+
+func (c advancedRevoke) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedRevoke) Examples() []string {
+	return nil
+}
+
+func (advancedRevoke) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedRevoke) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedRevoke) Init() error {
+	return nil
+}
+
+func (c advancedRevoke) Run(m *core.Message) (any, error, error) {
+	n, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, fmt.Sprintf("Revoked %d token(s).", n), nil)
+}
+
+func (advancedRevoke) core(m *core.Message) (int64, error) {
+	author, err := m.Author.Scope()
+	if err != nil {
+		return 0, err
+	}
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return 0, err
+	}
+	return RevokeAll(author, here)
+}
@@ -0,0 +1,233 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/commands/automod"
+	"github.com/janitorjeff/jeff-bot/commands/god"
+	"github.com/janitorjeff/jeff-bot/commands/points"
+	"github.com/janitorjeff/jeff-bot/commands/trivia"
+	"github.com/janitorjeff/jeff-bot/commands/tts"
+	"github.com/janitorjeff/jeff-bot/commands/warn"
+	"github.com/janitorjeff/jeff-bot/core"
+)
+
+var (
+	ErrInvalidToken   = errors.New("invalid token")
+	ErrUnknownSetting = errors.New("unknown setting")
+	ErrInvalidValue   = errors.New("invalid value")
+)
+
+// Token is what an API token grants access to. The permissions are checked
+// again every time the token is used, the same checks that the chat commands
+// perform, so that they are lost if the person is no longer a mod.
+type Token struct {
+	Person int64 `json:"person"`
+	Place  int64 `json:"place"`
+	Mod    bool  `json:"mod"`
+	Admin  bool  `json:"admin"`
+}
+
+// Tokens are saved hashed so that a leaked database doesn't leak them as
+// well, since they are long and random a plain sha256 is enough.
+func hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Issue creates a new token that gives the person access to the place, with
+// whatever permissions they have there when it's used. The token itself is only
+// returned here, it can't be retrieved later.
+func Issue(person, place int64) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	t := Token{
+		Person: person,
+		Place:  place,
+	}
+	return token, dbAdd(hash(token), t)
+}
+
+// How long a person's permissions are cached for, checking them may require
+// a request to the frontend's API so they aren't checked on every request.
+const permissionsTTL = time.Minute
+
+// permissions returns whether the person is a mod and whether they are an
+// admin in the place.
+func permissions(person, place int64) ([2]bool, error) {
+	key := fmt.Sprintf("api_permissions_%d_%d", person, place)
+	return core.CacheGet(key, permissionsTTL, func() ([2]bool, error) {
+		mod, admin, err := core.Frontends.Permissions(person, place)
+		return [2]bool{mod, admin}, err
+	})
+}
+
+// Authenticate returns what the token grants access to, returns
+// ErrInvalidToken if it doesn't exist. The person's permissions are the ones
+// they currently have in the place, not the ones they had when the token was
+// issued.
+func Authenticate(token string) (Token, error, error) {
+	t, err := dbGet(hash(token))
+	if err == sql.ErrNoRows {
+		return Token{}, ErrInvalidToken, nil
+	}
+	if err != nil {
+		return Token{}, nil, err
+	}
+
+	perms, err := permissions(t.Person, t.Place)
+	if err != nil {
+		return Token{}, nil, err
+	}
+	t.Mod, t.Admin = perms[0] || perms[1], perms[1]
+
+	return t, nil, nil
+}
+
+// Revoke deletes the token, if it doesn't exist nothing happens.
+func Revoke(token string) error {
+	return dbDelete(hash(token))
+}
+
+// RevokeAll deletes all of the tokens the person has been issued for the
+// place and returns how many there were.
+func RevokeAll(person, place int64) (int64, error) {
+	return dbDeleteAll(person, place)
+}
+
+//////////////
+//          //
+// settings //
+//          //
+//////////////
+
+// setting is a settings_place column that mods can change, the value is set
+// through the same function the chat command uses so that it gets validated
+// in the same way.
+type setting struct {
+	column string
+	set    func(place int64, value json.RawMessage) (error, error)
+}
+
+func boolSetting(column string, set func(int64, bool) error) setting {
+	return setting{column, func(place int64, value json.RawMessage) (error, error) {
+		var b bool
+		if err := json.Unmarshal(value, &b); err != nil {
+			return ErrInvalidValue, nil
+		}
+		return nil, set(place, b)
+	}}
+}
+
+func intSetting(column string, set func(int64, int64) (error, error)) setting {
+	return setting{column, func(place int64, value json.RawMessage) (error, error) {
+		var n int64
+		if err := json.Unmarshal(value, &n); err != nil {
+			return ErrInvalidValue, nil
+		}
+		return set(place, n)
+	}}
+}
+
+// secondsSetting is for durations, which are given in seconds.
+func secondsSetting(column string, set func(int64, time.Duration) (error, error)) setting {
+	return intSetting(column, func(place, n int64) (error, error) {
+		return set(place, time.Duration(n)*time.Second)
+	})
+}
+
+func stringSetting(column string, set func(int64, string) (error, error)) setting {
+	return setting{column, func(place int64, value json.RawMessage) (error, error) {
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return ErrInvalidValue, nil
+		}
+		return set(place, s)
+	}}
+}
+
+// settings are keyed by the name used in the API, which is the column name
+// without the cmd_ prefix.
+var settings = map[string]setting{
+	"tts_subonly": boolSetting("cmd_tts_subonly", tts.SubOnlySet),
+
+	"god_reply_on":       boolSetting("cmd_god_reply_on", god.ReplyOnSet),
+	"god_reply_interval": secondsSetting("cmd_god_reply_interval", god.ReplyIntervalSet),
+
+	"automod_on":      boolSetting("cmd_automod_on", automod.OnSet),
+	"automod_links":   boolSetting("cmd_automod_links", automod.LinksSet),
+	"automod_caps":    intSetting("cmd_automod_caps", automod.CapsSet),
+	"automod_emotes":  intSetting("cmd_automod_emotes", automod.EmotesSet),
+	"automod_symbols": intSetting("cmd_automod_symbols", automod.SymbolsSet),
+	"automod_repeat":  intSetting("cmd_automod_repeat", automod.RepeatSet),
+	"automod_actions": stringSetting("cmd_automod_actions", func(place int64, s string) (error, error) {
+		actions, usrErr := automod.ParseActions(strings.Fields(s))
+		if usrErr != nil {
+			return usrErr, nil
+		}
+		return nil, automod.ActionsSet(place, actions)
+	}),
+	"automod_timeout":     secondsSetting("cmd_automod_timeout", automod.TimeoutSet),
+	"automod_exempt_mods": boolSetting("cmd_automod_exempt_mods", automod.ExemptModsSet),
+	"automod_exempt_subs": boolSetting("cmd_automod_exempt_subs", automod.ExemptSubsSet),
+
+	"warn_threshold": intSetting("cmd_warn_threshold", func(place, n int64) (error, error) {
+		if n < 0 {
+			return warn.ErrInvalidThreshold, nil
+		}
+		return nil, warn.ThresholdSet(place, n)
+	}),
+	"warn_window": secondsSetting("cmd_warn_window", warn.WindowSet),
+	"warn_action": stringSetting("cmd_warn_action", func(place int64, s string) (error, error) {
+		return warn.ActionSet(place, warn.Action(strings.ToLower(s)))
+	}),
+	"warn_timeout": secondsSetting("cmd_warn_timeout", warn.TimeoutSet),
+
+	"points_chat":  intSetting("cmd_points_chat", points.ChatSet),
+	"points_watch": intSetting("cmd_points_watch", points.WatchSet),
+
+	"trivia_timeout": secondsSetting("cmd_trivia_timeout", trivia.TimeoutSet),
+}
+
+// SettingsGet returns the current values of all the settings that can be
+// changed through the API.
+func SettingsGet(place int64) (map[string]any, error) {
+	values := map[string]any{}
+	for name, s := range settings {
+		v, err := core.DB.SettingPlaceGet(s.column, place)
+		if err != nil {
+			return nil, err
+		}
+		values[name] = v
+	}
+	return values, nil
+}
+
+// SettingsSet changes the given settings, stops at the first one that is
+// unknown or has an invalid value.
+func SettingsSet(place int64, values map[string]json.RawMessage) (error, error) {
+	for name := range values {
+		if _, ok := settings[name]; !ok {
+			return ErrUnknownSetting, nil
+		}
+	}
+	for name, v := range values {
+		if usrErr, err := settings[name].set(place, v); usrErr != nil || err != nil {
+			return usrErr, err
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/rs/zerolog/log"
+)
+
+func dbAdd(hash string, t Token) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		INSERT INTO cmd_api_tokens(hash, person, place, created)
+		VALUES ($1, $2, $3, $4)
+	`, hash, t.Person, t.Place, time.Now().UTC().Unix())
+
+	log.Debug().
+		Err(err).
+		Int64("person", t.Person).
+		Int64("place", t.Place).
+		Msg("added api token")
+
+	return err
+}
+
+func dbGet(hash string) (Token, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	var t Token
+	err := db.DB.QueryRow(`
+		SELECT person, place
+		FROM cmd_api_tokens
+		WHERE hash = $1
+	`, hash).Scan(&t.Person, &t.Place)
+
+	log.Debug().
+		Err(err).
+		Int64("person", t.Person).
+		Int64("place", t.Place).
+		Msg("got api token")
+
+	return t, err
+}
+
+func dbDelete(hash string) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		DELETE FROM cmd_api_tokens
+		WHERE hash = $1
+	`, hash)
+
+	log.Debug().Err(err).Msg("deleted api token")
+
+	return err
+}
+
+func dbDeleteAll(person, place int64) (int64, error) {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	res, err := db.DB.Exec(`
+		DELETE FROM cmd_api_tokens
+		WHERE person = $1 and place = $2
+	`, person, place)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := res.RowsAffected()
+
+	log.Debug().
+		Err(err).
+		Int64("person", person).
+		Int64("place", place).
+		Int64("deleted", n).
+		Msg("deleted api tokens")
+
+	return n, err
+}
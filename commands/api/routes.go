@@ -0,0 +1,428 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/commands/custom-command"
+	"github.com/janitorjeff/jeff-bot/commands/prefix"
+	tc "github.com/janitorjeff/jeff-bot/commands/time"
+	"github.com/janitorjeff/jeff-bot/commands/tts"
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/twitch"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// nextCookie remembers where to go once a twitch login is done.
+const nextCookie = "api_login_next"
+
+// LoginURL returns the address at which twitch broadcasters can get a token
+// for their own channel.
+func LoginURL() string {
+	return "https://" + core.VirtualHost + "/api/v1/auth/twitch"
+}
+
+// respond writes the response in the same way for all routes: errors are
+// logged and hidden, user errors are returned as is and a nil data means that
+// there's nothing to return.
+func respond(c *gin.Context, data any, usrErr, err error) {
+	if err != nil {
+		log.Error().Err(err).Str("path", c.FullPath()).Msg("api request failed")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "something went wrong"})
+		return
+	}
+	if usrErr != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": usrErr.Error()})
+		return
+	}
+	if data == nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+func bearer(c *gin.Context) string {
+	return strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+}
+
+func token(c *gin.Context) Token {
+	return c.MustGet("token").(Token)
+}
+
+// authenticate only lets requests with a valid token through.
+func authenticate(c *gin.Context) {
+	t, usrErr, err := Authenticate(bearer(c))
+	if err != nil {
+		respond(c, nil, nil, err)
+		return
+	}
+	if usrErr != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": usrErr.Error()})
+		return
+	}
+	c.Set("token", t)
+}
+
+// mod only lets requests through if the token's person is currently a mod,
+// the same check the corresponding chat commands perform.
+func mod(c *gin.Context) {
+	if !token(c).Mod {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "only moderators can do this"})
+	}
+}
+
+// bind decodes the JSON body into v, aborts the request and returns false if
+// it is not valid.
+func bind(c *gin.Context, v any) bool {
+	if err := c.ShouldBindJSON(v); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		return false
+	}
+	return true
+}
+
+////////////
+//        //
+// tokens //
+//        //
+////////////
+
+func getMe(c *gin.Context) {
+	respond(c, token(c), nil, nil)
+}
+
+func deleteToken(c *gin.Context) {
+	respond(c, nil, nil, Revoke(bearer(c)))
+}
+
+// login is called by the twitch frontend once a broadcaster has logged in, the
+// channel is the broadcaster's own so the token has admin access to it.
+func login(c *gin.Context, scope int64) {
+	t, err := Issue(scope, scope)
+	if err != nil {
+		respond(c, nil, nil, err)
+		return
+	}
+
+	// if the login was started from the dashboard then the token is handed
+	// back to it in the fragment, which isn't sent to the server
+	if next, err := c.Cookie(nextCookie); err == nil && next == "dashboard" {
+		c.SetCookie(nextCookie, "", -1, "/", "", true, true)
+		c.Redirect(http.StatusFound, "/dashboard#token="+t)
+		return
+	}
+
+	respond(c, gin.H{"token": t}, nil, nil)
+}
+
+/////////////////////
+//                 //
+// custom commands //
+//                 //
+/////////////////////
+
+type customCommand struct {
+	Trigger  string `json:"trigger"`
+	Response string `json:"response"`
+}
+
+func getCustomCommands(c *gin.Context) {
+	triggers, err := custom_command.List(token(c).Place)
+	if triggers == nil {
+		triggers = []string{}
+	}
+	respond(c, triggers, nil, err)
+}
+
+func getCustomCommand(c *gin.Context) {
+	trigger := c.Param("trigger")
+	resp, err := custom_command.Show(token(c).Place, trigger)
+	if err == sql.ErrNoRows {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": custom_command.ErrTriggerNotFound.Error()})
+		return
+	}
+	respond(c, customCommand{trigger, resp}, nil, err)
+}
+
+func postCustomCommand(c *gin.Context) {
+	var body customCommand
+	if !bind(c, &body) {
+		return
+	}
+	if body.Trigger == "" || body.Response == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "expected a trigger and a response"})
+		return
+	}
+	t := token(c)
+	usrErr, err := custom_command.Add(t.Place, t.Person, body.Trigger, body.Response)
+	respond(c, body, usrErr, err)
+}
+
+func putCustomCommand(c *gin.Context) {
+	var body customCommand
+	if !bind(c, &body) {
+		return
+	}
+	if body.Response == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "expected a response"})
+		return
+	}
+	body.Trigger = c.Param("trigger")
+	t := token(c)
+	usrErr, err := custom_command.Edit(t.Place, t.Person, body.Trigger, body.Response)
+	respond(c, body, usrErr, err)
+}
+
+func deleteCustomCommand(c *gin.Context) {
+	t := token(c)
+	usrErr, err := custom_command.Delete(t.Place, t.Person, c.Param("trigger"))
+	respond(c, nil, usrErr, err)
+}
+
+//////////////
+//          //
+// prefixes //
+//          //
+//////////////
+
+type prefixJSON struct {
+	Prefix string `json:"prefix"`
+	Type   string `json:"type"`
+	Spaced bool   `json:"spaced"`
+}
+
+// Only normal and advanced prefixes can be changed, the same as in chat.
+func prefixType(s string) (core.CommandType, bool) {
+	switch s {
+	case "normal", "":
+		return core.Normal, true
+	case "advanced":
+		return core.Advanced, true
+	default:
+		return 0, false
+	}
+}
+
+func prefixTypeName(t core.CommandType) string {
+	if t == core.Advanced {
+		return "advanced"
+	}
+	return "normal"
+}
+
+func getPrefixes(c *gin.Context) {
+	prefixes, err := prefix.List(core.Normal|core.Advanced, token(c).Place)
+	ps := []prefixJSON{}
+	for _, p := range prefixes {
+		ps = append(ps, prefixJSON{p.Prefix, prefixTypeName(p.Type), p.Spaced})
+	}
+	respond(c, ps, nil, err)
+}
+
+func postPrefix(c *gin.Context) {
+	var body prefixJSON
+	if !bind(c, &body) {
+		return
+	}
+	t, ok := prefixType(body.Type)
+	if !ok || body.Prefix == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "expected a prefix and a type of normal or advanced"})
+		return
+	}
+	p := core.Prefix{Type: t, Prefix: body.Prefix, Spaced: body.Spaced}
+	collision, usrErr, err := prefix.Add(p, token(c).Place)
+	if usrErr == prefix.ErrCustomCommandExists {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "a custom command would collide", "trigger": collision})
+		return
+	}
+	body.Type = prefixTypeName(t)
+	respond(c, body, usrErr, err)
+}
+
+func deletePrefix(c *gin.Context) {
+	t, ok := prefixType(c.Query("type"))
+	if !ok || c.Query("prefix") == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "expected a prefix and a type of normal or advanced"})
+		return
+	}
+	usrErr, err := prefix.Delete(c.Query("prefix"), t, token(c).Place)
+	respond(c, nil, usrErr, err)
+}
+
+///////////////
+//           //
+// reminders //
+//           //
+///////////////
+
+type reminderJSON struct {
+	ID    int64     `json:"id"`
+	Place int64     `json:"place"`
+	When  time.Time `json:"when"`
+	What  string    `json:"what"`
+}
+
+func getReminders(c *gin.Context) {
+	rs, err := tc.RemindListPerson(token(c).Person)
+	reminders := []reminderJSON{}
+	for _, r := range rs {
+		reminders = append(reminders, reminderJSON{r.ID, r.Place, r.When, r.What})
+	}
+	respond(c, reminders, nil, err)
+}
+
+func deleteReminder(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	usrErr, err := tc.RemindDelete(id, token(c).Person)
+	if usrErr != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": usrErr.Error()})
+		return
+	}
+	respond(c, nil, nil, err)
+}
+
+//////////////
+//          //
+// timezone //
+//          //
+//////////////
+
+type timezoneJSON struct {
+	Timezone string `json:"timezone"`
+}
+
+func getTimezone(c *gin.Context) {
+	t := token(c)
+	tz, err := tc.TimezoneShow(t.Person, t.Place)
+	respond(c, timezoneJSON{tz}, nil, err)
+}
+
+func putTimezone(c *gin.Context) {
+	var body timezoneJSON
+	if !bind(c, &body) {
+		return
+	}
+	t := token(c)
+	tz, usrErr, err := tc.TimezoneSet(body.Timezone, t.Person, t.Place)
+	respond(c, timezoneJSON{tz}, usrErr, err)
+}
+
+func deleteTimezone(c *gin.Context) {
+	t := token(c)
+	respond(c, nil, nil, tc.TimezoneDelete(t.Person, t.Place))
+}
+
+///////////
+//       //
+// voice //
+//       //
+///////////
+
+type voiceJSON struct {
+	Voice string `json:"voice"`
+}
+
+func getVoice(c *gin.Context) {
+	t := token(c)
+	voice, err := tts.VoiceGet(t.Person, t.Place)
+	respond(c, voiceJSON{voice}, nil, err)
+}
+
+func putVoice(c *gin.Context) {
+	var body voiceJSON
+	if !bind(c, &body) {
+		return
+	}
+	t := token(c)
+	err := tts.VoiceSet(t.Person, t.Place, body.Voice)
+	if errors.Is(err, tts.ErrInvalidVoice) {
+		respond(c, nil, err, nil)
+		return
+	}
+	respond(c, body, nil, err)
+}
+
+//////////////
+//          //
+// settings //
+//          //
+//////////////
+
+func getSettings(c *gin.Context) {
+	values, err := SettingsGet(token(c).Place)
+	respond(c, values, nil, err)
+}
+
+func patchSettings(c *gin.Context) {
+	var body map[string]json.RawMessage
+	if !bind(c, &body) {
+		return
+	}
+	place := token(c).Place
+	usrErr, err := SettingsSet(place, body)
+	if usrErr != nil || err != nil {
+		respond(c, nil, usrErr, err)
+		return
+	}
+	values, err := SettingsGet(place)
+	respond(c, values, nil, err)
+}
+
+func init() {
+	twitch.OnLogin = login
+
+	core.Gin.GET("/api/v1/auth/twitch", func(c *gin.Context) {
+		if c.Query("next") == "dashboard" {
+			c.SetCookie(nextCookie, "dashboard", 600, "/", "", true, true)
+		}
+
+		url, err := twitch.LoginURL()
+		if err != nil {
+			respond(c, nil, nil, err)
+			return
+		}
+		c.Redirect(http.StatusFound, url)
+	})
+
+	v1 := core.Gin.Group("/api/v1", authenticate)
+
+	v1.GET("/me", getMe)
+	v1.DELETE("/me/token", deleteToken)
+
+	v1.GET("/reminders", getReminders)
+	v1.DELETE("/reminders/:id", deleteReminder)
+
+	v1.GET("/timezone", getTimezone)
+	v1.PUT("/timezone", putTimezone)
+	v1.DELETE("/timezone", deleteTimezone)
+
+	v1.GET("/tts/voice", getVoice)
+	v1.PUT("/tts/voice", putVoice)
+
+	mods := v1.Group("", mod)
+
+	mods.GET("/custom-commands", getCustomCommands)
+	mods.POST("/custom-commands", postCustomCommand)
+	mods.GET("/custom-commands/:trigger", getCustomCommand)
+	mods.PUT("/custom-commands/:trigger", putCustomCommand)
+	mods.DELETE("/custom-commands/:trigger", deleteCustomCommand)
+
+	mods.GET("/prefixes", getPrefixes)
+	mods.POST("/prefixes", postPrefix)
+	mods.DELETE("/prefixes", deletePrefix)
+
+	mods.GET("/settings", getSettings)
+	mods.PATCH("/settings", patchSettings)
+}
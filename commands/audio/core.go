@@ -31,15 +31,20 @@ type Playing struct {
 
 var playing = gosafe.Map[int64, *Playing]{}
 
+var metricQueues = core.NewGauge("jeff_audio_queues", "Number of places that are currently playing audio.")
+
 func stream(sp core.AudioSpeaker, p *Playing, place int64) {
 	for {
 		if p.Queue.Len() == 0 {
 			playing.Delete(place)
+			metricQueues.Dec()
+			core.Overlay.Publish(place, core.OverlayAudio, nil)
 			return
 		}
 
 		switch p.State.Get() {
 		case core.AudioPlay, core.AudioLoop:
+			core.Overlay.Publish(place, core.OverlayAudio, p.Queue.Get(0))
 			// Audio only format might not exist in which case we grab the
 			// whole thing and let ffmpeg extract the audio
 			ytdl := exec.Command("yt-dlp", "-f", "bestaudio/best", "-o", "-", p.Queue.Get(0).URL)
@@ -128,6 +133,7 @@ func Play(args []string, sp core.AudioSpeaker, place int64) (Item, error, error)
 	}
 	go stream(sp, p, place)
 	playing.Set(place, p)
+	metricQueues.Inc()
 
 	return item, nil, nil
 }
@@ -0,0 +1,1833 @@
+package automod
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/commands/moderation"
+	"github.com/janitorjeff/jeff-bot/commands/nick"
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+
+	dg "github.com/bwmarrin/discordgo"
+)
+
+func render(m *core.Message, resp string, usrErr error) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return &dg.MessageEmbed{Description: resp}, usrErr, nil
+	default:
+		return resp, usrErr, nil
+	}
+}
+
+// parseToggle returns true for any of core.AliasesOn and false for any of
+// core.AliasesOff.
+func parseToggle(s string) (bool, error) {
+	s = strings.ToLower(s)
+	for _, a := range core.AliasesOn {
+		if s == a {
+			return true, nil
+		}
+	}
+	for _, a := range core.AliasesOff {
+		if s == a {
+			return false, nil
+		}
+	}
+	return false, moderation.ErrUnexpectedToggle
+}
+
+// parseLimit returns 0 for any of core.AliasesOff, otherwise expects a
+// positive number.
+func parseLimit(s string) (int64, error) {
+	if on, err := parseToggle(s); err == nil && !on {
+		return 0, nil
+	}
+	var n int64
+	if _, err := fmt.Sscan(s, &n); err != nil || n <= 0 {
+		return 0, ErrInvalidLimit
+	}
+	return n, nil
+}
+
+func fmtToggle(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+func fmtLimit(n int64, unit string) string {
+	if n == 0 {
+		return "off"
+	}
+	return fmt.Sprintf("%d%s", n, unit)
+}
+
+func placeInQuotes(m *core.Message, s string) string {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return discord.PlaceInBackticks(s)
+	default:
+		return fmt.Sprintf("'%s'", s)
+	}
+}
+
+var Advanced = advanced{}
+
+type advanced struct{}
+
+func (advanced) Type() core.CommandType {
+	return core.Advanced
+}
+
+func (advanced) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advanced) Names() []string {
+	return []string{
+		"automod",
+	}
+}
+
+func (advanced) Description() string {
+	return "Automatically moderate the chat."
+}
+
+func (c advanced) UsageArgs() string {
+	return c.Children().Usage()
+}
+
+func (advanced) Category() core.CommandCategory {
+	return core.CommandCategoryModerators
+}
+
+func (advanced) Examples() []string {
+	return nil
+}
+
+func (advanced) Parent() core.CommandStatic {
+	return nil
+}
+
+func (advanced) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		AdvancedShow,
+		AdvancedOn,
+		AdvancedOff,
+		AdvancedLinks,
+		AdvancedAllow,
+		AdvancedPermit,
+		AdvancedCaps,
+		AdvancedEmotes,
+		AdvancedSymbols,
+		AdvancedRepeat,
+		AdvancedPhrase,
+		AdvancedActions,
+		AdvancedTimeout,
+		AdvancedExempt,
+	}
+}
+
+func (advanced) Init() error {
+	core.Hooks.Register(hook)
+
+	go func() {
+		for {
+			time.Sleep(pruneInterval)
+			prune()
+		}
+	}()
+
+	return nil
+}
+
+func (advanced) Run(m *core.Message) (any, error, error) {
+	return m.Usage(), core.ErrMissingArgs, nil
+}
+
+//////////
+//      //
+// show //
+//      //
+//////////
+
+var AdvancedShow = advancedShow{}
+
+type advancedShow struct{}
+
+func (c advancedShow) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedShow) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedShow) Names() []string {
+	return core.AliasesShow
+}
+
+func (advancedShow) Description() string {
+	return "Show the automod settings."
+}
+
+func (advancedShow) UsageArgs() string {
+	return ""
+}
+
+func (c advancedShow) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedShow) Examples() []string {
+	return nil
+}
+
+func (advancedShow) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedShow) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedShow) Init() error {
+	return nil
+}
+
+func (c advancedShow) Run(m *core.Message) (any, error, error) {
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedShow) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	c, err := ConfigGet(here)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var actions []string
+	for _, a := range c.Actions {
+		actions = append(actions, string(a))
+	}
+
+	resp := fmt.Sprintf("Automod is %s. Links: %s, caps: %s, emotes: %s, symbols: %s, repeat: %s. Actions: %s, timeout: %s. Exempt mods: %s, exempt subs: %s.",
+		fmtToggle(c.On),
+		fmtToggle(c.Links),
+		fmtLimit(c.Caps, "%"),
+		fmtLimit(c.Emotes, ""),
+		fmtLimit(c.Symbols, "%"),
+		fmtLimit(c.Repeat, ""),
+		strings.Join(actions, ", "),
+		moderation.FormatDuration(c.Timeout),
+		fmtToggle(c.ExemptMods),
+		fmtToggle(c.ExemptSubs),
+	)
+	return resp, nil, nil
+}
+
+////////
+//    //
+// on //
+//    //
+////////
+
+var AdvancedOn = advancedOn{}
+
+type advancedOn struct{}
+
+func (c advancedOn) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedOn) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedOn) Names() []string {
+	return core.AliasesOn
+}
+
+func (advancedOn) Description() string {
+	return "Turn automod on."
+}
+
+func (advancedOn) UsageArgs() string {
+	return ""
+}
+
+func (c advancedOn) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedOn) Examples() []string {
+	return nil
+}
+
+func (advancedOn) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedOn) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedOn) Init() error {
+	return nil
+}
+
+func (c advancedOn) Run(m *core.Message) (any, error, error) {
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedOn) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return "Automod has been turned on.", nil, OnSet(here, true)
+}
+
+/////////
+//     //
+// off //
+//     //
+/////////
+
+var AdvancedOff = advancedOff{}
+
+type advancedOff struct{}
+
+func (c advancedOff) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedOff) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedOff) Names() []string {
+	return core.AliasesOff
+}
+
+func (advancedOff) Description() string {
+	return "Turn automod off."
+}
+
+func (advancedOff) UsageArgs() string {
+	return ""
+}
+
+func (c advancedOff) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedOff) Examples() []string {
+	return nil
+}
+
+func (advancedOff) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedOff) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedOff) Init() error {
+	return nil
+}
+
+func (c advancedOff) Run(m *core.Message) (any, error, error) {
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedOff) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return "Automod has been turned off.", nil, OnSet(here, false)
+}
+
+///////////
+//       //
+// links //
+//       //
+///////////
+
+var AdvancedLinks = advancedLinks{}
+
+type advancedLinks struct{}
+
+func (c advancedLinks) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedLinks) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedLinks) Names() []string {
+	return []string{
+		"links",
+	}
+}
+
+func (advancedLinks) Description() string {
+	return "Turn link filtering on or off."
+}
+
+func (advancedLinks) UsageArgs() string {
+	return "<on | off>"
+}
+
+func (c advancedLinks) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedLinks) Examples() []string {
+	return nil
+}
+
+func (advancedLinks) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedLinks) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedLinks) Init() error {
+	return nil
+}
+
+func (c advancedLinks) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedLinks) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	on, usrErr := parseToggle(m.Command.Args[0])
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+	if err := LinksSet(here, on); err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("Link filtering has been turned %s.", fmtToggle(on)), nil, nil
+}
+
+///////////
+//       //
+// allow //
+//       //
+///////////
+
+var AdvancedAllow = advancedAllow{}
+
+type advancedAllow struct{}
+
+func (c advancedAllow) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedAllow) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedAllow) Names() []string {
+	return []string{
+		"allow",
+		"allowlist",
+	}
+}
+
+func (advancedAllow) Description() string {
+	return "Manage the domains that links are allowed to."
+}
+
+func (c advancedAllow) UsageArgs() string {
+	return c.Children().Usage()
+}
+
+func (c advancedAllow) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedAllow) Examples() []string {
+	return nil
+}
+
+func (advancedAllow) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedAllow) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		AdvancedAllowAdd,
+		AdvancedAllowDelete,
+		AdvancedAllowList,
+	}
+}
+
+func (advancedAllow) Init() error {
+	return nil
+}
+
+func (advancedAllow) Run(m *core.Message) (any, error, error) {
+	return m.Usage(), core.ErrMissingArgs, nil
+}
+
+///////////////
+//           //
+// allow add //
+//           //
+///////////////
+
+var AdvancedAllowAdd = advancedAllowAdd{}
+
+type advancedAllowAdd struct{}
+
+func (c advancedAllowAdd) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedAllowAdd) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedAllowAdd) Names() []string {
+	return core.AliasesAdd
+}
+
+func (advancedAllowAdd) Description() string {
+	return "Allow links to a domain."
+}
+
+func (advancedAllowAdd) UsageArgs() string {
+	return "<domain>"
+}
+
+func (c advancedAllowAdd) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedAllowAdd) Examples() []string {
+	return nil
+}
+
+func (advancedAllowAdd) Parent() core.CommandStatic {
+	return AdvancedAllow
+}
+
+func (advancedAllowAdd) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedAllowAdd) Init() error {
+	return nil
+}
+
+func (c advancedAllowAdd) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedAllowAdd) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	domain := m.Command.Args[0]
+	usrErr, err := AllowAdd(here, domain)
+	switch usrErr {
+	case nil:
+		return fmt.Sprintf("Links to %s are now allowed.", placeInQuotes(m, domain)), nil, err
+	case ErrDomainExists:
+		return fmt.Sprintf("Links to %s are already allowed.", placeInQuotes(m, domain)), usrErr, err
+	default:
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+}
+
+//////////////////
+//              //
+// allow delete //
+//              //
+//////////////////
+
+var AdvancedAllowDelete = advancedAllowDelete{}
+
+type advancedAllowDelete struct{}
+
+func (c advancedAllowDelete) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedAllowDelete) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedAllowDelete) Names() []string {
+	return core.AliasesDelete
+}
+
+func (advancedAllowDelete) Description() string {
+	return "Stop allowing links to a domain."
+}
+
+func (advancedAllowDelete) UsageArgs() string {
+	return "<domain>"
+}
+
+func (c advancedAllowDelete) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedAllowDelete) Examples() []string {
+	return nil
+}
+
+func (advancedAllowDelete) Parent() core.CommandStatic {
+	return AdvancedAllow
+}
+
+func (advancedAllowDelete) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedAllowDelete) Init() error {
+	return nil
+}
+
+func (c advancedAllowDelete) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedAllowDelete) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	domain := m.Command.Args[0]
+	usrErr, err := AllowDelete(here, domain)
+	switch usrErr {
+	case nil:
+		return fmt.Sprintf("Links to %s are no longer allowed.", placeInQuotes(m, domain)), nil, err
+	case ErrDomainNotFound:
+		return fmt.Sprintf("%s is not in the allowlist.", placeInQuotes(m, domain)), usrErr, err
+	default:
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+}
+
+////////////////
+//            //
+// allow list //
+//            //
+////////////////
+
+var AdvancedAllowList = advancedAllowList{}
+
+type advancedAllowList struct{}
+
+func (c advancedAllowList) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedAllowList) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedAllowList) Names() []string {
+	return core.AliasesList
+}
+
+func (advancedAllowList) Description() string {
+	return "List the allowed domains."
+}
+
+func (advancedAllowList) UsageArgs() string {
+	return ""
+}
+
+func (c advancedAllowList) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedAllowList) Examples() []string {
+	return nil
+}
+
+func (advancedAllowList) Parent() core.CommandStatic {
+	return AdvancedAllow
+}
+
+func (advancedAllowList) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedAllowList) Init() error {
+	return nil
+}
+
+func (c advancedAllowList) Run(m *core.Message) (any, error, error) {
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedAllowList) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	domains, err := AllowList(here)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(domains) == 0 {
+		return "No domains are allowed.", nil, nil
+	}
+	return "Allowed domains: " + strings.Join(domains, ", "), nil, nil
+}
+
+////////////
+//        //
+// permit //
+//        //
+////////////
+
+var AdvancedPermit = advancedPermit{}
+
+type advancedPermit struct{}
+
+func (c advancedPermit) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedPermit) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedPermit) Names() []string {
+	return []string{
+		"permit",
+	}
+}
+
+func (advancedPermit) Description() string {
+	return "Allow someone to post a single link."
+}
+
+func (advancedPermit) UsageArgs() string {
+	return "<person>"
+}
+
+func (c advancedPermit) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedPermit) Examples() []string {
+	return nil
+}
+
+func (advancedPermit) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedPermit) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedPermit) Init() error {
+	return nil
+}
+
+func (c advancedPermit) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedPermit) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	target := m.Command.Args[0]
+	person, err := nick.ParsePersonHere(m, target)
+	if err != nil {
+		return fmt.Sprint(moderation.ErrPersonNotFound), moderation.ErrPersonNotFound, nil
+	}
+	Permit(here, person)
+	return fmt.Sprintf("%s can post a link during the next %s.", target, moderation.FormatDuration(PermitDuration)), nil, nil
+}
+
+//////////
+//      //
+// caps //
+//      //
+//////////
+
+var AdvancedCaps = advancedCaps{}
+
+type advancedCaps struct{}
+
+func (c advancedCaps) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedCaps) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedCaps) Names() []string {
+	return []string{
+		"caps",
+	}
+}
+
+func (advancedCaps) Description() string {
+	return "Set the maximum percentage of caps in a message."
+}
+
+func (advancedCaps) UsageArgs() string {
+	return "<percent | off>"
+}
+
+func (c advancedCaps) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedCaps) Examples() []string {
+	return nil
+}
+
+func (advancedCaps) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedCaps) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedCaps) Init() error {
+	return nil
+}
+
+func (c advancedCaps) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedCaps) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	limit, usrErr := parseLimit(m.Command.Args[0])
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+	usrErr, err = CapsSet(here, limit)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	if limit == 0 {
+		return "The caps filter has been turned off.", nil, nil
+	}
+	return fmt.Sprintf("The caps limit has been set to %d%%.", limit), nil, nil
+}
+
+////////////
+//        //
+// emotes //
+//        //
+////////////
+
+var AdvancedEmotes = advancedEmotes{}
+
+type advancedEmotes struct{}
+
+func (c advancedEmotes) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedEmotes) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedEmotes) Names() []string {
+	return []string{
+		"emotes",
+	}
+}
+
+func (advancedEmotes) Description() string {
+	return "Set the maximum number of emotes in a message."
+}
+
+func (advancedEmotes) UsageArgs() string {
+	return "<number | off>"
+}
+
+func (c advancedEmotes) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedEmotes) Examples() []string {
+	return nil
+}
+
+func (advancedEmotes) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedEmotes) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedEmotes) Init() error {
+	return nil
+}
+
+func (c advancedEmotes) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedEmotes) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	limit, usrErr := parseLimit(m.Command.Args[0])
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+	usrErr, err = EmotesSet(here, limit)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	if limit == 0 {
+		return "The emotes filter has been turned off.", nil, nil
+	}
+	return fmt.Sprintf("The emotes limit has been set to %d.", limit), nil, nil
+}
+
+/////////////
+//         //
+// symbols //
+//         //
+/////////////
+
+var AdvancedSymbols = advancedSymbols{}
+
+type advancedSymbols struct{}
+
+func (c advancedSymbols) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedSymbols) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedSymbols) Names() []string {
+	return []string{
+		"symbols",
+	}
+}
+
+func (advancedSymbols) Description() string {
+	return "Set the maximum percentage of symbols in a message."
+}
+
+func (advancedSymbols) UsageArgs() string {
+	return "<percent | off>"
+}
+
+func (c advancedSymbols) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedSymbols) Examples() []string {
+	return nil
+}
+
+func (advancedSymbols) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedSymbols) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedSymbols) Init() error {
+	return nil
+}
+
+func (c advancedSymbols) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedSymbols) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	limit, usrErr := parseLimit(m.Command.Args[0])
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+	usrErr, err = SymbolsSet(here, limit)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	if limit == 0 {
+		return "The symbols filter has been turned off.", nil, nil
+	}
+	return fmt.Sprintf("The symbols limit has been set to %d%%.", limit), nil, nil
+}
+
+////////////
+//        //
+// repeat //
+//        //
+////////////
+
+var AdvancedRepeat = advancedRepeat{}
+
+type advancedRepeat struct{}
+
+func (c advancedRepeat) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedRepeat) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedRepeat) Names() []string {
+	return []string{
+		"repeat",
+	}
+}
+
+func (advancedRepeat) Description() string {
+	return "Set how many times the same message can be sent in a row."
+}
+
+func (advancedRepeat) UsageArgs() string {
+	return "<number | off>"
+}
+
+func (c advancedRepeat) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedRepeat) Examples() []string {
+	return nil
+}
+
+func (advancedRepeat) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedRepeat) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedRepeat) Init() error {
+	return nil
+}
+
+func (c advancedRepeat) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedRepeat) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	limit, usrErr := parseLimit(m.Command.Args[0])
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+	usrErr, err = RepeatSet(here, limit)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	if limit == 0 {
+		return "The repeat filter has been turned off.", nil, nil
+	}
+	return fmt.Sprintf("The repeat limit has been set to %d.", limit), nil, nil
+}
+
+////////////
+//        //
+// phrase //
+//        //
+////////////
+
+var AdvancedPhrase = advancedPhrase{}
+
+type advancedPhrase struct{}
+
+func (c advancedPhrase) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedPhrase) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedPhrase) Names() []string {
+	return []string{
+		"phrase",
+		"phrases",
+	}
+}
+
+func (advancedPhrase) Description() string {
+	return "Manage the blocked phrases."
+}
+
+func (c advancedPhrase) UsageArgs() string {
+	return c.Children().Usage()
+}
+
+func (c advancedPhrase) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedPhrase) Examples() []string {
+	return nil
+}
+
+func (advancedPhrase) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedPhrase) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		AdvancedPhraseAdd,
+		AdvancedPhraseDelete,
+		AdvancedPhraseList,
+	}
+}
+
+func (advancedPhrase) Init() error {
+	return nil
+}
+
+func (advancedPhrase) Run(m *core.Message) (any, error, error) {
+	return m.Usage(), core.ErrMissingArgs, nil
+}
+
+////////////////
+//            //
+// phrase add //
+//            //
+////////////////
+
+var AdvancedPhraseAdd = advancedPhraseAdd{}
+
+type advancedPhraseAdd struct{}
+
+func (c advancedPhraseAdd) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedPhraseAdd) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedPhraseAdd) Names() []string {
+	return core.AliasesAdd
+}
+
+func (advancedPhraseAdd) Description() string {
+	return "Block a phrase, regular expressions are supported."
+}
+
+func (advancedPhraseAdd) UsageArgs() string {
+	return "<phrase...>"
+}
+
+func (c advancedPhraseAdd) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedPhraseAdd) Examples() []string {
+	return nil
+}
+
+func (advancedPhraseAdd) Parent() core.CommandStatic {
+	return AdvancedPhrase
+}
+
+func (advancedPhraseAdd) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedPhraseAdd) Init() error {
+	return nil
+}
+
+func (c advancedPhraseAdd) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedPhraseAdd) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	phrase := m.RawArgs(0)
+	usrErr, err := PhraseAdd(here, phrase)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	return fmt.Sprintf("Blocked the phrase %s.", placeInQuotes(m, phrase)), nil, nil
+}
+
+///////////////////
+//               //
+// phrase delete //
+//               //
+///////////////////
+
+var AdvancedPhraseDelete = advancedPhraseDelete{}
+
+type advancedPhraseDelete struct{}
+
+func (c advancedPhraseDelete) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedPhraseDelete) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedPhraseDelete) Names() []string {
+	return core.AliasesDelete
+}
+
+func (advancedPhraseDelete) Description() string {
+	return "Unblock a phrase."
+}
+
+func (advancedPhraseDelete) UsageArgs() string {
+	return "<phrase...>"
+}
+
+func (c advancedPhraseDelete) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedPhraseDelete) Examples() []string {
+	return nil
+}
+
+func (advancedPhraseDelete) Parent() core.CommandStatic {
+	return AdvancedPhrase
+}
+
+func (advancedPhraseDelete) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedPhraseDelete) Init() error {
+	return nil
+}
+
+func (c advancedPhraseDelete) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedPhraseDelete) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	phrase := m.RawArgs(0)
+	usrErr, err := PhraseDelete(here, phrase)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	return fmt.Sprintf("Unblocked the phrase %s.", placeInQuotes(m, phrase)), nil, nil
+}
+
+/////////////////
+//             //
+// phrase list //
+//             //
+/////////////////
+
+var AdvancedPhraseList = advancedPhraseList{}
+
+type advancedPhraseList struct{}
+
+func (c advancedPhraseList) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedPhraseList) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedPhraseList) Names() []string {
+	return core.AliasesList
+}
+
+func (advancedPhraseList) Description() string {
+	return "List the blocked phrases."
+}
+
+func (advancedPhraseList) UsageArgs() string {
+	return ""
+}
+
+func (c advancedPhraseList) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedPhraseList) Examples() []string {
+	return nil
+}
+
+func (advancedPhraseList) Parent() core.CommandStatic {
+	return AdvancedPhrase
+}
+
+func (advancedPhraseList) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedPhraseList) Init() error {
+	return nil
+}
+
+func (c advancedPhraseList) Run(m *core.Message) (any, error, error) {
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedPhraseList) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	phrases, err := PhraseList(here)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(phrases) == 0 {
+		return "No phrases are blocked.", nil, nil
+	}
+	for i, p := range phrases {
+		phrases[i] = placeInQuotes(m, p)
+	}
+	return "Blocked phrases: " + strings.Join(phrases, ", "), nil, nil
+}
+
+/////////////
+//         //
+// actions //
+//         //
+/////////////
+
+var AdvancedActions = advancedActions{}
+
+type advancedActions struct{}
+
+func (c advancedActions) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedActions) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedActions) Names() []string {
+	return []string{
+		"actions",
+	}
+}
+
+func (advancedActions) Description() string {
+	return "Set the actions taken each time someone breaks the rules, the last one is repeated."
+}
+
+func (advancedActions) UsageArgs() string {
+	return "<warn | delete | timeout...>"
+}
+
+func (c advancedActions) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedActions) Examples() []string {
+	return nil
+}
+
+func (advancedActions) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedActions) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedActions) Init() error {
+	return nil
+}
+
+func (c advancedActions) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedActions) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	actions, usrErr := ParseActions(m.Command.Args)
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+	if err := ActionsSet(here, actions); err != nil {
+		return "", nil, err
+	}
+	var s []string
+	for _, a := range actions {
+		s = append(s, string(a))
+	}
+	return "The actions have been set to: " + strings.Join(s, ", "), nil, nil
+}
+
+/////////////
+//         //
+// timeout //
+//         //
+/////////////
+
+var AdvancedTimeout = advancedTimeout{}
+
+type advancedTimeout struct{}
+
+func (c advancedTimeout) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedTimeout) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedTimeout) Names() []string {
+	return []string{
+		"timeout",
+	}
+}
+
+func (advancedTimeout) Description() string {
+	return "Set how long the timeout action lasts."
+}
+
+func (advancedTimeout) UsageArgs() string {
+	return "<duration>"
+}
+
+func (c advancedTimeout) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedTimeout) Examples() []string {
+	return nil
+}
+
+func (advancedTimeout) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedTimeout) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedTimeout) Init() error {
+	return nil
+}
+
+func (c advancedTimeout) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedTimeout) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	dur, usrErr := moderation.ParseDuration(m.Command.Args[0])
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+	usrErr, err = TimeoutSet(here, dur)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	return fmt.Sprintf("The timeout has been set to %s.", moderation.FormatDuration(dur)), nil, nil
+}
+
+////////////
+//        //
+// exempt //
+//        //
+////////////
+
+var AdvancedExempt = advancedExempt{}
+
+type advancedExempt struct{}
+
+func (c advancedExempt) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedExempt) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedExempt) Names() []string {
+	return []string{
+		"exempt",
+	}
+}
+
+func (advancedExempt) Description() string {
+	return "Control who is exempt from automod."
+}
+
+func (c advancedExempt) UsageArgs() string {
+	return c.Children().Usage()
+}
+
+func (c advancedExempt) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedExempt) Examples() []string {
+	return nil
+}
+
+func (advancedExempt) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedExempt) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		AdvancedExemptMods,
+		AdvancedExemptSubs,
+	}
+}
+
+func (advancedExempt) Init() error {
+	return nil
+}
+
+func (advancedExempt) Run(m *core.Message) (any, error, error) {
+	return m.Usage(), core.ErrMissingArgs, nil
+}
+
+/////////////////
+//             //
+// exempt mods //
+//             //
+/////////////////
+
+var AdvancedExemptMods = advancedExemptMods{}
+
+type advancedExemptMods struct{}
+
+func (c advancedExemptMods) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedExemptMods) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedExemptMods) Names() []string {
+	return []string{
+		"mods",
+		"mod",
+		"moderators",
+	}
+}
+
+func (advancedExemptMods) Description() string {
+	return "Exempt mods or not."
+}
+
+func (advancedExemptMods) UsageArgs() string {
+	return "<on | off>"
+}
+
+func (c advancedExemptMods) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedExemptMods) Examples() []string {
+	return nil
+}
+
+func (advancedExemptMods) Parent() core.CommandStatic {
+	return AdvancedExempt
+}
+
+func (advancedExemptMods) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedExemptMods) Init() error {
+	return nil
+}
+
+func (c advancedExemptMods) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedExemptMods) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	on, usrErr := parseToggle(m.Command.Args[0])
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+	if err := ExemptModsSet(here, on); err != nil {
+		return "", nil, err
+	}
+	if on {
+		return "Mods are now exempt.", nil, nil
+	}
+	return "Mods are no longer exempt.", nil, nil
+}
+
+/////////////////
+//             //
+// exempt subs //
+//             //
+/////////////////
+
+var AdvancedExemptSubs = advancedExemptSubs{}
+
+type advancedExemptSubs struct{}
+
+func (c advancedExemptSubs) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedExemptSubs) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedExemptSubs) Names() []string {
+	return []string{
+		"subs",
+		"sub",
+		"subscribers",
+	}
+}
+
+func (advancedExemptSubs) Description() string {
+	return "Exempt subscribers or not."
+}
+
+func (advancedExemptSubs) UsageArgs() string {
+	return "<on | off>"
+}
+
+func (c advancedExemptSubs) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedExemptSubs) Examples() []string {
+	return nil
+}
+
+func (advancedExemptSubs) Parent() core.CommandStatic {
+	return AdvancedExempt
+}
+
+func (advancedExemptSubs) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedExemptSubs) Init() error {
+	return nil
+}
+
+func (c advancedExemptSubs) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedExemptSubs) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	on, usrErr := parseToggle(m.Command.Args[0])
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+	if err := ExemptSubsSet(here, on); err != nil {
+		return "", nil, err
+	}
+	if on {
+		return "Subscribers are now exempt.", nil, nil
+	}
+	return "Subscribers are no longer exempt.", nil, nil
+}
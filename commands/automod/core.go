@@ -0,0 +1,739 @@
+package automod
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/janitorjeff/jeff-bot/commands/moderation"
+	"github.com/janitorjeff/jeff-bot/commands/warn"
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/janitorjeff/gosafe"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrInvalidActions  = errors.New("Expected a list of actions, each one being either warn, delete or timeout.")
+	ErrInvalidLimit    = errors.New("Expected a positive number or off.")
+	ErrInvalidPercent  = errors.New("Expected a percentage between 1 and 100 or off.")
+	ErrInvalidDomain   = errors.New("Expected a domain, for example youtube.com.")
+	ErrInvalidPhrase   = errors.New("The phrase is not a valid regular expression.")
+	ErrDomainExists    = errors.New("The domain is already allowed.")
+	ErrDomainNotFound  = errors.New("The domain is not in the allowlist.")
+	ErrPhraseExists    = errors.New("The phrase is already blocked.")
+	ErrPhraseNotFound  = errors.New("The phrase is not blocked.")
+	ErrTimeoutTooShort = errors.New("The timeout must be at least one second long.")
+)
+
+const (
+	// PermitDuration is how long someone who was permitted has to post a link.
+	PermitDuration = time.Minute
+
+	// The minimum number of characters a message must have before the caps
+	// and symbols filters apply, so that short messages like "LOL" are left
+	// alone.
+	minLength = 10
+
+	// Only the automod warnings that were added within this period count
+	// towards the escalation, so if someone doesn't break any rules for this
+	// long then the escalation starts from the beginning.
+	strikeReset = time.Hour
+
+	// If someone doesn't send anything for this long then the next message
+	// isn't counted as a repeat.
+	repeatReset = 10 * time.Minute
+
+	// How often the expired permits and repeats are removed from memory.
+	pruneInterval = 10 * time.Minute
+)
+
+// Action is what is done when a message breaks a rule.
+type Action string
+
+const (
+	ActionWarn    Action = "warn"
+	ActionDelete  Action = "delete"
+	ActionTimeout Action = "timeout"
+)
+
+// Violation is the rule that a message broke.
+type Violation string
+
+const (
+	ViolationNone    Violation = ""
+	ViolationLink    Violation = "link"
+	ViolationCaps    Violation = "caps"
+	ViolationEmotes  Violation = "emotes"
+	ViolationSymbols Violation = "symbols"
+	ViolationRepeat  Violation = "repeat"
+	ViolationPhrase  Violation = "phrase"
+)
+
+// Reason returns the reason that is shown to the person that broke the rule.
+func (v Violation) Reason() string {
+	switch v {
+	case ViolationLink:
+		return "Please don't post links without permission."
+	case ViolationCaps:
+		return "Please don't use so many caps."
+	case ViolationEmotes:
+		return "Please don't use so many emotes."
+	case ViolationSymbols:
+		return "Please don't use so many symbols."
+	case ViolationRepeat:
+		return "Please don't repeat the same message."
+	case ViolationPhrase:
+		return "Please watch your language."
+	default:
+		return ""
+	}
+}
+
+// Config holds a place's automod settings. Limits that are set to 0 are off.
+type Config struct {
+	On    bool
+	Links bool
+
+	Caps    int64 // maximum percentage of uppercase letters
+	Emotes  int64 // maximum number of emotes
+	Symbols int64 // maximum percentage of symbols
+	Repeat  int64 // maximum number of times the same message can be sent
+
+	// Actions are applied in order the more someone breaks the rules, the
+	// last action is repeated once the end is reached.
+	Actions []Action
+	Timeout time.Duration
+
+	ExemptMods bool
+	ExemptSubs bool
+}
+
+var DefaultActions = []Action{ActionWarn, ActionDelete, ActionTimeout}
+
+func parseActions(s string) []Action {
+	var actions []Action
+	for _, f := range strings.Fields(s) {
+		actions = append(actions, Action(f))
+	}
+	if len(actions) == 0 {
+		return DefaultActions
+	}
+	return actions
+}
+
+func secondsToDuration(secs int64) time.Duration {
+	return time.Duration(secs) * time.Second
+}
+
+// ParseActions parses a list of actions, returns ErrInvalidActions if any of
+// them are not valid.
+func ParseActions(args []string) ([]Action, error) {
+	if len(args) == 0 {
+		return nil, ErrInvalidActions
+	}
+	var actions []Action
+	for _, arg := range args {
+		switch a := Action(strings.ToLower(arg)); a {
+		case ActionWarn, ActionDelete, ActionTimeout:
+			actions = append(actions, a)
+		default:
+			return nil, ErrInvalidActions
+		}
+	}
+	return actions, nil
+}
+
+//////////////
+//          //
+// settings //
+//          //
+//////////////
+
+// ConfigGet returns the automod config of the specified place.
+func ConfigGet(place int64) (Config, error) {
+	return dbGetConfig(place)
+}
+
+// OnSet turns automod on or off in the specified place.
+func OnSet(place int64, on bool) error {
+	return core.DB.SettingPlaceSet("cmd_automod_on", place, on)
+}
+
+// LinksSet turns link filtering on or off in the specified place.
+func LinksSet(place int64, on bool) error {
+	return core.DB.SettingPlaceSet("cmd_automod_links", place, on)
+}
+
+// CapsSet sets the maximum percentage of uppercase letters a message can
+// have, 0 turns the filter off. Returns ErrInvalidPercent if it's not a valid
+// percentage.
+func CapsSet(place, percent int64) (error, error) {
+	if percent < 0 || percent > 100 {
+		return ErrInvalidPercent, nil
+	}
+	return nil, core.DB.SettingPlaceSet("cmd_automod_caps", place, percent)
+}
+
+// EmotesSet sets the maximum number of emotes a message can have, 0 turns the
+// filter off.
+func EmotesSet(place, limit int64) (error, error) {
+	if limit < 0 {
+		return ErrInvalidLimit, nil
+	}
+	return nil, core.DB.SettingPlaceSet("cmd_automod_emotes", place, limit)
+}
+
+// SymbolsSet sets the maximum percentage of symbols a message can have, 0
+// turns the filter off. Returns ErrInvalidPercent if it's not a valid
+// percentage.
+func SymbolsSet(place, percent int64) (error, error) {
+	if percent < 0 || percent > 100 {
+		return ErrInvalidPercent, nil
+	}
+	return nil, core.DB.SettingPlaceSet("cmd_automod_symbols", place, percent)
+}
+
+// RepeatSet sets the maximum number of times someone can send the same
+// message in a row, 0 turns the filter off.
+func RepeatSet(place, limit int64) (error, error) {
+	if limit < 0 {
+		return ErrInvalidLimit, nil
+	}
+	return nil, core.DB.SettingPlaceSet("cmd_automod_repeat", place, limit)
+}
+
+// ActionsSet sets the escalating actions for the specified place.
+func ActionsSet(place int64, actions []Action) error {
+	var s []string
+	for _, a := range actions {
+		s = append(s, string(a))
+	}
+	return core.DB.SettingPlaceSet("cmd_automod_actions", place, strings.Join(s, " "))
+}
+
+// TimeoutSet sets how long people are timed out for when the timeout action
+// is applied.
+func TimeoutSet(place int64, dur time.Duration) (error, error) {
+	if dur < time.Second {
+		return ErrTimeoutTooShort, nil
+	}
+	return nil, core.DB.SettingPlaceSet("cmd_automod_timeout", place, int64(dur.Seconds()))
+}
+
+// ExemptModsSet sets whether mods are exempt from automod.
+func ExemptModsSet(place int64, exempt bool) error {
+	return core.DB.SettingPlaceSet("cmd_automod_exempt_mods", place, exempt)
+}
+
+// ExemptSubsSet sets whether subscribers are exempt from automod.
+func ExemptSubsSet(place int64, exempt bool) error {
+	return core.DB.SettingPlaceSet("cmd_automod_exempt_subs", place, exempt)
+}
+
+///////////////
+//           //
+// allowlist //
+//           //
+///////////////
+
+var domainRegex = regexp.MustCompile(`^([a-z0-9-]+\.)+[a-z]{2,}$`)
+
+// normalizeDomain returns the domain's host in lowercase, without a www.
+// prefix. Returns false if the string doesn't look like a domain.
+func normalizeDomain(s string) (string, bool) {
+	s = strings.ToLower(s)
+	if !strings.Contains(s, "://") {
+		s = "http://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", false
+	}
+	host := strings.TrimPrefix(u.Hostname(), "www.")
+	if !domainRegex.MatchString(host) {
+		return "", false
+	}
+	return host, true
+}
+
+// AllowAdd adds a domain to the place's allowlist. Links to the domain or any
+// of its subdomains are not filtered.
+func AllowAdd(place int64, domain string) (error, error) {
+	domain, ok := normalizeDomain(domain)
+	if !ok {
+		return ErrInvalidDomain, nil
+	}
+	exists, err := dbAllowExists(place, domain)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return ErrDomainExists, nil
+	}
+	return nil, dbAllowAdd(place, domain)
+}
+
+// AllowDelete removes a domain from the place's allowlist.
+func AllowDelete(place int64, domain string) (error, error) {
+	domain, ok := normalizeDomain(domain)
+	if !ok {
+		return ErrInvalidDomain, nil
+	}
+	exists, err := dbAllowExists(place, domain)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return ErrDomainNotFound, nil
+	}
+	return nil, dbAllowDelete(place, domain)
+}
+
+// AllowList returns the place's allowlist.
+func AllowList(place int64) ([]string, error) {
+	return dbAllowList(place)
+}
+
+/////////////
+//         //
+// phrases //
+//         //
+/////////////
+
+// PhraseAdd adds a blocked phrase in the specified place. The phrase is a
+// case insensitive regular expression.
+func PhraseAdd(place int64, phrase string) (error, error) {
+	if _, err := compilePhrase(phrase); err != nil {
+		return ErrInvalidPhrase, nil
+	}
+	exists, err := dbPhraseExists(place, phrase)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return ErrPhraseExists, nil
+	}
+	if err := dbPhraseAdd(place, phrase); err != nil {
+		return nil, err
+	}
+	return nil, core.CacheInvalidate(phrasesKey(place))
+}
+
+// PhraseDelete removes a blocked phrase from the specified place.
+func PhraseDelete(place int64, phrase string) (error, error) {
+	exists, err := dbPhraseExists(place, phrase)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return ErrPhraseNotFound, nil
+	}
+	if err := dbPhraseDelete(place, phrase); err != nil {
+		return nil, err
+	}
+	compiled.Delete(phrase)
+	return nil, core.CacheInvalidate(phrasesKey(place))
+}
+
+func phrasesKey(place int64) string {
+	return fmt.Sprintf("automod_phrases_%d", place)
+}
+
+// PhraseList returns the blocked phrases of the specified place.
+func PhraseList(place int64) ([]string, error) {
+	return core.CacheGet(phrasesKey(place), core.CacheTTL, func() ([]string, error) {
+		return dbPhraseList(place)
+	})
+}
+
+func compilePhrase(phrase string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + phrase)
+}
+
+// Compiled regular expressions can't be put in the cache, so they are kept
+// in memory instead, keyed by the phrase itself.
+var compiled = gosafe.Map[string, *regexp.Regexp]{}
+
+// phraseRegexps returns the compiled blocked phrases of the specified place,
+// each phrase is only compiled the first time it is needed.
+func phraseRegexps(place int64) ([]*regexp.Regexp, error) {
+	phrases, err := PhraseList(place)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*regexp.Regexp, 0, len(phrases))
+	for _, phrase := range phrases {
+		re, ok := compiled.Get(phrase)
+		if !ok {
+			re, err = compilePhrase(phrase)
+			if err != nil {
+				continue
+			}
+			compiled.Set(phrase, re)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+///////////
+//       //
+// state //
+//       //
+///////////
+
+// The state that is only relevant for a short amount of time is kept in
+// memory, there's no point in saving it.
+
+type key struct {
+	place  int64
+	person int64
+}
+
+type repeat struct {
+	text  string
+	count int
+	last  time.Time
+}
+
+var (
+	lock    sync.Mutex
+	permits = map[key]time.Time{}
+	repeats = map[key]repeat{}
+)
+
+// Permit allows the person to post a single link in the specified place
+// during the next PermitDuration.
+func Permit(place, person int64) {
+	lock.Lock()
+	defer lock.Unlock()
+	permits[key{place, person}] = time.Now().Add(PermitDuration)
+}
+
+// usePermit returns true if the person had a valid permit, which is then
+// used up.
+func usePermit(place, person int64) bool {
+	lock.Lock()
+	defer lock.Unlock()
+	k := key{place, person}
+	expires, ok := permits[k]
+	if !ok {
+		return false
+	}
+	delete(permits, k)
+	return time.Now().Before(expires)
+}
+
+// addRepeat returns the number of times in a row the person has sent text.
+func addRepeat(place, person int64, text string) int {
+	lock.Lock()
+	defer lock.Unlock()
+	k := key{place, person}
+	text = strings.ToLower(strings.TrimSpace(text))
+	now := time.Now()
+	r := repeats[k]
+	if r.text == text && now.Sub(r.last) < repeatReset {
+		r.count++
+	} else {
+		r = repeat{text: text, count: 1}
+	}
+	r.last = now
+	repeats[k] = r
+	return r.count
+}
+
+// prune removes the permits that have expired and the repeats that would be
+// reset anyway, so that they don't pile up.
+func prune() {
+	lock.Lock()
+	defer lock.Unlock()
+	now := time.Now()
+	for k, expires := range permits {
+		if now.After(expires) {
+			delete(permits, k)
+		}
+	}
+	for k, r := range repeats {
+		if now.Sub(r.last) >= repeatReset {
+			delete(repeats, k)
+		}
+	}
+}
+
+////////////
+//        //
+// checks //
+//        //
+////////////
+
+// links returns the hosts of the links found in the text.
+func links(text string) []string {
+	var hosts []string
+	for _, f := range strings.Fields(text) {
+		f = strings.Trim(f, `.,!?;:'"()[]{}<>`)
+		host, ok := normalizeDomain(f)
+		if !ok {
+			continue
+		}
+		// filter out things like "e.g" and "file.txt" which are
+		// technically valid domains but very rarely actual links
+		if !core.IsValidURL(f) && !strings.Contains(f, "/") && !strings.HasPrefix(strings.ToLower(f), "www.") {
+			if tld := host[strings.LastIndex(host, ".")+1:]; !commonTLDs[tld] {
+				continue
+			}
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+var commonTLDs = map[string]bool{
+	"com": true, "net": true, "org": true, "io": true, "gg": true,
+	"tv": true, "co": true, "me": true, "ly": true, "xyz": true,
+	"ru": true, "de": true, "uk": true, "gr": true, "info": true,
+	"link": true, "live": true, "app": true, "dev": true, "be": true,
+}
+
+// allowed returns true if host is one of the domains or one of their
+// subdomains.
+func allowed(host string, domains []string) bool {
+	for _, d := range domains {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// percent returns the percentage of the characters for which f returns true
+// out of those for which of returns true. Returns -1 if there are fewer than
+// minLength of them.
+func percent(text string, f, of func(rune) bool) int {
+	var n, total int
+	for _, r := range text {
+		if !of(r) {
+			continue
+		}
+		total++
+		if f(r) {
+			n++
+		}
+	}
+	if total < minLength {
+		return -1
+	}
+	return n * 100 / total
+}
+
+func capsPercent(text string) int {
+	return percent(text, unicode.IsUpper, unicode.IsLetter)
+}
+
+func isSymbol(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+}
+
+func symbolsPercent(text string) int {
+	return percent(text, isSymbol, func(r rune) bool { return !unicode.IsSpace(r) })
+}
+
+var discordEmoteRegex = regexp.MustCompile(`<a?:\w+:\d+>`)
+
+// emotes returns the number of emotes in the message. Frontends that know
+// which parts of a message are emotes can implement the Emotes method,
+// otherwise discord's custom emote syntax and unicode emojis are counted.
+func emotes(m *core.Message) int {
+	if e, ok := m.Client.(interface{ Emotes() int }); ok {
+		return e.Emotes()
+	}
+	count := len(discordEmoteRegex.FindAllString(m.Raw, -1))
+	for _, r := range m.Raw {
+		if unicode.Is(unicode.So, r) {
+			count++
+		}
+	}
+	return count
+}
+
+func matchPhrase(text string, phrases []*regexp.Regexp) bool {
+	for _, re := range phrases {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// Exempt returns true if the message's author is exempt from automod.
+func Exempt(m *core.Message, c Config) bool {
+	if c.ExemptMods && m.Author.Mod() {
+		return true
+	}
+	if c.ExemptSubs && m.Author.Subscriber() {
+		return true
+	}
+	return false
+}
+
+// Check returns the rule the message broke, if any, in the specified place.
+func Check(m *core.Message, place, person int64, c Config) (Violation, error) {
+	// always keep track of repeats, even if the message breaks some other
+	// rule, so that it's counted correctly
+	repeats := addRepeat(place, person, m.Raw)
+
+	phrases, err := phraseRegexps(place)
+	if err != nil {
+		return ViolationNone, err
+	}
+	if matchPhrase(m.Raw, phrases) {
+		return ViolationPhrase, nil
+	}
+
+	if c.Links {
+		if hosts := links(m.Raw); len(hosts) != 0 {
+			domains, err := AllowList(place)
+			if err != nil {
+				return ViolationNone, err
+			}
+			for _, host := range hosts {
+				if allowed(host, domains) {
+					continue
+				}
+				if usePermit(place, person) {
+					break
+				}
+				return ViolationLink, nil
+			}
+		}
+	}
+
+	if c.Caps != 0 && int64(capsPercent(m.Raw)) > c.Caps {
+		return ViolationCaps, nil
+	}
+
+	if c.Symbols != 0 && int64(symbolsPercent(m.Raw)) > c.Symbols {
+		return ViolationSymbols, nil
+	}
+
+	if c.Emotes != 0 && int64(emotes(m)) > c.Emotes {
+		return ViolationEmotes, nil
+	}
+
+	if c.Repeat != 0 && int64(repeats) > c.Repeat {
+		return ViolationRepeat, nil
+	}
+
+	return ViolationNone, nil
+}
+
+// Punish applies the appropriate action to the message's author depending on
+// how many times they have broken the rules recently. The violation is added
+// as a warning, so if it reaches the place's warning threshold then the
+// threshold's action is applied instead.
+func Punish(m *core.Message, place, person int64, c Config, v Violation) error {
+	actions := c.Actions
+	if len(actions) == 0 {
+		actions = DefaultActions
+	}
+
+	reason := v.Reason()
+
+	// every violation is recorded as a warning, the ones that were added
+	// recently are used to determine how far along the escalation is, and
+	// just like any other warning they count towards the place's threshold
+	_, reached, usrErr, err := warn.Warn(m, person, warn.Automod, reason)
+	if err != nil {
+		return err
+	}
+	switch {
+	case usrErr != nil:
+		log.Debug().AnErr("usrErr", usrErr).Msg("automod failed to apply warning threshold")
+	case reached == warn.ActionTimeout:
+		reason += " Reached the warning threshold and was timed out."
+	case reached == warn.ActionBan:
+		reason += " Reached the warning threshold and was banned."
+	}
+	// the threshold action already overrides any of automod's own
+	if usrErr == nil && reached != warn.ActionNone {
+		_, err = m.Client.Send(m.Author.Mention()+" "+reason, nil)
+		return err
+	}
+
+	n, err := warn.CountAutomod(place, person, time.Now().Add(-strikeReset))
+	if err != nil {
+		return err
+	}
+	if n < 1 {
+		n = 1
+	}
+	if n > len(actions) {
+		n = len(actions)
+	}
+	action := actions[n-1]
+
+	switch action {
+	case ActionDelete:
+		usrErr, err = moderation.Delete(m)
+	case ActionTimeout:
+		usrErr, err = moderation.Timeout(m, person, c.Timeout, reason)
+		reason = fmt.Sprintf("%s Timed out for %s.", reason, moderation.FormatDuration(c.Timeout))
+	}
+
+	log.Debug().
+		Err(err).
+		AnErr("usrErr", usrErr).
+		Int64("place", place).
+		Int64("person", person).
+		Str("violation", string(v)).
+		Str("action", string(action)).
+		Int("strike", n).
+		Msg("automod punished")
+
+	if err != nil {
+		return err
+	}
+
+	_, err = m.Client.Send(m.Author.Mention()+" "+reason, nil)
+	return err
+}
+
+// hook is run on every message and applies the place's rules.
+func hook(m *core.Message) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return
+	}
+
+	c, err := ConfigGet(here)
+	if err != nil || !c.On {
+		return
+	}
+
+	if Exempt(m, c) {
+		return
+	}
+
+	person, err := m.Author.Scope()
+	if err != nil {
+		return
+	}
+
+	v, err := Check(m, here, person, c)
+	if err != nil {
+		log.Debug().Err(err).Msg("automod failed to check message")
+		return
+	}
+	if v == ViolationNone {
+		return
+	}
+
+	if err := Punish(m, here, person, c, v); err != nil {
+		log.Debug().Err(err).Msg("automod failed to punish")
+	}
+}
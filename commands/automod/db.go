@@ -0,0 +1,261 @@
+package automod
+
+import (
+	"strings"
+
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/rs/zerolog/log"
+)
+
+func dbGetConfig(place int64) (Config, error) {
+	var c Config
+
+	// Make sure that the place settings are present
+	if err := core.DB.SettingsPlaceGenerate(place); err != nil {
+		return c, err
+	}
+
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	var actions string
+	var timeout int64
+
+	err := db.DB.QueryRow(`
+		SELECT
+			cmd_automod_on,
+			cmd_automod_links,
+			cmd_automod_caps,
+			cmd_automod_emotes,
+			cmd_automod_symbols,
+			cmd_automod_repeat,
+			cmd_automod_actions,
+			cmd_automod_timeout,
+			cmd_automod_exempt_mods,
+			cmd_automod_exempt_subs
+		FROM settings_place
+		WHERE place = $1
+	`, place).Scan(
+		&c.On,
+		&c.Links,
+		&c.Caps,
+		&c.Emotes,
+		&c.Symbols,
+		&c.Repeat,
+		&actions,
+		&timeout,
+		&c.ExemptMods,
+		&c.ExemptSubs,
+	)
+
+	c.Actions = parseActions(actions)
+	c.Timeout = secondsToDuration(timeout)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Interface("config", c).
+		Msg("got automod config")
+
+	return c, err
+}
+
+func dbAllowAdd(place int64, domain string) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		INSERT INTO cmd_automod_allowlist(place, domain)
+		VALUES ($1, $2)
+	`, place, domain)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Str("domain", domain).
+		Msg("added domain to allowlist")
+
+	return err
+}
+
+func dbAllowDelete(place int64, domain string) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		DELETE FROM cmd_automod_allowlist
+		WHERE place = $1 and domain = $2
+	`, place, domain)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Str("domain", domain).
+		Msg("deleted domain from allowlist")
+
+	return err
+}
+
+func dbAllowExists(place int64, domain string) (bool, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	var exists bool
+	row := db.DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM cmd_automod_allowlist
+			WHERE place = $1 and domain = $2
+			LIMIT 1
+		)
+	`, place, domain)
+	err := row.Scan(&exists)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Str("domain", domain).
+		Bool("exists", exists).
+		Msg("checked if domain is allowed")
+
+	return exists, err
+}
+
+func dbAllowList(place int64) ([]string, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	rows, err := db.DB.Query(`
+		SELECT domain
+		FROM cmd_automod_allowlist
+		WHERE place = $1
+		ORDER BY domain
+	`, place)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var domains []string
+	for rows.Next() {
+		var domain string
+		if err := rows.Scan(&domain); err != nil {
+			return nil, err
+		}
+		domains = append(domains, domain)
+	}
+
+	err = rows.Err()
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Str("domains", strings.Join(domains, " ")).
+		Msg("got allowlist")
+
+	return domains, err
+}
+
+func dbPhraseAdd(place int64, phrase string) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		INSERT INTO cmd_automod_phrases(place, phrase)
+		VALUES ($1, $2)
+	`, place, phrase)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Str("phrase", phrase).
+		Msg("added blocked phrase")
+
+	return err
+}
+
+func dbPhraseDelete(place int64, phrase string) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		DELETE FROM cmd_automod_phrases
+		WHERE place = $1 and phrase = $2
+	`, place, phrase)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Str("phrase", phrase).
+		Msg("deleted blocked phrase")
+
+	return err
+}
+
+func dbPhraseExists(place int64, phrase string) (bool, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	var exists bool
+	row := db.DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM cmd_automod_phrases
+			WHERE place = $1 and phrase = $2
+			LIMIT 1
+		)
+	`, place, phrase)
+	err := row.Scan(&exists)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Str("phrase", phrase).
+		Bool("exists", exists).
+		Msg("checked if phrase is blocked")
+
+	return exists, err
+}
+
+func dbPhraseList(place int64) ([]string, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	rows, err := db.DB.Query(`
+		SELECT phrase
+		FROM cmd_automod_phrases
+		WHERE place = $1
+		ORDER BY id
+	`, place)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var phrases []string
+	for rows.Next() {
+		var phrase string
+		if err := rows.Scan(&phrase); err != nil {
+			return nil, err
+		}
+		phrases = append(phrases, phrase)
+	}
+
+	err = rows.Err()
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int("count", len(phrases)).
+		Msg("got blocked phrases")
+
+	return phrases, err
+}
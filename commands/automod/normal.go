@@ -0,0 +1,59 @@
+package automod
+
+import (
+	"github.com/janitorjeff/jeff-bot/core"
+)
+
+////////////
+//        //
+// permit //
+//        //
+////////////
+
+var NormalPermit = normalPermit{}
+
+type normalPermit struct{}
+
+func (normalPermit) Type() core.CommandType {
+	return core.Normal
+}
+
+func (normalPermit) Permitted(m *core.Message) bool {
+	return AdvancedPermit.Permitted(m)
+}
+
+func (normalPermit) Names() []string {
+	return AdvancedPermit.Names()
+}
+
+func (normalPermit) Description() string {
+	return AdvancedPermit.Description()
+}
+
+func (normalPermit) UsageArgs() string {
+	return AdvancedPermit.UsageArgs()
+}
+
+func (normalPermit) Category() core.CommandCategory {
+	return AdvancedPermit.Category()
+}
+
+func (normalPermit) Examples() []string {
+	return nil
+}
+
+func (normalPermit) Parent() core.CommandStatic {
+	return nil
+}
+
+func (normalPermit) Children() core.CommandsStatic {
+	return nil
+}
+
+func (normalPermit) Init() error {
+	return nil
+}
+
+func (normalPermit) Run(m *core.Message) (any, error, error) {
+	return AdvancedPermit.Run(m)
+}
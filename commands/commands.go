@@ -3,24 +3,44 @@ package commands
 import (
 	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/janitorjeff/jeff-bot/commands/alias"
+	"github.com/janitorjeff/jeff-bot/commands/api"
 	"github.com/janitorjeff/jeff-bot/commands/audio"
+	"github.com/janitorjeff/jeff-bot/commands/automod"
 	"github.com/janitorjeff/jeff-bot/commands/category"
 	"github.com/janitorjeff/jeff-bot/commands/connect"
+	"github.com/janitorjeff/jeff-bot/commands/counter"
 	"github.com/janitorjeff/jeff-bot/commands/custom-command"
+	"github.com/janitorjeff/jeff-bot/commands/giveaway"
 	"github.com/janitorjeff/jeff-bot/commands/god"
 	"github.com/janitorjeff/jeff-bot/commands/help"
 	"github.com/janitorjeff/jeff-bot/commands/id"
+	"github.com/janitorjeff/jeff-bot/commands/language"
 	"github.com/janitorjeff/jeff-bot/commands/mask"
+	"github.com/janitorjeff/jeff-bot/commands/moderation"
 	"github.com/janitorjeff/jeff-bot/commands/nick"
+	"github.com/janitorjeff/jeff-bot/commands/overlay"
 	"github.com/janitorjeff/jeff-bot/commands/paintball"
+	"github.com/janitorjeff/jeff-bot/commands/points"
+	"github.com/janitorjeff/jeff-bot/commands/poll"
 	"github.com/janitorjeff/jeff-bot/commands/prefix"
+	"github.com/janitorjeff/jeff-bot/commands/quote"
+	"github.com/janitorjeff/jeff-bot/commands/random"
 	"github.com/janitorjeff/jeff-bot/commands/rps"
 	"github.com/janitorjeff/jeff-bot/commands/search"
+	"github.com/janitorjeff/jeff-bot/commands/status"
 	"github.com/janitorjeff/jeff-bot/commands/time"
 	"github.com/janitorjeff/jeff-bot/commands/title"
+	"github.com/janitorjeff/jeff-bot/commands/trivia"
 	"github.com/janitorjeff/jeff-bot/commands/tts"
+	"github.com/janitorjeff/jeff-bot/commands/twitch-channel"
 	"github.com/janitorjeff/jeff-bot/commands/urban-dictionary"
+	"github.com/janitorjeff/jeff-bot/commands/warn"
+	"github.com/janitorjeff/jeff-bot/commands/webhook"
 	"github.com/janitorjeff/jeff-bot/commands/wikipedia"
 	"github.com/janitorjeff/jeff-bot/commands/youtube"
 	"github.com/janitorjeff/jeff-bot/core"
@@ -30,15 +50,26 @@ import (
 )
 
 var Commands = core.CommandsStatic{
+	alias.Advanced,
+
+	api.Advanced,
+
 	audio.Advanced,
 
+	automod.Advanced,
+	automod.NormalPermit,
+
 	category.Normal,
 	category.Advanced,
 
 	connect.Normal,
 
+	counter.Advanced,
+
 	custom_command.Advanced,
 
+	giveaway.Advanced,
+
 	god.Advanced,
 	god.Normal,
 
@@ -48,22 +79,53 @@ var Commands = core.CommandsStatic{
 
 	id.Normal,
 
+	language.Normal,
+	language.Advanced,
+
 	mask.Admin,
 
+	moderation.NormalTimeout,
+	moderation.NormalBan,
+	moderation.NormalUnban,
+	moderation.NormalPurge,
+	moderation.NormalClear,
+	moderation.NormalSlow,
+	moderation.NormalFollowers,
+	moderation.NormalEmoteOnly,
+
 	nick.Normal,
 	nick.Advanced,
 	nick.Admin,
 
+	overlay.Advanced,
+
 	paintball.Normal,
 
+	points.Advanced,
+	points.NormalPoints,
+	points.NormalGive,
+
+	poll.Advanced,
+
 	prefix.Normal,
 	prefix.Advanced,
 	prefix.Admin,
 
+	quote.Normal,
+	quote.Advanced,
+
+	random.NormalRoll,
+	random.NormalChoose,
+	random.NormalCoin,
+	random.NormalEightBall,
+	random.Advanced,
+
 	rps.Normal,
 
 	search.Advanced,
 
+	status.Admin,
+
 	time.Advanced,
 	time.NormalTime,
 	time.NormalTimezone,
@@ -71,14 +133,24 @@ var Commands = core.CommandsStatic{
 	title.Normal,
 	title.Advanced,
 
+	trivia.Normal,
+	trivia.Advanced,
+
 	tts.Advanced,
 	tts.NormalTTS,
 	tts.NormalVoice,
 	tts.NormalSubOnly,
 
+	twitch_channel.Advanced,
+	twitch_channel.Admin,
+
 	urban_dictionary.Normal,
 	urban_dictionary.Advanced,
 
+	warn.Advanced,
+
+	webhook.Admin,
+
 	wikipedia.Normal,
 
 	youtube.Normal,
@@ -176,21 +248,46 @@ func Init() {
 type CommandJSON struct {
 	Names       []string `json:"names"`
 	Description string   `json:"description"`
-	Example     string   `json:"example"`
+	Usage       string   `json:"usage"`
+	Examples    []string `json:"examples"`
 	Parent      int      `json:"parent"`
 	Children    []int    `json:"children"`
 	Category    string   `json:"category"`
 }
 
 type Resp struct {
+	Type       string        `json:"type"`
 	Prefix     string        `json:"prefix"`
 	Categories []string      `json:"categories"`
 	Commands   []CommandJSON `json:"commands"`
 }
 
-func ToJSON(t core.CommandType) Resp {
+// examples returns the command's examples the way they would be typed in chat,
+// including the prefix and the command's name.
+func examples(cmd core.CommandStatic, prefix string) []string {
+	path := []string{}
+	for c := cmd; c != nil; c = c.Parent() {
+		path = append([]string{c.Names()[0]}, path...)
+	}
+	base := prefix + strings.Join(path, " ")
+
+	exs := []string{}
+	for _, ex := range cmd.Examples() {
+		if ex == "" {
+			exs = append(exs, base)
+		} else {
+			exs = append(exs, base+" "+ex)
+		}
+	}
+	return exs
+}
+
+// ToJSON returns the tree of commands of type t, the usage and examples use
+// the specified prefix.
+func ToJSON(t core.CommandType, prefix string) Resp {
 	resp := Resp{
-		Prefix:     "!",
+		Type:       typeNames[t],
+		Prefix:     prefix,
 		Categories: []string{},
 		Commands:   []CommandJSON{},
 	}
@@ -207,7 +304,8 @@ func ToJSON(t core.CommandType) Resp {
 		cmdJSON := CommandJSON{
 			Names:       cmd.Names(),
 			Description: cmd.Description(),
-			Example:     "Example.",
+			Usage:       core.Format(cmd, prefix),
+			Examples:    examples(cmd, prefix),
 			Category:    string(cmd.Category()),
 		}
 
@@ -246,10 +344,44 @@ func ToJSON(t core.CommandType) Resp {
 	for cat, _ := range categories {
 		resp.Categories = append(resp.Categories, string(cat))
 	}
+	sort.Strings(resp.Categories)
 
 	return resp
 }
 
+var typeNames = map[core.CommandType]string{
+	core.Normal:   "normal",
+	core.Advanced: "advanced",
+	core.Admin:    "admin",
+}
+
+// Prefix returns the prefix that is shown in the docs for commands of type t
+// in the place, which is the shortest one. If place is -1 then the default
+// prefixes are used.
+func Prefix(t core.CommandType, place int64) (string, error) {
+	var prefixes []core.Prefix
+	if place == -1 {
+		prefixes = append(core.Prefixes.Others(), core.Prefixes.Admin()...)
+	} else {
+		ps, _, err := core.PlacePrefixes(place)
+		if err != nil {
+			return "", err
+		}
+		prefixes = ps
+	}
+
+	prefix := ""
+	for _, p := range prefixes {
+		if p.Type != t {
+			continue
+		}
+		if prefix == "" || len(p.Prefix) < len(prefix) {
+			prefix = p.Prefix
+		}
+	}
+	return prefix, nil
+}
+
 func init() {
 	core.Gin.GET("/api/v1/commands", func(c *gin.Context) {
 		var t core.CommandType
@@ -258,10 +390,25 @@ func init() {
 			t = core.Normal
 		case "advanced":
 			t = core.Advanced
+		case "admin":
+			t = core.Admin
 		default:
 			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid type"})
 			return
 		}
-		c.JSON(http.StatusOK, ToJSON(t))
+
+		place, err := strconv.ParseInt(c.DefaultQuery("place", "-1"), 10, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid place"})
+			return
+		}
+
+		prefix, err := Prefix(t, place)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "something went wrong"})
+			return
+		}
+
+		c.JSON(http.StatusOK, ToJSON(t, prefix))
 	})
 }
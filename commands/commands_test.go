@@ -0,0 +1,16 @@
+package commands_test
+
+import (
+	"testing"
+
+	"github.com/janitorjeff/jeff-bot/commands"
+	_ "github.com/janitorjeff/jeff-bot/frontends"
+)
+
+// The command tree is checked when the package is initialized, so importing
+// it is enough for a child with the wrong type, category or parent to fail.
+func TestCommands(t *testing.T) {
+	if len(commands.Commands) == 0 {
+		t.Fatal("expected commands to be registered")
+	}
+}
@@ -149,6 +149,9 @@ func (normalTwitch) core(m *core.Message) (string, error) {
 		"channel:manage:broadcast",
 		"channel:moderate",
 		"moderation:read",
+		"moderator:manage:banned_users",
+		"moderator:manage:chat_messages",
+		"moderator:manage:chat_settings",
 	}
 
 	state, err := twitch.NewState()
@@ -0,0 +1,897 @@
+package counter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/janitorjeff/jeff-bot/commands/custom-command"
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+
+	dg "github.com/bwmarrin/discordgo"
+)
+
+func render(m *core.Message, resp string, usrErr error) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return &dg.MessageEmbed{Description: resp}, usrErr, nil
+	default:
+		return resp, usrErr, nil
+	}
+}
+
+// renderHook is the same as render but for hooks, which write the response
+// themselves.
+func renderHook(m *core.Message, resp string, usrErr error) (any, error) {
+	msg, usrErr, _ := render(m, resp, usrErr)
+	return msg, usrErr
+}
+
+// normalPrefix returns one of the place's normal prefixes, used when telling
+// people how to use a counter.
+func normalPrefix(m *core.Message) string {
+	prefixes, _, err := m.Prefixes()
+	if err != nil {
+		return "!"
+	}
+	for _, p := range prefixes {
+		if p.Type == core.Normal {
+			return p.Prefix
+		}
+	}
+	return "!"
+}
+
+func format(name string, value int64) string {
+	return fmt.Sprintf("%s: %d", name, value)
+}
+
+var Advanced = advanced{}
+
+type advanced struct{}
+
+func (advanced) Type() core.CommandType {
+	return core.Advanced
+}
+
+func (advanced) Permitted(*core.Message) bool {
+	return true
+}
+
+func (advanced) Names() []string {
+	return []string{
+		"counter",
+		"counters",
+	}
+}
+
+func (advanced) Description() string {
+	return "Create and manage counters, use !<name> to show one and !<name> +1 to change it."
+}
+
+func (c advanced) UsageArgs() string {
+	return c.Children().Usage()
+}
+
+func (advanced) Category() core.CommandCategory {
+	return core.CommandCategoryOther
+}
+
+func (advanced) Examples() []string {
+	return nil
+}
+
+func (advanced) Parent() core.CommandStatic {
+	return nil
+}
+
+func (advanced) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		AdvancedCreate,
+		AdvancedDelete,
+		AdvancedList,
+		AdvancedShow,
+		AdvancedSet,
+		AdvancedReset,
+		AdvancedPermission,
+		AdvancedURL,
+	}
+}
+
+func (c advanced) Init() error {
+	core.Hooks.Register(c.writeCounter)
+	custom_command.RegisterVariable("counter", variable)
+	core.Overlay.OnConnect(initial)
+	return nil
+}
+
+// initial sends the current values of the place's counters to overlays that
+// just connected.
+func initial(place int64) []core.OverlayEvent {
+	counters, err := List(place)
+	if err != nil {
+		return nil
+	}
+	var events []core.OverlayEvent
+	for _, c := range counters {
+		events = append(events, core.OverlayEvent{Type: core.OverlayCounter, Data: c})
+	}
+	return events
+}
+
+// writeCounter shows or changes a counter when someone types its name using
+// one of the normal prefixes, for example !deaths or !deaths +1.
+func (advanced) writeCounter(m *core.Message) {
+	fields := m.Fields()
+	if len(fields) == 0 || len(fields) > 2 {
+		return
+	}
+
+	prefixes, _, err := m.Prefixes()
+	if err != nil {
+		return
+	}
+
+	var name string
+	for _, p := range prefixes {
+		if p.Type != core.Normal {
+			continue
+		}
+		if rest, ok := p.Match(fields[0]); ok {
+			name = rest
+			break
+		}
+	}
+	if name == "" {
+		return
+	}
+
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return
+	}
+
+	c, usrErr, err := Get(here, name)
+	if usrErr != nil || err != nil {
+		return
+	}
+
+	if len(fields) == 1 {
+		m.Write(renderHook(m, format(c.Name, c.Value), nil))
+		return
+	}
+
+	delta, ok := ParseChange(fields[1])
+	if !ok {
+		return
+	}
+
+	if c.Restricted && !m.Author.Mod() {
+		m.Write(renderHook(m, fmt.Sprint(ErrNotPermitted), ErrNotPermitted))
+		return
+	}
+
+	value, usrErr, err := Change(here, c.Name, delta)
+	if err != nil {
+		return
+	}
+	if usrErr != nil {
+		m.Write(renderHook(m, fmt.Sprint(usrErr), usrErr))
+		return
+	}
+	m.Write(renderHook(m, format(c.Name, value), nil))
+}
+
+// variable implements $(counter <name> [change]) for custom commands, for
+// example "Deaths: $(counter deaths +1)" increments the counter every time the
+// custom command is used. Custom commands can only be created by mods, so
+// there are no permission checks.
+func variable(m *core.Message, place int64, args []string) (string, error) {
+	if len(args) == 0 {
+		return "", ErrCounterNotFound
+	}
+
+	if len(args) > 1 {
+		delta, ok := ParseChange(args[1])
+		if !ok {
+			return "", ErrInvalidValue
+		}
+		value, usrErr, err := Change(place, args[0], delta)
+		if err != nil {
+			return "", err
+		}
+		if usrErr != nil {
+			return "", usrErr
+		}
+		return strconv.FormatInt(value, 10), nil
+	}
+
+	c, usrErr, err := Get(place, args[0])
+	if err != nil {
+		return "", err
+	}
+	if usrErr != nil {
+		return "", usrErr
+	}
+	return strconv.FormatInt(c.Value, 10), nil
+}
+
+func (advanced) Run(m *core.Message) (any, error, error) {
+	return m.Usage(), core.ErrMissingArgs, nil
+}
+
+////////////
+//        //
+// create //
+//        //
+////////////
+
+var AdvancedCreate = advancedCreate{}
+
+type advancedCreate struct{}
+
+func (c advancedCreate) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedCreate) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedCreate) Names() []string {
+	return []string{
+		"create",
+		"new",
+	}
+}
+
+func (advancedCreate) Description() string {
+	return "Create a counter that starts from 0."
+}
+
+func (advancedCreate) UsageArgs() string {
+	return "<name>"
+}
+
+func (c advancedCreate) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedCreate) Examples() []string {
+	return []string{
+		"deaths",
+	}
+}
+
+func (advancedCreate) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedCreate) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedCreate) Init() error {
+	return nil
+}
+
+func (c advancedCreate) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedCreate) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+	author, err := m.Author.Scope()
+	if err != nil {
+		return "", nil, err
+	}
+
+	name := m.Command.Args[0]
+	usrErr, err := Create(here, author, name)
+	if err != nil {
+		return "", nil, err
+	}
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+	name = strings.ToLower(name)
+	return fmt.Sprintf("Created the counter %s, use %s%s +1 to increase it.", name, normalPrefix(m), name), nil, nil
+}
+
+////////////
+//        //
+// delete //
+//        //
+////////////
+
+var AdvancedDelete = advancedDelete{}
+
+type advancedDelete struct{}
+
+func (c advancedDelete) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedDelete) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedDelete) Names() []string {
+	return core.AliasesDelete
+}
+
+func (advancedDelete) Description() string {
+	return "Delete a counter."
+}
+
+func (advancedDelete) UsageArgs() string {
+	return "<name>"
+}
+
+func (c advancedDelete) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedDelete) Examples() []string {
+	return nil
+}
+
+func (advancedDelete) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedDelete) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedDelete) Init() error {
+	return nil
+}
+
+func (c advancedDelete) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedDelete) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	usrErr, err := Delete(here, m.Command.Args[0])
+	if err != nil {
+		return "", nil, err
+	}
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+	return "Deleted the counter.", nil, nil
+}
+
+//////////
+//      //
+// list //
+//      //
+//////////
+
+var AdvancedList = advancedList{}
+
+type advancedList struct{}
+
+func (c advancedList) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedList) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedList) Names() []string {
+	return core.AliasesList
+}
+
+func (advancedList) Description() string {
+	return "List the counters and their values."
+}
+
+func (advancedList) UsageArgs() string {
+	return ""
+}
+
+func (c advancedList) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedList) Examples() []string {
+	return nil
+}
+
+func (advancedList) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedList) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedList) Init() error {
+	return nil
+}
+
+func (c advancedList) Run(m *core.Message) (any, error, error) {
+	lines, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(lines) == 0 {
+		return render(m, "There are no counters.", nil)
+	}
+
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		embed := &dg.MessageEmbed{
+			Title:       "Counters",
+			Description: strings.Join(lines, "\n"),
+		}
+		return embed, nil, nil
+	default:
+		return strings.Join(lines, ", "), nil, nil
+	}
+}
+
+func (advancedList) core(m *core.Message) ([]string, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return nil, err
+	}
+
+	counters, err := List(here)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, c := range counters {
+		lines = append(lines, format(c.Name, c.Value))
+	}
+	return lines, nil
+}
+
+//////////
+//      //
+// show //
+//      //
+//////////
+
+var AdvancedShow = advancedShow{}
+
+type advancedShow struct{}
+
+func (c advancedShow) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedShow) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedShow) Names() []string {
+	return core.AliasesShow
+}
+
+func (advancedShow) Description() string {
+	return "Show a counter's value."
+}
+
+func (advancedShow) UsageArgs() string {
+	return "<name>"
+}
+
+func (c advancedShow) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedShow) Examples() []string {
+	return nil
+}
+
+func (advancedShow) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedShow) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedShow) Init() error {
+	return nil
+}
+
+func (c advancedShow) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedShow) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	c, usrErr, err := Get(here, m.Command.Args[0])
+	if err != nil {
+		return "", nil, err
+	}
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+	return format(c.Name, c.Value), nil, nil
+}
+
+/////////
+//     //
+// set //
+//     //
+/////////
+
+var AdvancedSet = advancedSet{}
+
+type advancedSet struct{}
+
+func (c advancedSet) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedSet) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedSet) Names() []string {
+	return []string{
+		"set",
+	}
+}
+
+func (advancedSet) Description() string {
+	return "Set a counter's value."
+}
+
+func (advancedSet) UsageArgs() string {
+	return "<name> <value>"
+}
+
+func (c advancedSet) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedSet) Examples() []string {
+	return []string{
+		"deaths 10",
+	}
+}
+
+func (advancedSet) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedSet) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedSet) Init() error {
+	return nil
+}
+
+func (c advancedSet) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 2 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedSet) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	value, err := strconv.ParseInt(m.Command.Args[1], 10, 64)
+	if err != nil {
+		return fmt.Sprint(ErrInvalidValue), ErrInvalidValue, nil
+	}
+
+	name := m.Command.Args[0]
+	usrErr, err := Set(here, name, value)
+	if err != nil {
+		return "", nil, err
+	}
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+	return format(strings.ToLower(name), value), nil, nil
+}
+
+///////////
+//       //
+// reset //
+//       //
+///////////
+
+var AdvancedReset = advancedReset{}
+
+type advancedReset struct{}
+
+func (c advancedReset) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedReset) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedReset) Names() []string {
+	return []string{
+		"reset",
+	}
+}
+
+func (advancedReset) Description() string {
+	return "Set a counter back to 0."
+}
+
+func (advancedReset) UsageArgs() string {
+	return "<name>"
+}
+
+func (c advancedReset) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedReset) Examples() []string {
+	return nil
+}
+
+func (advancedReset) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedReset) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedReset) Init() error {
+	return nil
+}
+
+func (c advancedReset) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedReset) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	name := m.Command.Args[0]
+	usrErr, err := Set(here, name, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+	return format(strings.ToLower(name), 0), nil, nil
+}
+
+////////////////
+//            //
+// permission //
+//            //
+////////////////
+
+var AdvancedPermission = advancedPermission{}
+
+type advancedPermission struct{}
+
+func (c advancedPermission) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedPermission) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedPermission) Names() []string {
+	return []string{
+		"permission",
+		"perm",
+	}
+}
+
+func (advancedPermission) Description() string {
+	return "Set who can change a counter from chat."
+}
+
+func (advancedPermission) UsageArgs() string {
+	return "<name> (mods | everyone)"
+}
+
+func (c advancedPermission) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedPermission) Examples() []string {
+	return []string{
+		"deaths everyone",
+	}
+}
+
+func (advancedPermission) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedPermission) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedPermission) Init() error {
+	return nil
+}
+
+func (c advancedPermission) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 2 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedPermission) core(m *core.Message) (string, error, error) {
+	var restricted bool
+	switch m.Command.Args[1] {
+	case "mods", "mod", "moderators":
+		restricted = true
+	case "everyone", "all":
+		restricted = false
+	default:
+		return fmt.Sprint(ErrInvalidPermission), ErrInvalidPermission, nil
+	}
+
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	usrErr, err := Restrict(here, m.Command.Args[0], restricted)
+	if err != nil {
+		return "", nil, err
+	}
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+
+	if restricted {
+		return "Only moderators can now change the counter.", nil, nil
+	}
+	return "Everyone can now change the counter.", nil, nil
+}
+
+/////////
+//     //
+// url //
+//     //
+/////////
+
+var AdvancedURL = advancedURL{}
+
+type advancedURL struct{}
+
+func (c advancedURL) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedURL) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedURL) Names() []string {
+	return []string{
+		"url",
+		"overlay",
+	}
+}
+
+func (advancedURL) Description() string {
+	return "Get the address from which overlays can get a counter's value as JSON."
+}
+
+func (advancedURL) UsageArgs() string {
+	return "<name>"
+}
+
+func (c advancedURL) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedURL) Examples() []string {
+	return nil
+}
+
+func (advancedURL) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedURL) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedURL) Init() error {
+	return nil
+}
+
+func (c advancedURL) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedURL) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	c, usrErr, err := Get(here, m.Command.Args[0])
+	if err != nil {
+		return "", nil, err
+	}
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+	return URL(here, c.Name), nil, nil
+}
@@ -0,0 +1,220 @@
+package counter
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	ErrCounterExists   = errors.New("A counter with that name already exists.")
+	ErrCounterNotFound = errors.New("Couldn't find a counter with that name.")
+	ErrInvalidName     = errors.New("Counter names can only contain letters, numbers and underscores and can be at most 32 characters long.")
+	ErrBuiltinCommand  = errors.New("That name is already used by a built-in command.")
+	ErrInvalidValue    = errors.New("Expected a whole number.")
+	ErrNotPermitted    = errors.New("Only moderators can change this counter.")
+
+	ErrInvalidPermission = errors.New("Expected either mods or everyone.")
+)
+
+var reName = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
+// Counter is a named number that belongs to a place, for example the number of
+// times the streamer has died.
+type Counter struct {
+	Name  string `json:"name"`
+	Value int64  `json:"value"`
+
+	// If true only mods can change the value from chat.
+	Restricted bool `json:"-"`
+}
+
+// ParseName returns the counter name in the form in which it is saved, names
+// are case insensitive.
+func ParseName(s string) (string, error) {
+	name := strings.ToLower(s)
+	if !reName.MatchString(name) {
+		return "", ErrInvalidName
+	}
+	return name, nil
+}
+
+// ParseChange parses an increment or decrement, for example +, -, +5 or -2. A
+// lone sign means 1. Returns false if s isn't a change.
+func ParseChange(s string) (int64, bool) {
+	if s == "+" || s == "++" {
+		return 1, true
+	}
+	if s == "-" || s == "--" {
+		return -1, true
+	}
+	if !strings.HasPrefix(s, "+") && !strings.HasPrefix(s, "-") {
+		return 0, false
+	}
+	delta, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return delta, true
+}
+
+// isCommand checks if a normal built-in command uses the name, since counters
+// are also triggered using the normal prefixes they would collide.
+func isCommand(name string) bool {
+	for _, c := range *core.Commands {
+		if c.Type() != core.Normal {
+			continue
+		}
+		for _, n := range c.Names() {
+			if n == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Create creates a new counter in the place that starts from 0. Only mods can
+// change it by default.
+func Create(place, creator int64, name string) (error, error) {
+	name, usrErr := ParseName(name)
+	if usrErr != nil {
+		return usrErr, nil
+	}
+	if isCommand(name) {
+		return ErrBuiltinCommand, nil
+	}
+
+	exists, err := dbExists(place, name)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return ErrCounterExists, nil
+	}
+
+	return nil, dbAdd(place, creator, name)
+}
+
+// Delete deletes the place's counter.
+func Delete(place int64, name string) (error, error) {
+	c, usrErr, err := Get(place, name)
+	if usrErr != nil || err != nil {
+		return usrErr, err
+	}
+	return nil, dbDelete(place, c.Name)
+}
+
+// Get returns the place's counter, returns ErrCounterNotFound if it doesn't
+// exist.
+func Get(place int64, name string) (Counter, error, error) {
+	c, err := dbGet(place, strings.ToLower(name))
+	if err == sql.ErrNoRows {
+		return Counter{}, ErrCounterNotFound, nil
+	}
+	if err != nil {
+		return Counter{}, nil, err
+	}
+	return c, nil, nil
+}
+
+// List returns all of the place's counters sorted by name.
+func List(place int64) ([]Counter, error) {
+	return dbList(place)
+}
+
+// Set sets the counter's value.
+func Set(place int64, name string, value int64) (error, error) {
+	c, usrErr, err := Get(place, name)
+	if usrErr != nil || err != nil {
+		return usrErr, err
+	}
+	if err := dbSet(place, c.Name, value); err != nil {
+		return nil, err
+	}
+	c.Value = value
+	core.Overlay.Publish(place, core.OverlayCounter, c)
+	return nil, nil
+}
+
+// Change adds delta to the counter's value, which can be negative, and returns
+// the new value.
+func Change(place int64, name string, delta int64) (int64, error, error) {
+	name = strings.ToLower(name)
+	value, err := dbAddValue(place, name, delta)
+	if err == sql.ErrNoRows {
+		return 0, ErrCounterNotFound, nil
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	core.Overlay.Publish(place, core.OverlayCounter, Counter{Name: name, Value: value})
+	return value, nil, nil
+}
+
+// Restrict sets whether only mods can change the counter from chat.
+func Restrict(place int64, name string, restricted bool) (error, error) {
+	c, usrErr, err := Get(place, name)
+	if usrErr != nil || err != nil {
+		return usrErr, err
+	}
+	return nil, dbSetRestricted(place, c.Name, restricted)
+}
+
+// URL returns the address from which the counter's value can be fetched as
+// JSON, meant to be used by stream overlays.
+func URL(place int64, name string) string {
+	return fmt.Sprintf("https://%s/api/v1/counters/%d/%s", core.VirtualHost, place, strings.ToLower(name))
+}
+
+func init() {
+	// overlays are usually local files or hosted elsewhere
+	cors := func(c *gin.Context) {
+		c.Header("Access-Control-Allow-Origin", "*")
+	}
+
+	core.Gin.GET("/api/v1/counters/:place", cors, func(c *gin.Context) {
+		place, err := strconv.ParseInt(c.Param("place"), 10, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid place"})
+			return
+		}
+
+		counters, err := List(place)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "something went wrong"})
+			return
+		}
+		if counters == nil {
+			counters = []Counter{}
+		}
+		c.JSON(http.StatusOK, counters)
+	})
+
+	core.Gin.GET("/api/v1/counters/:place/:name", cors, func(c *gin.Context) {
+		place, err := strconv.ParseInt(c.Param("place"), 10, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid place"})
+			return
+		}
+
+		counter, usrErr, err := Get(place, c.Param("name"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "something went wrong"})
+			return
+		}
+		if usrErr != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "counter not found"})
+			return
+		}
+		c.JSON(http.StatusOK, counter)
+	})
+}
@@ -0,0 +1,202 @@
+package counter
+
+import (
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/rs/zerolog/log"
+)
+
+func dbAdd(place, creator int64, name string) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		INSERT INTO cmd_counter_counters(place, name, value, restricted, creator, created)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, place, name, 0, true, creator, time.Now().UTC().Unix())
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int64("creator", creator).
+		Str("name", name).
+		Msg("added counter")
+
+	return err
+}
+
+func dbDelete(place int64, name string) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		DELETE FROM cmd_counter_counters
+		WHERE place = $1 and name = $2
+	`, place, name)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Str("name", name).
+		Msg("deleted counter")
+
+	return err
+}
+
+func dbExists(place int64, name string) (bool, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	var exists bool
+
+	row := db.DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM cmd_counter_counters
+			WHERE place = $1 and name = $2
+			LIMIT 1
+		)`, place, name)
+
+	err := row.Scan(&exists)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Str("name", name).
+		Bool("exists", exists).
+		Msg("checked db to see if counter exists")
+
+	return exists, err
+}
+
+// dbGet returns sql.ErrNoRows if the counter doesn't exist.
+func dbGet(place int64, name string) (Counter, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	var c Counter
+	row := db.DB.QueryRow(`
+		SELECT name, value, restricted
+		FROM cmd_counter_counters
+		WHERE place = $1 and name = $2
+	`, place, name)
+
+	err := row.Scan(&c.Name, &c.Value, &c.Restricted)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Interface("counter", c).
+		Msg("got counter")
+
+	return c, err
+}
+
+func dbList(place int64) ([]Counter, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	rows, err := db.DB.Query(`
+		SELECT name, value, restricted
+		FROM cmd_counter_counters
+		WHERE place = $1
+		ORDER BY name
+	`, place)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counters []Counter
+	for rows.Next() {
+		var c Counter
+		if err := rows.Scan(&c.Name, &c.Value, &c.Restricted); err != nil {
+			return nil, err
+		}
+		counters = append(counters, c)
+	}
+
+	err = rows.Err()
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int("count", len(counters)).
+		Msg("got counters")
+
+	return counters, err
+}
+
+func dbSet(place int64, name string, value int64) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		UPDATE cmd_counter_counters
+		SET value = $1
+		WHERE place = $2 and name = $3
+	`, value, place, name)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Str("name", name).
+		Int64("value", value).
+		Msg("set counter")
+
+	return err
+}
+
+// dbAddValue adds delta to the counter's value and returns the new value,
+// returns sql.ErrNoRows if the counter doesn't exist.
+func dbAddValue(place int64, name string, delta int64) (int64, error) {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	var value int64
+	err := db.DB.QueryRow(`
+		UPDATE cmd_counter_counters
+		SET value = value + $1
+		WHERE place = $2 and name = $3
+		RETURNING value
+	`, delta, place, name).Scan(&value)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Str("name", name).
+		Int64("delta", delta).
+		Int64("value", value).
+		Msg("changed counter")
+
+	return value, err
+}
+
+func dbSetRestricted(place int64, name string, restricted bool) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		UPDATE cmd_counter_counters
+		SET restricted = $1
+		WHERE place = $2 and name = $3
+	`, restricted, place, name)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Str("name", name).
+		Bool("restricted", restricted).
+		Msg("set counter permissions")
+
+	return err
+}
@@ -81,7 +81,7 @@ func (advanced) writeCustomCommand(m *core.Message) {
 		return
 	}
 
-	m.Write(resp, nil)
+	m.Write(Expand(m, here, resp), nil)
 }
 
 func (advanced) Run(m *core.Message) (any, error, error) {
@@ -160,7 +160,7 @@ func (c advancedAdd) discord(m *core.Message) (*dg.MessageEmbed, error, error) {
 	trigger = discord.PlaceInBackticks(trigger)
 
 	embed := &dg.MessageEmbed{
-		Description: c.err(usrErr, trigger),
+		Description: c.err(usrErr, m, trigger),
 	}
 
 	return embed, usrErr, nil
@@ -174,19 +174,21 @@ func (c advancedAdd) text(m *core.Message) (string, error, error) {
 
 	trigger = fmt.Sprintf("'%s'", trigger)
 
-	return c.err(usrErr, trigger), usrErr, nil
+	return c.err(usrErr, m, trigger), usrErr, nil
 }
 
-func (advancedAdd) err(usrErr error, trigger string) string {
+func (advancedAdd) err(usrErr error, m *core.Message, trigger string) string {
 	switch usrErr {
 	case nil:
-		return fmt.Sprintf("Custom command %s has been added.", trigger)
+		return m.T("custom_command.add", trigger)
 	case ErrTriggerExists:
-		return fmt.Sprintf("Custom command %s already exists.", trigger)
+		return m.T("custom_command.add.exists", trigger)
 	case ErrBuiltinCommand:
-		return fmt.Sprintf("Command %s already exists as a built-in command.", trigger)
+		return m.T("custom_command.add.builtin", trigger)
+	case ErrAliasExists:
+		return m.T("custom_command.add.alias", trigger)
 	default:
-		return "Something went wrong..."
+		return m.T("core.something_went_wrong")
 	}
 }
 
@@ -280,7 +282,7 @@ func (c advancedEdit) discord(m *core.Message) (*dg.MessageEmbed, error, error)
 	trigger = discord.PlaceInBackticks(trigger)
 
 	embed := &dg.MessageEmbed{
-		Description: c.err(usrErr, trigger),
+		Description: c.err(usrErr, m, trigger),
 	}
 
 	return embed, usrErr, nil
@@ -294,17 +296,17 @@ func (c advancedEdit) text(m *core.Message) (string, error, error) {
 
 	trigger = fmt.Sprintf("'%s'", trigger)
 
-	return c.err(usrErr, trigger), usrErr, nil
+	return c.err(usrErr, m, trigger), usrErr, nil
 }
 
-func (advancedEdit) err(usrErr error, trigger string) string {
+func (advancedEdit) err(usrErr error, m *core.Message, trigger string) string {
 	switch usrErr {
 	case nil:
-		return fmt.Sprintf("Custom command %s has been modified.", trigger)
+		return m.T("custom_command.edit", trigger)
 	case ErrTriggerNotFound:
-		return fmt.Sprintf("Custom command %s doesn't exist.", trigger)
+		return m.T("custom_command.not_found", trigger)
 	default:
-		return "Something went wrong..."
+		return m.T("core.something_went_wrong")
 	}
 }
 
@@ -398,7 +400,7 @@ func (c advancedDelete) discord(m *core.Message) (*dg.MessageEmbed, error, error
 	trigger = discord.PlaceInBackticks(trigger)
 
 	embed := &dg.MessageEmbed{
-		Description: c.err(usrErr, trigger),
+		Description: c.err(usrErr, m, trigger),
 	}
 
 	return embed, usrErr, nil
@@ -412,17 +414,17 @@ func (c advancedDelete) text(m *core.Message) (string, error, error) {
 
 	trigger = fmt.Sprintf("'%s'", trigger)
 
-	return c.err(usrErr, trigger), usrErr, nil
+	return c.err(usrErr, m, trigger), usrErr, nil
 }
 
-func (advancedDelete) err(usrErr error, trigger string) string {
+func (advancedDelete) err(usrErr error, m *core.Message, trigger string) string {
 	switch usrErr {
 	case nil:
-		return fmt.Sprintf("Custom command %s has been deleted.", trigger)
+		return m.T("custom_command.delete", trigger)
 	case ErrTriggerNotFound:
-		return fmt.Sprintf("Custom command %s doesn't exist.", trigger)
+		return m.T("custom_command.not_found", trigger)
 	default:
-		return "Something went wrong..."
+		return m.T("core.something_went_wrong")
 	}
 }
 
@@ -511,7 +513,7 @@ func (c advancedList) discord(m *core.Message) (*dg.MessageEmbed, error, error)
 	var reply string
 
 	if len(triggers) == 0 {
-		reply = "There are no custom commands."
+		reply = m.T("custom_command.list.empty")
 	} else {
 		for i := range triggers {
 			triggers[i] = "- " + discord.PlaceInBackticks(triggers[i])
@@ -533,7 +535,7 @@ func (c advancedList) text(m *core.Message) (string, error, error) {
 	}
 
 	if len(triggers) == 0 {
-		return "There are no custom commands.", nil, nil
+		return m.T("custom_command.list.empty"), nil, nil
 	}
 	return strings.Join(triggers, ", "), nil, nil
 }
@@ -650,27 +652,27 @@ func (c advancedHistory) discord(m *core.Message) (*dg.MessageEmbed, error, erro
 
 		if i == 0 {
 			// creation
-			action = append(action, "created")
+			action = append(action, m.T("custom_command.history.created"))
 			response = append(response, hist.response)
 			when = append(when, formatTime(hist.created))
 		} else if history[i-1].deleted == hist.created {
 			// modification
-			action = append(action, "edited")
+			action = append(action, m.T("custom_command.history.edited"))
 			response = append(response, hist.response)
 			when = append(when, formatTime(hist.created))
 		} else {
 			// deletion
-			action = append(action, "deleted")
+			action = append(action, m.T("custom_command.history.deleted"))
 			response = append(response, "")
 			when = append(when, formatTime(history[i-1].deleted))
 
-			action = append(action, "created")
+			action = append(action, m.T("custom_command.history.created"))
 			response = append(response, hist.response)
 			when = append(when, formatTime(hist.created))
 		}
 
 		if i == len(history)-1 && hist.deleted != 0 {
-			action = append(action, "deleted")
+			action = append(action, m.T("custom_command.history.deleted"))
 			response = append(response, "")
 			when = append(when, formatTime(hist.deleted))
 		}
@@ -680,17 +682,17 @@ func (c advancedHistory) discord(m *core.Message) (*dg.MessageEmbed, error, erro
 		Title: discord.PlaceInBackticks(trigger),
 		Fields: []*dg.MessageEmbedField{
 			{
-				Name:   "action",
+				Name:   m.T("custom_command.history.action"),
 				Value:  strings.Join(action, "\n"),
 				Inline: true,
 			},
 			{
-				Name:   "response",
+				Name:   m.T("custom_command.history.response"),
 				Value:  strings.Join(response, "\n"),
 				Inline: true,
 			},
 			{
-				Name:   "when",
+				Name:   m.T("custom_command.history.when"),
 				Value:  strings.Join(when, "\n"),
 				Inline: true,
 			},
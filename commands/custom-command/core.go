@@ -1,16 +1,24 @@
 package custom_command
 
 import (
-	"errors"
+	"embed"
+	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/rs/zerolog/log"
 )
 
+//go:embed locales
+var locales embed.FS
+
 var (
-	ErrTriggerExists   = errors.New("trigger already exists")
-	ErrBuiltinCommand  = errors.New("trigger collides with a built-in command")
-	ErrTriggerNotFound = errors.New("trigger was not found")
+	ErrTriggerExists   = core.NewError("custom_command.err.trigger_exists")
+	ErrBuiltinCommand  = core.NewError("custom_command.err.builtin_command")
+	ErrTriggerNotFound = core.NewError("custom_command.err.trigger_not_found")
+	ErrAliasExists     = core.NewError("custom_command.err.alias_exists")
 )
 
 // Check if a string corresponds to a command name. Doesn't check sub-commands.
@@ -25,7 +33,9 @@ func isCommand(t core.CommandType, s string) bool {
 	return false
 }
 
-func isBuiltin(place int64, trigger string) (bool, error) {
+// IsBuiltin returns true if the trigger would collide with a built-in command
+// when used in the specified place.
+func IsBuiltin(place int64, trigger string) (bool, error) {
 	prefixes, _, err := core.PlacePrefixes(place)
 	if err != nil {
 		return false, err
@@ -34,11 +44,12 @@ func isBuiltin(place int64, trigger string) (bool, error) {
 	for _, p := range prefixes {
 		// only check if there is a collision if the trigger begins with a
 		// prefix used by the builtin commands
-		if !strings.HasPrefix(trigger, p.Prefix) {
+		rest, ok := p.Match(trigger)
+		if !ok {
 			continue
 		}
 
-		if isCommand(p.Type, strings.TrimPrefix(trigger, p.Prefix)) {
+		if isCommand(p.Type, rest) {
 			return true, nil
 		}
 	}
@@ -55,7 +66,7 @@ func Add(place, creator int64, trigger, response string) (error, error) {
 		return ErrTriggerExists, nil
 	}
 
-	builtin, err := isBuiltin(place, trigger)
+	builtin, err := IsBuiltin(place, trigger)
 	if err != nil {
 		return nil, err
 	}
@@ -63,6 +74,15 @@ func Add(place, creator int64, trigger, response string) (error, error) {
 		return ErrBuiltinCommand, nil
 	}
 
+	// both the alias and the custom command would be triggered
+	_, alias, err := core.PlaceAlias(place, trigger)
+	if err != nil {
+		return nil, err
+	}
+	if alias {
+		return ErrAliasExists, nil
+	}
+
 	return nil, dbAdd(place, creator, trigger, response)
 }
 
@@ -88,6 +108,12 @@ func Delete(place, deleter int64, trigger string) (error, error) {
 	return nil, dbDelete(place, deleter, trigger)
 }
 
+// Exists returns true if an active custom command with the trigger exists in
+// the specified place.
+func Exists(place int64, trigger string) (bool, error) {
+	return dbTriggerExists(place, trigger)
+}
+
 func List(place int64) ([]string, error) {
 	return dbList(place)
 }
@@ -101,3 +127,54 @@ func History(place int64, trigger string) ([]customCommand, error) {
 	// used to view the history of a deleted trigger
 	return dbHistory(place, trigger)
 }
+
+// Variable returns the text that $(name args...) is replaced with in a custom
+// command's response.
+type Variable func(m *core.Message, place int64, args []string) (string, error)
+
+var (
+	variablesLock sync.RWMutex
+	variables     = map[string]Variable{}
+
+	reVariable = regexp.MustCompile(`\$\(([^()]*)\)`)
+)
+
+// RegisterVariable makes $(name args...) usable in custom command responses.
+// Meant to be called by other commands' Init functions.
+func RegisterVariable(name string, v Variable) {
+	variablesLock.Lock()
+	defer variablesLock.Unlock()
+	variables[name] = v
+}
+
+// Expand replaces the variables in the response with their values. Unknown
+// variables are left as they are.
+func Expand(m *core.Message, place int64, response string) string {
+	variablesLock.RLock()
+	defer variablesLock.RUnlock()
+
+	return reVariable.ReplaceAllStringFunc(response, func(s string) string {
+		fields := strings.Fields(s[2 : len(s)-1])
+		if len(fields) == 0 {
+			return s
+		}
+
+		v, ok := variables[fields[0]]
+		if !ok {
+			return s
+		}
+
+		value, err := v(m, place, fields[1:])
+		if err != nil {
+			log.Debug().Err(err).Str("variable", s).Msg("failed to expand variable")
+			return "?"
+		}
+		return value
+	})
+}
+
+func init() {
+	if err := core.Catalogs.Load(locales, "locales"); err != nil {
+		panic(err)
+	}
+}
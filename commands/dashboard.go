@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"embed"
+	"net/http"
+
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The dashboard shows the documentation of all the commands and lets mods
+// manage their place through the REST API.
+//
+//go:embed dashboard
+var dashboard embed.FS
+
+func init() {
+	// served directly since http.FileServer redirects index.html to the
+	// directory
+	index := func(c *gin.Context) {
+		page, err := dashboard.ReadFile("dashboard/index.html")
+		if err != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+	}
+
+	core.Gin.GET("/dashboard", index)
+	core.Gin.GET("/dashboard/:file", func(c *gin.Context) {
+		if c.Param("file") == "index.html" {
+			index(c)
+			return
+		}
+		c.FileFromFS("dashboard/"+c.Param("file"), http.FS(dashboard))
+	})
+}
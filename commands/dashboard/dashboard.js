@@ -0,0 +1,275 @@
+// The token is kept in local storage, after logging in with twitch it is
+// passed in the address' fragment so that it never reaches the server's logs.
+const hash = new URLSearchParams(location.hash.slice(1));
+if (hash.get("token")) {
+	localStorage.setItem("token", hash.get("token"));
+	history.replaceState(null, "", location.pathname);
+}
+
+let me = null;
+let triggers = [];
+
+function el(tag, text, className) {
+	const e = document.createElement(tag);
+	if (text) {
+		e.textContent = text;
+	}
+	if (className) {
+		e.className = className;
+	}
+	return e;
+}
+
+// Calls the REST API, throws the error the API returned if the request failed.
+async function api(method, path, body) {
+	const opts = {
+		method: method,
+		headers: {"Authorization": "Bearer " + localStorage.getItem("token")},
+	};
+	if (body !== undefined) {
+		opts.headers["Content-Type"] = "application/json";
+		opts.body = JSON.stringify(body);
+	}
+	const resp = await fetch("/api/v1" + path, opts);
+	if (resp.status === 204) {
+		return null;
+	}
+	const data = await resp.json();
+	if (!resp.ok) {
+		throw new Error(data.error);
+	}
+	return data;
+}
+
+function showError(err) {
+	document.getElementById("error").textContent = err ? err.message : "";
+}
+
+//////////
+//      //
+// tabs //
+//      //
+//////////
+
+for (const button of document.querySelectorAll("nav button")) {
+	button.onclick = () => {
+		for (const b of document.querySelectorAll("nav button")) {
+			b.classList.toggle("active", b === button);
+			document.getElementById(b.dataset.tab).classList.toggle("hidden", b !== button);
+		}
+	};
+}
+
+//////////
+//      //
+// docs //
+//      //
+//////////
+
+function renderCommand(cmd, all) {
+	const div = el("div", null, "command");
+	div.append(el("code", cmd.usage), el("p", cmd.description));
+
+	if (cmd.names.length > 1) {
+		div.append(el("p", "Aliases: " + cmd.names.slice(1).join(", "), "muted"));
+	}
+
+	if (cmd.examples.length > 0) {
+		const ul = el("ul");
+		for (const ex of cmd.examples) {
+			const li = el("li");
+			li.append(el("code", ex));
+			ul.append(li);
+		}
+		div.append(el("p", "Examples:", "muted"), ul);
+	}
+
+	for (const i of cmd.children) {
+		div.append(renderCommand(all[i], all));
+	}
+	return div;
+}
+
+async function loadDocs() {
+	const type = document.getElementById("type").value;
+	let url = "/api/v1/commands?type=" + type;
+	if (me) {
+		url += "&place=" + me.place;
+	}
+	const resp = await fetch(url);
+	const docs = await resp.json();
+
+	const root = document.getElementById("commands");
+	root.replaceChildren();
+
+	for (const category of docs.categories) {
+		const section = el("div", null, "category");
+		section.append(el("h2", category));
+		for (const cmd of docs.commands) {
+			if (cmd.parent === -1 && cmd.category === category) {
+				section.append(renderCommand(cmd, docs.commands));
+			}
+		}
+		root.append(section);
+	}
+	filterDocs();
+}
+
+function filterDocs() {
+	const query = document.getElementById("search").value.toLowerCase();
+	for (const cmd of document.querySelectorAll("#commands > .category > .command")) {
+		cmd.classList.toggle("hidden", !cmd.textContent.toLowerCase().includes(query));
+	}
+}
+
+document.getElementById("type").onchange = loadDocs;
+document.getElementById("search").oninput = filterDocs;
+
+///////////
+//       //
+// place //
+//       //
+///////////
+
+function button(text, onclick) {
+	const b = el("button", text);
+	b.onclick = async () => {
+		try {
+			await onclick();
+			showError(null);
+		} catch (err) {
+			showError(err);
+		}
+	};
+	return b;
+}
+
+async function loadCustomCommands() {
+	triggers = await api("GET", "/custom-commands");
+	const table = document.getElementById("custom-commands");
+	table.replaceChildren();
+
+	for (const trigger of triggers) {
+		const path = "/custom-commands/" + encodeURIComponent(trigger);
+		const row = el("tr");
+		const actions = el("td");
+		actions.append(
+			button("Edit", async () => {
+				const cmd = await api("GET", path);
+				document.getElementById("command-trigger").value = cmd.trigger;
+				document.getElementById("command-response").value = cmd.response;
+			}),
+			button("Delete", async () => {
+				await api("DELETE", path);
+				await loadCustomCommands();
+			}),
+		);
+		row.append(el("td", trigger), actions);
+		table.append(row);
+	}
+}
+
+async function loadPrefixes() {
+	const prefixes = await api("GET", "/prefixes");
+	const table = document.getElementById("prefixes");
+	table.replaceChildren();
+
+	for (const p of prefixes) {
+		const row = el("tr");
+		const actions = el("td");
+		actions.append(button("Delete", async () => {
+			const query = "?prefix=" + encodeURIComponent(p.prefix) + "&type=" + p.type;
+			await api("DELETE", "/prefixes" + query);
+			await loadPrefixes();
+			await loadDocs();
+		}));
+		row.append(el("td", p.prefix), el("td", p.type), actions);
+		table.append(row);
+	}
+}
+
+document.getElementById("command-form").onsubmit = async (e) => {
+	e.preventDefault();
+	const trigger = document.getElementById("command-trigger").value;
+	const response = document.getElementById("command-response").value;
+	try {
+		if (triggers.includes(trigger)) {
+			await api("PUT", "/custom-commands/" + encodeURIComponent(trigger), {response: response});
+		} else {
+			await api("POST", "/custom-commands", {trigger: trigger, response: response});
+		}
+		e.target.reset();
+		showError(null);
+		await loadCustomCommands();
+	} catch (err) {
+		showError(err);
+	}
+};
+
+document.getElementById("prefix-form").onsubmit = async (e) => {
+	e.preventDefault();
+	const prefix = document.getElementById("prefix").value;
+	const type = document.getElementById("prefix-type").value;
+	try {
+		await api("POST", "/prefixes", {prefix: prefix, type: type});
+		e.target.reset();
+		showError(null);
+		await loadPrefixes();
+		await loadDocs();
+	} catch (err) {
+		showError(err);
+	}
+};
+
+document.getElementById("login-form").onsubmit = async (e) => {
+	e.preventDefault();
+	localStorage.setItem("token", document.getElementById("token").value);
+	await login();
+};
+
+document.getElementById("logout").onclick = () => {
+	localStorage.removeItem("token");
+	me = null;
+	document.getElementById("login").classList.remove("hidden");
+	document.getElementById("settings").classList.add("hidden");
+	loadDocs();
+};
+
+async function login() {
+	if (!localStorage.getItem("token")) {
+		return;
+	}
+	try {
+		me = await api("GET", "/me");
+	} catch (err) {
+		localStorage.removeItem("token");
+		return;
+	}
+
+	let role = "member";
+	if (me.admin) {
+		role = "admin";
+	} else if (me.mod) {
+		role = "moderator";
+	}
+	document.getElementById("whoami").textContent = "Logged in to place " + me.place + " as a " + role + ".";
+	document.getElementById("login").classList.add("hidden");
+	document.getElementById("settings").classList.remove("hidden");
+	document.getElementById("mods").classList.toggle("hidden", !me.mod);
+
+	if (me.mod) {
+		try {
+			await loadCustomCommands();
+			await loadPrefixes();
+		} catch (err) {
+			showError(err);
+		}
+	}
+	await loadDocs();
+}
+
+login().then(() => {
+	if (!me) {
+		loadDocs();
+	}
+});
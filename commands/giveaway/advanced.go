@@ -0,0 +1,620 @@
+package giveaway
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/janitorjeff/jeff-bot/commands/nick"
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+
+	dg "github.com/bwmarrin/discordgo"
+)
+
+func render(m *core.Message, resp string, usrErr error) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return &dg.MessageEmbed{Description: resp}, usrErr, nil
+	default:
+		return resp, usrErr, nil
+	}
+}
+
+// names returns the people's names joined by commas.
+func names(people []int64, place int64) ([]string, error) {
+	var ns []string
+	for _, p := range people {
+		name, err := nick.Name(p, place)
+		if err != nil {
+			return nil, err
+		}
+		ns = append(ns, name)
+	}
+	return ns, nil
+}
+
+var Advanced = advanced{}
+
+type advanced struct{}
+
+func (advanced) Type() core.CommandType {
+	return core.Advanced
+}
+
+func (advanced) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advanced) Names() []string {
+	return []string{
+		"giveaway",
+		"raffle",
+	}
+}
+
+func (advanced) Description() string {
+	return "Run giveaways that chat can enter by typing a keyword."
+}
+
+func (c advanced) UsageArgs() string {
+	return c.Children().Usage()
+}
+
+func (advanced) Category() core.CommandCategory {
+	return core.CommandCategoryOther
+}
+
+func (advanced) Examples() []string {
+	return nil
+}
+
+func (advanced) Parent() core.CommandStatic {
+	return nil
+}
+
+func (advanced) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		AdvancedStart,
+		AdvancedEnd,
+		AdvancedDraw,
+		AdvancedReroll,
+		AdvancedWinners,
+		AdvancedShow,
+	}
+}
+
+func (advanced) Init() error {
+	return resume()
+}
+
+func (advanced) Run(m *core.Message) (any, error, error) {
+	return m.Usage(), core.ErrMissingArgs, nil
+}
+
+///////////
+//       //
+// start //
+//       //
+///////////
+
+var AdvancedStart = advancedStart{}
+
+type advancedStart struct{}
+
+func (c advancedStart) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedStart) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedStart) Names() []string {
+	return []string{
+		"start",
+		"new",
+		"create",
+	}
+}
+
+func (advancedStart) Description() string {
+	return "Start a giveaway, chat enters by typing the keyword."
+}
+
+func (advancedStart) UsageArgs() string {
+	return "<keyword> [duration] [everyone | subs | mods | followers [minimum follow age]]"
+}
+
+func (c advancedStart) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedStart) Examples() []string {
+	return []string{
+		"!enter",
+		"!enter 5m subs",
+		"!enter 15m followers 7d",
+	}
+}
+
+func (advancedStart) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedStart) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedStart) Init() error {
+	return nil
+}
+
+func (c advancedStart) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedStart) core(m *core.Message) (string, error, error) {
+	keyword, dur, eligible, followAge, usrErr := ParseArgs(m.Command.Args)
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+
+	g, usrErr, err := Start(m, keyword, dur, eligible, followAge)
+	if err != nil {
+		return "", nil, err
+	}
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+	return g.Text(), nil, nil
+}
+
+/////////
+//     //
+// end //
+//     //
+/////////
+
+var AdvancedEnd = advancedEnd{}
+
+type advancedEnd struct{}
+
+func (c advancedEnd) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedEnd) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedEnd) Names() []string {
+	return []string{
+		"end",
+		"close",
+		"stop",
+	}
+}
+
+func (advancedEnd) Description() string {
+	return "Stop accepting entries early."
+}
+
+func (advancedEnd) UsageArgs() string {
+	return ""
+}
+
+func (c advancedEnd) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedEnd) Examples() []string {
+	return nil
+}
+
+func (advancedEnd) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedEnd) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedEnd) Init() error {
+	return nil
+}
+
+func (advancedEnd) Run(m *core.Message) (any, error, error) {
+	here, err := m.Here.ScopeExact()
+	if err != nil {
+		return nil, nil, err
+	}
+	if usrErr := End(here); usrErr != nil {
+		return render(m, fmt.Sprint(usrErr), usrErr)
+	}
+	// the closing is announced separately
+	return nil, nil, core.ErrSilence
+}
+
+//////////
+//      //
+// draw //
+//      //
+//////////
+
+var AdvancedDraw = advancedDraw{}
+
+type advancedDraw struct{}
+
+func (c advancedDraw) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedDraw) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedDraw) Names() []string {
+	return []string{
+		"draw",
+		"pick",
+		"roll",
+	}
+}
+
+func (advancedDraw) Description() string {
+	return "Draw winners from the latest giveaway, closes it if it's still open."
+}
+
+func (advancedDraw) UsageArgs() string {
+	return "[number of winners]"
+}
+
+func (c advancedDraw) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedDraw) Examples() []string {
+	return nil
+}
+
+func (advancedDraw) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedDraw) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedDraw) Init() error {
+	return nil
+}
+
+func (c advancedDraw) Run(m *core.Message) (any, error, error) {
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedDraw) core(m *core.Message) (string, error, error) {
+	count := 1
+	if len(m.Command.Args) > 0 {
+		n, err := strconv.Atoi(m.Command.Args[0])
+		if err != nil {
+			return fmt.Sprint(ErrInvalidCount), ErrInvalidCount, nil
+		}
+		count = n
+	}
+
+	here, err := m.Here.ScopeExact()
+	if err != nil {
+		return "", nil, err
+	}
+	logical, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	winners, usrErr, err := Draw(here, count)
+	if err != nil {
+		return "", nil, err
+	}
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+
+	ns, err := names(winners, logical)
+	if err != nil {
+		return "", nil, err
+	}
+	PublishWinners(m, ns)
+
+	if len(winners) < count {
+		return fmt.Sprintf("Only %d people could be drawn, congratulations %s!", len(winners), strings.Join(ns, ", ")), nil, nil
+	}
+	return fmt.Sprintf("Congratulations %s!", strings.Join(ns, ", ")), nil, nil
+}
+
+////////////
+//        //
+// reroll //
+//        //
+////////////
+
+var AdvancedReroll = advancedReroll{}
+
+type advancedReroll struct{}
+
+func (c advancedReroll) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedReroll) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedReroll) Names() []string {
+	return []string{
+		"reroll",
+		"redraw",
+	}
+}
+
+func (advancedReroll) Description() string {
+	return "Take back a win and draw someone else, the latest winner by default."
+}
+
+func (advancedReroll) UsageArgs() string {
+	return "[person]"
+}
+
+func (c advancedReroll) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedReroll) Examples() []string {
+	return nil
+}
+
+func (advancedReroll) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedReroll) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedReroll) Init() error {
+	return nil
+}
+
+func (c advancedReroll) Run(m *core.Message) (any, error, error) {
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedReroll) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeExact()
+	if err != nil {
+		return "", nil, err
+	}
+	logical, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var person int64 = -1
+	if len(m.Command.Args) > 0 {
+		person, err = nick.ParsePerson(m, logical, m.Command.Args[0])
+		if err != nil {
+			return fmt.Sprint(ErrPersonNotFound), ErrPersonNotFound, nil
+		}
+	}
+
+	old, winner, usrErr, err := Reroll(here, person)
+	if err != nil {
+		return "", nil, err
+	}
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+
+	oldName, err := nick.Name(old, logical)
+	if err != nil {
+		return "", nil, err
+	}
+	winnerName, err := nick.Name(winner, logical)
+	if err != nil {
+		return "", nil, err
+	}
+	PublishWinners(m, []string{winnerName})
+
+	return fmt.Sprintf("Rerolled %s, congratulations %s!", oldName, winnerName), nil, nil
+}
+
+/////////////
+//         //
+// winners //
+//         //
+/////////////
+
+var AdvancedWinners = advancedWinners{}
+
+type advancedWinners struct{}
+
+func (c advancedWinners) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedWinners) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedWinners) Names() []string {
+	return []string{
+		"winners",
+		"history",
+	}
+}
+
+func (advancedWinners) Description() string {
+	return "Show the most recent winners."
+}
+
+func (advancedWinners) UsageArgs() string {
+	return ""
+}
+
+func (c advancedWinners) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedWinners) Examples() []string {
+	return nil
+}
+
+func (advancedWinners) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedWinners) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedWinners) Init() error {
+	return nil
+}
+
+func (c advancedWinners) Run(m *core.Message) (any, error, error) {
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedWinners) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeExact()
+	if err != nil {
+		return "", nil, err
+	}
+	logical, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	winners, usrErr, err := Winners(here)
+	if err != nil {
+		return "", nil, err
+	}
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+
+	var lines []string
+	for _, w := range winners {
+		name, err := nick.Name(w.Person, logical)
+		if err != nil {
+			return "", nil, err
+		}
+		lines = append(lines, fmt.Sprintf("%s (%s, %s)", name, w.Keyword, w.Drawn.Format("2006-01-02")))
+	}
+
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return strings.Join(lines, "\n"), nil, nil
+	default:
+		return strings.Join(lines, ", "), nil, nil
+	}
+}
+
+//////////
+//      //
+// show //
+//      //
+//////////
+
+var AdvancedShow = advancedShow{}
+
+type advancedShow struct{}
+
+func (c advancedShow) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedShow) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedShow) Names() []string {
+	return core.AliasesShow
+}
+
+func (advancedShow) Description() string {
+	return "Show the latest giveaway."
+}
+
+func (advancedShow) UsageArgs() string {
+	return ""
+}
+
+func (c advancedShow) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedShow) Examples() []string {
+	return nil
+}
+
+func (advancedShow) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedShow) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedShow) Init() error {
+	return nil
+}
+
+func (c advancedShow) Run(m *core.Message) (any, error, error) {
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedShow) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeExact()
+	if err != nil {
+		return "", nil, err
+	}
+
+	g, entries, usrErr, err := Show(here)
+	if err != nil {
+		return "", nil, err
+	}
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+
+	state := "closed"
+	if g.Active {
+		state = "open"
+	}
+	return fmt.Sprintf("Giveaway for %s (%s) is %s with %d entries.", g.Keyword, g.Eligible, state, entries), nil, nil
+}
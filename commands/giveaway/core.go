@@ -0,0 +1,550 @@
+package giveaway
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/commands/moderation"
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/twitch"
+
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrActive         = errors.New("There's already a giveaway running here.")
+	ErrNoGiveaway     = errors.New("There hasn't been a giveaway here yet.")
+	ErrNotRunning     = errors.New("There's no giveaway accepting entries here.")
+	ErrNoEntries      = errors.New("There's no one left to draw.")
+	ErrNotWinner      = errors.New("That person hasn't won the latest giveaway.")
+	ErrNoWinners      = errors.New("No one has won a giveaway here yet.")
+	ErrInvalidArgs    = errors.New("Expected a keyword, optionally followed by a duration, who can enter (everyone, subs, mods or followers) and a minimum follow age.")
+	ErrInvalidCount   = errors.New("Expected a number of winners between 1 and 10.")
+	ErrFollowers      = errors.New("Follower only giveaways are only supported on twitch.")
+	ErrPersonNotFound = errors.New("Couldn't find that person.")
+)
+
+const (
+	DefaultDuration = 10 * time.Minute
+	MaxWinners      = 10
+
+	// the number of past winners shown
+	winnersShown = 10
+)
+
+// Eligibility is who can enter a giveaway.
+type Eligibility string
+
+const (
+	Everyone    Eligibility = "everyone"
+	Subscribers Eligibility = "subs"
+	Moderators  Eligibility = "mods"
+	Followers   Eligibility = "followers"
+)
+
+// ParseEligibility returns the eligibility that s refers to, returns false if
+// it doesn't refer to any.
+func ParseEligibility(s string) (Eligibility, bool) {
+	switch strings.ToLower(s) {
+	case "everyone", "all":
+		return Everyone, true
+	case "subs", "sub", "subscribers":
+		return Subscribers, true
+	case "mods", "mod", "moderators":
+		return Moderators, true
+	case "followers", "follower", "follows":
+		return Followers, true
+	default:
+		return "", false
+	}
+}
+
+type Giveaway struct {
+	ID        int64
+	Place     int64 // the exact place
+	Creator   int64
+	Keyword   string
+	Eligible  Eligibility
+	FollowAge time.Duration // minimum follow age, only used for Followers
+	Ends      time.Time
+	Active    bool // whether entries are still accepted
+}
+
+type Winner struct {
+	Keyword string
+	Person  int64
+	Drawn   time.Time
+}
+
+// ParseArgs parses the arguments of the form:
+// <keyword> [duration] [everyone|subs|mods|followers] [minimum follow age]
+func ParseArgs(args []string) (string, time.Duration, Eligibility, time.Duration, error) {
+	keyword := args[0]
+	dur := DefaultDuration
+	eligible := Everyone
+	var followAge time.Duration
+
+	i := 1
+	if i < len(args) {
+		if d, err := moderation.ParseDuration(args[i]); err == nil && d > 0 {
+			dur = d
+			i++
+		}
+	}
+	if i < len(args) {
+		e, ok := ParseEligibility(args[i])
+		if !ok {
+			return "", 0, "", 0, ErrInvalidArgs
+		}
+		eligible = e
+		i++
+	}
+	if i < len(args) && eligible == Followers {
+		d, err := moderation.ParseDuration(args[i])
+		if err != nil || d < 0 {
+			return "", 0, "", 0, moderation.ErrInvalidDuration
+		}
+		followAge = d
+		i++
+	}
+	if i < len(args) {
+		return "", 0, "", 0, ErrInvalidArgs
+	}
+
+	return keyword, dur, eligible, followAge, nil
+}
+
+// CanEnter checks if the message's author is allowed to enter the giveaway.
+func (g Giveaway) CanEnter(m *core.Message) bool {
+	switch g.Eligible {
+	case Subscribers:
+		return m.Author.Subscriber()
+	case Moderators:
+		return m.Author.Mod()
+	case Followers:
+		return following(m, g.FollowAge)
+	default:
+		return true
+	}
+}
+
+// following checks if the author has been following the twitch channel the
+// message came from for at least age.
+func following(m *core.Message, age time.Duration) bool {
+	if m.Frontend.Type() != twitch.Frontend.Type() {
+		return false
+	}
+
+	h, err := twitch.HelixChannel(m.Here.ID())
+	if err != nil {
+		log.Debug().Err(err).Msg("failed to get helix client")
+		return false
+	}
+
+	// returns an error if they are not following
+	f, err := h.GetFollower(m.Here.ID(), m.Author.ID())
+	if err != nil {
+		return false
+	}
+
+	return time.Since(f.FollowedAt) >= age
+}
+
+// Text returns the message that announces the giveaway.
+func (g Giveaway) Text() string {
+	s := fmt.Sprintf("Giveaway started! Type %s to enter, entries close in %s.",
+		g.Keyword, moderation.FormatDuration(time.Until(g.Ends).Round(time.Second)))
+
+	switch g.Eligible {
+	case Subscribers:
+		s += " Subscribers only."
+	case Moderators:
+		s += " Moderators only."
+	case Followers:
+		if g.FollowAge > 0 {
+			s += fmt.Sprintf(" Followers of at least %s only.", moderation.FormatDuration(g.FollowAge))
+		} else {
+			s += " Followers only."
+		}
+	}
+	return s
+}
+
+// pick returns count randomly chosen people, the order of people is changed.
+func pick(people []int64, count int) ([]int64, error) {
+	if count > len(people) {
+		count = len(people)
+	}
+	// partial Fisher-Yates shuffle
+	for i := 0; i < count; i++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(people)-i)))
+		if err != nil {
+			return nil, err
+		}
+		j := i + int(n.Int64())
+		people[i], people[j] = people[j], people[i]
+	}
+	return people[:count], nil
+}
+
+// overlayEvent is what gets sent to the place's overlays whenever the
+// giveaway changes.
+type overlayEvent struct {
+	Keyword string   `json:"keyword"`
+	Active  bool     `json:"active"`
+	Entries int64    `json:"entries"`
+	Ends    int64    `json:"ends"` // unix timestamp
+	Winners []string `json:"winners"`
+}
+
+// publish sends the giveaway's current state to the overlays of the place the
+// message came from.
+func (g Giveaway) publish(m *core.Message, winners []string) {
+	entries, err := dbEntries(g.ID)
+	if err != nil {
+		return
+	}
+	if winners == nil {
+		winners = []string{}
+	}
+	core.Overlay.PublishHere(m, core.OverlayGiveaway, overlayEvent{
+		Keyword: g.Keyword,
+		Active:  g.Active,
+		Entries: entries,
+		Ends:    g.Ends.UTC().Unix(),
+		Winners: winners,
+	})
+}
+
+// PublishWinners lets the overlays know who won the latest giveaway in the
+// place the message came from.
+func PublishWinners(m *core.Message, winners []string) {
+	place, err := m.Here.ScopeExact()
+	if err != nil {
+		return
+	}
+	g, usrErr, err := latest(place)
+	if usrErr != nil || err != nil {
+		return
+	}
+	g.publish(m, winners)
+}
+
+/////////////
+//         //
+// running //
+//         //
+/////////////
+
+type running struct {
+	Giveaway
+
+	hook int
+	done chan struct{}
+	once sync.Once
+}
+
+var (
+	lock sync.Mutex
+
+	// the giveaways that are currently accepting entries in each exact place
+	giveaways = map[int64]*running{}
+)
+
+// start registers the hook that collects the giveaway's entries and closes
+// them once the time is up. Returns ErrActive if there's already a giveaway
+// accepting entries in the same place.
+func start(g Giveaway) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, ok := giveaways[g.Place]; ok {
+		return ErrActive
+	}
+
+	r := &running{
+		Giveaway: g,
+		done:     make(chan struct{}),
+	}
+	r.hook = core.Hooks.Register(r.enter)
+	giveaways[g.Place] = r
+
+	go func() {
+		select {
+		case <-time.After(time.Until(g.Ends)):
+			r.close(true)
+		case <-r.done:
+		}
+	}()
+
+	return nil
+}
+
+// enter is the hook that collects the entries, an entry is any message that
+// is just the keyword. Each person can only enter once.
+func (r *running) enter(m *core.Message) {
+	if !strings.EqualFold(strings.TrimSpace(m.Raw), r.Keyword) {
+		return
+	}
+
+	place, err := m.Here.ScopeExact()
+	if err != nil || place != r.Place {
+		return
+	}
+
+	if !r.CanEnter(m) {
+		return
+	}
+
+	person, err := m.Author.Scope()
+	if err != nil {
+		return
+	}
+
+	entered, err := dbEnter(r.ID, person)
+	log.Debug().Err(err).Int64("id", r.ID).Int64("person", person).Msg("giveaway entry")
+
+	if entered {
+		r.publish(m, nil)
+	}
+}
+
+// close stops accepting entries and if announce is true lets the chat know.
+func (r *running) close(announce bool) {
+	r.once.Do(func() {
+		close(r.done)
+
+		lock.Lock()
+		core.Hooks.Delete(r.hook)
+		delete(giveaways, r.Place)
+		lock.Unlock()
+
+		if err := dbClose(r.ID); err != nil {
+			return
+		}
+
+		m, err := core.Frontends.CreateMessage(r.Creator, r.Place, "")
+		if err != nil {
+			log.Debug().Err(err).Int64("id", r.ID).Msg("failed to create giveaway message")
+			return
+		}
+
+		g := r.Giveaway
+		g.Active = false
+		g.publish(m, nil)
+
+		if !announce {
+			return
+		}
+
+		entries, err := dbEntries(r.ID)
+		if err != nil {
+			return
+		}
+
+		_, err = m.Client.Send(fmt.Sprintf("Entries for the giveaway are now closed, %d people entered.", entries), nil)
+		log.Debug().Err(err).Int64("id", r.ID).Msg("announced giveaway closing")
+	})
+}
+
+func find(place int64) (*running, bool) {
+	lock.Lock()
+	defer lock.Unlock()
+	r, ok := giveaways[place]
+	return r, ok
+}
+
+// resume restarts the giveaways that were accepting entries before the bot was
+// shut down, the ones whose time ran out in the meantime close immediately.
+func resume() error {
+	gs, err := dbActive()
+	if err != nil {
+		return err
+	}
+	for _, g := range gs {
+		if err := start(g); err != nil {
+			log.Debug().Err(err).Int64("id", g.ID).Msg("failed to resume giveaway")
+		}
+	}
+	return nil
+}
+
+// Start starts a giveaway in the place the message came from. Returns
+// ErrActive if there's already one accepting entries there.
+func Start(m *core.Message, keyword string, dur time.Duration, eligible Eligibility, followAge time.Duration) (Giveaway, error, error) {
+	if eligible == Followers && m.Frontend.Type() != twitch.Frontend.Type() {
+		return Giveaway{}, ErrFollowers, nil
+	}
+
+	place, err := m.Here.ScopeExact()
+	if err != nil {
+		return Giveaway{}, nil, err
+	}
+	creator, err := m.Author.Scope()
+	if err != nil {
+		return Giveaway{}, nil, err
+	}
+
+	if _, ok := find(place); ok {
+		return Giveaway{}, ErrActive, nil
+	}
+
+	g := Giveaway{
+		Place:     place,
+		Creator:   creator,
+		Keyword:   keyword,
+		Eligible:  eligible,
+		FollowAge: followAge,
+		Ends:      time.Now().Add(dur),
+		Active:    true,
+	}
+
+	if g.ID, err = dbAdd(g); err != nil {
+		return Giveaway{}, nil, err
+	}
+
+	if err := start(g); err == ErrActive {
+		// another giveaway was started in the meantime
+		return Giveaway{}, ErrActive, dbClose(g.ID)
+	} else if err != nil {
+		return Giveaway{}, nil, err
+	}
+
+	g.publish(m, nil)
+	return g, nil, nil
+}
+
+// End stops accepting entries for the giveaway running in the specified exact
+// place.
+func End(place int64) error {
+	r, ok := find(place)
+	if !ok {
+		return ErrNotRunning
+	}
+	r.close(true)
+	return nil
+}
+
+// latest returns the place's latest giveaway.
+func latest(place int64) (Giveaway, error, error) {
+	g, err := dbLatest(place)
+	if err == sql.ErrNoRows {
+		return g, ErrNoGiveaway, nil
+	}
+	return g, nil, err
+}
+
+// Draw draws count winners from the latest giveaway in the specified exact
+// place, if it's still accepting entries then they are closed. People can only
+// win once per giveaway.
+func Draw(place int64, count int) ([]int64, error, error) {
+	if count < 1 || count > MaxWinners {
+		return nil, ErrInvalidCount, nil
+	}
+
+	g, usrErr, err := latest(place)
+	if usrErr != nil || err != nil {
+		return nil, usrErr, err
+	}
+
+	if r, ok := find(place); ok {
+		r.close(false)
+	}
+
+	return draw(g.ID, count)
+}
+
+func draw(id int64, count int) ([]int64, error, error) {
+	candidates, err := dbCandidates(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, ErrNoEntries, nil
+	}
+
+	winners, err := pick(candidates, count)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, w := range winners {
+		if err := dbWinnerAdd(id, w); err != nil {
+			return nil, nil, err
+		}
+	}
+	return winners, nil, nil
+}
+
+// Reroll takes back the person's win in the latest giveaway in the specified
+// exact place and draws someone else. If person is -1 then the most recently
+// drawn winner is rerolled. Returns the person that was rerolled and the new
+// winner.
+func Reroll(place, person int64) (int64, int64, error, error) {
+	g, usrErr, err := latest(place)
+	if usrErr != nil || err != nil {
+		return -1, -1, usrErr, err
+	}
+
+	if person == -1 {
+		person, err = dbLastWinner(g.ID)
+		if err == sql.ErrNoRows {
+			return -1, -1, ErrNoWinners, nil
+		}
+		if err != nil {
+			return -1, -1, nil, err
+		}
+	}
+
+	// make sure that there's someone to draw before taking the win back
+	candidates, err := dbCandidates(g.ID)
+	if err != nil {
+		return -1, -1, nil, err
+	}
+	if len(candidates) == 0 {
+		return -1, -1, ErrNoEntries, nil
+	}
+
+	rerolled, err := dbReroll(g.ID, person)
+	if err != nil {
+		return -1, -1, nil, err
+	}
+	if !rerolled {
+		return -1, -1, ErrNotWinner, nil
+	}
+
+	winners, usrErr, err := draw(g.ID, 1)
+	if usrErr != nil || err != nil {
+		return -1, -1, usrErr, err
+	}
+	return person, winners[0], nil, nil
+}
+
+// Winners returns the most recent winners in the specified exact place.
+func Winners(place int64) ([]Winner, error, error) {
+	winners, err := dbWinners(place, winnersShown)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(winners) == 0 {
+		return nil, ErrNoWinners, nil
+	}
+	return winners, nil, nil
+}
+
+// Show returns the latest giveaway in the specified exact place and its
+// number of entries.
+func Show(place int64) (Giveaway, int64, error, error) {
+	g, usrErr, err := latest(place)
+	if usrErr != nil || err != nil {
+		return g, 0, usrErr, err
+	}
+	entries, err := dbEntries(g.ID)
+	return g, entries, nil, err
+}
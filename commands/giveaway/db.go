@@ -0,0 +1,338 @@
+package giveaway
+
+import (
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/rs/zerolog/log"
+)
+
+const giveawayColumns = `id, place, creator, keyword, eligible, follow_age, ends, active`
+
+func scanGiveaway(row interface{ Scan(...any) error }) (Giveaway, error) {
+	var g Giveaway
+	var followAge, ends int64
+	err := row.Scan(&g.ID, &g.Place, &g.Creator, &g.Keyword, &g.Eligible, &followAge, &ends, &g.Active)
+	g.FollowAge = time.Duration(followAge) * time.Second
+	g.Ends = time.Unix(ends, 0).UTC()
+	return g, err
+}
+
+func dbAdd(g Giveaway) (int64, error) {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	var id int64
+	err := db.DB.QueryRow(`
+		INSERT INTO cmd_giveaway_giveaways(
+			place, creator, keyword, eligible, follow_age, ends, active, created
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`,
+		g.Place, g.Creator, g.Keyword, g.Eligible, int64(g.FollowAge.Seconds()),
+		g.Ends.UTC().Unix(), true, time.Now().UTC().Unix()).Scan(&id)
+
+	log.Debug().
+		Err(err).
+		Int64("id", id).
+		Int64("place", g.Place).
+		Int64("creator", g.Creator).
+		Str("keyword", g.Keyword).
+		Str("eligible", string(g.Eligible)).
+		Dur("follow_age", g.FollowAge).
+		Time("ends", g.Ends).
+		Msg("added giveaway")
+
+	return id, err
+}
+
+func dbClose(id int64) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		UPDATE cmd_giveaway_giveaways
+		SET active = $1
+		WHERE id = $2
+	`, false, id)
+
+	log.Debug().
+		Err(err).
+		Int64("id", id).
+		Msg("closed giveaway entries")
+
+	return err
+}
+
+// dbActive returns the giveaways that are still accepting entries, used to
+// resume them after a restart.
+func dbActive() ([]Giveaway, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	rows, err := db.DB.Query(`
+		SELECT `+giveawayColumns+`
+		FROM cmd_giveaway_giveaways
+		WHERE active = $1
+	`, true)
+	if err != nil {
+		log.Debug().Err(err).Msg("failed to make query")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var gs []Giveaway
+	for rows.Next() {
+		g, err := scanGiveaway(rows)
+		if err != nil {
+			log.Debug().Err(err).Msg("failed while scanning rows")
+			return nil, err
+		}
+		gs = append(gs, g)
+	}
+
+	err = rows.Err()
+
+	log.Debug().
+		Err(err).
+		Int("count", len(gs)).
+		Msg("got active giveaways")
+
+	return gs, err
+}
+
+// dbLatest returns the place's most recent giveaway.
+func dbLatest(place int64) (Giveaway, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	row := db.DB.QueryRow(`
+		SELECT `+giveawayColumns+`
+		FROM cmd_giveaway_giveaways
+		WHERE place = $1
+		ORDER BY id DESC
+		LIMIT 1
+	`, place)
+
+	g, err := scanGiveaway(row)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int64("id", g.ID).
+		Msg("got latest giveaway")
+
+	return g, err
+}
+
+// dbEnter adds the person's entry, returns false if they had already entered.
+func dbEnter(id, person int64) (bool, error) {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	res, err := db.DB.Exec(`
+		INSERT INTO cmd_giveaway_entries(giveaway, person, entered)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`, id, person, time.Now().UTC().Unix())
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+
+	log.Debug().
+		Err(err).
+		Int64("id", id).
+		Int64("person", person).
+		Bool("entered", n == 1).
+		Msg("entered giveaway")
+
+	return n == 1, err
+}
+
+func dbEntries(id int64) (int64, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	var count int64
+	err := db.DB.QueryRow(`
+		SELECT COUNT(*)
+		FROM cmd_giveaway_entries
+		WHERE giveaway = $1
+	`, id).Scan(&count)
+
+	log.Debug().
+		Err(err).
+		Int64("id", id).
+		Int64("count", count).
+		Msg("counted giveaway entries")
+
+	return count, err
+}
+
+// dbCandidates returns the people that have entered the giveaway and haven't
+// been drawn yet, including the ones that were rerolled.
+func dbCandidates(id int64) ([]int64, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	rows, err := db.DB.Query(`
+		SELECT person
+		FROM cmd_giveaway_entries
+		WHERE giveaway = $1 and person NOT IN (
+			SELECT person
+			FROM cmd_giveaway_winners
+			WHERE giveaway = $1
+		)
+		ORDER BY entered
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var people []int64
+	for rows.Next() {
+		var person int64
+		if err := rows.Scan(&person); err != nil {
+			return nil, err
+		}
+		people = append(people, person)
+	}
+
+	err = rows.Err()
+
+	log.Debug().
+		Err(err).
+		Int64("id", id).
+		Int("count", len(people)).
+		Msg("got giveaway candidates")
+
+	return people, err
+}
+
+func dbWinnerAdd(id, person int64) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		INSERT INTO cmd_giveaway_winners(giveaway, person, drawn, rerolled)
+		VALUES ($1, $2, $3, $4)
+	`, id, person, time.Now().UTC().Unix(), false)
+
+	log.Debug().
+		Err(err).
+		Int64("id", id).
+		Int64("person", person).
+		Msg("added giveaway winner")
+
+	return err
+}
+
+// dbLastWinner returns the most recently drawn winner that hasn't been
+// rerolled.
+func dbLastWinner(id int64) (int64, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	var person int64
+	err := db.DB.QueryRow(`
+		SELECT person
+		FROM cmd_giveaway_winners
+		WHERE giveaway = $1 and rerolled = $2
+		ORDER BY id DESC
+		LIMIT 1
+	`, id, false).Scan(&person)
+
+	log.Debug().
+		Err(err).
+		Int64("id", id).
+		Int64("person", person).
+		Msg("got last giveaway winner")
+
+	return person, err
+}
+
+// dbReroll takes the person's win back, returns false if they weren't a
+// winner.
+func dbReroll(id, person int64) (bool, error) {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	res, err := db.DB.Exec(`
+		UPDATE cmd_giveaway_winners
+		SET rerolled = $1
+		WHERE giveaway = $2 and person = $3 and rerolled = $4
+	`, true, id, person, false)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+
+	log.Debug().
+		Err(err).
+		Int64("id", id).
+		Int64("person", person).
+		Bool("rerolled", n == 1).
+		Msg("rerolled giveaway winner")
+
+	return n == 1, err
+}
+
+// dbWinners returns the place's most recent winners, rerolled ones excluded.
+func dbWinners(place int64, limit int) ([]Winner, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	rows, err := db.DB.Query(`
+		SELECT cmd_giveaway_giveaways.keyword, cmd_giveaway_winners.person,
+			cmd_giveaway_winners.drawn
+		FROM cmd_giveaway_winners
+		JOIN cmd_giveaway_giveaways
+			ON cmd_giveaway_giveaways.id = cmd_giveaway_winners.giveaway
+		WHERE cmd_giveaway_giveaways.place = $1 and cmd_giveaway_winners.rerolled = $2
+		ORDER BY cmd_giveaway_winners.id DESC
+		LIMIT $3
+	`, place, false, limit)
+	if err != nil {
+		log.Debug().Err(err).Msg("failed to make query")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var winners []Winner
+	for rows.Next() {
+		var w Winner
+		var drawn int64
+		if err := rows.Scan(&w.Keyword, &w.Person, &drawn); err != nil {
+			log.Debug().Err(err).Msg("failed while scanning rows")
+			return nil, err
+		}
+		w.Drawn = time.Unix(drawn, 0).UTC()
+		winners = append(winners, w)
+	}
+
+	err = rows.Err()
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int("count", len(winners)).
+		Msg("got giveaway winners")
+
+	return winners, err
+}
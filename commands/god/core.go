@@ -14,7 +14,9 @@ var ErrIntervalTooShort = errors.New("The given interval is too short.")
 
 // Talk returns GPT3's response to a prompt.
 func Talk(prompt string) (string, error) {
-	c := gogpt.NewClient(core.OpenAIKey)
+	config := gogpt.DefaultConfig(core.OpenAIKey)
+	config.HTTPClient = core.APIClient("openai")
+	c := gogpt.NewClientWithConfig(config)
 	ctx := context.Background()
 
 	req := gogpt.CompletionRequest{
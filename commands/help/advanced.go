@@ -2,6 +2,9 @@ package help
 
 import (
 	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+
+	dg "github.com/bwmarrin/discordgo"
 )
 
 var Advanced = advanced{}
@@ -41,13 +44,350 @@ func (advanced) Parent() core.CommandStatic {
 }
 
 func (advanced) Children() core.CommandsStatic {
-	return nil
+	return core.CommandsStatic{
+		AdvancedSuggestions,
+	}
 }
 
 func (advanced) Init() error {
+	core.Hooks.Register(suggest)
 	return nil
 }
 
 func (advanced) Run(m *core.Message) (any, error, error) {
 	return run(core.Advanced, m)
 }
+
+/////////////////
+//             //
+// suggestions //
+//             //
+/////////////////
+
+var AdvancedSuggestions = advancedSuggestions{}
+
+type advancedSuggestions struct{}
+
+func (c advancedSuggestions) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedSuggestions) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedSuggestions) Names() []string {
+	return []string{
+		"suggestions",
+		"suggest",
+	}
+}
+
+func (advancedSuggestions) Description() string {
+	return "Control whether commands are suggested when an unknown one is used."
+}
+
+func (c advancedSuggestions) UsageArgs() string {
+	return c.Children().Usage()
+}
+
+func (c advancedSuggestions) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedSuggestions) Examples() []string {
+	return nil
+}
+
+func (advancedSuggestions) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedSuggestions) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		AdvancedSuggestionsShow,
+		AdvancedSuggestionsOn,
+		AdvancedSuggestionsOff,
+	}
+}
+
+func (advancedSuggestions) Init() error {
+	return nil
+}
+
+func (advancedSuggestions) Run(m *core.Message) (any, error, error) {
+	return m.Usage(), core.ErrMissingArgs, nil
+}
+
+//////////////////////
+//                  //
+// suggestions show //
+//                  //
+//////////////////////
+
+var AdvancedSuggestionsShow = advancedSuggestionsShow{}
+
+type advancedSuggestionsShow struct{}
+
+func (c advancedSuggestionsShow) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedSuggestionsShow) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedSuggestionsShow) Names() []string {
+	return core.AliasesShow
+}
+
+func (advancedSuggestionsShow) Description() string {
+	return "Show if suggestions are on or off."
+}
+
+func (advancedSuggestionsShow) UsageArgs() string {
+	return ""
+}
+
+func (c advancedSuggestionsShow) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedSuggestionsShow) Examples() []string {
+	return nil
+}
+
+func (advancedSuggestionsShow) Parent() core.CommandStatic {
+	return AdvancedSuggestions
+}
+
+func (advancedSuggestionsShow) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedSuggestionsShow) Init() error {
+	return nil
+}
+
+func (c advancedSuggestionsShow) Run(m *core.Message) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return c.discord(m)
+	default:
+		return c.text(m)
+	}
+}
+
+func (c advancedSuggestionsShow) discord(m *core.Message) (*dg.MessageEmbed, error, error) {
+	on, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	embed := &dg.MessageEmbed{
+		Description: c.fmt(m, on),
+	}
+	return embed, nil, nil
+}
+
+func (c advancedSuggestionsShow) text(m *core.Message) (string, error, error) {
+	on, err := c.core(m)
+	if err != nil {
+		return "", nil, err
+	}
+	return c.fmt(m, on), nil, nil
+}
+
+func (advancedSuggestionsShow) fmt(m *core.Message, on bool) string {
+	if on {
+		return m.T("help.suggestions.show.on")
+	}
+	return m.T("help.suggestions.show.off")
+}
+
+func (advancedSuggestionsShow) core(m *core.Message) (bool, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return false, err
+	}
+	return SuggestionsOnGet(here)
+}
+
+////////////////////
+//                //
+// suggestions on //
+//                //
+////////////////////
+
+var AdvancedSuggestionsOn = advancedSuggestionsOn{}
+
+type advancedSuggestionsOn struct{}
+
+func (c advancedSuggestionsOn) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedSuggestionsOn) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedSuggestionsOn) Names() []string {
+	return core.AliasesOn
+}
+
+func (advancedSuggestionsOn) Description() string {
+	return "Turn suggestions on."
+}
+
+func (advancedSuggestionsOn) UsageArgs() string {
+	return ""
+}
+
+func (c advancedSuggestionsOn) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedSuggestionsOn) Examples() []string {
+	return nil
+}
+
+func (advancedSuggestionsOn) Parent() core.CommandStatic {
+	return AdvancedSuggestions
+}
+
+func (advancedSuggestionsOn) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedSuggestionsOn) Init() error {
+	return nil
+}
+
+func (c advancedSuggestionsOn) Run(m *core.Message) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return c.discord(m)
+	default:
+		return c.text(m)
+	}
+}
+
+func (c advancedSuggestionsOn) discord(m *core.Message) (*dg.MessageEmbed, error, error) {
+	err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	embed := &dg.MessageEmbed{
+		Description: c.fmt(m),
+	}
+	return embed, nil, nil
+}
+
+func (c advancedSuggestionsOn) text(m *core.Message) (string, error, error) {
+	err := c.core(m)
+	if err != nil {
+		return "", nil, err
+	}
+	return c.fmt(m), nil, nil
+}
+
+func (advancedSuggestionsOn) fmt(m *core.Message) string {
+	return m.T("help.suggestions.on")
+}
+
+func (advancedSuggestionsOn) core(m *core.Message) error {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return err
+	}
+	return SuggestionsOnSet(here, true)
+}
+
+/////////////////////
+//                 //
+// suggestions off //
+//                 //
+/////////////////////
+
+var AdvancedSuggestionsOff = advancedSuggestionsOff{}
+
+type advancedSuggestionsOff struct{}
+
+func (c advancedSuggestionsOff) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedSuggestionsOff) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedSuggestionsOff) Names() []string {
+	return core.AliasesOff
+}
+
+func (advancedSuggestionsOff) Description() string {
+	return "Turn suggestions off."
+}
+
+func (advancedSuggestionsOff) UsageArgs() string {
+	return ""
+}
+
+func (c advancedSuggestionsOff) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedSuggestionsOff) Examples() []string {
+	return nil
+}
+
+func (advancedSuggestionsOff) Parent() core.CommandStatic {
+	return AdvancedSuggestions
+}
+
+func (advancedSuggestionsOff) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedSuggestionsOff) Init() error {
+	return nil
+}
+
+func (c advancedSuggestionsOff) Run(m *core.Message) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return c.discord(m)
+	default:
+		return c.text(m)
+	}
+}
+
+func (c advancedSuggestionsOff) discord(m *core.Message) (*dg.MessageEmbed, error, error) {
+	err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	embed := &dg.MessageEmbed{
+		Description: c.fmt(m),
+	}
+	return embed, nil, nil
+}
+
+func (c advancedSuggestionsOff) text(m *core.Message) (string, error, error) {
+	err := c.core(m)
+	if err != nil {
+		return "", nil, err
+	}
+	return c.fmt(m), nil, nil
+}
+
+func (advancedSuggestionsOff) fmt(m *core.Message) string {
+	return m.T("help.suggestions.off")
+}
+
+func (advancedSuggestionsOff) core(m *core.Message) error {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return err
+	}
+	return SuggestionsOnSet(here, false)
+}
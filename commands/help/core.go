@@ -1,10 +1,14 @@
 package help
 
 import (
-	"errors"
+	"embed"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/janitorjeff/jeff-bot/commands/counter"
+	"github.com/janitorjeff/jeff-bot/commands/custom-command"
 	"github.com/janitorjeff/jeff-bot/core"
 	"github.com/janitorjeff/jeff-bot/frontends/discord"
 
@@ -17,6 +21,11 @@ import (
 // being the list of commands from which it matches (e.g. if it is called with
 // a normal prefix it will match normal commands). This means that almost all
 // of its functionality is implemented in the core, including the rendering.
+//
+// If no command is specified then all of the commands that the author can use
+// are listed, grouped by category. On discord every category gets its own
+// page which can be scrolled through, while on other frontends the list is
+// split into compact pages which are picked by passing the page's number.
 
 var cmdNames = []string{
 	"help",
@@ -24,10 +33,29 @@ var cmdNames = []string{
 
 const (
 	cmdDescription = "Shows a help message for the specified %s command."
-	cmdUsageArgs   = "<command...>"
+	cmdUsageArgs   = "[command... | page]"
 )
 
-var errCommandNotFound = errors.New("Command could not be found.")
+//go:embed locales
+var locales embed.FS
+
+var (
+	errCommandNotFound = core.NewError("help.command_not_found")
+	errPageNotFound    = core.NewError("help.page_not_found")
+)
+
+const (
+	// The maximum number of commands in a page on discord.
+	pageCommands = 10
+
+	// The maximum length of a page on other frontends, short enough to fit
+	// in a single message on twitch along with the page number and the hint.
+	pageLength = 350
+
+	// The maximum edit distance between the name that was typed out and a
+	// command's for the command to be suggested.
+	suggestDistance = 2
+)
 
 func runCore(t core.CommandType, m *core.Message, args []string, prefix string) (*core.Command, []string, []string, error) {
 	cmdStatic, index, err := core.Commands.Match(t, m, args)
@@ -64,26 +92,26 @@ func runCore(t core.CommandType, m *core.Message, args []string, prefix string)
 	return cmd, aliases, cmdStatic.Examples(), nil
 }
 
-func renderText(cmd *core.Command, aliases []string) string {
+func renderText(m *core.Message, cmd *core.Command, aliases []string) string {
 	var help strings.Builder
-	fmt.Fprintf(&help, "Usage: %s.", cmd.Usage())
+	help.WriteString(m.T("help.usage", cmd.Usage()))
 
-	if cmd.Description() != "" {
-		help.WriteString(" " + cmd.Description())
+	if desc := m.TDescription(cmd); desc != "" {
+		help.WriteString(" " + desc)
 	}
 
 	if len(aliases) > 0 {
-		fmt.Fprintf(&help, " Aliases: %s.", strings.Join(aliases, ", "))
+		help.WriteString(" " + m.T("help.aliases", strings.Join(aliases, ", ")))
 	}
 
 	return help.String()
 }
 
-func renderDiscord(cmd *core.Command, aliases []string, examples []string) *dg.MessageEmbed {
+func renderDiscord(m *core.Message, cmd *core.Command, aliases []string, examples []string) *dg.MessageEmbed {
 	var desc strings.Builder
 
-	if cmd.Description() != "" {
-		fmt.Fprintf(&desc, "*%s*", cmd.Description())
+	if d := m.TDescription(cmd); d != "" {
+		fmt.Fprintf(&desc, "*%s*", d)
 	}
 
 	if len(examples) > 0 {
@@ -96,7 +124,7 @@ func renderDiscord(cmd *core.Command, aliases []string, examples []string) *dg.M
 				examples[i] = fmt.Sprintf("- `%s%s`", base, examples[i])
 			}
 		}
-		fmt.Fprintf(&desc, "\n\nExamples:\n%s", strings.Join(examples, "\n"))
+		fmt.Fprintf(&desc, "\n\n%s\n%s", m.T("help.examples"), strings.Join(examples, "\n"))
 	}
 
 	if len(aliases) > 0 {
@@ -111,11 +139,11 @@ func renderDiscord(cmd *core.Command, aliases []string, examples []string) *dg.M
 		for i := range aliases {
 			aliases[i] = fmt.Sprintf("- `%s%s`", base, aliases[i])
 		}
-		fmt.Fprintf(&desc, "\n\nAliases:\n%s", strings.Join(aliases, "\n"))
+		fmt.Fprintf(&desc, "\n\n%s\n%s", m.T("help.aliases_title"), strings.Join(aliases, "\n"))
 	}
 
 	embed := &dg.MessageEmbed{
-		Title:       fmt.Sprintf("Usage: `%s`", cmd.Usage()),
+		Title:       m.T("help.usage_title", cmd.Usage()),
 		Description: desc.String(),
 	}
 
@@ -125,22 +153,25 @@ func renderDiscord(cmd *core.Command, aliases []string, examples []string) *dg.M
 func runDiscord(t core.CommandType, m *core.Message) (*dg.MessageEmbed, error, error) {
 	cmd, aliases, examples, usrErr := runCore(t, m, m.Command.Args, m.Command.Prefix)
 	if usrErr != nil {
-		return &dg.MessageEmbed{Description: fmt.Sprint(usrErr)}, usrErr, nil
+		return &dg.MessageEmbed{Description: m.TError(usrErr)}, usrErr, nil
 	}
-	return renderDiscord(cmd, aliases, examples), nil, nil
+	return renderDiscord(m, cmd, aliases, examples), nil, nil
 }
 
 func runText(t core.CommandType, m *core.Message) (string, error, error) {
 	cmd, aliases, _, usrErr := runCore(t, m, m.Command.Args, m.Command.Prefix)
 	if usrErr != nil {
-		return fmt.Sprint(usrErr), usrErr, nil
+		return m.TError(usrErr), usrErr, nil
 	}
-	return renderText(cmd, aliases), nil, nil
+	return renderText(m, cmd, aliases), nil, nil
 }
 
 func run(t core.CommandType, m *core.Message) (any, error, error) {
 	if len(m.Command.Args) < 1 {
-		return m.Usage(), core.ErrMissingArgs, nil
+		return runList(t, m, 1)
+	}
+	if page, err := strconv.Atoi(m.Command.Args[0]); err == nil && len(m.Command.Args) == 1 {
+		return runList(t, m, page)
 	}
 
 	switch m.Frontend.Type() {
@@ -150,3 +181,296 @@ func run(t core.CommandType, m *core.Message) (any, error, error) {
 		return runText(t, m)
 	}
 }
+
+//////////
+//      //
+// list //
+//      //
+//////////
+
+type category struct {
+	Name     core.CommandCategory
+	Commands []core.CommandStatic
+}
+
+// listCore returns the top level commands of type t that m's author is
+// permitted to use, grouped by category. Both the categories and the commands
+// in them are sorted by name.
+func listCore(t core.CommandType, m *core.Message) []category {
+	byName := map[core.CommandCategory][]core.CommandStatic{}
+	for _, cmd := range *core.Commands {
+		if cmd.Type() != t || !cmd.Permitted(m) {
+			continue
+		}
+		byName[cmd.Category()] = append(byName[cmd.Category()], cmd)
+	}
+
+	var categories []category
+	for name, cmds := range byName {
+		sort.Slice(cmds, func(i, j int) bool {
+			return cmds[i].Names()[0] < cmds[j].Names()[0]
+		})
+		categories = append(categories, category{name, cmds})
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Name < categories[j].Name
+	})
+
+	return categories
+}
+
+func listDiscord(m *core.Message, categories []category) []*dg.MessageEmbed {
+	hint := m.T("help.list.hint", discord.PlaceInBackticks(m.Command.Prefix+m.Command.Path[0]+" <command>"))
+
+	var pages []*dg.MessageEmbed
+	for _, c := range categories {
+		for start := 0; start < len(c.Commands); start += pageCommands {
+			end := start + pageCommands
+			if end > len(c.Commands) {
+				end = len(c.Commands)
+			}
+
+			var lines []string
+			for _, cmd := range c.Commands[start:end] {
+				name := discord.PlaceInBackticks(m.Command.Prefix + cmd.Names()[0])
+				lines = append(lines, fmt.Sprintf("%s - %s", name, m.TDescription(cmd)))
+			}
+
+			pages = append(pages, &dg.MessageEmbed{
+				Title:       m.T("help.list.title", c.Name),
+				Description: strings.Join(lines, "\n") + "\n\n" + hint,
+			})
+		}
+	}
+
+	return pages
+}
+
+func listText(m *core.Message, categories []category) []string {
+	var groups []string
+	for _, c := range categories {
+		var names []string
+		for _, cmd := range c.Commands {
+			names = append(names, m.Command.Prefix+cmd.Names()[0])
+		}
+		groups = append(groups, fmt.Sprintf("%s: %s.", c.Name, strings.Join(names, ", ")))
+	}
+
+	lenCnt := func(s string) int { return len(s) }
+	return core.Split(strings.Join(groups, " "), lenCnt, pageLength)
+}
+
+// runList shows the list of commands starting from the specified page, pages
+// are numbered starting from 1.
+func runList(t core.CommandType, m *core.Message, page int) (any, error, error) {
+	categories := listCore(t, m)
+
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		pages := listDiscord(m, categories)
+		if page < 1 || page > len(pages) {
+			return &dg.MessageEmbed{Description: m.TError(errPageNotFound)}, errPageNotFound, nil
+		}
+		return discord.Pages{Embeds: pages, Current: page - 1}, nil, nil
+
+	default:
+		pages := listText(m, categories)
+		if page < 1 || page > len(pages) {
+			return m.TError(errPageNotFound), errPageNotFound, nil
+		}
+
+		resp := fmt.Sprintf("(%d/%d) %s", page, len(pages), pages[page-1])
+		if page < len(pages) {
+			next := fmt.Sprintf("%s%s %d", m.Command.Prefix, m.Command.Path[0], page+1)
+			resp += " " + m.T("help.list.next", next)
+		}
+		return resp, nil, nil
+	}
+}
+
+/////////////
+//         //
+// suggest //
+//         //
+/////////////
+
+// SuggestionsOnGet returns whether or not commands are suggested when an
+// unknown one is used in the specified place.
+func SuggestionsOnGet(place int64) (bool, error) {
+	on, err := core.DB.SettingPlaceGet("cmd_help_suggest", place)
+	if err != nil {
+		return false, err
+	}
+	return on.(bool), nil
+}
+
+// SuggestionsOnSet sets whether or not commands are suggested when an unknown
+// one is used in the specified place.
+func SuggestionsOnSet(place int64, on bool) error {
+	return core.DB.SettingPlaceSet("cmd_help_suggest", place, on)
+}
+
+// distance returns the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minimum(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func minimum(nums ...int) int {
+	m := nums[0]
+	for _, n := range nums[1:] {
+		if n < m {
+			m = n
+		}
+	}
+	return m
+}
+
+// closest returns the candidate that is the closest to s, as long as it's
+// close enough for s to likely be a typo of it.
+func closest(s string, candidates []string) (string, bool) {
+	s = strings.ToLower(s)
+
+	// a single typo in a very short name could make it look like anything
+	limit := suggestDistance
+	if len([]rune(s)) <= 4 {
+		limit = 1
+	}
+
+	best, bestDist := "", limit+1
+	for _, c := range candidates {
+		d := distance(s, strings.ToLower(c))
+		if d == 0 {
+			return "", false
+		}
+		if d < bestDist || (d == bestDist && c < best) {
+			best, bestDist = c, d
+		}
+	}
+
+	return best, best != ""
+}
+
+// suggestCore returns a command, custom command, alias or counter that the
+// message likely meant to call, if the message begins with one of the place's
+// prefixes but doesn't call anything.
+func suggestCore(m *core.Message, here int64) (string, string, bool, error) {
+	fields := m.Fields()
+	if len(fields) == 0 {
+		return "", "", false, nil
+	}
+	typed := fields[0]
+
+	prefixes, _, err := core.PlacePrefixes(here)
+	if err != nil {
+		return "", "", false, err
+	}
+
+	prefix, name, found := core.MatchPrefix(prefixes, typed)
+	if !found || (prefix.Type == core.Admin && !m.Author.BotAdmin()) {
+		return "", "", false, nil
+	}
+
+	if on, err := SuggestionsOnGet(here); err != nil || !on {
+		return "", "", false, err
+	}
+
+	if _, _, err := core.Commands.Match(prefix.Type, m, []string{name}); err == nil {
+		return "", "", false, nil
+	}
+
+	var candidates []string
+	for _, cmd := range *core.Commands {
+		if cmd.Type() != prefix.Type || !cmd.Permitted(m) {
+			continue
+		}
+		for _, n := range cmd.Names() {
+			candidates = append(candidates, prefix.Prefix+n)
+		}
+	}
+
+	triggers, err := custom_command.List(here)
+	if err != nil {
+		return "", "", false, err
+	}
+	candidates = append(candidates, triggers...)
+
+	aliases, err := core.PlaceAliases(here)
+	if err != nil {
+		return "", "", false, err
+	}
+	for _, a := range aliases {
+		candidates = append(candidates, a.Trigger)
+	}
+
+	// counters are used with the normal prefixes, e.g. !deaths
+	if prefix.Type == core.Normal {
+		counters, err := counter.List(here)
+		if err != nil {
+			return "", "", false, err
+		}
+		for _, c := range counters {
+			candidates = append(candidates, prefix.Prefix+c.Name)
+		}
+	}
+
+	// an exact match means that a custom command, an alias or a counter was
+	// called
+	suggestion, ok := closest(typed, candidates)
+	return typed, suggestion, ok, nil
+}
+
+// suggest is a hook that replies with a suggestion when an unknown command is
+// used, if suggestions are turned on in the place.
+func suggest(m *core.Message) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return
+	}
+
+	typed, suggestion, ok, err := suggestCore(m, here)
+	if err != nil {
+		log.Debug().Err(err).Msg("failed to find a suggestion")
+		return
+	}
+	if !ok {
+		return
+	}
+
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		embed := &dg.MessageEmbed{
+			Description: m.T("help.suggest", discord.PlaceInBackticks(typed), discord.PlaceInBackticks(suggestion)),
+		}
+		m.Write(embed, errCommandNotFound)
+	default:
+		typed = fmt.Sprintf("'%s'", typed)
+		suggestion = fmt.Sprintf("'%s'", suggestion)
+		m.Write(m.T("help.suggest", typed, suggestion), errCommandNotFound)
+	}
+}
+
+func init() {
+	if err := core.Catalogs.Load(locales, "locales"); err != nil {
+		panic(err)
+	}
+}
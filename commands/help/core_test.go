@@ -0,0 +1,51 @@
+package help
+
+import (
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"time", "time", 0},
+		{"tme", "time", 1},
+		{"tiem", "time", 2},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+		{"ñandú", "nandu", 2},
+	}
+
+	for _, test := range tests {
+		if got := distance(test.a, test.b); got != test.expected {
+			t.Errorf("distance('%s', '%s'): expected %d, got %d", test.a, test.b, test.expected, got)
+		}
+	}
+}
+
+func TestClosest(t *testing.T) {
+	candidates := []string{"!time", "!tz", "!timezone", "!rps", "!hello"}
+
+	tests := []struct {
+		typed    string
+		expected string
+		ok       bool
+	}{
+		{"!tme", "!time", true},
+		{"!TIME", "", false},
+		{"!helo", "!hello", true},
+		{"!timezon", "!timezone", true},
+		// short names only allow a single typo
+		{"!ab", "", false},
+		{"!something", "", false},
+	}
+
+	for _, test := range tests {
+		got, ok := closest(test.typed, candidates)
+		if got != test.expected || ok != test.ok {
+			t.Errorf("closest('%s'): expected ('%s', %t), got ('%s', %t)", test.typed, test.expected, test.ok, got, ok)
+		}
+	}
+}
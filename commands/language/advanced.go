@@ -0,0 +1,787 @@
+package language
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+
+	dg "github.com/bwmarrin/discordgo"
+)
+
+var Advanced = advanced{}
+
+type advanced struct{}
+
+func (advanced) Type() core.CommandType {
+	return core.Advanced
+}
+
+func (advanced) Permitted(*core.Message) bool {
+	return true
+}
+
+func (advanced) Names() []string {
+	return []string{
+		"language",
+		"lang",
+	}
+}
+
+func (advanced) Description() string {
+	return "Show or change the language that the bot replies in."
+}
+
+func (c advanced) UsageArgs() string {
+	return c.Children().Usage()
+}
+
+func (advanced) Category() core.CommandCategory {
+	return core.CommandCategoryOther
+}
+
+func (advanced) Examples() []string {
+	return nil
+}
+
+func (advanced) Parent() core.CommandStatic {
+	return nil
+}
+
+func (advanced) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		AdvancedShow,
+		AdvancedSet,
+		AdvancedDelete,
+		AdvancedList,
+		AdvancedPlace,
+	}
+}
+
+func (advanced) Init() error {
+	return nil
+}
+
+func (advanced) Run(m *core.Message) (any, error, error) {
+	return m.Usage(), core.ErrMissingArgs, nil
+}
+
+//////////
+//      //
+// show //
+//      //
+//////////
+
+var AdvancedShow = advancedShow{}
+
+type advancedShow struct{}
+
+func (c advancedShow) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedShow) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedShow) Names() []string {
+	return core.AliasesShow
+}
+
+func (advancedShow) Description() string {
+	return "Show the language that the bot replies to you in."
+}
+
+func (advancedShow) UsageArgs() string {
+	return ""
+}
+
+func (c advancedShow) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedShow) Examples() []string {
+	return nil
+}
+
+func (advancedShow) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedShow) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedShow) Init() error {
+	return nil
+}
+
+func (c advancedShow) Run(m *core.Message) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return c.discord(m)
+	default:
+		return c.text(m)
+	}
+}
+
+func (c advancedShow) discord(m *core.Message) (*dg.MessageEmbed, error, error) {
+	lang, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	embed := &dg.MessageEmbed{
+		Description: m.T("language.show", discord.PlaceInBackticks(lang)),
+	}
+
+	return embed, nil, nil
+}
+
+func (c advancedShow) text(m *core.Message) (string, error, error) {
+	lang, err := c.core(m)
+	if err != nil {
+		return "", nil, err
+	}
+	return m.T("language.show", fmt.Sprintf("'%s'", lang)), nil, nil
+}
+
+func (advancedShow) core(m *core.Message) (string, error) {
+	author, err := m.Author.Scope()
+	if err != nil {
+		return "", err
+	}
+
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", err
+	}
+
+	return PersonShow(author, here)
+}
+
+/////////
+//     //
+// set //
+//     //
+/////////
+
+var AdvancedSet = advancedSet{}
+
+type advancedSet struct{}
+
+func (c advancedSet) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedSet) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedSet) Names() []string {
+	return []string{
+		"set",
+	}
+}
+
+func (advancedSet) Description() string {
+	return "Set the language that the bot replies to you in."
+}
+
+func (advancedSet) UsageArgs() string {
+	return "<language>"
+}
+
+func (c advancedSet) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedSet) Examples() []string {
+	return []string{
+		"es",
+	}
+}
+
+func (advancedSet) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedSet) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedSet) Init() error {
+	return nil
+}
+
+func (c advancedSet) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return c.discord(m)
+	default:
+		return c.text(m)
+	}
+}
+
+func (c advancedSet) discord(m *core.Message) (*dg.MessageEmbed, error, error) {
+	lang, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	embed := &dg.MessageEmbed{
+		Description: setErr(m, usrErr, "language.set", lang, discord.PlaceInBackticks),
+	}
+
+	return embed, usrErr, nil
+}
+
+func (c advancedSet) text(m *core.Message) (string, error, error) {
+	lang, usrErr, err := c.core(m)
+	if err != nil {
+		return "", nil, err
+	}
+	return setErr(m, usrErr, "language.set", lang, quote), usrErr, nil
+}
+
+func (advancedSet) core(m *core.Message) (string, error, error) {
+	author, err := m.Author.Scope()
+	if err != nil {
+		return "", nil, err
+	}
+
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	lang, usrErr, err := PersonSet(author, here, m.Command.Args[0])
+	if usrErr != nil {
+		return m.Command.Args[0], usrErr, err
+	}
+	return lang, usrErr, err
+}
+
+func quote(s string) string {
+	return fmt.Sprintf("'%s'", s)
+}
+
+// setErr is shared by both of the set commands, since the only thing that
+// differs in their replies is the message on success.
+func setErr(m *core.Message, usrErr error, id, lang string, wrap func(string) string) string {
+	switch usrErr {
+	case nil:
+		return m.T(id, wrap(lang))
+	case ErrUnsupported:
+		langs := List()
+		for i := range langs {
+			langs[i] = wrap(langs[i])
+		}
+		return m.T("language.set.unsupported", wrap(lang), strings.Join(langs, ", "))
+	default:
+		return m.TError(usrErr)
+	}
+}
+
+////////////
+//        //
+// delete //
+//        //
+////////////
+
+var AdvancedDelete = advancedDelete{}
+
+type advancedDelete struct{}
+
+func (c advancedDelete) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedDelete) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedDelete) Names() []string {
+	return core.AliasesDelete
+}
+
+func (advancedDelete) Description() string {
+	return "Delete the language you set, the place's language is used instead."
+}
+
+func (advancedDelete) UsageArgs() string {
+	return ""
+}
+
+func (c advancedDelete) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedDelete) Examples() []string {
+	return nil
+}
+
+func (advancedDelete) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedDelete) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedDelete) Init() error {
+	return nil
+}
+
+func (c advancedDelete) Run(m *core.Message) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return c.discord(m)
+	default:
+		return c.text(m)
+	}
+}
+
+func (c advancedDelete) discord(m *core.Message) (*dg.MessageEmbed, error, error) {
+	if err := c.core(m); err != nil {
+		return nil, nil, err
+	}
+	embed := &dg.MessageEmbed{
+		Description: m.T("language.delete"),
+	}
+	return embed, nil, nil
+}
+
+func (c advancedDelete) text(m *core.Message) (string, error, error) {
+	if err := c.core(m); err != nil {
+		return "", nil, err
+	}
+	return m.T("language.delete"), nil, nil
+}
+
+func (advancedDelete) core(m *core.Message) error {
+	author, err := m.Author.Scope()
+	if err != nil {
+		return err
+	}
+
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return err
+	}
+
+	return PersonDelete(author, here)
+}
+
+//////////
+//      //
+// list //
+//      //
+//////////
+
+var AdvancedList = advancedList{}
+
+type advancedList struct{}
+
+func (c advancedList) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedList) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedList) Names() []string {
+	return core.AliasesList
+}
+
+func (advancedList) Description() string {
+	return "List the languages that the bot can reply in."
+}
+
+func (advancedList) UsageArgs() string {
+	return ""
+}
+
+func (c advancedList) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedList) Examples() []string {
+	return nil
+}
+
+func (advancedList) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedList) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedList) Init() error {
+	return nil
+}
+
+func (c advancedList) Run(m *core.Message) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return c.discord(m)
+	default:
+		return c.text(m)
+	}
+}
+
+func (c advancedList) discord(m *core.Message) (*dg.MessageEmbed, error, error) {
+	langs := List()
+	for i := range langs {
+		langs[i] = discord.PlaceInBackticks(langs[i])
+	}
+	embed := &dg.MessageEmbed{
+		Description: m.T("language.list", strings.Join(langs, ", ")),
+	}
+	return embed, nil, nil
+}
+
+func (c advancedList) text(m *core.Message) (string, error, error) {
+	return m.T("language.list", strings.Join(List(), ", ")), nil, nil
+}
+
+///////////
+//       //
+// place //
+//       //
+///////////
+
+var AdvancedPlace = advancedPlace{}
+
+type advancedPlace struct{}
+
+func (c advancedPlace) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedPlace) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedPlace) Names() []string {
+	return []string{
+		"place",
+		"here",
+	}
+}
+
+func (advancedPlace) Description() string {
+	return "Show or change the language used in this place."
+}
+
+func (c advancedPlace) UsageArgs() string {
+	return c.Children().Usage()
+}
+
+func (c advancedPlace) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedPlace) Examples() []string {
+	return nil
+}
+
+func (advancedPlace) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedPlace) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		AdvancedPlaceShow,
+		AdvancedPlaceSet,
+		AdvancedPlaceDelete,
+	}
+}
+
+func (advancedPlace) Init() error {
+	return nil
+}
+
+func (advancedPlace) Run(m *core.Message) (any, error, error) {
+	return m.Usage(), core.ErrMissingArgs, nil
+}
+
+////////////////
+//            //
+// place show //
+//            //
+////////////////
+
+var AdvancedPlaceShow = advancedPlaceShow{}
+
+type advancedPlaceShow struct{}
+
+func (c advancedPlaceShow) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedPlaceShow) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedPlaceShow) Names() []string {
+	return core.AliasesShow
+}
+
+func (advancedPlaceShow) Description() string {
+	return "Show the language used in this place."
+}
+
+func (advancedPlaceShow) UsageArgs() string {
+	return ""
+}
+
+func (c advancedPlaceShow) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedPlaceShow) Examples() []string {
+	return nil
+}
+
+func (advancedPlaceShow) Parent() core.CommandStatic {
+	return AdvancedPlace
+}
+
+func (advancedPlaceShow) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedPlaceShow) Init() error {
+	return nil
+}
+
+func (c advancedPlaceShow) Run(m *core.Message) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return c.discord(m)
+	default:
+		return c.text(m)
+	}
+}
+
+func (c advancedPlaceShow) discord(m *core.Message) (*dg.MessageEmbed, error, error) {
+	lang, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	embed := &dg.MessageEmbed{
+		Description: m.T("language.place.show", discord.PlaceInBackticks(lang)),
+	}
+
+	return embed, nil, nil
+}
+
+func (c advancedPlaceShow) text(m *core.Message) (string, error, error) {
+	lang, err := c.core(m)
+	if err != nil {
+		return "", nil, err
+	}
+	return m.T("language.place.show", quote(lang)), nil, nil
+}
+
+func (advancedPlaceShow) core(m *core.Message) (string, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", err
+	}
+	return PlaceShow(here)
+}
+
+///////////////
+//           //
+// place set //
+//           //
+///////////////
+
+var AdvancedPlaceSet = advancedPlaceSet{}
+
+type advancedPlaceSet struct{}
+
+func (c advancedPlaceSet) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedPlaceSet) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedPlaceSet) Names() []string {
+	return []string{
+		"set",
+	}
+}
+
+func (advancedPlaceSet) Description() string {
+	return "Set the language used in this place."
+}
+
+func (advancedPlaceSet) UsageArgs() string {
+	return "<language>"
+}
+
+func (c advancedPlaceSet) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedPlaceSet) Examples() []string {
+	return []string{
+		"es",
+	}
+}
+
+func (advancedPlaceSet) Parent() core.CommandStatic {
+	return AdvancedPlace
+}
+
+func (advancedPlaceSet) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedPlaceSet) Init() error {
+	return nil
+}
+
+func (c advancedPlaceSet) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return c.discord(m)
+	default:
+		return c.text(m)
+	}
+}
+
+func (c advancedPlaceSet) discord(m *core.Message) (*dg.MessageEmbed, error, error) {
+	lang, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	embed := &dg.MessageEmbed{
+		Description: setErr(m, usrErr, "language.place.set", lang, discord.PlaceInBackticks),
+	}
+
+	return embed, usrErr, nil
+}
+
+func (c advancedPlaceSet) text(m *core.Message) (string, error, error) {
+	lang, usrErr, err := c.core(m)
+	if err != nil {
+		return "", nil, err
+	}
+	return setErr(m, usrErr, "language.place.set", lang, quote), usrErr, nil
+}
+
+func (advancedPlaceSet) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	lang, usrErr, err := PlaceSet(here, m.Command.Args[0])
+	if usrErr != nil {
+		return m.Command.Args[0], usrErr, err
+	}
+	return lang, usrErr, err
+}
+
+//////////////////
+//              //
+// place delete //
+//              //
+//////////////////
+
+var AdvancedPlaceDelete = advancedPlaceDelete{}
+
+type advancedPlaceDelete struct{}
+
+func (c advancedPlaceDelete) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedPlaceDelete) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedPlaceDelete) Names() []string {
+	return core.AliasesDelete
+}
+
+func (advancedPlaceDelete) Description() string {
+	return "Reset the language used in this place to the default one."
+}
+
+func (advancedPlaceDelete) UsageArgs() string {
+	return ""
+}
+
+func (c advancedPlaceDelete) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedPlaceDelete) Examples() []string {
+	return nil
+}
+
+func (advancedPlaceDelete) Parent() core.CommandStatic {
+	return AdvancedPlace
+}
+
+func (advancedPlaceDelete) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedPlaceDelete) Init() error {
+	return nil
+}
+
+func (c advancedPlaceDelete) Run(m *core.Message) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return c.discord(m)
+	default:
+		return c.text(m)
+	}
+}
+
+func (c advancedPlaceDelete) discord(m *core.Message) (*dg.MessageEmbed, error, error) {
+	if err := c.core(m); err != nil {
+		return nil, nil, err
+	}
+	embed := &dg.MessageEmbed{
+		Description: m.T("language.place.delete", discord.PlaceInBackticks(core.DefaultLanguage)),
+	}
+	return embed, nil, nil
+}
+
+func (c advancedPlaceDelete) text(m *core.Message) (string, error, error) {
+	if err := c.core(m); err != nil {
+		return "", nil, err
+	}
+	return m.T("language.place.delete", quote(core.DefaultLanguage)), nil, nil
+}
+
+func (advancedPlaceDelete) core(m *core.Message) error {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return err
+	}
+	return PlaceDelete(here)
+}
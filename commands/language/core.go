@@ -0,0 +1,99 @@
+package language
+
+import (
+	"embed"
+	"sort"
+
+	"github.com/janitorjeff/jeff-bot/core"
+)
+
+//go:embed locales
+var locales embed.FS
+
+var ErrUnsupported = core.NewError("language.err.unsupported")
+
+// List returns the languages that the bot has been translated to.
+func List() []string {
+	langs := core.Catalogs.Languages()
+	sort.Strings(langs)
+	return langs
+}
+
+func supported(lang string) (string, error) {
+	lang = core.NormalizeLanguage(lang)
+	if !core.Catalogs.Exists(lang) {
+		return "", ErrUnsupported
+	}
+	return lang, nil
+}
+
+func get(val any) string {
+	if lang, ok := val.(string); ok {
+		return lang
+	}
+	return ""
+}
+
+// PlaceShow returns the language used in the place, if one hasn't been set
+// then the default one is returned.
+func PlaceShow(place int64) (string, error) {
+	val, err := core.DB.SettingPlaceGet("language", place)
+	if err != nil {
+		return "", err
+	}
+	if lang := get(val); lang != "" {
+		return lang, nil
+	}
+	return core.DefaultLanguage, nil
+}
+
+// PlaceSet sets the language used in the place. Returns the language in the
+// form that it was saved in.
+func PlaceSet(place int64, lang string) (string, error, error) {
+	lang, usrErr := supported(lang)
+	if usrErr != nil {
+		return "", usrErr, nil
+	}
+	return lang, nil, core.DB.SettingPlaceSet("language", place, lang)
+}
+
+// PlaceDelete resets the place's language to the default one.
+func PlaceDelete(place int64) error {
+	return core.DB.SettingPlaceSet("language", place, nil)
+}
+
+// PersonShow returns the language that the person's replies are in, if they
+// haven't set one then the place's language is returned.
+func PersonShow(person, place int64) (string, error) {
+	val, err := core.DB.SettingPersonGet("language", person, place)
+	if err != nil {
+		return "", err
+	}
+	if lang := get(val); lang != "" {
+		return lang, nil
+	}
+	return PlaceShow(place)
+}
+
+// PersonSet sets the language that the person's replies are in, overriding
+// the place's language. Returns the language in the form that it was saved
+// in.
+func PersonSet(person, place int64, lang string) (string, error, error) {
+	lang, usrErr := supported(lang)
+	if usrErr != nil {
+		return "", usrErr, nil
+	}
+	return lang, nil, core.DB.SettingPersonSet("language", person, place, lang)
+}
+
+// PersonDelete deletes the person's language, meaning that the place's
+// language is used for them instead.
+func PersonDelete(person, place int64) error {
+	return core.DB.SettingPersonSet("language", person, place, nil)
+}
+
+func init() {
+	if err := core.Catalogs.Load(locales, "locales"); err != nil {
+		panic(err)
+	}
+}
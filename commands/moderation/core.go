@@ -0,0 +1,279 @@
+package moderation
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+	"github.com/janitorjeff/jeff-bot/frontends/twitch"
+)
+
+var (
+	ErrNotSupported     = errors.New("This action is not supported here.")
+	ErrTimeoutTooLong   = errors.New("The timeout is too long.")
+	ErrInvalidDuration  = errors.New("Expected a duration, for example 10m or 1h30m.")
+	ErrPersonNotFound   = errors.New("Couldn't find that person.")
+	ErrSelf             = errors.New("You can't do that to yourself.")
+	ErrOutranked        = errors.New("You can't do that to someone whose highest role isn't below yours.")
+	ErrUnexpectedToggle = errors.New("Expected either on or off.")
+)
+
+// DefaultTimeout is used when no duration is given.
+const DefaultTimeout = 10 * time.Minute
+
+// ParseDuration parses durations like the ones time.ParseDuration accepts with
+// the addition of days, e.g. 1d12h.
+func ParseDuration(s string) (time.Duration, error) {
+	var days int
+	if i := strings.Index(s, "d"); i != -1 {
+		var err error
+		days, err = strconv.Atoi(s[:i])
+		if err != nil {
+			return 0, ErrInvalidDuration
+		}
+		s = s[i+1:]
+	}
+
+	var dur time.Duration
+	if s != "" {
+		var err error
+		dur, err = time.ParseDuration(s)
+		if err != nil {
+			return 0, ErrInvalidDuration
+		}
+	}
+
+	dur += time.Duration(days) * 24 * time.Hour
+	if dur <= 0 {
+		return 0, ErrInvalidDuration
+	}
+	return dur, nil
+}
+
+// FormatDuration returns the duration as a string without any trailing zero
+// units, e.g. 10m instead of 10m0s.
+func FormatDuration(dur time.Duration) string {
+	if dur < time.Second {
+		return dur.String()
+	}
+	dur = dur.Round(time.Second)
+
+	h := int64(dur / time.Hour)
+	m := int64(dur % time.Hour / time.Minute)
+	sec := int64(dur % time.Minute / time.Second)
+
+	var b strings.Builder
+	if h != 0 {
+		fmt.Fprintf(&b, "%dh", h)
+	}
+	if m != 0 {
+		fmt.Fprintf(&b, "%dm", m)
+	}
+	if sec != 0 {
+		fmt.Fprintf(&b, "%ds", sec)
+	}
+	return b.String()
+}
+
+func guildID(m *core.Message) string {
+	return m.Here.(*discord.Here).GuildID
+}
+
+// checkRank returns ErrOutranked if the message's author is acting on someone
+// on discord whose highest role isn't below their own, otherwise the bot's
+// role could be used to get around discord's role hierarchy. Actions against
+// the author themselves, e.g. by automod, aren't checked.
+func checkRank(m *core.Message, id string) (error, error) {
+	if m.Author.ID() == id {
+		return nil, nil
+	}
+	ok, err := discord.Outranks(guildID(m), m.Author.ID(), id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return ErrOutranked, nil
+	}
+	return nil, nil
+}
+
+// Timeout times out the person in the place the message came from for dur.
+// Returns ErrTimeoutTooLong if dur exceeds the frontend's limit. On discord
+// returns ErrOutranked if the person's highest role isn't below the author's.
+func Timeout(m *core.Message, person int64, dur time.Duration, reason string) (error, error) {
+	id, err := core.DB.ScopeID(person)
+	if err != nil {
+		return nil, err
+	}
+
+	switch m.Frontend.Type() {
+	case twitch.Frontend.Type():
+		if dur > twitch.MaxTimeout {
+			return ErrTimeoutTooLong, nil
+		}
+		h, err := twitch.HelixChannel(m.Here.ID())
+		if err != nil {
+			return nil, err
+		}
+		return h.BanUser(m.Here.ID(), id, dur, reason)
+
+	case discord.Frontend.Type():
+		if dur > discord.MaxTimeout {
+			return ErrTimeoutTooLong, nil
+		}
+		if usrErr, err := checkRank(m, id); usrErr != nil || err != nil {
+			return usrErr, err
+		}
+		return nil, discord.Timeout(guildID(m), id, dur, reason)
+
+	default:
+		return ErrNotSupported, nil
+	}
+}
+
+// Ban permanently bans the person from the place the message came from. On
+// discord returns ErrOutranked if the person's highest role isn't below the
+// author's.
+func Ban(m *core.Message, person int64, reason string) (error, error) {
+	id, err := core.DB.ScopeID(person)
+	if err != nil {
+		return nil, err
+	}
+
+	switch m.Frontend.Type() {
+	case twitch.Frontend.Type():
+		h, err := twitch.HelixChannel(m.Here.ID())
+		if err != nil {
+			return nil, err
+		}
+		return h.BanUser(m.Here.ID(), id, 0, reason)
+
+	case discord.Frontend.Type():
+		if usrErr, err := checkRank(m, id); usrErr != nil || err != nil {
+			return usrErr, err
+		}
+		return nil, discord.Ban(guildID(m), id, reason)
+
+	default:
+		return ErrNotSupported, nil
+	}
+}
+
+// Unban removes the person's ban or timeout in the place the message came
+// from.
+func Unban(m *core.Message, person int64) (error, error) {
+	id, err := core.DB.ScopeID(person)
+	if err != nil {
+		return nil, err
+	}
+
+	switch m.Frontend.Type() {
+	case twitch.Frontend.Type():
+		h, err := twitch.HelixChannel(m.Here.ID())
+		if err != nil {
+			return nil, err
+		}
+		return h.UnbanUser(m.Here.ID(), id)
+
+	case discord.Frontend.Type():
+		return nil, discord.Unban(guildID(m), id)
+
+	default:
+		return ErrNotSupported, nil
+	}
+}
+
+// Purge deletes the person's recent messages in the place the message came
+// from. On twitch this is done with a one second timeout, which is what the
+// chat client also does.
+func Purge(m *core.Message, person int64) (error, error) {
+	id, err := core.DB.ScopeID(person)
+	if err != nil {
+		return nil, err
+	}
+
+	switch m.Frontend.Type() {
+	case twitch.Frontend.Type():
+		h, err := twitch.HelixChannel(m.Here.ID())
+		if err != nil {
+			return nil, err
+		}
+		return h.BanUser(m.Here.ID(), id, time.Second, "purge")
+
+	case discord.Frontend.Type():
+		_, err := discord.DeleteUserMessages(m.Here.ID(), id)
+		return nil, err
+
+	default:
+		return ErrNotSupported, nil
+	}
+}
+
+// Delete deletes the message itself.
+func Delete(m *core.Message) (error, error) {
+	switch m.Frontend.Type() {
+	case twitch.Frontend.Type():
+		h, err := twitch.HelixChannel(m.Here.ID())
+		if err != nil {
+			return nil, err
+		}
+		return h.DeleteChatMessages(m.Here.ID(), m.ID)
+
+	case discord.Frontend.Type():
+		return nil, discord.DeleteMessage(m.Here.ID(), m.ID)
+
+	default:
+		return ErrNotSupported, nil
+	}
+}
+
+// Clear deletes all the messages in the chat. Twitch only.
+func Clear(m *core.Message) (error, error) {
+	h, err := twitch.HelixChannel(m.Here.ID())
+	if err != nil {
+		return nil, err
+	}
+	return h.DeleteChatMessages(m.Here.ID(), "")
+}
+
+// SlowMode turns slow mode on or off, wait is the number of seconds people
+// have to wait between messages. Twitch only.
+func SlowMode(m *core.Message, on bool, wait int) (error, error) {
+	h, err := twitch.HelixChannel(m.Here.ID())
+	if err != nil {
+		return nil, err
+	}
+	settings := twitch.ChatSettings{SlowMode: &on}
+	if on {
+		settings.SlowModeWaitTime = &wait
+	}
+	return h.UpdateChatSettings(m.Here.ID(), settings)
+}
+
+// FollowersOnly turns followers-only mode on or off, dur is how long someone
+// must have been following in order to chat. Twitch only.
+func FollowersOnly(m *core.Message, on bool, dur time.Duration) (error, error) {
+	h, err := twitch.HelixChannel(m.Here.ID())
+	if err != nil {
+		return nil, err
+	}
+	settings := twitch.ChatSettings{FollowerMode: &on}
+	if on {
+		minutes := int(dur.Minutes())
+		settings.FollowerModeDuration = &minutes
+	}
+	return h.UpdateChatSettings(m.Here.ID(), settings)
+}
+
+// EmoteOnly turns emote-only mode on or off. Twitch only.
+func EmoteOnly(m *core.Message, on bool) (error, error) {
+	h, err := twitch.HelixChannel(m.Here.ID())
+	if err != nil {
+		return nil, err
+	}
+	return h.UpdateChatSettings(m.Here.ID(), twitch.ChatSettings{EmoteMode: &on})
+}
@@ -0,0 +1,29 @@
+package moderation_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/commands/moderation"
+)
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		dur      time.Duration
+		expected string
+	}{
+		{10 * time.Minute, "10m"},
+		{30 * time.Second, "30s"},
+		{20 * time.Second, "20s"},
+		{time.Hour, "1h"},
+		{90 * time.Minute, "1h30m"},
+		{90 * time.Second, "1m30s"},
+		{48*time.Hour + 5*time.Second, "48h5s"},
+	}
+
+	for _, test := range tests {
+		if got := moderation.FormatDuration(test.dur); got != test.expected {
+			t.Errorf("duration %v: expected '%s', got '%s'", test.dur, test.expected, got)
+		}
+	}
+}
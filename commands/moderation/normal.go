@@ -0,0 +1,736 @@
+package moderation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/commands/nick"
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+	"github.com/janitorjeff/jeff-bot/frontends/twitch"
+
+	dg "github.com/bwmarrin/discordgo"
+)
+
+func permitted(m *core.Message) bool {
+	switch m.Frontend.Type() {
+	case twitch.Frontend.Type(), discord.Frontend.Type():
+		return m.Author.Mod()
+	default:
+		return false
+	}
+}
+
+func permittedTwitch(m *core.Message) bool {
+	if m.Frontend.Type() != twitch.Frontend.Type() {
+		return false
+	}
+	return m.Author.Mod()
+}
+
+func render(m *core.Message, resp string, usrErr error) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return &dg.MessageEmbed{Description: resp}, usrErr, nil
+	default:
+		return resp, usrErr, nil
+	}
+}
+
+// parsePerson returns the person's scope, returns ErrPersonNotFound if they
+// couldn't be found and ErrSelf if the person is the author.
+func parsePerson(m *core.Message, s string) (int64, error, error) {
+	person, err := nick.ParsePersonHere(m, s)
+	if err != nil {
+		return -1, ErrPersonNotFound, nil
+	}
+
+	author, err := m.Author.Scope()
+	if err != nil {
+		return -1, nil, err
+	}
+	if person == author {
+		return -1, ErrSelf, nil
+	}
+
+	return person, nil, nil
+}
+
+func isOn(s string) bool {
+	for _, a := range core.AliasesOn {
+		if s == a {
+			return true
+		}
+	}
+	return false
+}
+
+func isOff(s string) bool {
+	for _, a := range core.AliasesOff {
+		if s == a {
+			return true
+		}
+	}
+	return false
+}
+
+/////////////
+//         //
+// timeout //
+//         //
+/////////////
+
+var NormalTimeout = normalTimeout{}
+
+type normalTimeout struct{}
+
+func (normalTimeout) Type() core.CommandType {
+	return core.Normal
+}
+
+func (normalTimeout) Permitted(m *core.Message) bool {
+	return permitted(m)
+}
+
+func (normalTimeout) Names() []string {
+	return []string{
+		"timeout",
+		"to",
+	}
+}
+
+func (normalTimeout) Description() string {
+	return "Time someone out."
+}
+
+func (normalTimeout) UsageArgs() string {
+	return "<person> [duration] [reason...]"
+}
+
+func (normalTimeout) Category() core.CommandCategory {
+	return core.CommandCategoryModerators
+}
+
+func (normalTimeout) Examples() []string {
+	return []string{
+		"@person",
+		"@person 10m",
+		"@person 1h spamming",
+	}
+}
+
+func (normalTimeout) Parent() core.CommandStatic {
+	return nil
+}
+
+func (normalTimeout) Children() core.CommandsStatic {
+	return nil
+}
+
+func (normalTimeout) Init() error {
+	return nil
+}
+
+func (c normalTimeout) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (normalTimeout) core(m *core.Message) (string, error, error) {
+	target := m.Command.Args[0]
+
+	person, usrErr, err := parsePerson(m, target)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+
+	dur := DefaultTimeout
+	var reason string
+	if len(m.Command.Args) > 1 {
+		if d, err := ParseDuration(m.Command.Args[1]); err == nil {
+			dur = d
+			reason = m.RawArgs(2)
+		} else {
+			reason = m.RawArgs(1)
+		}
+	}
+
+	usrErr, err = Timeout(m, person, dur, reason)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	return fmt.Sprintf("Timed out %s for %s.", target, FormatDuration(dur)), nil, nil
+}
+
+/////////
+//     //
+// ban //
+//     //
+/////////
+
+var NormalBan = normalBan{}
+
+type normalBan struct{}
+
+func (normalBan) Type() core.CommandType {
+	return core.Normal
+}
+
+func (normalBan) Permitted(m *core.Message) bool {
+	return permitted(m)
+}
+
+func (normalBan) Names() []string {
+	return []string{
+		"ban",
+	}
+}
+
+func (normalBan) Description() string {
+	return "Ban someone."
+}
+
+func (normalBan) UsageArgs() string {
+	return "<person> [reason...]"
+}
+
+func (normalBan) Category() core.CommandCategory {
+	return core.CommandCategoryModerators
+}
+
+func (normalBan) Examples() []string {
+	return []string{
+		"@person",
+		"@person being rude",
+	}
+}
+
+func (normalBan) Parent() core.CommandStatic {
+	return nil
+}
+
+func (normalBan) Children() core.CommandsStatic {
+	return nil
+}
+
+func (normalBan) Init() error {
+	return nil
+}
+
+func (c normalBan) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (normalBan) core(m *core.Message) (string, error, error) {
+	target := m.Command.Args[0]
+
+	person, usrErr, err := parsePerson(m, target)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+
+	usrErr, err = Ban(m, person, m.RawArgs(1))
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	return fmt.Sprintf("Banned %s.", target), nil, nil
+}
+
+///////////
+//       //
+// unban //
+//       //
+///////////
+
+var NormalUnban = normalUnban{}
+
+type normalUnban struct{}
+
+func (normalUnban) Type() core.CommandType {
+	return core.Normal
+}
+
+func (normalUnban) Permitted(m *core.Message) bool {
+	return permitted(m)
+}
+
+func (normalUnban) Names() []string {
+	return []string{
+		"unban",
+		"untimeout",
+	}
+}
+
+func (normalUnban) Description() string {
+	return "Remove someone's ban or timeout."
+}
+
+func (normalUnban) UsageArgs() string {
+	return "<person>"
+}
+
+func (normalUnban) Category() core.CommandCategory {
+	return core.CommandCategoryModerators
+}
+
+func (normalUnban) Examples() []string {
+	return []string{
+		"@person",
+	}
+}
+
+func (normalUnban) Parent() core.CommandStatic {
+	return nil
+}
+
+func (normalUnban) Children() core.CommandsStatic {
+	return nil
+}
+
+func (normalUnban) Init() error {
+	return nil
+}
+
+func (c normalUnban) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (normalUnban) core(m *core.Message) (string, error, error) {
+	target := m.Command.Args[0]
+
+	person, usrErr, err := parsePerson(m, target)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+
+	usrErr, err = Unban(m, person)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	return fmt.Sprintf("Unbanned %s.", target), nil, nil
+}
+
+///////////
+//       //
+// purge //
+//       //
+///////////
+
+var NormalPurge = normalPurge{}
+
+type normalPurge struct{}
+
+func (normalPurge) Type() core.CommandType {
+	return core.Normal
+}
+
+func (normalPurge) Permitted(m *core.Message) bool {
+	return permitted(m)
+}
+
+func (normalPurge) Names() []string {
+	return []string{
+		"purge",
+	}
+}
+
+func (normalPurge) Description() string {
+	return "Delete someone's recent messages."
+}
+
+func (normalPurge) UsageArgs() string {
+	return "<person>"
+}
+
+func (normalPurge) Category() core.CommandCategory {
+	return core.CommandCategoryModerators
+}
+
+func (normalPurge) Examples() []string {
+	return []string{
+		"@person",
+	}
+}
+
+func (normalPurge) Parent() core.CommandStatic {
+	return nil
+}
+
+func (normalPurge) Children() core.CommandsStatic {
+	return nil
+}
+
+func (normalPurge) Init() error {
+	return nil
+}
+
+func (c normalPurge) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (normalPurge) core(m *core.Message) (string, error, error) {
+	target := m.Command.Args[0]
+
+	person, usrErr, err := parsePerson(m, target)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+
+	usrErr, err = Purge(m, person)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	return fmt.Sprintf("Purged %s's messages.", target), nil, nil
+}
+
+///////////
+//       //
+// clear //
+//       //
+///////////
+
+var NormalClear = normalClear{}
+
+type normalClear struct{}
+
+func (normalClear) Type() core.CommandType {
+	return core.Normal
+}
+
+func (normalClear) Permitted(m *core.Message) bool {
+	return permittedTwitch(m)
+}
+
+func (normalClear) Names() []string {
+	return []string{
+		"clear",
+	}
+}
+
+func (normalClear) Description() string {
+	return "Delete all the messages in the chat."
+}
+
+func (normalClear) UsageArgs() string {
+	return ""
+}
+
+func (normalClear) Category() core.CommandCategory {
+	return core.CommandCategoryModerators
+}
+
+func (normalClear) Examples() []string {
+	return nil
+}
+
+func (normalClear) Parent() core.CommandStatic {
+	return nil
+}
+
+func (normalClear) Children() core.CommandsStatic {
+	return nil
+}
+
+func (normalClear) Init() error {
+	return nil
+}
+
+func (normalClear) Run(m *core.Message) (any, error, error) {
+	usrErr, err := Clear(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+	return "Cleared the chat.", nil, nil
+}
+
+//////////
+//      //
+// slow //
+//      //
+//////////
+
+var NormalSlow = normalSlow{}
+
+type normalSlow struct{}
+
+func (normalSlow) Type() core.CommandType {
+	return core.Normal
+}
+
+func (normalSlow) Permitted(m *core.Message) bool {
+	return permittedTwitch(m)
+}
+
+func (normalSlow) Names() []string {
+	return []string{
+		"slow",
+		"slowmode",
+	}
+}
+
+func (normalSlow) Description() string {
+	return "Turn slow mode on or off."
+}
+
+func (normalSlow) UsageArgs() string {
+	return "[seconds | off]"
+}
+
+func (normalSlow) Category() core.CommandCategory {
+	return core.CommandCategoryModerators
+}
+
+func (normalSlow) Examples() []string {
+	return []string{
+		"",
+		"60",
+		"off",
+	}
+}
+
+func (normalSlow) Parent() core.CommandStatic {
+	return nil
+}
+
+func (normalSlow) Children() core.CommandsStatic {
+	return nil
+}
+
+func (normalSlow) Init() error {
+	return nil
+}
+
+func (normalSlow) Run(m *core.Message) (any, error, error) {
+	on := true
+	wait := 30
+
+	if len(m.Command.Args) > 0 {
+		arg := strings.ToLower(m.Command.Args[0])
+		if isOff(arg) {
+			on = false
+		} else if n, err := strconv.Atoi(arg); err == nil {
+			wait = n
+		} else {
+			return m.Usage(), core.ErrMissingArgs, nil
+		}
+	}
+
+	usrErr, err := SlowMode(m, on, wait)
+	if err != nil {
+		return nil, nil, err
+	}
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+
+	if !on {
+		return "Slow mode is off.", nil, nil
+	}
+	return fmt.Sprintf("Slow mode is on, %d seconds between messages.", wait), nil, nil
+}
+
+///////////////
+//           //
+// followers //
+//           //
+///////////////
+
+var NormalFollowers = normalFollowers{}
+
+type normalFollowers struct{}
+
+func (normalFollowers) Type() core.CommandType {
+	return core.Normal
+}
+
+func (normalFollowers) Permitted(m *core.Message) bool {
+	return permittedTwitch(m)
+}
+
+func (normalFollowers) Names() []string {
+	return []string{
+		"followers",
+		"followersonly",
+	}
+}
+
+func (normalFollowers) Description() string {
+	return "Turn followers-only mode on or off."
+}
+
+func (normalFollowers) UsageArgs() string {
+	return "[duration | off]"
+}
+
+func (normalFollowers) Category() core.CommandCategory {
+	return core.CommandCategoryModerators
+}
+
+func (normalFollowers) Examples() []string {
+	return []string{
+		"",
+		"10m",
+		"off",
+	}
+}
+
+func (normalFollowers) Parent() core.CommandStatic {
+	return nil
+}
+
+func (normalFollowers) Children() core.CommandsStatic {
+	return nil
+}
+
+func (normalFollowers) Init() error {
+	return nil
+}
+
+func (normalFollowers) Run(m *core.Message) (any, error, error) {
+	on := true
+	var dur time.Duration
+
+	if len(m.Command.Args) > 0 {
+		arg := strings.ToLower(m.Command.Args[0])
+		if isOff(arg) {
+			on = false
+		} else if d, err := ParseDuration(arg); err == nil {
+			dur = d
+		} else {
+			return fmt.Sprint(err), err, nil
+		}
+	}
+
+	usrErr, err := FollowersOnly(m, on, dur)
+	if err != nil {
+		return nil, nil, err
+	}
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+
+	if !on {
+		return "Followers-only mode is off.", nil, nil
+	}
+	if dur == 0 {
+		return "Followers-only mode is on.", nil, nil
+	}
+	return fmt.Sprintf("Followers-only mode is on, people must have followed for %s.", FormatDuration(dur)), nil, nil
+}
+
+///////////////
+//           //
+// emoteonly //
+//           //
+///////////////
+
+var NormalEmoteOnly = normalEmoteOnly{}
+
+type normalEmoteOnly struct{}
+
+func (normalEmoteOnly) Type() core.CommandType {
+	return core.Normal
+}
+
+func (normalEmoteOnly) Permitted(m *core.Message) bool {
+	return permittedTwitch(m)
+}
+
+func (normalEmoteOnly) Names() []string {
+	return []string{
+		"emoteonly",
+		"emotes",
+	}
+}
+
+func (normalEmoteOnly) Description() string {
+	return "Turn emote-only mode on or off."
+}
+
+func (normalEmoteOnly) UsageArgs() string {
+	return "(on | off)"
+}
+
+func (normalEmoteOnly) Category() core.CommandCategory {
+	return core.CommandCategoryModerators
+}
+
+func (normalEmoteOnly) Examples() []string {
+	return []string{
+		"on",
+		"off",
+	}
+}
+
+func (normalEmoteOnly) Parent() core.CommandStatic {
+	return nil
+}
+
+func (normalEmoteOnly) Children() core.CommandsStatic {
+	return nil
+}
+
+func (normalEmoteOnly) Init() error {
+	return nil
+}
+
+func (normalEmoteOnly) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+
+	var on bool
+	switch arg := strings.ToLower(m.Command.Args[0]); {
+	case isOn(arg):
+		on = true
+	case isOff(arg):
+		on = false
+	default:
+		return fmt.Sprint(ErrUnexpectedToggle), ErrUnexpectedToggle, nil
+	}
+
+	usrErr, err := EmoteOnly(m, on)
+	if err != nil {
+		return nil, nil, err
+	}
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+
+	if on {
+		return "Emote-only mode is on.", nil, nil
+	}
+	return "Emote-only mode is off.", nil, nil
+}
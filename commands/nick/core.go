@@ -2,8 +2,11 @@ package nick
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+	"github.com/janitorjeff/jeff-bot/frontends/twitch"
 
 	"github.com/rs/zerolog/log"
 )
@@ -95,6 +98,33 @@ func Delete(person, place int64) error {
 	return core.DB.SettingPersonSet("cmd_nick_nick", person, place, nil)
 }
 
+// Name returns a name that can be used to refer to the person in the specified
+// place, for example in leaderboards. Uses the person's nickname if they have
+// one, otherwise whatever their frontend offers.
+func Name(person, place int64) (string, error) {
+	if nick, usrErr, err := Show(person, place); usrErr == nil && err == nil {
+		return nick, nil
+	}
+
+	frontend, err := core.DB.ScopeFrontend(person)
+	if err != nil {
+		return "", err
+	}
+
+	switch core.FrontendType(frontend) {
+	case twitch.Frontend.Type():
+		return twitch.ChannelName(person)
+	case discord.Frontend.Type():
+		id, err := core.DB.ScopeID(person)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("<@%s>", id), nil
+	default:
+		return core.DB.ScopeID(person)
+	}
+}
+
 // Tries to find a person from the given string. If "me" is passed the author
 // is returned. Then tries to match a nickname and if it fails it tries various
 // platform specific things (checking if the string is a mention of some sort,
@@ -0,0 +1,290 @@
+package overlay
+
+import (
+	"strings"
+
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+
+	dg "github.com/bwmarrin/discordgo"
+)
+
+func render(m *core.Message, resp string, usrErr error) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return &dg.MessageEmbed{Description: resp}, usrErr, nil
+	default:
+		return resp, usrErr, nil
+	}
+}
+
+// sendURLs sends the overlay links to the author. On discord they are sent in
+// a DM since anyone with the token can connect, elsewhere there's no private
+// way to send them so they are posted in the chat.
+func sendURLs(m *core.Message, token string) (any, error, error) {
+	var lines []string
+	for _, p := range Pages {
+		lines = append(lines, p+": "+URL(token, p))
+	}
+
+	if m.Frontend.Type() != discord.Frontend.Type() {
+		return strings.Join(lines, " | ") + " (anyone with these links can connect, use $overlay reset if they leak)", nil, nil
+	}
+
+	text := "Add these as browser sources in OBS:\n" + strings.Join(lines, "\n")
+	if err := discord.DM(m.Author.ID(), text); err != nil {
+		return nil, nil, err
+	}
+	return render(m, "Sent you the overlay links in a DM.", nil)
+}
+
+var Advanced = advanced{}
+
+type advanced struct{}
+
+func (advanced) Type() core.CommandType {
+	return core.Advanced
+}
+
+func (advanced) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advanced) Names() []string {
+	return []string{
+		"overlay",
+		"overlays",
+	}
+}
+
+func (advanced) Description() string {
+	return "Stream overlays that can be added as OBS browser sources."
+}
+
+func (c advanced) UsageArgs() string {
+	return c.Children().Usage()
+}
+
+func (advanced) Category() core.CommandCategory {
+	return core.CommandCategoryOther
+}
+
+func (advanced) Examples() []string {
+	return nil
+}
+
+func (advanced) Parent() core.CommandStatic {
+	return nil
+}
+
+func (advanced) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		AdvancedURL,
+		AdvancedReset,
+		AdvancedAlert,
+	}
+}
+
+func (advanced) Init() error {
+	return nil
+}
+
+func (advanced) Run(m *core.Message) (any, error, error) {
+	return m.Usage(), core.ErrMissingArgs, nil
+}
+
+/////////
+//     //
+// url //
+//     //
+/////////
+
+var AdvancedURL = advancedURL{}
+
+type advancedURL struct{}
+
+func (c advancedURL) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedURL) Permitted(m *core.Message) bool {
+	return m.Author.Admin()
+}
+
+func (advancedURL) Names() []string {
+	return []string{
+		"url",
+		"urls",
+		"links",
+	}
+}
+
+func (advancedURL) Description() string {
+	return "Get the links of the overlays."
+}
+
+func (advancedURL) UsageArgs() string {
+	return ""
+}
+
+func (c advancedURL) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedURL) Examples() []string {
+	return nil
+}
+
+func (advancedURL) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedURL) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedURL) Init() error {
+	return nil
+}
+
+func (advancedURL) Run(m *core.Message) (any, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return nil, nil, err
+	}
+	token, err := TokenGet(here)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sendURLs(m, token)
+}
+
+///////////
+//       //
+// reset //
+//       //
+///////////
+
+var AdvancedReset = advancedReset{}
+
+type advancedReset struct{}
+
+func (c advancedReset) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedReset) Permitted(m *core.Message) bool {
+	return m.Author.Admin()
+}
+
+func (advancedReset) Names() []string {
+	return []string{
+		"reset",
+	}
+}
+
+func (advancedReset) Description() string {
+	return "Generate new overlay links, the old ones stop working."
+}
+
+func (advancedReset) UsageArgs() string {
+	return ""
+}
+
+func (c advancedReset) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedReset) Examples() []string {
+	return nil
+}
+
+func (advancedReset) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedReset) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedReset) Init() error {
+	return nil
+}
+
+func (advancedReset) Run(m *core.Message) (any, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return nil, nil, err
+	}
+	token, err := TokenReset(here)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sendURLs(m, token)
+}
+
+///////////
+//       //
+// alert //
+//       //
+///////////
+
+var AdvancedAlert = advancedAlert{}
+
+type advancedAlert struct{}
+
+func (c advancedAlert) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedAlert) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedAlert) Names() []string {
+	return []string{
+		"alert",
+	}
+}
+
+func (advancedAlert) Description() string {
+	return "Show some text in the alerts overlay."
+}
+
+func (advancedAlert) UsageArgs() string {
+	return "<text...>"
+}
+
+func (c advancedAlert) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedAlert) Examples() []string {
+	return []string{
+		"Thanks for 100 followers!",
+	}
+}
+
+func (advancedAlert) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedAlert) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedAlert) Init() error {
+	return nil
+}
+
+func (advancedAlert) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return nil, nil, err
+	}
+	Alert(here, m.RawArgs(0))
+	return render(m, "Sent the alert.", nil)
+}
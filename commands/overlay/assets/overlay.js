@@ -0,0 +1,27 @@
+// Connects to the bot using the token in the page's address and calls
+// handlers[event.type](event.data) for every event that is received. If the
+// connection drops it is retried every few seconds.
+function overlay(handlers) {
+	const token = new URLSearchParams(location.search).get("token");
+	const scheme = location.protocol === "https:" ? "wss:" : "ws:";
+	const url = scheme + "//" + location.host + "/overlay/ws?token=" + encodeURIComponent(token);
+
+	function connect() {
+		const ws = new WebSocket(url);
+		ws.onmessage = (msg) => {
+			const event = JSON.parse(msg.data);
+			if (handlers[event.type]) {
+				handlers[event.type](event.data);
+			}
+		};
+		ws.onclose = () => setTimeout(connect, 5000);
+	}
+	connect();
+}
+
+// Shows the element for the specified number of milliseconds.
+function flash(el, ms) {
+	el.classList.add("visible");
+	clearTimeout(el.timeout);
+	el.timeout = setTimeout(() => el.classList.remove("visible"), ms);
+}
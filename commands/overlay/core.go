@@ -0,0 +1,168 @@
+package overlay
+
+import (
+	"crypto/rand"
+	"embed"
+	"encoding/base64"
+	"io/fs"
+	"net/http"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+)
+
+// Pages are the names of the default overlays, each one is served as
+// /overlay/<name>.html and only shows the events that concern it.
+var Pages = []string{
+	"alerts",
+	"tts",
+	"nowplaying",
+	"poll",
+	"counters",
+	"giveaway",
+}
+
+const (
+	// how often a ping is sent, so that dead connections get noticed
+	pingInterval = 30 * time.Second
+	writeTimeout = 10 * time.Second
+)
+
+//go:embed assets
+var assets embed.FS
+
+var upgrader = websocket.Upgrader{
+	// Browser sources are usually local files and so have no useful origin,
+	// the token is what authenticates the connection.
+	CheckOrigin: func(*http.Request) bool {
+		return true
+	},
+}
+
+func generate() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// TokenGet returns the place's secret overlay token, if it doesn't have one yet
+// then it is generated.
+func TokenGet(place int64) (string, error) {
+	token, err := core.DB.SettingPlaceGet("cmd_overlay_token", place)
+	if err != nil {
+		return "", err
+	}
+	if token.(string) != "" {
+		return token.(string), nil
+	}
+	return TokenReset(place)
+}
+
+// TokenReset generates a new token for the place, overlays that use the old
+// one can no longer connect.
+func TokenReset(place int64) (string, error) {
+	token, err := generate()
+	if err != nil {
+		return "", err
+	}
+	return token, core.DB.SettingPlaceSet("cmd_overlay_token", place, token)
+}
+
+// URL returns the address of the page that should be added as a browser
+// source.
+func URL(token, page string) string {
+	return "https://" + core.VirtualHost + "/overlay/" + page + ".html?token=" + token
+}
+
+// Alert shows the text in the place's alert overlays.
+func Alert(place int64, text string) {
+	core.Overlay.Publish(place, core.OverlayAlert, map[string]string{
+		"text": text,
+	})
+}
+
+// serve upgrades the connection to a websocket and sends the events of the
+// place the token belongs to until the overlay disconnects.
+func serve(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		return
+	}
+
+	place, err := dbPlace(token)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Debug().Err(err).Msg("failed to upgrade overlay connection")
+		return
+	}
+	defer conn.Close()
+
+	id, events := core.Overlay.Subscribe(place)
+	defer core.Overlay.Unsubscribe(place, id)
+
+	log.Debug().Int64("place", place).Int("id", id).Msg("overlay connected")
+
+	// overlays don't send anything, but reading is needed in order to notice
+	// when the connection is closed
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for _, e := range core.Overlay.Initial(place) {
+		conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := conn.WriteJSON(e); err != nil {
+			return
+		}
+	}
+
+	ping := time.NewTicker(pingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case e := <-events:
+			conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteJSON(e); err != nil {
+				return
+			}
+		case <-ping.C:
+			conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-closed:
+			log.Debug().Int64("place", place).Int("id", id).Msg("overlay disconnected")
+			return
+		}
+	}
+}
+
+func init() {
+	static, err := fs.Sub(assets, "assets")
+	if err != nil {
+		panic(err)
+	}
+
+	core.Gin.GET("/overlay/ws", serve)
+	core.Gin.GET("/overlay/:file", func(c *gin.Context) {
+		c.FileFromFS(c.Param("file"), http.FS(static))
+	})
+}
@@ -0,0 +1,29 @@
+package overlay
+
+import (
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/rs/zerolog/log"
+)
+
+// dbPlace returns the place that the token belongs to, returns sql.ErrNoRows
+// if it doesn't belong to any.
+func dbPlace(token string) (int64, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	var place int64
+	err := db.DB.QueryRow(`
+		SELECT place
+		FROM settings_place
+		WHERE cmd_overlay_token = $1
+	`, token).Scan(&place)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Msg("got overlay token's place")
+
+	return place, err
+}
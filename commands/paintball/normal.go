@@ -3,8 +3,8 @@ package paintball
 import (
 	"fmt"
 	"strconv"
-	"time"
 
+	"github.com/janitorjeff/jeff-bot/commands/trivia"
 	"github.com/janitorjeff/jeff-bot/core"
 	"github.com/janitorjeff/jeff-bot/frontends/discord"
 
@@ -19,10 +19,7 @@ func (normal) Type() core.CommandType {
 	return core.Normal
 }
 
-func (normal) Permitted(m *core.Message) bool {
-	if m.Frontend.Type() != discord.Frontend.Type() {
-		return false
-	}
+func (normal) Permitted(*core.Message) bool {
 	return true
 }
 
@@ -57,7 +54,6 @@ func (normal) Children() core.CommandsStatic {
 }
 
 func (normal) Init() error {
-	game = createGame()
 	movies = readMovies()
 	fakeMovies = readFakeMovies()
 	return nil
@@ -77,75 +73,63 @@ var normalHelp = &dg.MessageEmbed{
 	Color: embedColor,
 }
 
-func (c normal) Run(m *core.Message) (any, error, error) {
-	switch m.Frontend.Type() {
-	case discord.Frontend.Type():
-		return c.discord(m)
-	default:
-		return nil, nil, fmt.Errorf("Discord only")
-	}
-}
+const normalHelpText = "Paintball is a game of speed and knowledge. " +
+	"Be the first to answer the movie question correctly! " +
+	"Start a game with !pb <rounds>, UP TO 15 rounds."
 
-func (c normal) discord(m *core.Message) (*dg.MessageEmbed, error, error) {
+func (c normal) Run(m *core.Message) (any, error, error) {
 	if len(m.Command.Args) < 1 {
-		return normalHelp, core.ErrMissingArgs, nil
+		return c.help(m)
 	}
-	return c.play(m)
-}
-
-func (c normal) play(m *core.Message) (*dg.MessageEmbed, error, error) {
 	rounds, err := strconv.Atoi(m.Command.Args[0])
+	if err != nil || rounds < 1 || rounds > trivia.MaxRounds {
+		return c.help(m)
+	}
+
+	here, err := m.Here.ScopeLogical()
 	if err != nil {
-		return normalHelp, nil, nil
+		return nil, nil, err
 	}
-	if rounds < 1 || rounds > 15 {
-		return normalHelp, nil, nil
+	timeout, err := trivia.TimeoutGet(here)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// only discord can show the posters
+	images := m.Frontend.Type() == discord.Frontend.Type()
+
+	g := trivia.Game{
+		Name:    "Free-For-All",
+		Rounds:  rounds,
+		Timeout: timeout,
+		Next: func(int) trivia.Question {
+			return generateQuestion(images)
+		},
+		Color:     embedColor,
+		Thumbnail: assetQuestion,
+		Footer:    "Want to play Paintball? Enter: !pb",
 	}
 
-	here, err := m.Here.ScopeExact()
+	usrErr, err := trivia.Play(m, g)
 	if err != nil {
 		return nil, nil, err
 	}
-
-	if game.Active(here) == true {
-		resp := &dg.MessageEmbed{
-			Description: "A game is already active.",
-			Color:       embedColor,
+	if usrErr != nil {
+		switch m.Frontend.Type() {
+		case discord.Frontend.Type():
+			return &dg.MessageEmbed{Description: fmt.Sprint(usrErr), Color: embedColor}, usrErr, nil
+		default:
+			return fmt.Sprint(usrErr), usrErr, nil
 		}
-		return resp, nil, nil
 	}
-
-	game.Playing(here, true)
-	return c.playF(m.Here.ID(), here, rounds)
+	return nil, nil, core.ErrSilence
 }
 
-func (normal) playF(channel string, here int64, rounds int) (*dg.MessageEmbed, error, error) {
-	write(channel, &dg.MessageEmbed{
-		Title:       "🔥 **Free-For-All** 🔥",
-		Description: "Game starting in a few seconds!",
-	})
-
-	for r := 1; r <= rounds; r++ {
-		// give some time for information to be processed by the players
-		time.Sleep(interval)
-
-		question, answers, poster := generateQuestion(r)
-		write(channel, question)
-
-		answer := awaitAnswer(here, answers)
-		var name string
-		if answer == nil {
-			name = ""
-		} else {
-			name = answer.Author.DisplayName()
-			game.Point(here, answer.Author)
-		}
-
-		resp := generateAnswer(r, name, poster, answers, r == rounds)
-		write(channel, resp)
+func (normal) help(m *core.Message) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return normalHelp, core.ErrMissingArgs, nil
+	default:
+		return normalHelpText, core.ErrMissingArgs, nil
 	}
-
-	write(channel, generateScorecard(game.Scores(here)))
-	game.Playing(here, false)
-	return nil, nil, core.ErrSilence
 }
@@ -0,0 +1,801 @@
+package points
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/commands/nick"
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+
+	dg "github.com/bwmarrin/discordgo"
+)
+
+func render(m *core.Message, resp string, usrErr error) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return &dg.MessageEmbed{Description: resp}, usrErr, nil
+	default:
+		return resp, usrErr, nil
+	}
+}
+
+func parseAmount(s string) (int64, error) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, ErrInvalidAmount
+	}
+	return n, nil
+}
+
+var Advanced = advanced{}
+
+type advanced struct{}
+
+func (advanced) Type() core.CommandType {
+	return core.Advanced
+}
+
+func (advanced) Permitted(*core.Message) bool {
+	return true
+}
+
+func (advanced) Names() []string {
+	return []string{
+		"points",
+		"pts",
+	}
+}
+
+func (advanced) Description() string {
+	return "Earn points by chatting and watching."
+}
+
+func (c advanced) UsageArgs() string {
+	return c.Children().Usage()
+}
+
+func (advanced) Category() core.CommandCategory {
+	return core.CommandCategoryOther
+}
+
+func (advanced) Examples() []string {
+	return nil
+}
+
+func (advanced) Parent() core.CommandStatic {
+	return nil
+}
+
+func (advanced) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		AdvancedShow,
+		AdvancedGive,
+		AdvancedTop,
+		AdvancedAdd,
+		AdvancedRemove,
+		AdvancedSet,
+		AdvancedChat,
+		AdvancedWatch,
+	}
+}
+
+func (advanced) Init() error {
+	core.Hooks.Register(hook)
+
+	go func() {
+		for {
+			time.Sleep(watchInterval)
+			watch()
+		}
+	}()
+
+	return nil
+}
+
+func (advanced) Run(m *core.Message) (any, error, error) {
+	return m.Usage(), core.ErrMissingArgs, nil
+}
+
+//////////
+//      //
+// show //
+//      //
+//////////
+
+var AdvancedShow = advancedShow{}
+
+type advancedShow struct{}
+
+func (c advancedShow) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedShow) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedShow) Names() []string {
+	return core.AliasesShow
+}
+
+func (advancedShow) Description() string {
+	return "Show how many points someone has."
+}
+
+func (advancedShow) UsageArgs() string {
+	return "[person]"
+}
+
+func (c advancedShow) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedShow) Examples() []string {
+	return nil
+}
+
+func (advancedShow) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedShow) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedShow) Init() error {
+	return nil
+}
+
+func (c advancedShow) Run(m *core.Message) (any, error, error) {
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedShow) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var target string
+	var person int64
+	if len(m.Command.Args) == 0 {
+		target = m.Author.DisplayName()
+		person, err = m.Author.Scope()
+		if err != nil {
+			return "", nil, err
+		}
+	} else {
+		target = m.Command.Args[0]
+		person, err = nick.ParsePerson(m, here, target)
+		if err != nil {
+			return fmt.Sprint(ErrPersonNotFound), ErrPersonNotFound, nil
+		}
+	}
+
+	points, err := Get(here, person)
+	if err != nil {
+		return "", nil, err
+	}
+	rank, err := Rank(here, person)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s has %d points and is rank #%d.", target, points, rank), nil, nil
+}
+
+//////////
+//      //
+// give //
+//      //
+//////////
+
+var AdvancedGive = advancedGive{}
+
+type advancedGive struct{}
+
+func (c advancedGive) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedGive) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedGive) Names() []string {
+	return []string{
+		"give",
+	}
+}
+
+func (advancedGive) Description() string {
+	return "Give some of your points to someone."
+}
+
+func (advancedGive) UsageArgs() string {
+	return "<person> <amount>"
+}
+
+func (c advancedGive) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedGive) Examples() []string {
+	return nil
+}
+
+func (advancedGive) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedGive) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedGive) Init() error {
+	return nil
+}
+
+func (c advancedGive) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 2 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedGive) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+	target := m.Command.Args[0]
+	person, err := nick.ParsePerson(m, here, target)
+	if err != nil {
+		return fmt.Sprint(ErrPersonNotFound), ErrPersonNotFound, nil
+	}
+	amount, usrErr := parseAmount(m.Command.Args[1])
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+
+	author, err := m.Author.Scope()
+	if err != nil {
+		return "", nil, err
+	}
+
+	usrErr, err = Transfer(here, author, person, amount)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	return fmt.Sprintf("Gave %d points to %s.", amount, target), nil, nil
+}
+
+/////////
+//     //
+// top //
+//     //
+/////////
+
+var AdvancedTop = advancedTop{}
+
+type advancedTop struct{}
+
+func (c advancedTop) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedTop) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedTop) Names() []string {
+	return []string{
+		"top",
+		"leaderboard",
+		"lb",
+	}
+}
+
+func (advancedTop) Description() string {
+	return "Show the people with the most points."
+}
+
+func (advancedTop) UsageArgs() string {
+	return ""
+}
+
+func (c advancedTop) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedTop) Examples() []string {
+	return nil
+}
+
+func (advancedTop) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedTop) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedTop) Init() error {
+	return nil
+}
+
+func (c advancedTop) Run(m *core.Message) (any, error, error) {
+	lines, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(lines) == 0 {
+		return render(m, "Nobody has any points yet.", nil)
+	}
+
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		embed := &dg.MessageEmbed{
+			Title:       "Leaderboard",
+			Description: strings.Join(lines, "\n"),
+		}
+		return embed, nil, nil
+	default:
+		return strings.Join(lines, " | "), nil, nil
+	}
+}
+
+func (advancedTop) core(m *core.Message) ([]string, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return nil, err
+	}
+
+	top, err := Top(here)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for i, b := range top {
+		name, err := nick.Name(b.Person, here)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, fmt.Sprintf("#%d %s: %d", i+1, name, b.Points))
+	}
+	return lines, nil
+}
+
+/////////
+//     //
+// add //
+//     //
+/////////
+
+var AdvancedAdd = advancedAdd{}
+
+type advancedAdd struct{}
+
+func (c advancedAdd) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedAdd) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedAdd) Names() []string {
+	return core.AliasesAdd
+}
+
+func (advancedAdd) Description() string {
+	return "Give someone points."
+}
+
+func (advancedAdd) UsageArgs() string {
+	return "<person> <amount>"
+}
+
+func (c advancedAdd) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedAdd) Examples() []string {
+	return nil
+}
+
+func (advancedAdd) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedAdd) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedAdd) Init() error {
+	return nil
+}
+
+func (c advancedAdd) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 2 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedAdd) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+	target := m.Command.Args[0]
+	person, err := nick.ParsePerson(m, here, target)
+	if err != nil {
+		return fmt.Sprint(ErrPersonNotFound), ErrPersonNotFound, nil
+	}
+	amount, usrErr := parseAmount(m.Command.Args[1])
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+
+	points, err := Credit(here, person, amount, "add")
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("Added %d points to %s, they now have %d.", amount, target, points), nil, nil
+}
+
+////////////
+//        //
+// remove //
+//        //
+////////////
+
+var AdvancedRemove = advancedRemove{}
+
+type advancedRemove struct{}
+
+func (c advancedRemove) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedRemove) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedRemove) Names() []string {
+	return []string{
+		"remove",
+		"rm",
+		"take",
+	}
+}
+
+func (advancedRemove) Description() string {
+	return "Take points from someone."
+}
+
+func (advancedRemove) UsageArgs() string {
+	return "<person> <amount>"
+}
+
+func (c advancedRemove) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedRemove) Examples() []string {
+	return nil
+}
+
+func (advancedRemove) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedRemove) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedRemove) Init() error {
+	return nil
+}
+
+func (c advancedRemove) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 2 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedRemove) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+	target := m.Command.Args[0]
+	person, err := nick.ParsePerson(m, here, target)
+	if err != nil {
+		return fmt.Sprint(ErrPersonNotFound), ErrPersonNotFound, nil
+	}
+	amount, usrErr := parseAmount(m.Command.Args[1])
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+
+	points, usrErr, err := Debit(here, person, amount, "remove")
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	return fmt.Sprintf("Removed %d points from %s, they now have %d.", amount, target, points), nil, nil
+}
+
+/////////
+//     //
+// set //
+//     //
+/////////
+
+var AdvancedSet = advancedSet{}
+
+type advancedSet struct{}
+
+func (c advancedSet) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedSet) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedSet) Names() []string {
+	return []string{
+		"set",
+	}
+}
+
+func (advancedSet) Description() string {
+	return "Set someone's points."
+}
+
+func (advancedSet) UsageArgs() string {
+	return "<person> <amount>"
+}
+
+func (c advancedSet) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedSet) Examples() []string {
+	return nil
+}
+
+func (advancedSet) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedSet) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedSet) Init() error {
+	return nil
+}
+
+func (c advancedSet) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 2 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedSet) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	target := m.Command.Args[0]
+	person, err := nick.ParsePerson(m, here, target)
+	if err != nil {
+		return fmt.Sprint(ErrPersonNotFound), ErrPersonNotFound, nil
+	}
+	amount, err := strconv.ParseInt(m.Command.Args[1], 10, 64)
+	if err != nil || amount < 0 {
+		return fmt.Sprint(ErrInvalidAmount), ErrInvalidAmount, nil
+	}
+
+	points, err := Set(here, person, amount, "set")
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s now has %d points.", target, points), nil, nil
+}
+
+//////////
+//      //
+// chat //
+//      //
+//////////
+
+var AdvancedChat = advancedChat{}
+
+type advancedChat struct{}
+
+func (c advancedChat) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedChat) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedChat) Names() []string {
+	return []string{
+		"chat",
+	}
+}
+
+func (advancedChat) Description() string {
+	return "Set the points given for chatting, at most once per minute."
+}
+
+func (advancedChat) UsageArgs() string {
+	return "<amount>"
+}
+
+func (c advancedChat) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedChat) Examples() []string {
+	return nil
+}
+
+func (advancedChat) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedChat) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedChat) Init() error {
+	return nil
+}
+
+func (c advancedChat) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedChat) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	amount, err := strconv.ParseInt(m.Command.Args[0], 10, 64)
+	if err != nil {
+		return fmt.Sprint(ErrInvalidAmount), ErrInvalidAmount, nil
+	}
+	usrErr, err := ChatSet(here, amount)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	if amount == 0 {
+		return "No points will be given for chatting.", nil, nil
+	}
+	return fmt.Sprintf("%d points will be given for chatting.", amount), nil, nil
+}
+
+///////////
+//       //
+// watch //
+//       //
+///////////
+
+var AdvancedWatch = advancedWatch{}
+
+type advancedWatch struct{}
+
+func (c advancedWatch) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedWatch) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedWatch) Names() []string {
+	return []string{
+		"watch",
+	}
+}
+
+func (advancedWatch) Description() string {
+	return "Set the points given every 5 minutes for watching a live stream."
+}
+
+func (advancedWatch) UsageArgs() string {
+	return "<amount>"
+}
+
+func (c advancedWatch) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedWatch) Examples() []string {
+	return nil
+}
+
+func (advancedWatch) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedWatch) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedWatch) Init() error {
+	return nil
+}
+
+func (c advancedWatch) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedWatch) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	amount, err := strconv.ParseInt(m.Command.Args[0], 10, 64)
+	if err != nil {
+		return fmt.Sprint(ErrInvalidAmount), ErrInvalidAmount, nil
+	}
+	usrErr, err := WatchSet(here, amount)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	if amount == 0 {
+		return "No points will be given for watching.", nil, nil
+	}
+	return fmt.Sprintf("%d points will be given for watching.", amount), nil, nil
+}
@@ -0,0 +1,347 @@
+package points
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/twitch"
+
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrNotEnough      = errors.New("Not enough points.")
+	ErrInvalidAmount  = errors.New("Expected a positive number of points.")
+	ErrPersonNotFound = errors.New("Couldn't find that person.")
+	ErrSelf           = errors.New("You can't give points to yourself.")
+)
+
+const (
+	// People can only earn points for chatting once per chatCooldown.
+	chatCooldown = time.Minute
+
+	// Watch time points are given every watchInterval to the people that have
+	// sent a message within the last activeWindow while the stream is live,
+	// since there's no way of knowing who is just watching.
+	watchInterval = 5 * time.Minute
+	activeWindow  = 30 * time.Minute
+
+	// LeaderboardSize is the number of people shown in the leaderboard.
+	LeaderboardSize = 10
+)
+
+// Balance is a person's number of points.
+type Balance struct {
+	Person int64
+	Points int64
+}
+
+// Tx is a points transaction, all the changes made through it are either
+// applied together or not at all.
+type Tx struct {
+	tx    *sql.Tx
+	place int64
+}
+
+// Balance returns the person's points.
+func (t *Tx) Balance(person int64) (int64, error) {
+	return t.balance(person)
+}
+
+// Credit gives the person amount points and returns their new balance.
+func (t *Tx) Credit(person, amount int64, reason string) (int64, error) {
+	if _, err := t.balance(person); err != nil {
+		return 0, err
+	}
+	return t.change(person, amount, reason)
+}
+
+// Debit takes amount points from the person and returns their new balance.
+// Returns ErrNotEnough if the person doesn't have enough points, in which case
+// nothing is changed.
+func (t *Tx) Debit(person, amount int64, reason string) (int64, error, error) {
+	points, err := t.balance(person)
+	if err != nil {
+		return 0, nil, err
+	}
+	if points < amount {
+		return points, ErrNotEnough, nil
+	}
+	points, err = t.change(person, -amount, reason)
+	return points, nil, err
+}
+
+// Set sets the person's points to the exact amount.
+func (t *Tx) Set(person, amount int64, reason string) (int64, error) {
+	points, err := t.balance(person)
+	if err != nil {
+		return 0, err
+	}
+	return t.change(person, amount-points, reason)
+}
+
+// Transaction runs f inside of a transaction in the specified place. If f
+// returns any kind of error then all of the changes are rolled back,
+// otherwise they are committed.
+func Transaction(place int64, f func(t *Tx) (error, error)) (error, error) {
+	t, err := dbBegin(place)
+	if err != nil {
+		return nil, err
+	}
+
+	// If f panics the transaction must still be closed, otherwise its
+	// connection and row locks would never be released.
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		if rbErr := dbEnd(t, false); rbErr != nil {
+			log.Debug().Err(rbErr).Msg("failed to roll back points transaction")
+		}
+	}()
+
+	usrErr, err := f(t)
+	if usrErr != nil || err != nil {
+		return usrErr, err
+	}
+
+	committed = true
+	return nil, dbEnd(t, true)
+}
+
+// Credit gives the person amount points in the specified place and returns
+// their new balance.
+func Credit(place, person, amount int64, reason string) (int64, error) {
+	var points int64
+	_, err := Transaction(place, func(t *Tx) (error, error) {
+		var err error
+		points, err = t.Credit(person, amount, reason)
+		return nil, err
+	})
+	return points, err
+}
+
+// Debit takes amount points from the person in the specified place and
+// returns their new balance. Returns ErrNotEnough if they don't have enough.
+func Debit(place, person, amount int64, reason string) (int64, error, error) {
+	var points int64
+	usrErr, err := Transaction(place, func(t *Tx) (error, error) {
+		var usrErr, err error
+		points, usrErr, err = t.Debit(person, amount, reason)
+		return usrErr, err
+	})
+	return points, usrErr, err
+}
+
+// Set sets the person's points in the specified place.
+func Set(place, person, amount int64, reason string) (int64, error) {
+	var points int64
+	_, err := Transaction(place, func(t *Tx) (error, error) {
+		var err error
+		points, err = t.Set(person, amount, reason)
+		return nil, err
+	})
+	return points, err
+}
+
+// Transfer moves amount points from one person to another. Returns
+// ErrNotEnough if the sender doesn't have enough points.
+func Transfer(place, from, to, amount int64) (error, error) {
+	if from == to {
+		return ErrSelf, nil
+	}
+	if amount <= 0 {
+		return ErrInvalidAmount, nil
+	}
+	return Transaction(place, func(t *Tx) (error, error) {
+		_, usrErr, err := t.Debit(from, amount, "give")
+		if usrErr != nil || err != nil {
+			return usrErr, err
+		}
+		_, err = t.Credit(to, amount, "give")
+		return nil, err
+	})
+}
+
+// Get returns the person's points in the specified place.
+func Get(place, person int64) (int64, error) {
+	return dbBalance(place, person)
+}
+
+// Rank returns the person's position in the place's leaderboard.
+func Rank(place, person int64) (int64, error) {
+	return dbRank(place, person)
+}
+
+// Top returns the people with the most points in the specified place.
+func Top(place int64) ([]Balance, error) {
+	return dbTop(place, LeaderboardSize)
+}
+
+//////////////
+//          //
+// settings //
+//          //
+//////////////
+
+// ChatGet returns the number of points given for chatting.
+func ChatGet(place int64) (int64, error) {
+	points, err := core.DB.SettingPlaceGet("cmd_points_chat", place)
+	if err != nil {
+		return 0, err
+	}
+	return points.(int64), nil
+}
+
+// ChatSet sets the number of points given for chatting, 0 turns it off.
+func ChatSet(place, points int64) (error, error) {
+	if points < 0 {
+		return ErrInvalidAmount, nil
+	}
+	return nil, core.DB.SettingPlaceSet("cmd_points_chat", place, points)
+}
+
+// WatchGet returns the number of points given for watching.
+func WatchGet(place int64) (int64, error) {
+	points, err := core.DB.SettingPlaceGet("cmd_points_watch", place)
+	if err != nil {
+		return 0, err
+	}
+	return points.(int64), nil
+}
+
+// WatchSet sets the number of points given for watching, 0 turns it off.
+func WatchSet(place, points int64) (error, error) {
+	if points < 0 {
+		return ErrInvalidAmount, nil
+	}
+	return nil, core.DB.SettingPlaceSet("cmd_points_watch", place, points)
+}
+
+/////////////
+//         //
+// earning //
+//         //
+/////////////
+
+type key struct {
+	place  int64
+	person int64
+}
+
+var (
+	lock sync.Mutex
+
+	// the last time each person was given points for chatting
+	lastChat = map[key]time.Time{}
+
+	// the last time each person sent a message in a twitch channel, used for
+	// watch time
+	lastSeen = map[key]time.Time{}
+
+	// the twitch channel ID of each place that has been seen
+	channels = map[int64]string{}
+)
+
+// hook gives points for chatting and keeps track of who is active.
+func hook(m *core.Message) {
+	place, err := m.Here.ScopeLogical()
+	if err != nil {
+		return
+	}
+	person, err := m.Author.Scope()
+	if err != nil {
+		return
+	}
+
+	k := key{place, person}
+	now := time.Now()
+
+	lock.Lock()
+	if m.Frontend.Type() == twitch.Frontend.Type() {
+		lastSeen[k] = now
+		channels[place] = m.Here.ID()
+	}
+	last := lastChat[k]
+	ready := now.Sub(last) >= chatCooldown
+	if ready {
+		lastChat[k] = now
+	}
+	lock.Unlock()
+
+	if !ready {
+		return
+	}
+
+	amount, err := ChatGet(place)
+	if err != nil || amount == 0 {
+		return
+	}
+
+	_, err = Credit(place, person, amount, "chat")
+	log.Debug().Err(err).Int64("person", person).Msg("gave points for chatting")
+}
+
+// active returns the people in each place that have sent a message recently,
+// entries that are too old are removed.
+func active() map[int64][]int64 {
+	lock.Lock()
+	defer lock.Unlock()
+
+	// Chat cooldowns that have already passed behave the same as no entry.
+	for k, last := range lastChat {
+		if time.Since(last) >= chatCooldown {
+			delete(lastChat, k)
+		}
+	}
+
+	people := map[int64][]int64{}
+	for k, seen := range lastSeen {
+		if time.Since(seen) > activeWindow {
+			delete(lastSeen, k)
+			continue
+		}
+		people[k.place] = append(people[k.place], k.person)
+	}
+
+	for place := range channels {
+		if _, ok := people[place]; !ok {
+			delete(channels, place)
+		}
+	}
+
+	return people
+}
+
+// watch gives points to the active people of each twitch channel that is
+// currently live.
+func watch() {
+	for place, people := range active() {
+		amount, err := WatchGet(place)
+		if err != nil || amount == 0 {
+			continue
+		}
+
+		lock.Lock()
+		channelID := channels[place]
+		lock.Unlock()
+
+		h, err := twitch.HelixChannel(channelID)
+		if err != nil {
+			continue
+		}
+		// returns an error if the stream is offline
+		if _, err := h.GetStream(channelID); err != nil {
+			continue
+		}
+
+		for _, person := range people {
+			_, err := Credit(place, person, amount, "watch")
+			log.Debug().Err(err).Int64("person", person).Msg("gave points for watching")
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package points
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Balances live in settings_person and every change is recorded in
+// cmd_points_transactions. All changes go through Tx so that a balance and
+// its transactions never disagree. Transactions don't hold the global database
+// lock, concurrent changes to the same balance are serialized by the row lock
+// taken in balance.
+
+func (t *Tx) balance(person int64) (int64, error) {
+	// Make sure that the person settings are present. This is done inside the
+	// transaction instead of using SettingsPersonGenerate so that the row
+	// exists before it gets locked below.
+	_, err := t.tx.Exec(`
+		INSERT INTO settings_person(person, place)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, person, t.place)
+	if err != nil {
+		return 0, err
+	}
+
+	var points int64
+	err = t.tx.QueryRow(`
+		SELECT cmd_points_points
+		FROM settings_person
+		WHERE person = $1 and place = $2
+		FOR UPDATE
+	`, person, t.place).Scan(&points)
+
+	log.Debug().
+		Err(err).
+		Int64("place", t.place).
+		Int64("person", person).
+		Int64("points", points).
+		Msg("got balance")
+
+	return points, err
+}
+
+func (t *Tx) change(person, amount int64, reason string) (int64, error) {
+	var points int64
+	err := t.tx.QueryRow(`
+		UPDATE settings_person
+		SET cmd_points_points = cmd_points_points + $1
+		WHERE person = $2 and place = $3
+		RETURNING cmd_points_points
+	`, amount, person, t.place).Scan(&points)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = t.tx.Exec(`
+		INSERT INTO cmd_points_transactions(place, person, amount, reason, created)
+		VALUES ($1, $2, $3, $4, $5)
+	`, t.place, person, amount, reason, time.Now().UTC().Unix())
+
+	log.Debug().
+		Err(err).
+		Int64("place", t.place).
+		Int64("person", person).
+		Int64("amount", amount).
+		Int64("points", points).
+		Str("reason", reason).
+		Msg("changed balance")
+
+	return points, err
+}
+
+func dbBalance(place, person int64) (int64, error) {
+	if err := core.DB.SettingsPersonGenerate(person, place); err != nil {
+		return 0, err
+	}
+
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	var points int64
+	err := db.DB.QueryRow(`
+		SELECT cmd_points_points
+		FROM settings_person
+		WHERE person = $1 and place = $2
+	`, person, place).Scan(&points)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int64("person", person).
+		Int64("points", points).
+		Msg("got balance")
+
+	return points, err
+}
+
+func dbRank(place, person int64) (int64, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	var rank int64
+	err := db.DB.QueryRow(`
+		SELECT COUNT(*) + 1
+		FROM settings_person
+		WHERE place = $1 and cmd_points_points > (
+			SELECT cmd_points_points
+			FROM settings_person
+			WHERE person = $2 and place = $1
+		)
+	`, place, person).Scan(&rank)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int64("person", person).
+		Int64("rank", rank).
+		Msg("got rank")
+
+	return rank, err
+}
+
+func dbTop(place int64, limit int) ([]Balance, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	rows, err := db.DB.Query(`
+		SELECT person, cmd_points_points
+		FROM settings_person
+		WHERE place = $1 and cmd_points_points > 0
+		ORDER BY cmd_points_points DESC, person
+		LIMIT $2
+	`, place, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var top []Balance
+	for rows.Next() {
+		var b Balance
+		if err := rows.Scan(&b.Person, &b.Points); err != nil {
+			return nil, err
+		}
+		top = append(top, b)
+	}
+
+	err = rows.Err()
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int("count", len(top)).
+		Msg("got leaderboard")
+
+	return top, err
+}
+
+func dbBegin(place int64) (*Tx, error) {
+	tx, err := core.DB.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tx{tx: tx, place: place}, nil
+}
+
+func dbEnd(t *Tx, commit bool) error {
+	var err error
+	if commit {
+		err = t.tx.Commit()
+	} else {
+		err = t.tx.Rollback()
+	}
+
+	log.Debug().
+		Err(err).
+		Int64("place", t.place).
+		Bool("commit", commit).
+		Msg("ended points transaction")
+
+	if err == sql.ErrTxDone {
+		return nil
+	}
+	return err
+}
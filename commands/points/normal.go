@@ -0,0 +1,169 @@
+package points
+
+import (
+	"github.com/janitorjeff/jeff-bot/core"
+)
+
+////////////
+//        //
+// points //
+//        //
+////////////
+
+var NormalPoints = normalPoints{}
+
+type normalPoints struct{}
+
+func (normalPoints) Type() core.CommandType {
+	return core.Normal
+}
+
+func (normalPoints) Permitted(m *core.Message) bool {
+	return AdvancedShow.Permitted(m)
+}
+
+func (normalPoints) Names() []string {
+	return Advanced.Names()
+}
+
+func (normalPoints) Description() string {
+	return AdvancedShow.Description()
+}
+
+func (normalPoints) UsageArgs() string {
+	return AdvancedShow.UsageArgs()
+}
+
+func (normalPoints) Category() core.CommandCategory {
+	return AdvancedShow.Category()
+}
+
+func (normalPoints) Examples() []string {
+	return nil
+}
+
+func (normalPoints) Parent() core.CommandStatic {
+	return nil
+}
+
+func (normalPoints) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		NormalTop,
+	}
+}
+
+func (normalPoints) Init() error {
+	return nil
+}
+
+func (normalPoints) Run(m *core.Message) (any, error, error) {
+	return AdvancedShow.Run(m)
+}
+
+////////////////
+//            //
+// points top //
+//            //
+////////////////
+
+var NormalTop = normalTop{}
+
+type normalTop struct{}
+
+func (c normalTop) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (normalTop) Permitted(m *core.Message) bool {
+	return AdvancedTop.Permitted(m)
+}
+
+func (normalTop) Names() []string {
+	return AdvancedTop.Names()
+}
+
+func (normalTop) Description() string {
+	return AdvancedTop.Description()
+}
+
+func (normalTop) UsageArgs() string {
+	return AdvancedTop.UsageArgs()
+}
+
+func (normalTop) Category() core.CommandCategory {
+	return AdvancedTop.Category()
+}
+
+func (normalTop) Examples() []string {
+	return nil
+}
+
+func (normalTop) Parent() core.CommandStatic {
+	return NormalPoints
+}
+
+func (normalTop) Children() core.CommandsStatic {
+	return nil
+}
+
+func (normalTop) Init() error {
+	return nil
+}
+
+func (normalTop) Run(m *core.Message) (any, error, error) {
+	return AdvancedTop.Run(m)
+}
+
+//////////
+//      //
+// give //
+//      //
+//////////
+
+var NormalGive = normalGive{}
+
+type normalGive struct{}
+
+func (normalGive) Type() core.CommandType {
+	return core.Normal
+}
+
+func (normalGive) Permitted(m *core.Message) bool {
+	return AdvancedGive.Permitted(m)
+}
+
+func (normalGive) Names() []string {
+	return AdvancedGive.Names()
+}
+
+func (normalGive) Description() string {
+	return AdvancedGive.Description()
+}
+
+func (normalGive) UsageArgs() string {
+	return AdvancedGive.UsageArgs()
+}
+
+func (normalGive) Category() core.CommandCategory {
+	return AdvancedGive.Category()
+}
+
+func (normalGive) Examples() []string {
+	return nil
+}
+
+func (normalGive) Parent() core.CommandStatic {
+	return nil
+}
+
+func (normalGive) Children() core.CommandsStatic {
+	return nil
+}
+
+func (normalGive) Init() error {
+	return nil
+}
+
+func (normalGive) Run(m *core.Message) (any, error, error) {
+	return AdvancedGive.Run(m)
+}
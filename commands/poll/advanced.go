@@ -0,0 +1,290 @@
+package poll
+
+import (
+	"fmt"
+
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+
+	dg "github.com/bwmarrin/discordgo"
+)
+
+func render(m *core.Message, resp string, usrErr error) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return &dg.MessageEmbed{Description: resp}, usrErr, nil
+	default:
+		return resp, usrErr, nil
+	}
+}
+
+var Advanced = advanced{}
+
+type advanced struct{}
+
+func (advanced) Type() core.CommandType {
+	return core.Advanced
+}
+
+func (advanced) Permitted(*core.Message) bool {
+	return true
+}
+
+func (advanced) Names() []string {
+	return []string{
+		"poll",
+		"vote",
+	}
+}
+
+func (advanced) Description() string {
+	return "Create polls that chat can vote in."
+}
+
+func (c advanced) UsageArgs() string {
+	return c.Children().Usage()
+}
+
+func (advanced) Category() core.CommandCategory {
+	return core.CommandCategoryOther
+}
+
+func (advanced) Examples() []string {
+	return nil
+}
+
+func (advanced) Parent() core.CommandStatic {
+	return nil
+}
+
+func (advanced) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		AdvancedCreate,
+		AdvancedEnd,
+		AdvancedShow,
+	}
+}
+
+func (advanced) Init() error {
+	return resume()
+}
+
+func (advanced) Run(m *core.Message) (any, error, error) {
+	return m.Usage(), core.ErrMissingArgs, nil
+}
+
+////////////
+//        //
+// create //
+//        //
+////////////
+
+var AdvancedCreate = advancedCreate{}
+
+type advancedCreate struct{}
+
+func (c advancedCreate) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedCreate) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedCreate) Names() []string {
+	return []string{
+		"create",
+		"new",
+		"start",
+	}
+}
+
+func (advancedCreate) Description() string {
+	return "Create a poll, chat votes by typing an option's number."
+}
+
+func (advancedCreate) UsageArgs() string {
+	return `"<question>" <option> <option...> [--duration <duration>]`
+}
+
+func (c advancedCreate) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedCreate) Examples() []string {
+	return []string{
+		`"Which game next?" Celeste "Hollow Knight" Hades --duration 10m`,
+	}
+}
+
+func (advancedCreate) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedCreate) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedCreate) Init() error {
+	return nil
+}
+
+func (advancedCreate) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 3 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+
+	question, options, dur, usrErr := ParseArgs(m.RawArgs(0))
+	if usrErr != nil {
+		return render(m, fmt.Sprint(usrErr), usrErr)
+	}
+
+	usrErr, err := Create(m, question, options, dur)
+	if err != nil {
+		return nil, nil, err
+	}
+	if usrErr != nil {
+		return render(m, fmt.Sprint(usrErr), usrErr)
+	}
+
+	// the poll's message has already been sent
+	return nil, nil, core.ErrSilence
+}
+
+/////////
+//     //
+// end //
+//     //
+/////////
+
+var AdvancedEnd = advancedEnd{}
+
+type advancedEnd struct{}
+
+func (c advancedEnd) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedEnd) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedEnd) Names() []string {
+	return []string{
+		"end",
+		"stop",
+		"close",
+	}
+}
+
+func (advancedEnd) Description() string {
+	return "End the current poll early."
+}
+
+func (advancedEnd) UsageArgs() string {
+	return ""
+}
+
+func (c advancedEnd) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedEnd) Examples() []string {
+	return nil
+}
+
+func (advancedEnd) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedEnd) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedEnd) Init() error {
+	return nil
+}
+
+func (advancedEnd) Run(m *core.Message) (any, error, error) {
+	here, err := m.Here.ScopeExact()
+	if err != nil {
+		return nil, nil, err
+	}
+	if usrErr := End(here); usrErr != nil {
+		return render(m, fmt.Sprint(usrErr), usrErr)
+	}
+	// the results are sent once the poll has ended
+	return nil, nil, core.ErrSilence
+}
+
+//////////
+//      //
+// show //
+//      //
+//////////
+
+var AdvancedShow = advancedShow{}
+
+type advancedShow struct{}
+
+func (c advancedShow) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedShow) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedShow) Names() []string {
+	return core.AliasesShow
+}
+
+func (advancedShow) Description() string {
+	return "Show the current poll's votes."
+}
+
+func (advancedShow) UsageArgs() string {
+	return ""
+}
+
+func (c advancedShow) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedShow) Examples() []string {
+	return nil
+}
+
+func (advancedShow) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedShow) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedShow) Init() error {
+	return nil
+}
+
+func (advancedShow) Run(m *core.Message) (any, error, error) {
+	here, err := m.Here.ScopeExact()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p, votes, usrErr, err := Show(here)
+	if err != nil {
+		return nil, nil, err
+	}
+	if usrErr != nil {
+		return render(m, fmt.Sprint(usrErr), usrErr)
+	}
+
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return p.Embed(votes, false), nil, nil
+	default:
+		return p.Standings(votes), nil, nil
+	}
+}
@@ -0,0 +1,544 @@
+package poll
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/janitorjeff/jeff-bot/commands/moderation"
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+
+	dg "github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrActive          = errors.New("There's already a poll running here.")
+	ErrNoPoll          = errors.New("There's no poll running here.")
+	ErrTooFewOptions   = errors.New("A poll needs a question and at least 2 options.")
+	ErrTooManyOptions  = errors.New("A poll can have at most 10 options.")
+	ErrTooLong         = errors.New("The question and options can be at most 255 characters long.")
+	ErrDurationTooLong = errors.New("A poll can last at most 7 days.")
+)
+
+const (
+	DefaultDuration = 5 * time.Minute
+	maxDuration     = 7 * 24 * time.Hour
+	minOptions      = 2
+	maxOptions      = 10
+	maxLength       = 255
+
+	// The discord message is edited at most once per refreshInterval, no
+	// matter how many votes come in, so as to not get rate limited.
+	refreshInterval = 3 * time.Second
+)
+
+type Poll struct {
+	ID       int64
+	Place    int64 // the exact place
+	Creator  int64
+	Question string
+	Options  []string
+	MsgID    string
+	Ends     time.Time
+}
+
+// split splits the string into words, text inside of double or single quotes
+// is treated as a single word.
+func split(s string) []string {
+	var words []string
+	var word strings.Builder
+	var quote rune
+	inWord := false
+
+	for _, r := range s {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			word.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+// ParseArgs parses the arguments of the form:
+// "question" option1 option2 ... [--duration 5m]
+// Quotes can be used for questions and options that contain spaces. If no
+// duration is given then DefaultDuration is used.
+func ParseArgs(args string) (string, []string, time.Duration, error) {
+	dur := DefaultDuration
+
+	var rest []string
+	words := split(args)
+	for i := 0; i < len(words); i++ {
+		name, value, hasValue := strings.Cut(words[i], "=")
+		if name != "--duration" && name != "-duration" && name != "-d" {
+			rest = append(rest, words[i])
+			continue
+		}
+		if !hasValue {
+			if i+1 == len(words) {
+				return "", nil, 0, moderation.ErrInvalidDuration
+			}
+			i++
+			value = words[i]
+		}
+		var err error
+		if dur, err = moderation.ParseDuration(value); err != nil || dur <= 0 {
+			return "", nil, 0, moderation.ErrInvalidDuration
+		}
+	}
+
+	if dur > maxDuration {
+		return "", nil, 0, ErrDurationTooLong
+	}
+	if len(rest) < 1+minOptions {
+		return "", nil, 0, ErrTooFewOptions
+	}
+	if len(rest) > 1+maxOptions {
+		return "", nil, 0, ErrTooManyOptions
+	}
+	for _, w := range rest {
+		if utf8.RuneCountInString(w) > maxLength {
+			return "", nil, 0, ErrTooLong
+		}
+	}
+
+	return rest[0], rest[1:], dur, nil
+}
+
+// percent returns the percentage of votes as a whole number.
+func percent(votes, total int64) int64 {
+	if total == 0 {
+		return 0
+	}
+	return votes * 100 / total
+}
+
+func sum(votes []int64) int64 {
+	var total int64
+	for _, v := range votes {
+		total += v
+	}
+	return total
+}
+
+// winners returns the options with the most votes, more than one means that
+// there was a tie.
+func (p Poll) winners(votes []int64) []string {
+	var most int64
+	var ws []string
+	for i, v := range votes {
+		switch {
+		case v > most:
+			most = v
+			ws = []string{p.Options[i]}
+		case v == most && v != 0:
+			ws = append(ws, p.Options[i])
+		}
+	}
+	return ws
+}
+
+// Text returns the poll in a single line, used when creating the poll on
+// frontends that don't support editing messages.
+func (p Poll) Text() string {
+	var opts []string
+	for i, o := range p.Options {
+		opts = append(opts, fmt.Sprintf("%d for %s", i+1, o))
+	}
+	dur := time.Until(p.Ends).Round(time.Second)
+	return fmt.Sprintf("Poll: %s | Type %s. Ends in %s.", p.Question,
+		strings.Join(opts, ", "), moderation.FormatDuration(dur))
+}
+
+// Standings returns the poll's current votes in a single line.
+func (p Poll) Standings(votes []int64) string {
+	total := sum(votes)
+	var opts []string
+	for i, o := range p.Options {
+		opts = append(opts, fmt.Sprintf("%d. %s: %d (%d%%)", i+1, o, votes[i], percent(votes[i], total)))
+	}
+	return p.Question + " | " + strings.Join(opts, ", ")
+}
+
+// Results returns the poll's final results in a single line.
+func (p Poll) Results(votes []int64) string {
+	ws := p.winners(votes)
+	switch len(ws) {
+	case 0:
+		return "Poll ended: " + p.Question + " | No one voted."
+	case 1:
+		return "Poll ended: " + p.Standings(votes) + " | Winner: " + ws[0]
+	default:
+		return "Poll ended: " + p.Standings(votes) + " | Tie between " + strings.Join(ws, ", ")
+	}
+}
+
+// Embed returns the poll as a discord embed, this is the message that gets
+// edited as votes come in.
+func (p Poll) Embed(votes []int64, ended bool) *dg.MessageEmbed {
+	total := sum(votes)
+
+	var desc strings.Builder
+	for i, o := range p.Options {
+		fmt.Fprintf(&desc, "**%d.** %s — %d (%d%%)\n", i+1, o, votes[i], percent(votes[i], total))
+	}
+
+	embed := &dg.MessageEmbed{
+		Title:       p.Question,
+		Description: desc.String(),
+		Timestamp:   p.Ends.Format(time.RFC3339),
+	}
+
+	if ended {
+		embed.Footer = &dg.MessageEmbedFooter{
+			Text: fmt.Sprintf("Poll ended with %d votes", total),
+		}
+	} else {
+		embed.Footer = &dg.MessageEmbedFooter{
+			Text: fmt.Sprintf("Type an option's number to vote • %d votes • Ends", total),
+		}
+	}
+
+	return embed
+}
+
+// ResultsEmbed returns the poll's final results as a discord embed.
+func (p Poll) ResultsEmbed(votes []int64) *dg.MessageEmbed {
+	embed := &dg.MessageEmbed{
+		Title: "Poll ended: " + p.Question,
+	}
+	ws := p.winners(votes)
+	switch len(ws) {
+	case 0:
+		embed.Description = "No one voted."
+	case 1:
+		embed.Description = "Winner: **" + ws[0] + "**"
+	default:
+		embed.Description = "Tie between **" + strings.Join(ws, "**, **") + "**"
+	}
+	return embed
+}
+
+// overlayEvent is what gets sent to the place's overlays whenever the poll
+// changes.
+type overlayEvent struct {
+	Question string   `json:"question"`
+	Options  []string `json:"options"`
+	Votes    []int64  `json:"votes"`
+	Ends     int64    `json:"ends"` // unix timestamp
+	Ended    bool     `json:"ended"`
+}
+
+// publish sends the poll's current standings to the overlays of the place the
+// message came from.
+func (p Poll) publish(m *core.Message, votes []int64, ended bool) {
+	core.Overlay.PublishHere(m, core.OverlayPoll, overlayEvent{
+		Question: p.Question,
+		Options:  p.Options,
+		Votes:    votes,
+		Ends:     p.Ends.UTC().Unix(),
+		Ended:    ended,
+	})
+}
+
+/////////////
+//         //
+// running //
+//         //
+/////////////
+
+type running struct {
+	Poll
+
+	hook    int
+	discord bool
+	stop    chan struct{}
+
+	lock    sync.Mutex
+	pending bool // a discord edit has been scheduled
+}
+
+var (
+	lock sync.Mutex
+
+	// the polls that are currently running in each exact place
+	polls = map[int64]*running{}
+)
+
+// start registers the hook that collects the poll's votes and ends the poll
+// once its time is up. Returns ErrActive if there's already a poll running in
+// the same place.
+func start(p Poll) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, ok := polls[p.Place]; ok {
+		return ErrActive
+	}
+
+	frontend, err := core.DB.ScopeFrontend(p.Place)
+	if err != nil {
+		return err
+	}
+
+	r := &running{
+		Poll:    p,
+		discord: core.FrontendType(frontend) == discord.Frontend.Type(),
+		stop:    make(chan struct{}),
+	}
+	r.hook = core.Hooks.Register(r.vote)
+	polls[p.Place] = r
+
+	go func() {
+		select {
+		case <-time.After(time.Until(p.Ends)):
+		case <-r.stop:
+		}
+
+		lock.Lock()
+		core.Hooks.Delete(r.hook)
+		delete(polls, p.Place)
+		lock.Unlock()
+
+		r.end()
+	}()
+
+	return nil
+}
+
+// vote is the hook that records the votes, a vote is any message that is just
+// one of the options' numbers. Only a person's first vote counts.
+func (r *running) vote(m *core.Message) {
+	number, err := strconv.Atoi(strings.TrimSpace(m.Raw))
+	if err != nil || number < 1 || number > len(r.Options) {
+		return
+	}
+
+	place, err := m.Here.ScopeExact()
+	if err != nil || place != r.Place {
+		return
+	}
+
+	person, err := m.Author.Scope()
+	if err != nil {
+		return
+	}
+
+	counted, err := dbVote(r.ID, person, number)
+	if err != nil || !counted {
+		return
+	}
+
+	r.refresh()
+
+	if votes, err := dbTally(r.ID, len(r.Options)); err == nil {
+		r.publish(m, votes, false)
+	}
+}
+
+// refresh schedules an edit of the discord message, multiple votes in quick
+// succession result in a single edit.
+func (r *running) refresh() {
+	if !r.discord {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.pending {
+		return
+	}
+	r.pending = true
+
+	time.AfterFunc(refreshInterval, func() {
+		r.lock.Lock()
+		r.pending = false
+		r.lock.Unlock()
+
+		votes, err := dbTally(r.ID, len(r.Options))
+		if err != nil {
+			return
+		}
+		r.edit(r.Embed(votes, false))
+	})
+}
+
+func (r *running) edit(embed *dg.MessageEmbed) {
+	channelID, err := core.DB.ScopeID(r.Place)
+	if err != nil {
+		return
+	}
+	err = discord.EditEmbed(channelID, r.MsgID, embed)
+	log.Debug().Err(err).Int64("id", r.ID).Msg("edited poll message")
+}
+
+// end marks the poll as ended and sends the results.
+func (r *running) end() {
+	if err := dbEnd(r.ID); err != nil {
+		return
+	}
+
+	votes, err := dbTally(r.ID, len(r.Options))
+	if err != nil {
+		return
+	}
+
+	m, err := core.Frontends.CreateMessage(r.Creator, r.Place, r.MsgID)
+	if err != nil {
+		log.Debug().Err(err).Int64("id", r.ID).Msg("failed to create poll message")
+		return
+	}
+
+	r.publish(m, votes, true)
+
+	if r.discord {
+		r.edit(r.Embed(votes, true))
+		_, err = m.Client.Send(r.ResultsEmbed(votes), nil)
+	} else {
+		_, err = m.Client.Send(r.Results(votes), nil)
+	}
+	log.Debug().Err(err).Int64("id", r.ID).Msg("sent poll results")
+}
+
+// resume restarts the polls that were running before the bot was shut down,
+// the ones whose time ran out in the meantime end immediately.
+func resume() error {
+	ps, err := dbActive()
+	if err != nil {
+		return err
+	}
+	for _, p := range ps {
+		if err := start(p); err != nil {
+			log.Debug().Err(err).Int64("id", p.ID).Msg("failed to resume poll")
+		}
+	}
+	return nil
+}
+
+// Create creates a poll in the place the message came from and sends the
+// poll's message. Returns ErrActive if there's already a poll running there.
+func Create(m *core.Message, question string, options []string, dur time.Duration) (error, error) {
+	place, err := m.Here.ScopeExact()
+	if err != nil {
+		return nil, err
+	}
+	creator, err := m.Author.Scope()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := find(place); ok {
+		return ErrActive, nil
+	}
+
+	ends := time.Now().Add(dur)
+	id, err := dbAdd(place, creator, question, options, ends)
+	if err != nil {
+		return nil, err
+	}
+
+	p := Poll{
+		ID:       id,
+		Place:    place,
+		Creator:  creator,
+		Question: question,
+		Options:  options,
+		Ends:     ends,
+	}
+
+	var resp *core.Message
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		resp, err = m.Client.Send(p.Embed(make([]int64, len(options)), false), nil)
+	default:
+		resp, err = m.Client.Send(p.Text(), nil)
+	}
+	if err != nil {
+		dbEnd(id)
+		return nil, err
+	}
+
+	if resp != nil {
+		p.MsgID = resp.ID
+		if err := dbSetMsgID(id, p.MsgID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := start(p); err == ErrActive {
+		// another poll was created in the meantime
+		dbEnd(id)
+		return ErrActive, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	p.publish(m, make([]int64, len(options)), false)
+	return nil, nil
+}
+
+// find returns the poll running in the specified exact place, if there is one.
+func find(place int64) (*running, bool) {
+	lock.Lock()
+	defer lock.Unlock()
+	r, ok := polls[place]
+	return r, ok
+}
+
+// End ends the poll running in the specified exact place early.
+func End(place int64) error {
+	r, ok := find(place)
+	if !ok {
+		return ErrNoPoll
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	select {
+	case <-r.stop:
+		return ErrNoPoll
+	default:
+		close(r.stop)
+	}
+	return nil
+}
+
+// Show returns the poll running in the specified exact place and its current
+// votes.
+func Show(place int64) (Poll, []int64, error, error) {
+	r, ok := find(place)
+	if !ok {
+		return Poll{}, nil, ErrNoPoll, nil
+	}
+	votes, err := dbTally(r.ID, len(r.Options))
+	if err != nil {
+		return Poll{}, nil, nil, err
+	}
+	return r.Poll, votes, nil, nil
+}
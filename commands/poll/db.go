@@ -0,0 +1,234 @@
+package poll
+
+import (
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/rs/zerolog/log"
+)
+
+func dbAdd(place, creator int64, question string, options []string, ends time.Time) (int64, error) {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return -1, err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRow(`
+		INSERT INTO cmd_poll_polls(place, creator, question, msg_id, ends, active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, place, creator, question, "", ends.UTC().Unix(), true).Scan(&id)
+	if err != nil {
+		return -1, err
+	}
+
+	for i, option := range options {
+		_, err := tx.Exec(`
+			INSERT INTO cmd_poll_options(poll, number, option)
+			VALUES ($1, $2, $3)
+		`, id, i+1, option)
+		if err != nil {
+			return -1, err
+		}
+	}
+
+	err = tx.Commit()
+
+	log.Debug().
+		Err(err).
+		Int64("id", id).
+		Int64("place", place).
+		Int64("creator", creator).
+		Str("question", question).
+		Strs("options", options).
+		Time("ends", ends).
+		Msg("added poll")
+
+	return id, err
+}
+
+func dbSetMsgID(id int64, msgID string) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		UPDATE cmd_poll_polls
+		SET msg_id = $1
+		WHERE id = $2
+	`, msgID, id)
+
+	log.Debug().
+		Err(err).
+		Int64("id", id).
+		Str("msg_id", msgID).
+		Msg("set poll message id")
+
+	return err
+}
+
+func dbEnd(id int64) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		UPDATE cmd_poll_polls
+		SET active = $1
+		WHERE id = $2
+	`, false, id)
+
+	log.Debug().
+		Err(err).
+		Int64("id", id).
+		Msg("ended poll")
+
+	return err
+}
+
+func _dbOptions(id int64) ([]string, error) {
+	db := core.DB
+
+	rows, err := db.DB.Query(`
+		SELECT option
+		FROM cmd_poll_options
+		WHERE poll = $1
+		ORDER BY number
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var options []string
+	for rows.Next() {
+		var option string
+		if err := rows.Scan(&option); err != nil {
+			return nil, err
+		}
+		options = append(options, option)
+	}
+	return options, rows.Err()
+}
+
+// dbActive returns all of the polls that haven't ended yet, used to resume them
+// after a restart.
+func dbActive() ([]Poll, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	rows, err := db.DB.Query(`
+		SELECT id, place, creator, question, msg_id, ends
+		FROM cmd_poll_polls
+		WHERE active = $1
+	`, true)
+	if err != nil {
+		log.Debug().Err(err).Msg("failed to make query")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var polls []Poll
+	for rows.Next() {
+		var p Poll
+		var ends int64
+		if err := rows.Scan(&p.ID, &p.Place, &p.Creator, &p.Question, &p.MsgID, &ends); err != nil {
+			log.Debug().Err(err).Msg("failed while scanning rows")
+			return nil, err
+		}
+		p.Ends = time.Unix(ends, 0).UTC()
+		polls = append(polls, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range polls {
+		if polls[i].Options, err = _dbOptions(polls[i].ID); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Debug().
+		Int("count", len(polls)).
+		Msg("got active polls")
+
+	return polls, nil
+}
+
+// dbVote records the person's vote, returns false if they have already voted.
+func dbVote(id, person int64, number int) (bool, error) {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	res, err := db.DB.Exec(`
+		INSERT INTO cmd_poll_votes(poll, person, number)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`, id, person, number)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+
+	log.Debug().
+		Err(err).
+		Int64("id", id).
+		Int64("person", person).
+		Int("number", number).
+		Bool("counted", n == 1).
+		Msg("voted in poll")
+
+	return n == 1, err
+}
+
+// dbTally returns the number of votes for each of the poll's options.
+func dbTally(id int64, options int) ([]int64, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	rows, err := db.DB.Query(`
+		SELECT number, COUNT(*)
+		FROM cmd_poll_votes
+		WHERE poll = $1
+		GROUP BY number
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	votes := make([]int64, options)
+	for rows.Next() {
+		var number int
+		var count int64
+		if err := rows.Scan(&number, &count); err != nil {
+			return nil, err
+		}
+		if number < 1 || number > options {
+			continue
+		}
+		votes[number-1] = count
+	}
+
+	err = rows.Err()
+
+	log.Debug().
+		Err(err).
+		Int64("id", id).
+		Ints64("votes", votes).
+		Msg("tallied poll")
+
+	return votes, err
+}
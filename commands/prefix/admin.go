@@ -9,7 +9,7 @@ import (
 )
 
 func getAdminFlags(m *core.Message) (*flags, []string, error) {
-	f := newFlags(m).TypeFlag().ScopeFlag()
+	f := newFlags(m).TypeFlag().ScopeFlag().SpacedFlag()
 	args, err := f.fs.Parse()
 	return f, args, err
 }
@@ -174,12 +174,12 @@ func (adminAdd) core(m *core.Message) (string, string, error, error) {
 	if len(args) == 0 {
 		return "", "", core.ErrMissingArgs, nil
 	}
-	prefix := args[0]
+	prefix := core.Prefix{Type: t, Prefix: args[0], Spaced: fs.spacedFlag}
 
 	scope := fs.scopeFlag
 
-	collision, usrErr, err := Add(prefix, t, scope)
-	return prefix, collision, usrErr, err
+	collision, usrErr, err := Add(prefix, scope)
+	return prefix.Prefix, collision, usrErr, err
 }
 
 ////////////
@@ -51,24 +51,10 @@ func (advanced) Children() core.CommandsStatic {
 	}
 }
 
-func (c advanced) Init() error {
-	core.Hooks.Register(c.emergencyReset)
+func (advanced) Init() error {
 	return nil
 }
 
-func (advanced) emergencyReset(m *core.Message) {
-	if m.Raw != "!!!PleaseResetThePrefixesBackToTheDefaultsThanks!!!" {
-		return
-	}
-
-	resp, usrErr, err := AdvancedReset.Run(m)
-	if err != nil {
-		return
-	}
-
-	m.Write(resp, usrErr)
-}
-
 func (advanced) Run(m *core.Message) (any, error, error) {
 	return m.Usage(), core.ErrMissingArgs, nil
 }
@@ -96,11 +82,11 @@ func (advancedAdd) Names() []string {
 }
 
 func (advancedAdd) Description() string {
-	return "Add a prefix."
+	return "Add a prefix, a spaced one can be separated from the command by whitespace."
 }
 
 func (advancedAdd) UsageArgs() string {
-	return "<prefix>"
+	return "[-spaced] <prefix>"
 }
 
 func (c advancedAdd) Category() core.CommandCategory {
@@ -108,7 +94,10 @@ func (c advancedAdd) Category() core.CommandCategory {
 }
 
 func (advancedAdd) Examples() []string {
-	return nil
+	return []string{
+		".",
+		"-spaced jeff,",
+	}
 }
 
 func (advancedAdd) Parent() core.CommandStatic {
@@ -35,14 +35,14 @@ var (
 // !cmd add .prefix test // this works because . is not a valid prefix atm
 // !prefix add .
 // .prefix // both trigger
-func customCommandCollision(prefix string, place int64) (string, error) {
+func customCommandCollision(prefix core.Prefix, place int64) (string, error) {
 	triggers, err := custom_command.List(place)
 	if err != nil {
 		return "", err
 	}
 
 	for _, t := range triggers {
-		if strings.HasPrefix(t, prefix) {
+		if _, ok := prefix.Match(t); ok {
 			return t, nil
 		}
 	}
@@ -50,13 +50,13 @@ func customCommandCollision(prefix string, place int64) (string, error) {
 	return "", nil
 }
 
-// Add adds a prefix of type t in the specified place. If the prefix, regardless
-// of type, already exists then returns an ErrExists error. If a custom command
+// Add adds the prefix in the specified place. If the prefix, regardless of
+// type, already exists then returns an ErrExists error. If a custom command
 // happens to collide with the newly added prefix (for example if the custom
 // command `.help` exists and the prefix `.` is added then `.help` would collide
 // with the built-in command `help`) then returns an ErrCustomCommandExists
 // error.
-func Add(prefix string, t core.CommandType, place int64) (string, error, error) {
+func Add(prefix core.Prefix, place int64) (string, error, error) {
 	prefixes, inDB, err := core.PlacePrefixes(place)
 	if err != nil {
 		return "", nil, err
@@ -67,14 +67,14 @@ func Add(prefix string, t core.CommandType, place int64) (string, error, error)
 	// get added without the user realizing.
 	if !inDB {
 		for _, p := range prefixes {
-			if err = dbAdd(p.Prefix, p.Type, place); err != nil {
+			if err = dbAdd(p, place); err != nil {
 				return "", nil, err
 			}
 		}
 	}
 
 	for _, p := range prefixes {
-		if p.Prefix == prefix {
+		if p.Prefix == prefix.Prefix {
 			return "", ErrExists, nil
 		}
 	}
@@ -87,7 +87,7 @@ func Add(prefix string, t core.CommandType, place int64) (string, error, error)
 		return collision, ErrCustomCommandExists, nil
 	}
 
-	return "", nil, dbAdd(prefix, t, place)
+	return "", nil, dbAdd(prefix, place)
 }
 
 func cmdAdd(t core.CommandType, m *core.Message) (any, error, error) {
@@ -141,15 +141,23 @@ func cmdAddErr(usrErr error, prefix, collision string) string {
 }
 
 func cmdAddCore(t core.CommandType, m *core.Message) (string, string, error, error) {
-	prefix := m.Command.Args[0]
+	f := newFlags(m).SpacedFlag()
+	args, err := f.fs.Parse()
+	if err != nil {
+		return "", "", nil, err
+	}
+	if len(args) == 0 {
+		return "", "", core.ErrMissingArgs, nil
+	}
+	prefix := core.Prefix{Type: t, Prefix: args[0], Spaced: f.spacedFlag}
 
 	here, err := m.Here.ScopeLogical()
 	if err != nil {
-		return prefix, "", nil, err
+		return prefix.Prefix, "", nil, err
 	}
 
-	collision, usrErr, err := Add(prefix, t, here)
-	return prefix, collision, usrErr, err
+	collision, usrErr, err := Add(prefix, here)
+	return prefix.Prefix, collision, usrErr, err
 }
 
 ////////////
@@ -190,7 +198,7 @@ func Delete(prefix string, t core.CommandType, place int64) (error, error) {
 	// nothing will happen. So we first add them all to the DB.
 	if !inDB {
 		for _, p := range prefixes {
-			if err = dbAdd(p.Prefix, p.Type, place); err != nil {
+			if err = dbAdd(p, place); err != nil {
 				return nil, err
 			}
 		}
@@ -357,7 +365,13 @@ func cmdListCore(t core.CommandType, m *core.Message) ([]string, error) {
 
 	filtered := []string{}
 	for _, p := range prefixes {
-		if p.Type == t {
+		if p.Type != t {
+			continue
+		}
+		// shown the way they're used, e.g. `jeff, command`
+		if p.Spaced {
+			filtered = append(filtered, p.Prefix+" ")
+		} else {
 			filtered = append(filtered, p.Prefix)
 		}
 	}
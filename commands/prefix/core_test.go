@@ -0,0 +1,94 @@
+package prefix_test
+
+import (
+	"log"
+	"os"
+	"testing"
+
+	"github.com/janitorjeff/jeff-bot/commands/prefix"
+	"github.com/janitorjeff/jeff-bot/core"
+	_ "github.com/janitorjeff/jeff-bot/internal/testing_init"
+	"github.com/janitorjeff/jeff-bot/internal/testkit"
+
+	"github.com/rs/zerolog"
+)
+
+var place int64
+
+func find(t *testing.T, p string) (core.Prefix, bool) {
+	prefixes, err := prefix.List(core.All, place)
+	if err != nil {
+		t.Fatalf("failed to list prefixes: %v", err)
+	}
+	for _, pr := range prefixes {
+		if pr.Prefix == p {
+			return pr, true
+		}
+	}
+	return core.Prefix{}, false
+}
+
+func TestAdd(t *testing.T) {
+	p := core.Prefix{Type: core.Normal, Prefix: "?"}
+	if _, usrErr, err := prefix.Add(p, place); usrErr != nil || err != nil {
+		t.Fatalf("failed to add prefix: usrErr = %v, err = %v", usrErr, err)
+	}
+	if _, usrErr, err := prefix.Add(p, place); usrErr != prefix.ErrExists || err != nil {
+		t.Fatalf("expected Exists user error, got: usrErr = %v, err = %v", usrErr, err)
+	}
+
+	spaced := core.Prefix{Type: core.Advanced, Prefix: "jeff,", Spaced: true}
+	if _, usrErr, err := prefix.Add(spaced, place); usrErr != nil || err != nil {
+		t.Fatalf("failed to add spaced prefix: usrErr = %v, err = %v", usrErr, err)
+	}
+
+	// the defaults are saved along with the first prefix that is added
+	for _, p := range []core.Prefix{p, spaced, {Type: core.Normal, Prefix: "!"}} {
+		got, ok := find(t, p.Prefix)
+		if !ok {
+			t.Fatalf("prefix '%s' not found", p.Prefix)
+		}
+		if got != p {
+			t.Fatalf("expected prefix %v, got %v", p, got)
+		}
+	}
+}
+
+func TestDelete(t *testing.T) {
+	if usrErr, err := prefix.Delete("?", core.Normal, place); usrErr != nil || err != nil {
+		t.Fatalf("failed to delete prefix: usrErr = %v, err = %v", usrErr, err)
+	}
+	if usrErr, err := prefix.Delete("?", core.Normal, place); usrErr != prefix.ErrNotFound || err != nil {
+		t.Fatalf("expected NotFound user error, got: usrErr = %v, err = %v", usrErr, err)
+	}
+	if _, ok := find(t, "?"); ok {
+		t.Fatalf("prefix '?' still exists after being deleted")
+	}
+
+	if err := prefix.Reset(place); err != nil {
+		t.Fatalf("failed to reset prefixes: %v", err)
+	}
+	if _, ok := find(t, "jeff,"); ok {
+		t.Fatalf("prefix 'jeff,' still exists after reset")
+	}
+}
+
+func TestMain(m *testing.M) {
+	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+
+	core.Prefixes.Add(core.Normal, "!")
+	core.Prefixes.Add(core.Advanced, "$")
+
+	tdb := testkit.NewTestDB()
+	msg := testkit.NewTestMessage().DiscordRandom().Message
+
+	var err error
+	place, err = msg.Here.ScopeLogical()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	code := m.Run()
+	tdb.Delete()
+	os.Exit(code)
+}
@@ -6,23 +6,27 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-func dbAdd(prefix string, t core.CommandType, place int64) error {
+func dbAdd(prefix core.Prefix, place int64) error {
 	db := core.DB
 	db.Lock.Lock()
 	defer db.Lock.Unlock()
 
 	_, err := db.DB.Exec(`
-		INSERT INTO CommandPrefixPrefixes(place, prefix, type)
-		VALUES ($1, $2, $3)`, place, prefix, t)
+		INSERT INTO prefixes(place, prefix, type, spaced)
+		VALUES ($1, $2, $3, $4)`, place, prefix.Prefix, prefix.Type, prefix.Spaced)
 
 	log.Debug().
 		Err(err).
-		Str("prefix", prefix).
-		Int("type", int(t)).
+		Str("prefix", prefix.Prefix).
+		Int("type", int(prefix.Type)).
+		Bool("spaced", prefix.Spaced).
 		Int64("place", place).
 		Msg("added prefix")
 
-	return err
+	if err != nil {
+		return err
+	}
+	return core.PlacePrefixesInvalidate(place)
 }
 
 func dbDelete(prefix string, place int64) error {
@@ -31,7 +35,7 @@ func dbDelete(prefix string, place int64) error {
 	defer db.Lock.Unlock()
 
 	_, err := db.DB.Exec(`
-		DELETE FROM CommandPrefixPrefixes
+		DELETE FROM prefixes
 		WHERE prefix = $1 and place = $2`, prefix, place)
 
 	log.Debug().
@@ -40,7 +44,10 @@ func dbDelete(prefix string, place int64) error {
 		Int64("place", place).
 		Msg("deleted prefix")
 
-	return err
+	if err != nil {
+		return err
+	}
+	return core.PlacePrefixesInvalidate(place)
 }
 
 func dbReset(place int64) error {
@@ -49,7 +56,7 @@ func dbReset(place int64) error {
 	defer db.Lock.Unlock()
 
 	_, err := db.DB.Exec(`
-		DELETE FROM CommandPrefixPrefixes
+		DELETE FROM prefixes
 		WHERE place = $1`, place)
 
 	log.Debug().
@@ -57,5 +64,8 @@ func dbReset(place int64) error {
 		Int64("place", place).
 		Msg("deleted all prefixes")
 
-	return err
+	if err != nil {
+		return err
+	}
+	return core.PlacePrefixesInvalidate(place)
 }
@@ -7,8 +7,9 @@ import (
 type flags struct {
 	fs *core.Flags
 
-	typeFlag  core.CommandType
-	scopeFlag int64
+	typeFlag   core.CommandType
+	scopeFlag  int64
+	spacedFlag bool
 }
 
 func newFlags(m *core.Message) *flags {
@@ -27,3 +28,8 @@ func (f *flags) ScopeFlag() *flags {
 	core.FlagPlace(&f.scopeFlag, f.fs)
 	return f
 }
+
+func (f *flags) SpacedFlag() *flags {
+	f.fs.FlagSet.BoolVar(&f.spacedFlag, "spaced", false, "allow whitespace after the prefix")
+	return f
+}
@@ -91,7 +91,7 @@ func (c normalAdd) Category() core.CommandCategory {
 }
 
 func (normalAdd) Examples() []string {
-	return nil
+	return AdvancedAdd.Examples()
 }
 
 func (normalAdd) Parent() core.CommandStatic {
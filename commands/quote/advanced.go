@@ -0,0 +1,679 @@
+package quote
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/commands/nick"
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+
+	dg "github.com/bwmarrin/discordgo"
+)
+
+func render(m *core.Message, resp string, usrErr error) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return &dg.MessageEmbed{Description: resp}, usrErr, nil
+	default:
+		return resp, usrErr, nil
+	}
+}
+
+// formatResults returns the first quote and the numbers of the rest, if
+// there are any.
+func formatResults(quotes []Quote) string {
+	s := quotes[0].Format()
+	if len(quotes) == 1 {
+		return s
+	}
+
+	const max = 10
+	var others []string
+	for i, q := range quotes[1:] {
+		if i == max {
+			others = append(others, "...")
+			break
+		}
+		others = append(others, fmt.Sprintf("#%d", q.Number))
+	}
+	return s + " (also " + strings.Join(others, ", ") + ")"
+}
+
+var Advanced = advanced{}
+
+type advanced struct{}
+
+func (advanced) Type() core.CommandType {
+	return core.Advanced
+}
+
+func (advanced) Permitted(*core.Message) bool {
+	return true
+}
+
+func (advanced) Names() []string {
+	return []string{
+		"quote",
+		"quotes",
+	}
+}
+
+func (advanced) Description() string {
+	return "Add, edit, delete or view quotes."
+}
+
+func (c advanced) UsageArgs() string {
+	return c.Children().Usage()
+}
+
+func (advanced) Category() core.CommandCategory {
+	return core.CommandCategoryOther
+}
+
+func (advanced) Examples() []string {
+	return nil
+}
+
+func (advanced) Parent() core.CommandStatic {
+	return nil
+}
+
+func (advanced) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		AdvancedAdd,
+		AdvancedEdit,
+		AdvancedDelete,
+		AdvancedShow,
+		AdvancedRandom,
+		AdvancedSearch,
+		AdvancedHistory,
+	}
+}
+
+func (advanced) Init() error {
+	return nil
+}
+
+func (advanced) Run(m *core.Message) (any, error, error) {
+	return m.Usage(), core.ErrMissingArgs, nil
+}
+
+/////////
+//     //
+// add //
+//     //
+/////////
+
+var AdvancedAdd = advancedAdd{}
+
+type advancedAdd struct{}
+
+func (c advancedAdd) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedAdd) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedAdd) Names() []string {
+	return core.AliasesAdd
+}
+
+func (advancedAdd) Description() string {
+	return "Add a quote."
+}
+
+func (advancedAdd) UsageArgs() string {
+	return "<person> <quote...>"
+}
+
+func (c advancedAdd) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedAdd) Examples() []string {
+	return nil
+}
+
+func (advancedAdd) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedAdd) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedAdd) Init() error {
+	return nil
+}
+
+func (c advancedAdd) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 2 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedAdd) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+	author, err := m.Author.Scope()
+	if err != nil {
+		return "", nil, err
+	}
+
+	quotedName := m.Command.Args[0]
+	quoted, err := nick.ParsePerson(m, here, quotedName)
+	if err != nil {
+		return fmt.Sprint(ErrPersonNotFound), ErrPersonNotFound, nil
+	}
+	quotedName = strings.TrimPrefix(quotedName, "@")
+
+	number, err := Add(here, author, quoted, quotedName, Game(m), m.RawArgs(1))
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("Added quote #%d.", number), nil, nil
+}
+
+//////////
+//      //
+// edit //
+//      //
+//////////
+
+var AdvancedEdit = advancedEdit{}
+
+type advancedEdit struct{}
+
+func (c advancedEdit) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedEdit) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedEdit) Names() []string {
+	return core.AliasesEdit
+}
+
+func (advancedEdit) Description() string {
+	return "Edit a quote."
+}
+
+func (advancedEdit) UsageArgs() string {
+	return "<number> <quote...>"
+}
+
+func (c advancedEdit) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedEdit) Examples() []string {
+	return nil
+}
+
+func (advancedEdit) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedEdit) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedEdit) Init() error {
+	return nil
+}
+
+func (c advancedEdit) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 2 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedEdit) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+	author, err := m.Author.Scope()
+	if err != nil {
+		return "", nil, err
+	}
+	number, usrErr := ParseNumber(m.Command.Args[0])
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+
+	usrErr, err = Edit(here, author, number, m.RawArgs(1))
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	return fmt.Sprintf("Edited quote #%d.", number), nil, nil
+}
+
+////////////
+//        //
+// delete //
+//        //
+////////////
+
+var AdvancedDelete = advancedDelete{}
+
+type advancedDelete struct{}
+
+func (c advancedDelete) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedDelete) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedDelete) Names() []string {
+	return core.AliasesDelete
+}
+
+func (advancedDelete) Description() string {
+	return "Delete a quote."
+}
+
+func (advancedDelete) UsageArgs() string {
+	return "<number>"
+}
+
+func (c advancedDelete) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedDelete) Examples() []string {
+	return nil
+}
+
+func (advancedDelete) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedDelete) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedDelete) Init() error {
+	return nil
+}
+
+func (c advancedDelete) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedDelete) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+	author, err := m.Author.Scope()
+	if err != nil {
+		return "", nil, err
+	}
+	number, usrErr := ParseNumber(m.Command.Args[0])
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+
+	usrErr, err = Delete(here, author, number)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	return fmt.Sprintf("Deleted quote #%d.", number), nil, nil
+}
+
+//////////
+//      //
+// show //
+//      //
+//////////
+
+var AdvancedShow = advancedShow{}
+
+type advancedShow struct{}
+
+func (c advancedShow) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedShow) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedShow) Names() []string {
+	return core.AliasesShow
+}
+
+func (advancedShow) Description() string {
+	return "Show a quote."
+}
+
+func (advancedShow) UsageArgs() string {
+	return "<number>"
+}
+
+func (c advancedShow) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedShow) Examples() []string {
+	return nil
+}
+
+func (advancedShow) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedShow) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedShow) Init() error {
+	return nil
+}
+
+func (c advancedShow) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedShow) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+	number, usrErr := ParseNumber(m.Command.Args[0])
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+
+	q, usrErr, err := Get(here, number)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	return q.Format(), nil, nil
+}
+
+////////////
+//        //
+// random //
+//        //
+////////////
+
+var AdvancedRandom = advancedRandom{}
+
+type advancedRandom struct{}
+
+func (c advancedRandom) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedRandom) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedRandom) Names() []string {
+	return []string{
+		"random",
+	}
+}
+
+func (advancedRandom) Description() string {
+	return "Show a random quote."
+}
+
+func (advancedRandom) UsageArgs() string {
+	return ""
+}
+
+func (c advancedRandom) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedRandom) Examples() []string {
+	return nil
+}
+
+func (advancedRandom) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedRandom) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedRandom) Init() error {
+	return nil
+}
+
+func (c advancedRandom) Run(m *core.Message) (any, error, error) {
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedRandom) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	q, usrErr, err := Random(here)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	return q.Format(), nil, nil
+}
+
+////////////
+//        //
+// search //
+//        //
+////////////
+
+var AdvancedSearch = advancedSearch{}
+
+type advancedSearch struct{}
+
+func (c advancedSearch) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedSearch) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedSearch) Names() []string {
+	return core.AliasesSearch
+}
+
+func (advancedSearch) Description() string {
+	return "Search for a quote."
+}
+
+func (advancedSearch) UsageArgs() string {
+	return "<text...>"
+}
+
+func (c advancedSearch) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedSearch) Examples() []string {
+	return nil
+}
+
+func (advancedSearch) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedSearch) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedSearch) Init() error {
+	return nil
+}
+
+func (c advancedSearch) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedSearch) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	quotes, usrErr, err := Search(here, m.RawArgs(0))
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	return formatResults(quotes), nil, nil
+}
+
+/////////////
+//         //
+// history //
+//         //
+/////////////
+
+var AdvancedHistory = advancedHistory{}
+
+type advancedHistory struct{}
+
+func (c advancedHistory) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedHistory) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedHistory) Names() []string {
+	return []string{
+		"history",
+	}
+}
+
+func (advancedHistory) Description() string {
+	return "View a quote's entire history of changes."
+}
+
+func (advancedHistory) UsageArgs() string {
+	return "<number>"
+}
+
+func (c advancedHistory) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedHistory) Examples() []string {
+	return nil
+}
+
+func (advancedHistory) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedHistory) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedHistory) Init() error {
+	return nil
+}
+
+func (c advancedHistory) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedHistory) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+	number, usrErr := ParseNumber(m.Command.Args[0])
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+
+	history, err := History(here, number)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(history) == 0 {
+		return fmt.Sprint(ErrNotFound), ErrNotFound, nil
+	}
+
+	date := func(timestamp int64) string {
+		return time.Unix(timestamp, 0).UTC().Format("2006-01-02")
+	}
+
+	var changes []string
+	for i, r := range history {
+		if i == 0 {
+			changes = append(changes, fmt.Sprintf("created '%s' on %s", r.quote, date(r.created)))
+		} else {
+			changes = append(changes, fmt.Sprintf("edited to '%s' on %s", r.quote, date(r.created)))
+		}
+		if i == len(history)-1 && r.deleted != 0 {
+			changes = append(changes, fmt.Sprintf("deleted on %s", date(r.deleted)))
+		}
+	}
+
+	sep := " | "
+	if m.Frontend.Type() == discord.Frontend.Type() {
+		sep = "\n"
+	}
+	return fmt.Sprintf("#%d: %s", number, strings.Join(changes, sep)), nil, nil
+}
@@ -0,0 +1,154 @@
+package quote
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/twitch"
+
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrNotFound       = errors.New("Couldn't find a quote with that number.")
+	ErrNoQuotes       = errors.New("There are no quotes yet.")
+	ErrNoResults      = errors.New("Couldn't find any matching quotes.")
+	ErrInvalidNumber  = errors.New("Expected a quote number, for example #3.")
+	ErrPersonNotFound = errors.New("Couldn't find that person.")
+)
+
+type Quote struct {
+	Number int64
+
+	Quote      string
+	Quoted     int64  // the quoted person's scope
+	QuotedName string // the name that was used for the quoted person
+	Game       string // the twitch game at the time of quoting, if any
+	Said       time.Time
+
+	Quoter int64
+}
+
+// Format returns the quote in the form of:
+// #3: "quote" - name, playing game on 2006-01-02
+func (q Quote) Format() string {
+	s := fmt.Sprintf(`#%d: "%s" - %s`, q.Number, q.Quote, q.QuotedName)
+	if q.Game != "" {
+		s += ", playing " + q.Game
+	}
+	return s + " on " + q.Said.Format("2006-01-02")
+}
+
+type revision struct {
+	quote   string
+	creator int64
+	created int64
+	deleter int64
+	deleted int64
+}
+
+// ParseNumber parses a quote number, which can optionally be prefixed with a
+// #.
+func ParseNumber(s string) (int64, error) {
+	n, err := strconv.ParseInt(strings.TrimPrefix(s, "#"), 10, 64)
+	if err != nil || n <= 0 {
+		return 0, ErrInvalidNumber
+	}
+	return n, nil
+}
+
+// Game returns the game that the channel the message came from is currently
+// playing. Returns an empty string if it's not a twitch message or the game
+// couldn't be found.
+func Game(m *core.Message) string {
+	if m.Frontend.Type() != twitch.Frontend.Type() {
+		return ""
+	}
+
+	h, err := twitch.HelixChannel(m.Here.ID())
+	if err != nil {
+		log.Debug().Err(err).Msg("failed to get helix client")
+		return ""
+	}
+
+	game, err := h.GetGameName(m.Here.ID())
+	if err != nil {
+		log.Debug().Err(err).Msg("failed to get game name")
+		return ""
+	}
+
+	return game
+}
+
+// Add adds a new quote and returns its number. Numbers are per place and
+// start from 1.
+func Add(place, quoter, quoted int64, quotedName, game, quote string) (int64, error) {
+	return dbAdd(place, quoter, quoted, quotedName, game, quote)
+}
+
+// Edit changes a quote's text, the old one is kept in the quote's history.
+func Edit(place, editor, number int64, quote string) (error, error) {
+	exists, err := dbExists(place, number)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return ErrNotFound, nil
+	}
+	return nil, dbEdit(place, number, editor, quote)
+}
+
+// Delete marks a quote as deleted, it can still be viewed in the quote's
+// history.
+func Delete(place, deleter, number int64) (error, error) {
+	exists, err := dbExists(place, number)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return ErrNotFound, nil
+	}
+	return nil, dbDelete(place, number, deleter)
+}
+
+// Get returns the quote with the specified number.
+func Get(place, number int64) (Quote, error, error) {
+	q, err := dbGet(place, number)
+	if err == sql.ErrNoRows {
+		return q, ErrNotFound, nil
+	}
+	return q, nil, err
+}
+
+// Random returns a random quote.
+func Random(place int64) (Quote, error, error) {
+	q, err := dbRandom(place)
+	if err == sql.ErrNoRows {
+		return q, ErrNoQuotes, nil
+	}
+	return q, nil, err
+}
+
+// Search returns the quotes that contain the text either in the quote itself,
+// the quoted person's name or the game.
+func Search(place int64, text string) ([]Quote, error, error) {
+	quotes, err := dbSearch(place, text)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(quotes) == 0 {
+		return nil, ErrNoResults, nil
+	}
+	return quotes, nil, nil
+}
+
+func History(place, number int64) ([]revision, error) {
+	// We don't check to see if the quote exists since this command may be
+	// used to view the history of a deleted quote
+	return dbHistory(place, number)
+}
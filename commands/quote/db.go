@@ -0,0 +1,293 @@
+package quote
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/rs/zerolog/log"
+)
+
+func _dbAdd(q Quote, place, creator, timestamp int64) error {
+	db := core.DB
+
+	_, err := db.DB.Exec(`
+		INSERT INTO cmd_quote_quotes(
+			place, number, quote, quoted, quoted_name, game, said, quoter,
+			active, creator, created
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		place, q.Number, q.Quote, q.Quoted, q.QuotedName, q.Game,
+		q.Said.UTC().Unix(), q.Quoter, true, creator, timestamp)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int64("number", q.Number).
+		Str("quote", q.Quote).
+		Int64("quoted", q.Quoted).
+		Str("game", q.Game).
+		Int64("creator", creator).
+		Int64("timestamp", timestamp).
+		Msg("added quote")
+
+	return err
+}
+
+func dbAdd(place, quoter, quoted int64, quotedName, game, quote string) (int64, error) {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	// deleted quotes are also taken into account so that numbers are never
+	// reused
+	var number int64
+	err := db.DB.QueryRow(`
+		SELECT COALESCE(MAX(number), 0) + 1
+		FROM cmd_quote_quotes
+		WHERE place = $1
+	`, place).Scan(&number)
+	if err != nil {
+		return -1, err
+	}
+
+	now := time.Now().UTC()
+
+	q := Quote{
+		Number:     number,
+		Quote:      quote,
+		Quoted:     quoted,
+		QuotedName: quotedName,
+		Game:       game,
+		Said:       now,
+		Quoter:     quoter,
+	}
+
+	return number, _dbAdd(q, place, quoter, now.Unix())
+}
+
+func _dbDel(place, number, deleter, timestamp int64) error {
+	db := core.DB
+
+	_, err := db.DB.Exec(`
+		UPDATE cmd_quote_quotes
+		SET active = $1, deleter = $2, deleted = $3
+		WHERE place = $4 and number = $5 and active = $6
+	`, false, deleter, timestamp, place, number, true)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int64("number", number).
+		Int64("deleter", deleter).
+		Int64("timestamp", timestamp).
+		Msg("set quote as deleted")
+
+	return err
+}
+
+func dbDelete(place, number, deleter int64) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	timestamp := time.Now().UTC().Unix()
+	return _dbDel(place, number, deleter, timestamp)
+}
+
+func dbEdit(place, number, editor int64, quote string) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	q, err := _dbGet(place, number)
+	if err != nil {
+		return err
+	}
+	q.Quote = quote
+
+	timestamp := time.Now().UTC().Unix()
+
+	if err := _dbDel(place, number, editor, timestamp); err != nil {
+		return err
+	}
+
+	return _dbAdd(q, place, editor, timestamp)
+}
+
+func dbExists(place, number int64) (bool, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	var exists bool
+
+	row := db.DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM cmd_quote_quotes
+			WHERE place = $1 and number = $2 and active = $3
+			LIMIT 1
+		)`, place, number, true)
+
+	err := row.Scan(&exists)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int64("number", number).
+		Bool("exists", exists).
+		Msg("checked db to see if quote exists")
+
+	return exists, err
+}
+
+const quoteColumns = `number, quote, quoted, quoted_name, game, said, quoter`
+
+func scanQuote(row interface{ Scan(...any) error }) (Quote, error) {
+	var q Quote
+	var said int64
+	err := row.Scan(&q.Number, &q.Quote, &q.Quoted, &q.QuotedName, &q.Game, &said, &q.Quoter)
+	q.Said = time.Unix(said, 0).UTC()
+	return q, err
+}
+
+func _dbGet(place, number int64) (Quote, error) {
+	db := core.DB
+
+	row := db.DB.QueryRow(`
+		SELECT `+quoteColumns+`
+		FROM cmd_quote_quotes
+		WHERE place = $1 and number = $2 and active = $3
+	`, place, number, true)
+
+	q, err := scanQuote(row)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int64("number", number).
+		Str("quote", q.Quote).
+		Msg("got quote")
+
+	return q, err
+}
+
+func dbGet(place, number int64) (Quote, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	return _dbGet(place, number)
+}
+
+func dbRandom(place int64) (Quote, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	row := db.DB.QueryRow(`
+		SELECT `+quoteColumns+`
+		FROM cmd_quote_quotes
+		WHERE place = $1 and active = $2
+		ORDER BY random()
+		LIMIT 1
+	`, place, true)
+
+	q, err := scanQuote(row)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int64("number", q.Number).
+		Msg("got random quote")
+
+	return q, err
+}
+
+func dbSearch(place int64, text string) ([]Quote, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	rows, err := db.DB.Query(`
+		SELECT `+quoteColumns+`
+		FROM cmd_quote_quotes
+		WHERE place = $1 and active = $2 and (
+			quote ILIKE '%' || $3 || '%' or
+			quoted_name ILIKE '%' || $3 || '%' or
+			game ILIKE '%' || $3 || '%'
+		)
+		ORDER BY number
+	`, place, true, text)
+	if err != nil {
+		log.Debug().Err(err).Msg("failed to make query")
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var quotes []Quote
+	for rows.Next() {
+		q, err := scanQuote(rows)
+		if err != nil {
+			log.Debug().Err(err).Msg("failed while scanning rows")
+			return nil, err
+		}
+		quotes = append(quotes, q)
+	}
+
+	err = rows.Err()
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Str("text", text).
+		Int("results", len(quotes)).
+		Msg("searched quotes")
+
+	return quotes, err
+}
+
+func dbHistory(place, number int64) ([]revision, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	rows, err := db.DB.Query(`
+		SELECT quote, creator, created, deleter, deleted
+		FROM cmd_quote_quotes
+		WHERE place = $1 and number = $2
+		ORDER BY created
+	`, place, number)
+	if err != nil {
+		log.Debug().Err(err).Msg("failed to make query")
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var history []revision
+	for rows.Next() {
+		var r revision
+		var deleter, deleted sql.NullInt64
+		if err := rows.Scan(&r.quote, &r.creator, &r.created, &deleter, &deleted); err != nil {
+			log.Debug().Err(err).Msg("failed while scanning rows")
+			return nil, err
+		}
+		r.deleter = deleter.Int64
+		r.deleted = deleted.Int64
+		history = append(history, r)
+	}
+
+	err = rows.Err()
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int64("number", number).
+		Interface("history", history).
+		Msg("got a quote's history")
+
+	return history, err
+}
@@ -0,0 +1,112 @@
+package quote
+
+import (
+	"github.com/janitorjeff/jeff-bot/core"
+)
+
+var Normal = normal{}
+
+type normal struct{}
+
+func (normal) Type() core.CommandType {
+	return core.Normal
+}
+
+func (normal) Permitted(*core.Message) bool {
+	return true
+}
+
+func (normal) Names() []string {
+	return Advanced.Names()
+}
+
+func (normal) Description() string {
+	return "Show a random quote or a specific one."
+}
+
+func (normal) UsageArgs() string {
+	return "[number] | search <text...>"
+}
+
+func (normal) Category() core.CommandCategory {
+	return Advanced.Category()
+}
+
+func (normal) Examples() []string {
+	return nil
+}
+
+func (normal) Parent() core.CommandStatic {
+	return nil
+}
+
+func (normal) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		NormalSearch,
+	}
+}
+
+func (normal) Init() error {
+	return nil
+}
+
+func (normal) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) == 0 {
+		return AdvancedRandom.Run(m)
+	}
+	return AdvancedShow.Run(m)
+}
+
+////////////
+//        //
+// search //
+//        //
+////////////
+
+var NormalSearch = normalSearch{}
+
+type normalSearch struct{}
+
+func (c normalSearch) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c normalSearch) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (normalSearch) Names() []string {
+	return AdvancedSearch.Names()
+}
+
+func (normalSearch) Description() string {
+	return AdvancedSearch.Description()
+}
+
+func (normalSearch) UsageArgs() string {
+	return AdvancedSearch.UsageArgs()
+}
+
+func (c normalSearch) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (normalSearch) Examples() []string {
+	return nil
+}
+
+func (normalSearch) Parent() core.CommandStatic {
+	return Normal
+}
+
+func (normalSearch) Children() core.CommandsStatic {
+	return nil
+}
+
+func (normalSearch) Init() error {
+	return nil
+}
+
+func (normalSearch) Run(m *core.Message) (any, error, error) {
+	return AdvancedSearch.Run(m)
+}
@@ -0,0 +1,462 @@
+package random
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+
+	dg "github.com/bwmarrin/discordgo"
+)
+
+var Advanced = advanced{}
+
+type advanced struct{}
+
+func (advanced) Type() core.CommandType {
+	return core.Advanced
+}
+
+func (advanced) Permitted(*core.Message) bool {
+	return true
+}
+
+func (advanced) Names() []string {
+	return []string{
+		"8ball",
+		"eightball",
+	}
+}
+
+func (advanced) Description() string {
+	return "Ask the magic 8-ball or customize its answers."
+}
+
+func (c advanced) UsageArgs() string {
+	return c.Children().Usage()
+}
+
+func (advanced) Category() core.CommandCategory {
+	return core.CommandCategoryGames
+}
+
+func (advanced) Examples() []string {
+	return nil
+}
+
+func (advanced) Parent() core.CommandStatic {
+	return nil
+}
+
+func (advanced) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		AdvancedAsk,
+		AdvancedAdd,
+		AdvancedDelete,
+		AdvancedList,
+		AdvancedReset,
+	}
+}
+
+func (advanced) Init() error {
+	return nil
+}
+
+func (advanced) Run(m *core.Message) (any, error, error) {
+	return m.Usage(), core.ErrMissingArgs, nil
+}
+
+/////////
+//     //
+// ask //
+//     //
+/////////
+
+var AdvancedAsk = advancedAsk{}
+
+type advancedAsk struct{}
+
+func (c advancedAsk) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedAsk) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedAsk) Names() []string {
+	return []string{
+		"ask",
+	}
+}
+
+func (advancedAsk) Description() string {
+	return "Ask the magic 8-ball a question."
+}
+
+func (advancedAsk) UsageArgs() string {
+	return "<question...>"
+}
+
+func (c advancedAsk) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedAsk) Examples() []string {
+	return []string{
+		"will it rain tomorrow?",
+	}
+}
+
+func (advancedAsk) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedAsk) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedAsk) Init() error {
+	return nil
+}
+
+func (c advancedAsk) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, "🎱 "+resp, nil)
+}
+
+func (advancedAsk) core(m *core.Message) (string, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", err
+	}
+	return Ask(here)
+}
+
+/////////
+//     //
+// add //
+//     //
+/////////
+
+var AdvancedAdd = advancedAdd{}
+
+type advancedAdd struct{}
+
+func (c advancedAdd) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedAdd) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedAdd) Names() []string {
+	return core.AliasesAdd
+}
+
+func (advancedAdd) Description() string {
+	return "Add a custom answer, once there is one the default answers are no longer used."
+}
+
+func (advancedAdd) UsageArgs() string {
+	return "<answer...>"
+}
+
+func (c advancedAdd) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedAdd) Examples() []string {
+	return nil
+}
+
+func (advancedAdd) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedAdd) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedAdd) Init() error {
+	return nil
+}
+
+func (c advancedAdd) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedAdd) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	usrErr, err := AnswerAdd(here, m.RawArgs(0))
+	if err != nil {
+		return "", nil, err
+	}
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+	return "Added the answer.", nil, nil
+}
+
+////////////
+//        //
+// delete //
+//        //
+////////////
+
+var AdvancedDelete = advancedDelete{}
+
+type advancedDelete struct{}
+
+func (c advancedDelete) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedDelete) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedDelete) Names() []string {
+	return core.AliasesDelete
+}
+
+func (advancedDelete) Description() string {
+	return "Delete a custom answer, use list to see their numbers."
+}
+
+func (advancedDelete) UsageArgs() string {
+	return "<number>"
+}
+
+func (c advancedDelete) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedDelete) Examples() []string {
+	return nil
+}
+
+func (advancedDelete) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedDelete) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedDelete) Init() error {
+	return nil
+}
+
+func (c advancedDelete) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedDelete) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	number, err := strconv.Atoi(strings.TrimPrefix(m.Command.Args[0], "#"))
+	if err != nil {
+		return fmt.Sprint(ErrAnswerNotFound), ErrAnswerNotFound, nil
+	}
+
+	usrErr, err := AnswerDelete(here, number)
+	if err != nil {
+		return "", nil, err
+	}
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+	return fmt.Sprintf("Deleted answer #%d.", number), nil, nil
+}
+
+//////////
+//      //
+// list //
+//      //
+//////////
+
+var AdvancedList = advancedList{}
+
+type advancedList struct{}
+
+func (c advancedList) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedList) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedList) Names() []string {
+	return core.AliasesList
+}
+
+func (advancedList) Description() string {
+	return "List the custom answers."
+}
+
+func (advancedList) UsageArgs() string {
+	return ""
+}
+
+func (c advancedList) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedList) Examples() []string {
+	return nil
+}
+
+func (advancedList) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedList) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedList) Init() error {
+	return nil
+}
+
+func (c advancedList) Run(m *core.Message) (any, error, error) {
+	lines, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(lines) == 0 {
+		return render(m, "There are no custom answers, the default ones are used.", nil)
+	}
+
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		embed := &dg.MessageEmbed{
+			Title:       "8-Ball Answers",
+			Description: strings.Join(lines, "\n"),
+		}
+		return embed, nil, nil
+	default:
+		return strings.Join(lines, " | "), nil, nil
+	}
+}
+
+func (advancedList) core(m *core.Message) ([]string, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return nil, err
+	}
+
+	answers, err := Answers(here)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for i, a := range answers {
+		lines = append(lines, fmt.Sprintf("#%d %s", i+1, a.Answer))
+	}
+	return lines, nil
+}
+
+///////////
+//       //
+// reset //
+//       //
+///////////
+
+var AdvancedReset = advancedReset{}
+
+type advancedReset struct{}
+
+func (c advancedReset) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedReset) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedReset) Names() []string {
+	return []string{
+		"reset",
+	}
+}
+
+func (advancedReset) Description() string {
+	return "Delete all of the custom answers and go back to the default ones."
+}
+
+func (advancedReset) UsageArgs() string {
+	return ""
+}
+
+func (c advancedReset) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedReset) Examples() []string {
+	return nil
+}
+
+func (advancedReset) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedReset) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedReset) Init() error {
+	return nil
+}
+
+func (c advancedReset) Run(m *core.Message) (any, error, error) {
+	resp, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, nil)
+}
+
+func (advancedReset) core(m *core.Message) (string, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", err
+	}
+	if err := AnswerReset(here); err != nil {
+		return "", err
+	}
+	return "The default answers will be used again.", nil
+}
@@ -0,0 +1,155 @@
+package random
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotEnoughOptions = errors.New("Give me at least two options to choose from, separated by |")
+	ErrAnswerNotFound   = errors.New("Couldn't find an answer with that number.")
+	ErrAnswerTooLong    = errors.New("Answers can be at most 255 characters long.")
+)
+
+// DefaultAnswers are used by the 8-ball in places that haven't added any
+// answers of their own.
+var DefaultAnswers = []string{
+	"It is certain.",
+	"It is decidedly so.",
+	"Without a doubt.",
+	"Yes, definitely.",
+	"You may rely on it.",
+	"As I see it, yes.",
+	"Most likely.",
+	"Outlook good.",
+	"Yes.",
+	"Signs point to yes.",
+	"Reply hazy, try again.",
+	"Ask again later.",
+	"Better not tell you now.",
+	"Cannot predict now.",
+	"Concentrate and ask again.",
+	"Don't count on it.",
+	"My reply is no.",
+	"My sources say no.",
+	"Outlook not so good.",
+	"Very doubtful.",
+}
+
+// Answer is a custom 8-ball answer.
+type Answer struct {
+	ID     int64
+	Answer string
+}
+
+func init() {
+	rand.Seed(time.Now().UnixNano())
+}
+
+// RollArgs parses the arguments of the roll command. The words adv, advantage,
+// dis and disadvantage can appear anywhere and apply advantage or
+// disadvantage to the roll. If there is no expression a d20 is rolled.
+func RollArgs(args []string) ([]Term, error) {
+	var expr []string
+	var adv, dis bool
+	for _, arg := range args {
+		switch strings.ToLower(arg) {
+		case "adv", "advantage":
+			adv = true
+		case "dis", "disadvantage":
+			dis = true
+		default:
+			expr = append(expr, arg)
+		}
+	}
+
+	if len(expr) == 0 {
+		expr = []string{"d20"}
+	}
+
+	terms, err := Parse(strings.Join(expr, ""))
+	if err != nil {
+		return nil, err
+	}
+
+	// they cancel each other out
+	if adv != dis {
+		if err := Advantage(terms, dis); err != nil {
+			return nil, err
+		}
+	}
+	return terms, nil
+}
+
+// Options splits s into the options that the choose command picks from.
+// Options are separated by |, empty ones are ignored.
+func Options(s string) []string {
+	var options []string
+	for _, o := range strings.Split(s, "|") {
+		if o = strings.TrimSpace(o); o != "" {
+			options = append(options, o)
+		}
+	}
+	return options
+}
+
+// Choose returns one of the options at random.
+func Choose(options []string) (string, error) {
+	if len(options) < 2 {
+		return "", ErrNotEnoughOptions
+	}
+	return options[rand.Intn(len(options))], nil
+}
+
+// Coin flips a coin, returns true for heads.
+func Coin() bool {
+	return rand.Intn(2) == 0
+}
+
+// Answers returns the place's custom 8-ball answers.
+func Answers(place int64) ([]Answer, error) {
+	return dbAnswers(place)
+}
+
+// AnswerAdd adds a custom 8-ball answer to the place. Once a place has at
+// least one custom answer the default ones are no longer used.
+func AnswerAdd(place int64, answer string) (error, error) {
+	if len(answer) > 255 {
+		return ErrAnswerTooLong, nil
+	}
+	return nil, dbAnswerAdd(place, answer)
+}
+
+// AnswerDelete deletes the place's custom answer with the specified number,
+// numbers start from 1 and follow the order returned by Answers.
+func AnswerDelete(place int64, number int) (error, error) {
+	answers, err := dbAnswers(place)
+	if err != nil {
+		return nil, err
+	}
+	if number < 1 || number > len(answers) {
+		return ErrAnswerNotFound, nil
+	}
+	return nil, dbAnswerDelete(answers[number-1].ID)
+}
+
+// AnswerReset deletes all of the place's custom answers, which means that the
+// default ones are used again.
+func AnswerReset(place int64) error {
+	return dbAnswerReset(place)
+}
+
+// Ask returns a random 8-ball answer, either one of the place's custom ones or
+// one of the defaults if the place has none.
+func Ask(place int64) (string, error) {
+	answers, err := dbAnswers(place)
+	if err != nil {
+		return "", err
+	}
+	if len(answers) == 0 {
+		return DefaultAnswers[rand.Intn(len(DefaultAnswers))], nil
+	}
+	return answers[rand.Intn(len(answers))].Answer, nil
+}
@@ -0,0 +1,99 @@
+package random
+
+import (
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/rs/zerolog/log"
+)
+
+func dbAnswerAdd(place int64, answer string) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		INSERT INTO cmd_random_answers(place, answer)
+		VALUES ($1, $2)
+	`, place, answer)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Str("answer", answer).
+		Msg("added 8-ball answer")
+
+	return err
+}
+
+func dbAnswerDelete(id int64) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		DELETE FROM cmd_random_answers
+		WHERE id = $1
+	`, id)
+
+	log.Debug().
+		Err(err).
+		Int64("id", id).
+		Msg("deleted 8-ball answer")
+
+	return err
+}
+
+func dbAnswerReset(place int64) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		DELETE FROM cmd_random_answers
+		WHERE place = $1
+	`, place)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Msg("deleted all 8-ball answers")
+
+	return err
+}
+
+// dbAnswers returns the place's custom answers in the order they were added.
+func dbAnswers(place int64) ([]Answer, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	rows, err := db.DB.Query(`
+		SELECT id, answer
+		FROM cmd_random_answers
+		WHERE place = $1
+		ORDER BY id
+	`, place)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var answers []Answer
+	for rows.Next() {
+		var a Answer
+		if err := rows.Scan(&a.ID, &a.Answer); err != nil {
+			return nil, err
+		}
+		answers = append(answers, a)
+	}
+
+	err = rows.Err()
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int("count", len(answers)).
+		Msg("got 8-ball answers")
+
+	return answers, err
+}
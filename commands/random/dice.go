@@ -0,0 +1,350 @@
+package random
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidNotation = errors.New("Couldn't understand that, expected something like 2d6+3, 4d6kh3 or d20.")
+	ErrTooManyDice     = errors.New("That's too many dice, I can only roll up to 100 at once.")
+	ErrTooManySides    = errors.New("Dice can have between 2 and 1000 sides.")
+	ErrInvalidKeep     = errors.New("Can't keep or drop more dice than were rolled.")
+	ErrAdvantage       = errors.New("Advantage and disadvantage only apply to rolls with a single die, like d20+5.")
+)
+
+const (
+	// MaxDice is the maximum number of dice rolled in a single roll, this
+	// includes the extra dice rolled by exploding dice.
+	MaxDice = 100
+
+	// MaxSides is the maximum number of sides a die can have.
+	MaxSides = 1000
+
+	maxTerms    = 20
+	maxConstant = 1000000
+)
+
+// Die is a single rolled die.
+type Die struct {
+	Value int
+
+	// Dropped is true if the die was not counted because of a keep or drop
+	// modifier.
+	Dropped bool
+
+	// Exploded is true if the die rolled its maximum value and caused another
+	// die to be rolled.
+	Exploded bool
+}
+
+// Term is either a group of dice or a constant, for example in 2d6+3 both 2d6
+// and 3 are terms.
+type Term struct {
+	Negative bool
+
+	// Sides is 0 if the term is a constant.
+	Sides    int
+	Count    int
+	Constant int
+
+	// Keep is the number of dice that are counted, 0 counts all of them.
+	Keep    int
+	KeepLow bool
+	Explode bool
+
+	Dice []Die
+}
+
+// Roll is the result of rolling a dice notation expression.
+type Roll struct {
+	Terms []Term
+	Total int
+}
+
+// Notation returns the term in standard dice notation.
+func (t Term) Notation() string {
+	if t.Sides == 0 {
+		return strconv.Itoa(t.Constant)
+	}
+
+	s := fmt.Sprintf("%dd%d", t.Count, t.Sides)
+	if t.Explode {
+		s += "!"
+	}
+	if t.Keep != 0 {
+		if t.KeepLow {
+			s += fmt.Sprintf("kl%d", t.Keep)
+		} else {
+			s += fmt.Sprintf("kh%d", t.Keep)
+		}
+	}
+	return s
+}
+
+// Sum returns the value of the term, taking its sign into account.
+func (t Term) Sum() int {
+	sum := t.Constant
+	if t.Sides != 0 {
+		sum = 0
+		for _, d := range t.Dice {
+			if !d.Dropped {
+				sum += d.Value
+			}
+		}
+	}
+	if t.Negative {
+		return -sum
+	}
+	return sum
+}
+
+// Notation returns the whole roll in standard dice notation.
+func (r Roll) Notation() string {
+	var b strings.Builder
+	for i, t := range r.Terms {
+		if t.Negative {
+			b.WriteString("-")
+		} else if i != 0 {
+			b.WriteString("+")
+		}
+		b.WriteString(t.Notation())
+	}
+	return b.String()
+}
+
+// Dice returns the total number of dice that were rolled.
+func (r Roll) Dice() int {
+	n := 0
+	for _, t := range r.Terms {
+		n += len(t.Dice)
+	}
+	return n
+}
+
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *parser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *parser) accept(c byte) bool {
+	if p.peek() == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// number returns the number at the current position, returns false if there
+// isn't one.
+func (p *parser) number() (int, bool) {
+	start := p.pos
+	for !p.done() && '0' <= p.peek() && p.peek() <= '9' {
+		p.pos++
+	}
+	if start == p.pos {
+		return 0, false
+	}
+	n, err := strconv.Atoi(p.s[start:p.pos])
+	if err != nil || n > maxConstant {
+		return 0, false
+	}
+	return n, true
+}
+
+func (p *parser) term() (Term, error) {
+	var t Term
+
+	n, ok := p.number()
+	if !p.accept('d') {
+		if !ok {
+			return t, ErrInvalidNotation
+		}
+		t.Constant = n
+		return t, nil
+	}
+
+	// d20 is the same as 1d20
+	t.Count = 1
+	if ok {
+		t.Count = n
+	}
+	if t.Count < 1 {
+		return t, ErrInvalidNotation
+	}
+	if t.Count > MaxDice {
+		return t, ErrTooManyDice
+	}
+
+	if p.accept('%') {
+		t.Sides = 100
+	} else if t.Sides, ok = p.number(); !ok {
+		return t, ErrInvalidNotation
+	}
+	if t.Sides < 2 || t.Sides > MaxSides {
+		return t, ErrTooManySides
+	}
+
+	for !p.done() && p.peek() != '+' && p.peek() != '-' {
+		if p.accept('!') {
+			t.Explode = true
+			continue
+		}
+
+		// kh, kl, k, dh and dl, k on its own is the same as kh
+		var drop bool
+		if p.accept('d') {
+			drop = true
+		} else if !p.accept('k') {
+			return t, ErrInvalidNotation
+		}
+
+		low := false
+		if p.accept('l') {
+			low = true
+		} else if !p.accept('h') && drop {
+			// d on its own would be ambiguous
+			return t, ErrInvalidNotation
+		}
+
+		n, ok := p.number()
+		if !ok {
+			n = 1
+		}
+		if n < 1 || n > t.Count {
+			return t, ErrInvalidKeep
+		}
+
+		// dropping the highest is the same as keeping the lowest
+		if drop {
+			t.Keep = t.Count - n
+			t.KeepLow = !low
+			if t.Keep == 0 {
+				return t, ErrInvalidKeep
+			}
+		} else {
+			t.Keep = n
+			t.KeepLow = low
+		}
+	}
+
+	return t, nil
+}
+
+// Parse parses a dice notation expression, for example 2d6+3, 4d6kh3 or
+// d20!-1. Spaces are ignored.
+func Parse(expr string) ([]Term, error) {
+	expr = strings.ToLower(strings.Join(strings.Fields(expr), ""))
+	p := &parser{s: expr}
+
+	var terms []Term
+	for first := true; first || !p.done(); first = false {
+		negative := false
+		if p.accept('-') {
+			negative = true
+		} else if !p.accept('+') && !first {
+			return nil, ErrInvalidNotation
+		}
+
+		t, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		t.Negative = negative
+		terms = append(terms, t)
+
+		if len(terms) > maxTerms {
+			return nil, ErrInvalidNotation
+		}
+	}
+
+	return terms, nil
+}
+
+// Advantage turns the first die of the roll into two dice of which the highest
+// is kept, or the lowest if low is true. Only works for single dice.
+func Advantage(terms []Term, low bool) error {
+	for i, t := range terms {
+		if t.Sides == 0 {
+			continue
+		}
+		if t.Count != 1 || t.Keep != 0 {
+			return ErrAdvantage
+		}
+		terms[i].Count = 2
+		terms[i].Keep = 1
+		terms[i].KeepLow = low
+		return nil
+	}
+	return ErrAdvantage
+}
+
+// Throw rolls the dice in the terms and returns the result.
+func Throw(terms []Term) (Roll, error) {
+	r := Roll{Terms: terms}
+	rolled := 0
+
+	for i := range r.Terms {
+		t := &r.Terms[i]
+		if t.Sides == 0 {
+			r.Total += t.Sum()
+			continue
+		}
+
+		t.Dice = nil
+		for j := 0; j < t.Count; j++ {
+			for {
+				rolled++
+				if rolled > MaxDice {
+					return Roll{}, ErrTooManyDice
+				}
+				d := Die{Value: rand.Intn(t.Sides) + 1}
+				d.Exploded = t.Explode && d.Value == t.Sides
+				t.Dice = append(t.Dice, d)
+				if !d.Exploded {
+					break
+				}
+			}
+		}
+
+		if t.Keep != 0 {
+			keep(t)
+		}
+		r.Total += t.Sum()
+	}
+
+	return r, nil
+}
+
+// keep marks the dice that shouldn't be counted as dropped.
+func keep(t *Term) {
+	indexes := make([]int, len(t.Dice))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		if t.KeepLow {
+			return t.Dice[indexes[i]].Value < t.Dice[indexes[j]].Value
+		}
+		return t.Dice[indexes[i]].Value > t.Dice[indexes[j]].Value
+	})
+	// exploded dice count as separate dice, so they can also be dropped
+	for _, i := range indexes[t.Keep:] {
+		t.Dice[i].Dropped = true
+	}
+}
@@ -0,0 +1,219 @@
+package random
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr     string
+		notation string
+		err      error
+	}{
+		{"2d6+3", "2d6+3", nil},
+		{"d20", "1d20", nil},
+		{"5", "5", nil},
+		{" 2D6 - 1 ", "2d6-1", nil},
+		{"-3+d4", "-3+1d4", nil},
+		{"d%", "1d100", nil},
+		{"3d6!", "3d6!", nil},
+		{"4d6kh3", "4d6kh3", nil},
+		// k on its own is the same as kh
+		{"4d6k3", "4d6kh3", nil},
+		{"4d6k", "4d6kh1", nil},
+		{"2d20kl1", "2d20kl1", nil},
+		// dropping the lowest is the same as keeping the highest
+		{"4d6dl1", "4d6kh3", nil},
+		{"4d6dh1", "4d6kl3", nil},
+		{"4d6!kh3", "4d6!kh3", nil},
+
+		// limits
+		{"100d6", "100d6", nil},
+		{"101d6", "", ErrTooManyDice},
+		{"d1000", "1d1000", nil},
+		{"d1001", "", ErrTooManySides},
+		{"d1", "", ErrTooManySides},
+		{"d0", "", ErrTooManySides},
+		{strings.Repeat("1+", maxTerms-1) + "1", strings.Repeat("1+", maxTerms-1) + "1", nil},
+		{strings.Repeat("1+", maxTerms) + "1", "", ErrInvalidNotation},
+		{"1000000", "1000000", nil},
+		{"1000001", "", ErrInvalidNotation},
+
+		// keep and drop
+		{"2d6kh3", "", ErrInvalidKeep},
+		{"2d6kh0", "", ErrInvalidKeep},
+		{"2d6dh2", "", ErrInvalidKeep},
+
+		// malformed
+		{"", "", ErrInvalidNotation},
+		{"abc", "", ErrInvalidNotation},
+		{"2d", "", ErrInvalidNotation},
+		{"0d6", "", ErrInvalidNotation},
+		{"2d6+", "", ErrInvalidNotation},
+		{"2d6++1", "", ErrInvalidNotation},
+		{"2d6x", "", ErrInvalidNotation},
+		{"2d6*3", "", ErrInvalidNotation},
+		// d on its own after the sides would be ambiguous
+		{"4d6d1", "", ErrInvalidNotation},
+	}
+
+	for _, test := range tests {
+		terms, err := Parse(test.expr)
+		if err != test.err {
+			t.Errorf("Parse('%s'): expected error %v, got %v", test.expr, test.err, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if notation := (Roll{Terms: terms}).Notation(); notation != test.notation {
+			t.Errorf("Parse('%s'): expected '%s', got '%s'", test.expr, test.notation, notation)
+		}
+	}
+}
+
+func TestAdvantage(t *testing.T) {
+	tests := []struct {
+		expr     string
+		low      bool
+		notation string
+		err      error
+	}{
+		{"d20", false, "2d20kh1", nil},
+		{"d20+5", true, "2d20kl1+5", nil},
+		{"3+d20", false, "3+2d20kh1", nil},
+		{"2d6", false, "", ErrAdvantage},
+		{"2d20kh1", false, "", ErrAdvantage},
+		{"5", false, "", ErrAdvantage},
+	}
+
+	for _, test := range tests {
+		terms, err := Parse(test.expr)
+		if err != nil {
+			t.Fatalf("Parse('%s'): unexpected error %v", test.expr, err)
+		}
+		if err := Advantage(terms, test.low); err != test.err {
+			t.Errorf("Advantage('%s'): expected error %v, got %v", test.expr, test.err, err)
+			continue
+		}
+		if test.err != nil {
+			continue
+		}
+		if notation := (Roll{Terms: terms}).Notation(); notation != test.notation {
+			t.Errorf("Advantage('%s'): expected '%s', got '%s'", test.expr, test.notation, notation)
+		}
+	}
+}
+
+func TestThrow(t *testing.T) {
+	tests := []struct {
+		expr     string
+		min, max int
+		dice     int
+		kept     int
+	}{
+		{"5", 5, 5, 0, 0},
+		{"2d6+3", 5, 15, 2, 2},
+		{"d20-1", 0, 19, 1, 1},
+		{"-d4", -4, -1, 1, 1},
+		{"4d6kh3", 3, 18, 4, 3},
+		{"2d20kl1", 1, 20, 2, 1},
+		{"d%", 1, 100, 1, 1},
+	}
+
+	// the dice are random so each expression is thrown a few times
+	for _, test := range tests {
+		terms, err := Parse(test.expr)
+		if err != nil {
+			t.Fatalf("Parse('%s'): unexpected error %v", test.expr, err)
+		}
+		for i := 0; i < 100; i++ {
+			r, err := Throw(terms)
+			if err != nil {
+				t.Fatalf("Throw('%s'): unexpected error %v", test.expr, err)
+			}
+			if r.Total < test.min || r.Total > test.max {
+				t.Fatalf("Throw('%s'): expected a total between %d and %d, got %d", test.expr, test.min, test.max, r.Total)
+			}
+			if r.Dice() != test.dice {
+				t.Fatalf("Throw('%s'): expected %d dice, got %d", test.expr, test.dice, r.Dice())
+			}
+			kept := 0
+			for _, term := range r.Terms {
+				for _, d := range term.Dice {
+					if d.Value < 1 || d.Value > term.Sides {
+						t.Fatalf("Throw('%s'): die out of range, got %d", test.expr, d.Value)
+					}
+					if !d.Dropped {
+						kept++
+					}
+				}
+			}
+			if kept != test.kept {
+				t.Fatalf("Throw('%s'): expected %d kept dice, got %d", test.expr, test.kept, kept)
+			}
+		}
+	}
+}
+
+func TestThrowExplode(t *testing.T) {
+	terms, err := Parse("d2!")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		r, err := Throw(terms)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		dice := r.Terms[0].Dice
+		for j, d := range dice {
+			if d.Exploded != (d.Value == 2) {
+				t.Fatalf("die %d rolled %d, exploded is %t", j, d.Value, d.Exploded)
+			}
+		}
+		if dice[len(dice)-1].Exploded {
+			t.Fatalf("the last die exploded but no more dice were rolled: %#v", dice)
+		}
+	}
+
+	// a 2 explodes, so rolling 100 of them is almost certainly over the limit
+	terms, err = Parse("100d2!")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, err := Throw(terms); err != ErrTooManyDice {
+		t.Fatalf("expected error %v, got %v", ErrTooManyDice, err)
+	}
+}
+
+func TestKeep(t *testing.T) {
+	tests := []struct {
+		values  []int
+		keep    int
+		low     bool
+		dropped []bool
+	}{
+		{[]int{3, 1, 5, 5}, 2, false, []bool{true, true, false, false}},
+		{[]int{3, 1, 5, 5}, 1, true, []bool{true, false, true, true}},
+		// ties are broken by the order in which the dice were rolled
+		{[]int{4, 2, 2}, 1, true, []bool{true, false, true}},
+		{[]int{6, 6, 6}, 2, false, []bool{false, false, true}},
+	}
+
+	for _, test := range tests {
+		term := Term{Keep: test.keep, KeepLow: test.low}
+		for _, v := range test.values {
+			term.Dice = append(term.Dice, Die{Value: v})
+		}
+		keep(&term)
+		for i, d := range term.Dice {
+			if d.Dropped != test.dropped[i] {
+				t.Errorf("keep(%v, %d, %t): expected dropped %v, got %#v", test.values, test.keep, test.low, test.dropped, term.Dice)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,365 @@
+package random
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+
+	dg "github.com/bwmarrin/discordgo"
+)
+
+func render(m *core.Message, resp string, usrErr error) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return &dg.MessageEmbed{Description: resp}, usrErr, nil
+	default:
+		return resp, usrErr, nil
+	}
+}
+
+//////////
+//      //
+// roll //
+//      //
+//////////
+
+var NormalRoll = normalRoll{}
+
+type normalRoll struct{}
+
+func (normalRoll) Type() core.CommandType {
+	return core.Normal
+}
+
+func (normalRoll) Permitted(*core.Message) bool {
+	return true
+}
+
+func (normalRoll) Names() []string {
+	return []string{
+		"roll",
+		"dice",
+		"r",
+	}
+}
+
+func (normalRoll) Description() string {
+	return "Roll dice using dice notation, rolls a d20 by default."
+}
+
+func (normalRoll) UsageArgs() string {
+	return "[dice...] [adv | dis]"
+}
+
+func (normalRoll) Category() core.CommandCategory {
+	return core.CommandCategoryGames
+}
+
+func (normalRoll) Examples() []string {
+	return []string{
+		"",
+		"2d6+3",
+		"4d6kh3",
+		"3d6!",
+		"d20+5 adv",
+		"d% - 10",
+	}
+}
+
+func (normalRoll) Parent() core.CommandStatic {
+	return nil
+}
+
+func (normalRoll) Children() core.CommandsStatic {
+	return nil
+}
+
+func (normalRoll) Init() error {
+	return nil
+}
+
+func (c normalRoll) Run(m *core.Message) (any, error, error) {
+	r, usrErr := c.core(m)
+	if usrErr != nil {
+		return render(m, fmt.Sprint(usrErr), usrErr)
+	}
+
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return c.discord(r), nil, nil
+	default:
+		return c.text(r), nil, nil
+	}
+}
+
+func (normalRoll) discord(r Roll) *dg.MessageEmbed {
+	var lines []string
+	for i, t := range r.Terms {
+		sign := ""
+		if t.Negative {
+			sign = "- "
+		} else if i != 0 {
+			sign = "+ "
+		}
+
+		if t.Sides == 0 {
+			lines = append(lines, fmt.Sprintf("%s**%d**", sign, t.Constant))
+			continue
+		}
+
+		var dice []string
+		for _, d := range t.Dice {
+			dice = append(dice, formatDie(d, "~~"+strconv.Itoa(d.Value)+"~~"))
+		}
+		lines = append(lines, fmt.Sprintf("%s**%s** [%s]", sign, t.Notation(), strings.Join(dice, ", ")))
+	}
+
+	return &dg.MessageEmbed{
+		Title:       fmt.Sprintf("🎲 %d", r.Total),
+		Description: strings.Join(lines, "\n"),
+		Footer: &dg.MessageEmbedFooter{
+			Text: r.Notation(),
+		},
+	}
+}
+
+func (normalRoll) text(r Roll) string {
+	// the breakdown of big rolls would be too long for a chat message
+	const max = 20
+	if r.Dice() > max {
+		return fmt.Sprintf("🎲 %s = %d", r.Notation(), r.Total)
+	}
+
+	var parts []string
+	for i, t := range r.Terms {
+		sign := ""
+		if t.Negative {
+			sign = "- "
+		} else if i != 0 {
+			sign = "+ "
+		}
+
+		if t.Sides == 0 {
+			parts = append(parts, sign+strconv.Itoa(t.Constant))
+			continue
+		}
+
+		var dice []string
+		for _, d := range t.Dice {
+			dice = append(dice, formatDie(d, "("+strconv.Itoa(d.Value)+")"))
+		}
+		parts = append(parts, sign+"["+strings.Join(dice, ", ")+"]")
+	}
+
+	return fmt.Sprintf("🎲 %s: %s = %d", r.Notation(), strings.Join(parts, " "), r.Total)
+}
+
+// formatDie returns the die's value followed by a ! if it exploded, dropped is
+// used instead of the value if the die wasn't counted.
+func formatDie(d Die, dropped string) string {
+	s := strconv.Itoa(d.Value)
+	if d.Dropped {
+		s = dropped
+	}
+	if d.Exploded {
+		s += "!"
+	}
+	return s
+}
+
+func (normalRoll) core(m *core.Message) (Roll, error) {
+	terms, err := RollArgs(m.Command.Args)
+	if err != nil {
+		return Roll{}, err
+	}
+	return Throw(terms)
+}
+
+////////////
+//        //
+// choose //
+//        //
+////////////
+
+var NormalChoose = normalChoose{}
+
+type normalChoose struct{}
+
+func (normalChoose) Type() core.CommandType {
+	return core.Normal
+}
+
+func (normalChoose) Permitted(*core.Message) bool {
+	return true
+}
+
+func (normalChoose) Names() []string {
+	return []string{
+		"choose",
+		"pick",
+	}
+}
+
+func (normalChoose) Description() string {
+	return "Pick one of the options at random."
+}
+
+func (normalChoose) UsageArgs() string {
+	return "<option> | <option>..."
+}
+
+func (normalChoose) Category() core.CommandCategory {
+	return core.CommandCategoryOther
+}
+
+func (normalChoose) Examples() []string {
+	return []string{
+		"pizza | burgers | sushi",
+	}
+}
+
+func (normalChoose) Parent() core.CommandStatic {
+	return nil
+}
+
+func (normalChoose) Children() core.CommandsStatic {
+	return nil
+}
+
+func (normalChoose) Init() error {
+	return nil
+}
+
+func (c normalChoose) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr := c.core(m)
+	return render(m, resp, usrErr)
+}
+
+func (normalChoose) core(m *core.Message) (string, error) {
+	choice, usrErr := Choose(Options(m.RawArgs(0)))
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr
+	}
+	return "I choose: " + choice, nil
+}
+
+//////////
+//      //
+// coin //
+//      //
+//////////
+
+var NormalCoin = normalCoin{}
+
+type normalCoin struct{}
+
+func (normalCoin) Type() core.CommandType {
+	return core.Normal
+}
+
+func (normalCoin) Permitted(*core.Message) bool {
+	return true
+}
+
+func (normalCoin) Names() []string {
+	return []string{
+		"coin",
+		"flip",
+		"coinflip",
+	}
+}
+
+func (normalCoin) Description() string {
+	return "Flip a coin."
+}
+
+func (normalCoin) UsageArgs() string {
+	return ""
+}
+
+func (normalCoin) Category() core.CommandCategory {
+	return core.CommandCategoryGames
+}
+
+func (normalCoin) Examples() []string {
+	return nil
+}
+
+func (normalCoin) Parent() core.CommandStatic {
+	return nil
+}
+
+func (normalCoin) Children() core.CommandsStatic {
+	return nil
+}
+
+func (normalCoin) Init() error {
+	return nil
+}
+
+func (normalCoin) Run(m *core.Message) (any, error, error) {
+	if Coin() {
+		return render(m, "🪙 Heads!", nil)
+	}
+	return render(m, "🪙 Tails!", nil)
+}
+
+///////////
+//       //
+// 8ball //
+//       //
+///////////
+
+var NormalEightBall = normalEightBall{}
+
+type normalEightBall struct{}
+
+func (normalEightBall) Type() core.CommandType {
+	return core.Normal
+}
+
+func (normalEightBall) Permitted(m *core.Message) bool {
+	return Advanced.Permitted(m)
+}
+
+func (normalEightBall) Names() []string {
+	return Advanced.Names()
+}
+
+func (normalEightBall) Description() string {
+	return AdvancedAsk.Description()
+}
+
+func (normalEightBall) UsageArgs() string {
+	return AdvancedAsk.UsageArgs()
+}
+
+func (normalEightBall) Category() core.CommandCategory {
+	return Advanced.Category()
+}
+
+func (normalEightBall) Examples() []string {
+	return AdvancedAsk.Examples()
+}
+
+func (normalEightBall) Parent() core.CommandStatic {
+	return nil
+}
+
+func (normalEightBall) Children() core.CommandsStatic {
+	return nil
+}
+
+func (normalEightBall) Init() error {
+	return nil
+}
+
+func (normalEightBall) Run(m *core.Message) (any, error, error) {
+	return AdvancedAsk.Run(m)
+}
@@ -1,8 +1,34 @@
 package rps
 
 import (
+	crand "crypto/rand"
+	"errors"
+	"fmt"
 	"math/rand"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/janitorjeff/jeff-bot/commands/nick"
+	"github.com/janitorjeff/jeff-bot/commands/points"
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+	"github.com/janitorjeff/jeff-bot/frontends/twitch"
+
+	dg "github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrSelf           = errors.New("You can't challenge yourself.")
+	ErrBusy           = errors.New("One of you is already in a game.")
+	ErrNoChallenge    = errors.New("You're not in a game right now.")
+	ErrAlreadyMoved   = errors.New("You've already chosen your move.")
+	ErrInvalidWager   = errors.New("Expected a positive number of points.")
+	ErrNotEnough      = errors.New("Both players need to have enough points for the wager.")
+	ErrPersonNotFound = errors.New("Couldn't find that person.")
+	ErrNoPrivate      = errors.New("Games against someone are only available on Discord and Twitch, where moves can be chosen in private.")
+	ErrPublicMove     = errors.New("Your move has to stay secret, send it in your DMs with me or use one of the secret codes I whispered you.")
 )
 
 const (
@@ -17,6 +43,69 @@ const (
 	loss
 )
 
+const (
+	// the time both players have to choose their moves
+	challengeTimeout = time.Minute
+
+	// the length of the secret codes that stand for moves, and the
+	// characters they are made of
+	codeLength = 5
+	codeChars  = "abcdefghjkmnpqrstuvwxyz23456789"
+
+	// LeaderboardSize is the number of people shown in the leaderboard.
+	LeaderboardSize = 10
+)
+
+// Stats are a person's record in a place. Streak is the current number of
+// consecutive wins, draws don't break it.
+type Stats struct {
+	Person int64
+	Wins   int64
+	Losses int64
+	Draws  int64
+	Streak int64
+	Best   int64
+}
+
+// parseMove returns the move that s refers to, returns false if it doesn't
+// refer to any.
+func parseMove(s string) (int, bool) {
+	switch s {
+	case "r", "rock", "🪨":
+		return rock, true
+	case "p", "paper", "🧻", "📰", "🗞 ":
+		return paper, true
+	case "s", "scissors", "✂":
+		return scissors, true
+	default:
+		return -1, false
+	}
+}
+
+func moveName(move int) string {
+	switch move {
+	case rock:
+		return "rock"
+	case paper:
+		return "paper"
+	default:
+		return "scissors"
+	}
+}
+
+// outcome returns the result of the game from the player's point of view.
+func outcome(player, other int) int {
+	if player == other {
+		return draw
+	} else if player == (other+1)%3 {
+		// the winning choice is always positioned to the right, for example
+		// paper = 0, scissors beats papers, scissors = 1. Mod 3 for rock = 2
+		// which beats paper.
+		return win
+	}
+	return loss
+}
+
 func run(player int) (int, int) {
 	var computer int
 	rand.Seed(time.Now().UnixNano())
@@ -28,18 +117,398 @@ func run(player int) (int, int) {
 	case 2:
 		computer = scissors
 	}
+	return outcome(player, computer), computer
+}
 
-	var result int
-	if player == computer {
-		result = draw
-	} else if player == (computer+1)%3 {
-		// the winning choice is always positioned to the right, for example
-		// paper = 0, scissors beats papers, scissors = 1. Mod 3 for rock = 2
-		// which beats paper.
-		result = win
+// StatsGet returns the person's record in the specified place.
+func StatsGet(place, person int64) (Stats, error) {
+	return dbStats(place, person)
+}
+
+// Top returns the people with the most wins in the specified place.
+func Top(place int64) ([]Stats, error) {
+	return dbTop(place, LeaderboardSize)
+}
+
+////////////////
+//            //
+// challenges //
+//            //
+////////////////
+
+type challenge struct {
+	place   int64 // the exact place, where the result is sent
+	logical int64 // where stats and points are kept
+	msgID   string
+
+	challenger int64
+	opponent   int64
+	wager      int64
+
+	// the secret codes that were whispered to the players, if any
+	codes []string
+
+	lock  sync.Mutex
+	moves map[int64]int
+	done  chan struct{}
+}
+
+// secret is what a secret code stands for.
+type secret struct {
+	person int64
+	move   int
+}
+
+var (
+	lock sync.Mutex
+
+	// the challenge each person is currently taking part in
+	challenges = map[int64]*challenge{}
+
+	// the secret codes of all the ongoing challenges
+	secrets = map[string]secret{}
+)
+
+// Challenge challenges the opponent to a game in the place the message came
+// from. Both players then have challengeTimeout to choose their moves using
+// Move, after which the result is sent. If wager is more than 0 then it's
+// taken from both players until the game is over, the winner gets both and
+// they are refunded on a draw or if the game is cancelled. Moves have to stay
+// secret, so on discord they are sent in DMs and on twitch each player is
+// whispered a secret code for each move which they then send in chat.
+func Challenge(m *core.Message, opponent, wager int64) (error, error) {
+	discordGame := m.Frontend.Type() == discord.Frontend.Type()
+	if !discordGame && m.Frontend.Type() != twitch.Frontend.Type() {
+		return ErrNoPrivate, nil
+	}
+
+	challenger, err := m.Author.Scope()
+	if err != nil {
+		return nil, err
+	}
+	if challenger == opponent {
+		return ErrSelf, nil
+	}
+	if wager < 0 {
+		return ErrInvalidWager, nil
+	}
+
+	place, err := m.Here.ScopeExact()
+	if err != nil {
+		return nil, err
+	}
+	logical, err := m.Here.ScopeLogical()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &challenge{
+		place:      place,
+		logical:    logical,
+		msgID:      m.ID,
+		challenger: challenger,
+		opponent:   opponent,
+		wager:      wager,
+		moves:      map[int64]int{},
+		done:       make(chan struct{}),
+	}
+
+	lock.Lock()
+	_, busy1 := challenges[challenger]
+	_, busy2 := challenges[opponent]
+	if busy1 || busy2 {
+		lock.Unlock()
+		return ErrBusy, nil
+	}
+	challenges[challenger] = c
+	challenges[opponent] = c
+	lock.Unlock()
+
+	if usrErr, err := c.escrow(); usrErr != nil || err != nil {
+		c.end()
+		return usrErr, err
+	}
+
+	if discordGame {
+		c.dm()
+	} else if usrErr, err := c.whisper(m.Here.Name()); usrErr != nil || err != nil {
+		c.end()
+		if rErr := c.refund(); rErr != nil {
+			log.Debug().Err(rErr).Msg("failed to refund rps wager")
+		}
+		return usrErr, err
+	}
+
+	go c.wait()
+	return nil, nil
+}
+
+// end removes the challenge and its secret codes, after which no more moves
+// can be made.
+func (c *challenge) end() {
+	lock.Lock()
+	defer lock.Unlock()
+	delete(challenges, c.challenger)
+	delete(challenges, c.opponent)
+	for _, code := range c.codes {
+		delete(secrets, code)
+	}
+}
+
+// escrow takes the wager from both players so that the winner can always be
+// paid. Returns ErrNotEnough if either of them doesn't have enough points, in
+// which case nothing is taken.
+func (c *challenge) escrow() (error, error) {
+	if c.wager == 0 {
+		return nil, nil
+	}
+	return points.Transaction(c.logical, func(t *points.Tx) (error, error) {
+		for _, p := range []int64{c.challenger, c.opponent} {
+			_, usrErr, err := t.Debit(p, c.wager, "rps")
+			if err != nil {
+				return nil, err
+			}
+			if usrErr != nil {
+				return ErrNotEnough, nil
+			}
+		}
+		return nil, nil
+	})
+}
+
+// pay gives each person the specified number of points out of the escrow.
+func (c *challenge) pay(amounts map[int64]int64, reason string) error {
+	if c.wager == 0 {
+		return nil
+	}
+	_, err := points.Transaction(c.logical, func(t *points.Tx) (error, error) {
+		for p, amount := range amounts {
+			if _, err := t.Credit(p, amount, reason); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// refund gives both players their wager back.
+func (c *challenge) refund() error {
+	return c.pay(map[int64]int64{c.challenger: c.wager, c.opponent: c.wager}, "rps refund")
+}
+
+// newCode returns a secret code that isn't in use, the lock must be held.
+// The codes must not be predictable, so they come from crypto/rand.
+func newCode() (string, error) {
+	for {
+		b := make([]byte, codeLength)
+		if _, err := crand.Read(b); err != nil {
+			return "", err
+		}
+		for i := range b {
+			b[i] = codeChars[int(b[i])%len(codeChars)]
+		}
+		if _, ok := secrets[string(b)]; !ok {
+			return string(b), nil
+		}
+	}
+}
+
+// whisper gives both players a secret code for each move and whispers it to
+// them, so that they can choose their move in chat without anyone else
+// knowing which one it is.
+func (c *challenge) whisper(channel string) (error, error) {
+	type whisper struct {
+		id   string
+		text string
+	}
+	var whispers []whisper
+
+	lock.Lock()
+	for _, p := range []int64{c.challenger, c.opponent} {
+		id, err := core.DB.ScopeID(p)
+		if err != nil {
+			lock.Unlock()
+			return nil, err
+		}
+
+		codes := map[int]string{}
+		for _, move := range []int{rock, paper, scissors} {
+			code, err := newCode()
+			if err != nil {
+				lock.Unlock()
+				return nil, err
+			}
+			secrets[code] = secret{person: p, move: move}
+			c.codes = append(c.codes, code)
+			codes[move] = code
+		}
+
+		text := fmt.Sprintf("Rock paper scissors in %s: send %s in chat for rock, "+
+			"%s for paper or %s for scissors within a minute. Only you know which "+
+			"code is which.", channel, codes[rock], codes[paper], codes[scissors])
+		whispers = append(whispers, whisper{id, text})
+	}
+	lock.Unlock()
+
+	for _, w := range whispers {
+		if usrErr, err := twitch.Whisper(w.id, w.text); usrErr != nil || err != nil {
+			return usrErr, err
+		}
+	}
+	return nil, nil
+}
+
+// secretHook lets players choose their move by sending one of the secret
+// codes they were whispered.
+func secretHook(m *core.Message) {
+	code := strings.ToLower(strings.TrimSpace(m.Raw))
+
+	lock.Lock()
+	s, ok := secrets[code]
+	lock.Unlock()
+	if !ok {
+		return
+	}
+
+	person, err := m.Author.Scope()
+	if err != nil || person != s.person {
+		return
+	}
+
+	resp := "Got your move."
+	if usrErr := Move(person, s.move); usrErr != nil {
+		resp = fmt.Sprint(usrErr)
+	}
+	_, err = m.Client.Write(resp, nil)
+	log.Debug().Err(err).Msg("got rps move from secret code")
+}
+
+// dm lets both players know that they can choose their moves in private.
+func (c *challenge) dm() {
+	const text = "Choose your move for rock paper scissors by replying here " +
+		"with `!rps move rock`, `paper` or `scissors` within a minute."
+
+	for _, p := range []int64{c.challenger, c.opponent} {
+		id, err := core.DB.ScopeID(p)
+		if err != nil {
+			continue
+		}
+		err = discord.DM(id, text)
+		log.Debug().Err(err).Int64("person", p).Msg("sent rps dm")
+	}
+}
+
+// Move sets the person's move in the game they are currently taking part in.
+func Move(person int64, move int) error {
+	lock.Lock()
+	c, ok := challenges[person]
+	lock.Unlock()
+	if !ok {
+		return ErrNoChallenge
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.moves[person]; ok {
+		return ErrAlreadyMoved
+	}
+	c.moves[person] = move
+
+	if len(c.moves) == 2 {
+		close(c.done)
+	}
+	return nil
+}
+
+// wait waits for both players to choose their moves and sends the result.
+func (c *challenge) wait() {
+	select {
+	case <-c.done:
+	case <-time.After(challengeTimeout):
+	}
+
+	c.end()
+
+	resp, err := c.resolve()
+	if err != nil {
+		log.Debug().Err(err).Msg("failed to resolve rps challenge")
+		return
+	}
+
+	m, err := core.Frontends.CreateMessage(c.challenger, c.place, c.msgID)
+	if err != nil {
+		log.Debug().Err(err).Msg("failed to create rps message")
+		return
+	}
+
+	if m.Frontend.Type() == discord.Frontend.Type() {
+		_, err = m.Client.Send(&dg.MessageEmbed{Description: resp}, nil)
 	} else {
-		result = loss
+		_, err = m.Client.Send(resp, nil)
+	}
+	log.Debug().Err(err).Msg("sent rps result")
+}
+
+// resolve records the result, pays out the wager or refunds it and returns
+// the message that announces the result.
+func (c *challenge) resolve() (string, error) {
+	challengerName, err := nick.Name(c.challenger, c.logical)
+	if err != nil {
+		return "", err
+	}
+	opponentName, err := nick.Name(c.opponent, c.logical)
+	if err != nil {
+		return "", err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	challengerMove, ok1 := c.moves[c.challenger]
+	opponentMove, ok2 := c.moves[c.opponent]
+	if !ok1 || !ok2 {
+		if err := c.refund(); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("The game between %s and %s was cancelled, not everyone chose a move in time.",
+			challengerName, opponentName), nil
+	}
+
+	result := outcome(challengerMove, opponentMove)
+
+	if err := dbRecord(c.logical, c.challenger, result); err != nil {
+		return "", err
+	}
+	// the opponent's result is the opposite
+	if err := dbRecord(c.logical, c.opponent, 2-result); err != nil {
+		return "", err
+	}
+
+	resp := fmt.Sprintf("%s chose %s, %s chose %s. ",
+		challengerName, moveName(challengerMove), opponentName, moveName(opponentMove))
+
+	var winner int64
+	var winnerName string
+	switch result {
+	case draw:
+		if err := c.refund(); err != nil {
+			return "", err
+		}
+		return resp + "It's a draw!", nil
+	case win:
+		winner, winnerName = c.challenger, challengerName
+	case loss:
+		winner, winnerName = c.opponent, opponentName
+	}
+
+	if c.wager == 0 {
+		return resp + winnerName + " wins!", nil
 	}
 
-	return result, computer
+	if err := c.pay(map[int64]int64{winner: 2 * c.wager}, "rps"); err != nil {
+		return "", err
+	}
+	return resp + fmt.Sprintf("%s wins %d points!", winnerName, c.wager), nil
 }
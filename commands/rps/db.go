@@ -0,0 +1,121 @@
+package rps
+
+import (
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/rs/zerolog/log"
+)
+
+func dbRecord(place, person int64, result int) error {
+	if err := core.DB.SettingsPersonGenerate(person, place); err != nil {
+		return err
+	}
+
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	// draws don't affect the streak
+	var set string
+	switch result {
+	case win:
+		set = `
+			cmd_rps_wins = cmd_rps_wins + 1,
+			cmd_rps_streak = cmd_rps_streak + 1,
+			cmd_rps_best = GREATEST(cmd_rps_best, cmd_rps_streak + 1)`
+	case loss:
+		set = `
+			cmd_rps_losses = cmd_rps_losses + 1,
+			cmd_rps_streak = 0`
+	default:
+		set = `
+			cmd_rps_draws = cmd_rps_draws + 1`
+	}
+
+	_, err := db.DB.Exec(`
+		UPDATE settings_person
+		SET `+set+`
+		WHERE person = $1 and place = $2
+	`, person, place)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int64("person", person).
+		Int("result", result).
+		Msg("recorded rps result")
+
+	return err
+}
+
+const statsColumns = `person, cmd_rps_wins, cmd_rps_losses, cmd_rps_draws, cmd_rps_streak, cmd_rps_best`
+
+func scanStats(row interface{ Scan(...any) error }) (Stats, error) {
+	var s Stats
+	err := row.Scan(&s.Person, &s.Wins, &s.Losses, &s.Draws, &s.Streak, &s.Best)
+	return s, err
+}
+
+func dbStats(place, person int64) (Stats, error) {
+	if err := core.DB.SettingsPersonGenerate(person, place); err != nil {
+		return Stats{}, err
+	}
+
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	row := db.DB.QueryRow(`
+		SELECT `+statsColumns+`
+		FROM settings_person
+		WHERE person = $1 and place = $2
+	`, person, place)
+
+	s, err := scanStats(row)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int64("person", person).
+		Interface("stats", s).
+		Msg("got rps stats")
+
+	return s, err
+}
+
+func dbTop(place int64, limit int) ([]Stats, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	rows, err := db.DB.Query(`
+		SELECT `+statsColumns+`
+		FROM settings_person
+		WHERE place = $1 and cmd_rps_wins > 0
+		ORDER BY cmd_rps_wins DESC, cmd_rps_losses, person
+		LIMIT $2
+	`, place, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var top []Stats
+	for rows.Next() {
+		s, err := scanStats(rows)
+		if err != nil {
+			return nil, err
+		}
+		top = append(top, s)
+	}
+
+	err = rows.Err()
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int("count", len(top)).
+		Msg("got rps leaderboard")
+
+	return top, err
+}
@@ -3,7 +3,10 @@ package rps
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/janitorjeff/jeff-bot/commands/nick"
 	"github.com/janitorjeff/jeff-bot/core"
 	"github.com/janitorjeff/jeff-bot/frontends/discord"
 
@@ -12,6 +15,15 @@ import (
 
 var errUnexpectedArgument = errors.New("got an unexpected argument")
 
+func render(m *core.Message, resp string, usrErr error) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return &dg.MessageEmbed{Description: resp}, usrErr, nil
+	default:
+		return resp, usrErr, nil
+	}
+}
+
 var Normal = normal{}
 
 type normal struct{}
@@ -35,7 +47,7 @@ func (normal) Description() string {
 }
 
 func (normal) UsageArgs() string {
-	return "(r[ock] | p[aper] | s[cissors])"
+	return "(r[ock] | p[aper] | s[cissors]) | <person> [wager]"
 }
 
 func (normal) Category() core.CommandCategory {
@@ -51,10 +63,15 @@ func (normal) Parent() core.CommandStatic {
 }
 
 func (normal) Children() core.CommandsStatic {
-	return nil
+	return core.CommandsStatic{
+		NormalMove,
+		NormalStats,
+		NormalTop,
+	}
 }
 
 func (normal) Init() error {
+	core.Hooks.Register(secretHook)
 	return nil
 }
 
@@ -63,6 +80,10 @@ func (c normal) Run(m *core.Message) (any, error, error) {
 		return m.Usage(), core.ErrMissingArgs, nil
 	}
 
+	if _, ok := parseMove(m.Command.Args[0]); !ok {
+		return c.challenge(m)
+	}
+
 	switch m.Frontend.Type() {
 	case discord.Frontend.Type():
 		return c.discord(m)
@@ -144,17 +165,346 @@ func (normal) err(usrErr error) string {
 }
 
 func (normal) core(m *core.Message) (int, int, error) {
-	var player int
-	switch m.Command.Args[0] {
-	case "r", "rock", "🪨":
-		player = rock
-	case "p", "paper", "🧻", "📰", "🗞 ":
-		player = paper
-	case "s", "scissors", "✂":
-		player = scissors
-	default:
+	player, ok := parseMove(m.Command.Args[0])
+	if !ok {
 		return -1, -1, errUnexpectedArgument
 	}
 	result, computer := run(player)
 	return result, computer, nil
 }
+
+func (c normal) challenge(m *core.Message) (any, error, error) {
+	resp, usrErr, err := c.challengeCore(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (normal) challengeCore(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	opponent, err := nick.ParsePerson(m, here, m.Command.Args[0])
+	if err != nil {
+		return fmt.Sprint(ErrPersonNotFound), ErrPersonNotFound, nil
+	}
+
+	var wager int64
+	if len(m.Command.Args) > 1 {
+		wager, err = strconv.ParseInt(m.Command.Args[1], 10, 64)
+		if err != nil || wager <= 0 {
+			return fmt.Sprint(ErrInvalidWager), ErrInvalidWager, nil
+		}
+	}
+
+	usrErr, err := Challenge(m, opponent, wager)
+	if err != nil {
+		return "", nil, err
+	}
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+
+	name, err := nick.Name(opponent, here)
+	if err != nil {
+		return "", nil, err
+	}
+
+	resp := fmt.Sprintf("%s challenged %s to rock paper scissors", m.Author.DisplayName(), name)
+	if wager > 0 {
+		resp += fmt.Sprintf(" for %d points", wager)
+	}
+
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return resp + "! Check your DMs to choose your moves, you have a minute.", nil, nil
+	default:
+		return resp + "! Check your whispers for the secret codes to choose your moves, you have a minute.", nil, nil
+	}
+}
+
+//////////
+//      //
+// move //
+//      //
+//////////
+
+var NormalMove = normalMove{}
+
+type normalMove struct{}
+
+func (c normalMove) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c normalMove) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (normalMove) Names() []string {
+	return []string{
+		"move",
+		"choose",
+	}
+}
+
+func (normalMove) Description() string {
+	return "Choose your move in a game against someone, only works in DMs so that it stays secret."
+}
+
+func (normalMove) UsageArgs() string {
+	return "(r[ock] | p[aper] | s[cissors])"
+}
+
+func (c normalMove) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (normalMove) Examples() []string {
+	return nil
+}
+
+func (normalMove) Parent() core.CommandStatic {
+	return Normal
+}
+
+func (normalMove) Children() core.CommandsStatic {
+	return nil
+}
+
+func (normalMove) Init() error {
+	return nil
+}
+
+func (c normalMove) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (normalMove) core(m *core.Message) (string, error, error) {
+	// a move sent anywhere else would be seen by the opponent
+	if !isDM(m) {
+		return fmt.Sprint(ErrPublicMove), ErrPublicMove, nil
+	}
+
+	move, ok := parseMove(m.Command.Args[0])
+	if !ok {
+		return fmt.Sprint(errUnexpectedArgument), errUnexpectedArgument, nil
+	}
+
+	author, err := m.Author.Scope()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if usrErr := Move(author, move); usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+
+	return "Got your move.", nil, nil
+}
+
+func isDM(m *core.Message) bool {
+	if m.Frontend.Type() != discord.Frontend.Type() {
+		return false
+	}
+	return m.Here.(*discord.Here).GuildID == ""
+}
+
+///////////
+//       //
+// stats //
+//       //
+///////////
+
+var NormalStats = normalStats{}
+
+type normalStats struct{}
+
+func (c normalStats) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c normalStats) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (normalStats) Names() []string {
+	return []string{
+		"stats",
+		"record",
+	}
+}
+
+func (normalStats) Description() string {
+	return "Show someone's wins, losses, draws and streaks against other people."
+}
+
+func (normalStats) UsageArgs() string {
+	return "[person]"
+}
+
+func (c normalStats) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (normalStats) Examples() []string {
+	return nil
+}
+
+func (normalStats) Parent() core.CommandStatic {
+	return Normal
+}
+
+func (normalStats) Children() core.CommandsStatic {
+	return nil
+}
+
+func (normalStats) Init() error {
+	return nil
+}
+
+func (c normalStats) Run(m *core.Message) (any, error, error) {
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (normalStats) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	person, err := m.Author.Scope()
+	if err != nil {
+		return "", nil, err
+	}
+	if len(m.Command.Args) > 0 {
+		person, err = nick.ParsePerson(m, here, m.Command.Args[0])
+		if err != nil {
+			return fmt.Sprint(ErrPersonNotFound), ErrPersonNotFound, nil
+		}
+	}
+
+	s, err := StatsGet(here, person)
+	if err != nil {
+		return "", nil, err
+	}
+	name, err := nick.Name(person, here)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return fmt.Sprintf("%s has %d wins, %d losses and %d draws. Current streak: %d, best streak: %d.",
+		name, s.Wins, s.Losses, s.Draws, s.Streak, s.Best), nil, nil
+}
+
+/////////
+//     //
+// top //
+//     //
+/////////
+
+var NormalTop = normalTop{}
+
+type normalTop struct{}
+
+func (c normalTop) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c normalTop) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (normalTop) Names() []string {
+	return []string{
+		"top",
+		"leaderboard",
+		"lb",
+	}
+}
+
+func (normalTop) Description() string {
+	return "Show the people with the most wins."
+}
+
+func (normalTop) UsageArgs() string {
+	return ""
+}
+
+func (c normalTop) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (normalTop) Examples() []string {
+	return nil
+}
+
+func (normalTop) Parent() core.CommandStatic {
+	return Normal
+}
+
+func (normalTop) Children() core.CommandsStatic {
+	return nil
+}
+
+func (normalTop) Init() error {
+	return nil
+}
+
+func (c normalTop) Run(m *core.Message) (any, error, error) {
+	lines, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(lines) == 0 {
+		return render(m, "Nobody has won a game yet.", nil)
+	}
+
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		embed := &dg.MessageEmbed{
+			Title:       "Rock Paper Scissors Leaderboard",
+			Description: strings.Join(lines, "\n"),
+		}
+		return embed, nil, nil
+	default:
+		return strings.Join(lines, " | "), nil, nil
+	}
+}
+
+func (normalTop) core(m *core.Message) ([]string, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return nil, err
+	}
+
+	top, err := Top(here)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for i, s := range top {
+		name, err := nick.Name(s.Person, here)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, fmt.Sprintf("#%d %s: %d-%d-%d", i+1, name, s.Wins, s.Losses, s.Draws))
+	}
+	return lines, nil
+}
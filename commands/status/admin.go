@@ -0,0 +1,99 @@
+package status
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+
+	dg "github.com/bwmarrin/discordgo"
+)
+
+var Admin = admin{}
+
+type admin struct{}
+
+func (admin) Type() core.CommandType {
+	return core.Admin
+}
+
+func (admin) Permitted(*core.Message) bool {
+	return true
+}
+
+func (admin) Names() []string {
+	return []string{
+		"status",
+		"health",
+	}
+}
+
+func (admin) Description() string {
+	return "Show whether each frontend is connected."
+}
+
+func (admin) UsageArgs() string {
+	return ""
+}
+
+func (admin) Category() core.CommandCategory {
+	return core.CommandCategoryOther
+}
+
+func (admin) Examples() []string {
+	return nil
+}
+
+func (admin) Parent() core.CommandStatic {
+	return nil
+}
+
+func (admin) Children() core.CommandsStatic {
+	return nil
+}
+
+func (admin) Init() error {
+	return nil
+}
+
+func (c admin) Run(m *core.Message) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return c.discord()
+	default:
+		return c.text()
+	}
+}
+
+func (c admin) discord() (*dg.MessageEmbed, error, error) {
+	var fields []*dg.MessageEmbedField
+	for _, st := range core.Supervisor.Statuses() {
+		fields = append(fields, &dg.MessageEmbedField{
+			Name:  st.Name,
+			Value: c.fmt(st),
+		})
+	}
+	embed := &dg.MessageEmbed{
+		Fields: fields,
+	}
+	return embed, nil, nil
+}
+
+func (c admin) text() (string, error, error) {
+	var lines []string
+	for _, st := range core.Supervisor.Statuses() {
+		lines = append(lines, st.Name+": "+c.fmt(st))
+	}
+	return strings.Join(lines, " | "), nil, nil
+}
+
+func (admin) fmt(st core.FrontendStatus) string {
+	since := time.Since(st.Since).Round(time.Second)
+	s := fmt.Sprintf("%s for %s", st.State, since)
+	if st.Failures > 0 {
+		s += fmt.Sprintf(", %d failed attempt(s), last error: %s", st.Failures, st.Error)
+	}
+	return s
+}
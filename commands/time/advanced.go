@@ -1,7 +1,6 @@
 package time
 
 import (
-	"errors"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -16,8 +15,8 @@ import (
 )
 
 var (
-	errPersonNotFound  = errors.New("was unable to find user")
-	errInvalidRemindID = errors.New("invalid reminder ID")
+	errPersonNotFound  = core.NewError("time.err.person_not_found")
+	errInvalidRemindID = core.NewError("time.err.invalid_remind_id")
 )
 
 var Advanced = advanced{}
@@ -173,11 +172,11 @@ func (advancedNow) err(usrErr error, m *core.Message, now time.Time, cmdTzSet st
 	case nil:
 		return now.Format(time.RFC1123)
 	case errTimezoneNotSet:
-		return fmt.Sprintf("User %s has not set their timezone, to set a timezone use the %s command.", m.Author.Mention(), cmdTzSet)
+		return m.T("time.now.timezone_not_set", m.Author.Mention(), cmdTzSet)
 	case errPersonNotFound:
-		return fmt.Sprintf("Was unable to find the user %s", m.Command.Args[0])
+		return m.T("time.now.person_not_found", m.Command.Args[0])
 	default:
-		return fmt.Sprint(usrErr)
+		return m.TError(usrErr)
 	}
 }
 
@@ -277,7 +276,7 @@ func (c advancedConvert) discord(m *core.Message) (*dg.MessageEmbed, error, erro
 	}
 
 	embed := &dg.MessageEmbed{
-		Description: c.err(usrErr, t),
+		Description: c.err(usrErr, m, t),
 	}
 
 	return embed, usrErr, nil
@@ -288,15 +287,15 @@ func (c advancedConvert) text(m *core.Message) (string, error, error) {
 	if err != nil {
 		return "", nil, err
 	}
-	return c.err(usrErr, t), usrErr, nil
+	return c.err(usrErr, m, t), usrErr, nil
 }
 
-func (advancedConvert) err(usrErr error, t string) string {
+func (advancedConvert) err(usrErr error, m *core.Message, t string) string {
 	switch usrErr {
 	case nil:
 		return t
 	default:
-		return fmt.Sprint(usrErr)
+		return m.TError(usrErr)
 	}
 }
 
@@ -378,7 +377,7 @@ func (c advancedTimestamp) discord(m *core.Message) (*dg.MessageEmbed, error, er
 	}
 
 	embed := &dg.MessageEmbed{
-		Description: c.err(usrErr, t),
+		Description: c.err(usrErr, m, t),
 	}
 
 	if usrErr != nil {
@@ -397,17 +396,17 @@ func (c advancedTimestamp) text(m *core.Message) (string, error, error) {
 	if err != nil {
 		return "", nil, err
 	}
-	return c.err(usrErr, t), usrErr, nil
+	return c.err(usrErr, m, t), usrErr, nil
 }
 
-func (advancedTimestamp) err(usrErr error, t time.Time) string {
+func (advancedTimestamp) err(usrErr error, m *core.Message, t time.Time) string {
 	switch usrErr {
 	case nil:
 		return fmt.Sprint(t.Unix())
 	case errInvalidTime:
-		return "I can't understand what date that is."
+		return m.T("time.timestamp.invalid_time")
 	default:
-		return fmt.Sprint(usrErr)
+		return m.TError(usrErr)
 	}
 }
 
@@ -557,7 +556,7 @@ func (c advancedTimezoneShow) discord(m *core.Message) (*dg.MessageEmbed, error,
 	tz = discord.PlaceInBackticks(tz)
 
 	embed := &dg.MessageEmbed{
-		Description: c.fmt(tz),
+		Description: c.fmt(m, tz),
 	}
 
 	return embed, nil, nil
@@ -569,11 +568,11 @@ func (c advancedTimezoneShow) text(m *core.Message) (string, error, error) {
 		return "", nil, err
 	}
 	tz = fmt.Sprintf("'%s'", tz)
-	return c.fmt(tz), nil, nil
+	return c.fmt(m, tz), nil, nil
 }
 
-func (advancedTimezoneShow) fmt(tz string) string {
-	return fmt.Sprintf("Your timezone is: %s", tz)
+func (advancedTimezoneShow) fmt(m *core.Message, tz string) string {
+	return m.T("time.timezone.show", tz)
 }
 
 func (advancedTimezoneShow) core(m *core.Message) (string, error) {
@@ -682,11 +681,11 @@ func (c advancedTimezoneSet) text(m *core.Message) (string, error, error) {
 func (advancedTimezoneSet) err(usrErr error, m *core.Message, tz string) string {
 	switch usrErr {
 	case nil:
-		return fmt.Sprintf("Added %s with timezone %s", m.Author.Mention(), tz)
+		return m.T("time.timezone.set", m.Author.Mention(), tz)
 	case errTimezone:
-		return fmt.Sprintf("%s is not a valid timezone.", tz)
+		return m.T("time.timezone.set.invalid", tz)
 	default:
-		return fmt.Sprint(usrErr)
+		return m.TError(usrErr)
 	}
 }
 
@@ -785,7 +784,7 @@ func (c advancedTimezoneDelete) text(m *core.Message) (string, error, error) {
 }
 
 func (advancedTimezoneDelete) fmt(m *core.Message) string {
-	return fmt.Sprintf("Deleted timezone for user %s", m.Author.Mention())
+	return m.T("time.timezone.delete", m.Author.Mention())
 }
 
 func (advancedTimezoneDelete) core(m *core.Message) error {
@@ -920,7 +919,7 @@ func (c advancedRemindAdd) Run(m *core.Message) (any, error, error) {
 		return nil, nil, err
 	}
 	if usrErr != nil {
-		return fmt.Sprint(usrErr), usrErr, nil
+		return m.TError(usrErr), usrErr, nil
 	}
 	return fmt.Sprintf("%s (#%d)", t.Format(time.RFC1123), id), nil, nil
 }
@@ -1036,7 +1035,7 @@ func (c advancedRemindDelete) discord(m *core.Message) (*dg.MessageEmbed, error,
 	}
 
 	embed := &dg.MessageEmbed{
-		Description: c.err(usrErr),
+		Description: c.err(usrErr, m),
 	}
 
 	return embed, usrErr, nil
@@ -1047,19 +1046,19 @@ func (c advancedRemindDelete) text(m *core.Message) (string, error, error) {
 	if err != nil {
 		return "", nil, err
 	}
-	return c.err(usrErr), usrErr, nil
+	return c.err(usrErr, m), usrErr, nil
 }
 
-func (advancedRemindDelete) err(usrErr error) string {
+func (advancedRemindDelete) err(usrErr error, m *core.Message) string {
 	switch usrErr {
 	case nil:
-		return "Deleted reminder."
+		return m.T("time.remind.delete")
 	case errReminderNotFound:
-		return "Reminder not found. Maybe you are not the one who created the reminder?"
+		return m.T("time.remind.delete.not_found")
 	case errInvalidRemindID:
-		return "The ID you provided is invalid, expected a number."
+		return m.T("time.remind.delete.invalid_id")
 	default:
-		return fmt.Sprint(usrErr)
+		return m.TError(usrErr)
 	}
 }
 
@@ -1142,22 +1141,18 @@ func (c advancedRemindList) discord(m *core.Message) (string, error, error) {
 		return "", nil, err
 	}
 	if usrErr != nil {
-		return fmt.Sprint(usrErr), usrErr, nil
+		return m.TError(usrErr), usrErr, nil
 	}
 
 	var resp strings.Builder
 
-	if len(rs) == 1 {
-		fmt.Fprintf(&resp, "%d timer open.\n", len(rs))
-	} else {
-		fmt.Fprintf(&resp, "%d timers open.\n", len(rs))
-	}
+	resp.WriteString(m.TPlural("time.remind.list.timers", int64(len(rs)), len(rs)) + "\n")
 
 	now := time.Now()
 
 	for _, r := range rs {
 		remaining := r.When.Sub(now).Round(time.Second)
-		fmt.Fprintf(&resp, "%d: %s (%s remaining)\n", r.ID, r.What, remaining)
+		fmt.Fprintf(&resp, "%d: %s\n", r.ID, m.T("time.remind.list.reminder", r.What, remaining))
 	}
 
 	return resp.String(), nil, nil
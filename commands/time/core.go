@@ -2,7 +2,7 @@ package time
 
 import (
 	"database/sql"
-	"errors"
+	"embed"
 	"strconv"
 	"sync"
 	"time"
@@ -13,14 +13,17 @@ import (
 	"github.com/tj/go-naturaldate"
 )
 
+//go:embed locales
+var locales embed.FS
+
 var (
-	errTimestamp        = errors.New("invalid timestamp")
-	errTimezone         = errors.New("invalid timezone")
-	errTimezoneNotSet   = errors.New("user hasn't set their timezone")
-	errInvalidTime      = errors.New("could not parse given time string")
-	errNoReminders      = errors.New("couldn't find any reminders")
-	errReminderNotFound = errors.New("couldn't find person's reminder")
-	errOldTime          = errors.New("given time has already passed")
+	errTimestamp        = core.NewError("time.err.timestamp")
+	errTimezone         = core.NewError("time.err.timezone")
+	errTimezoneNotSet   = core.NewError("time.err.timezone_not_set")
+	errInvalidTime      = core.NewError("time.err.invalid_time")
+	errNoReminders      = core.NewError("time.err.no_reminders")
+	errReminderNotFound = core.NewError("time.err.reminder_not_found")
+	errOldTime          = core.NewError("time.err.old_time")
 )
 
 type reminder struct {
@@ -119,6 +122,51 @@ func dbRemindList(person, place int64) ([]reminder, error) {
 	return rs, err
 }
 
+func dbRemindListPerson(person int64) ([]reminder, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	rows, err := db.DB.Query(`
+		SELECT id, person, place, time, what, msg_id
+		FROM cmd_time_reminders
+		WHERE person = $1
+		ORDER BY time
+	`, person)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rs, err := scanReminders(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	err = rows.Err()
+
+	log.Debug().
+		Err(err).
+		Int64("person", person).
+		Int("#reminders", len(rs)).
+		Msg("got all of person's reminders")
+
+	return rs, err
+}
+
+func dbRemindCount() (int64, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	var n int64
+	err := db.DB.QueryRow(`
+		SELECT COUNT(*)
+		FROM cmd_time_reminders
+	`).Scan(&n)
+	return n, err
+}
+
 func dbRemindUpcoming(nowSeconds int64) ([]reminder, error) {
 	db := core.DB
 	db.Lock.RLock()
@@ -323,6 +371,23 @@ func RemindList(person, place int64) ([]reminder, error, error) {
 	return rs, nil, nil
 }
 
+// RemindListPerson returns all of the person's reminders regardless of the
+// place they were added in, sorted by when they are due.
+func RemindListPerson(person int64) ([]reminder, error) {
+	return dbRemindListPerson(person)
+}
+
+func init() {
+	if err := core.Catalogs.Load(locales, "locales"); err != nil {
+		panic(err)
+	}
+
+	core.NewGaugeFunc("jeff_reminders_pending", "Number of reminders that haven't been sent yet.", func() (float64, error) {
+		n, err := dbRemindCount()
+		return float64(n), err
+	})
+}
+
 type upcoming struct {
 	lock sync.RWMutex
 
@@ -0,0 +1,428 @@
+package trivia
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/commands/nick"
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+
+	dg "github.com/bwmarrin/discordgo"
+)
+
+func render(m *core.Message, resp string, usrErr error) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return &dg.MessageEmbed{Description: resp}, usrErr, nil
+	default:
+		return resp, usrErr, nil
+	}
+}
+
+var Advanced = advanced{}
+
+type advanced struct{}
+
+func (advanced) Type() core.CommandType {
+	return core.Advanced
+}
+
+func (advanced) Permitted(*core.Message) bool {
+	return true
+}
+
+func (advanced) Names() []string {
+	return []string{
+		"trivia",
+		"quiz",
+	}
+}
+
+func (advanced) Description() string {
+	return "Play trivia, the first one to answer correctly gets the point."
+}
+
+func (c advanced) UsageArgs() string {
+	return c.Children().Usage()
+}
+
+func (advanced) Category() core.CommandCategory {
+	return core.CommandCategoryGames
+}
+
+func (advanced) Examples() []string {
+	return nil
+}
+
+func (advanced) Parent() core.CommandStatic {
+	return nil
+}
+
+func (advanced) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		AdvancedPlay,
+		AdvancedTop,
+		AdvancedCategories,
+		AdvancedTimeout,
+	}
+}
+
+func (advanced) Init() error {
+	qs, err := LoadPacks(packsDir)
+	if err != nil {
+		return err
+	}
+	packsLock.Lock()
+	questions = qs
+	packsLock.Unlock()
+	return nil
+}
+
+func (advanced) Run(m *core.Message) (any, error, error) {
+	return m.Usage(), core.ErrMissingArgs, nil
+}
+
+//////////
+//      //
+// play //
+//      //
+//////////
+
+var AdvancedPlay = advancedPlay{}
+
+type advancedPlay struct{}
+
+func (c advancedPlay) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedPlay) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedPlay) Names() []string {
+	return []string{
+		"play",
+		"start",
+	}
+}
+
+func (advancedPlay) Description() string {
+	return "Start a game, optionally only with questions of a category or difficulty."
+}
+
+func (advancedPlay) UsageArgs() string {
+	return "[rounds] [easy | medium | hard] [category...]"
+}
+
+func (c advancedPlay) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedPlay) Examples() []string {
+	return []string{
+		"",
+		"10",
+		"10 hard",
+		"5 science",
+	}
+}
+
+func (advancedPlay) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedPlay) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedPlay) Init() error {
+	return nil
+}
+
+func (advancedPlay) Run(m *core.Message) (any, error, error) {
+	rounds, difficulty, category, usrErr := ParseArgs(m.Command.Args)
+	if usrErr != nil {
+		return render(m, fmt.Sprint(usrErr), usrErr)
+	}
+
+	qs := Questions(category, difficulty)
+	if len(qs) == 0 {
+		return render(m, fmt.Sprint(ErrNoQuestions), ErrNoQuestions)
+	}
+
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return nil, nil, err
+	}
+	timeout, err := TimeoutGet(here)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	g := Game{
+		Name:    "Trivia",
+		Rounds:  rounds,
+		Timeout: timeout,
+		Next:    Random(qs),
+	}
+
+	usrErr, err = Play(m, g)
+	if err != nil {
+		return nil, nil, err
+	}
+	if usrErr != nil {
+		return render(m, fmt.Sprint(usrErr), usrErr)
+	}
+	// everything has already been sent
+	return nil, nil, core.ErrSilence
+}
+
+/////////
+//     //
+// top //
+//     //
+/////////
+
+var AdvancedTop = advancedTop{}
+
+type advancedTop struct{}
+
+func (c advancedTop) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedTop) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedTop) Names() []string {
+	return []string{
+		"top",
+		"leaderboard",
+		"lb",
+	}
+}
+
+func (advancedTop) Description() string {
+	return "Show the people with the most correct answers."
+}
+
+func (advancedTop) UsageArgs() string {
+	return ""
+}
+
+func (c advancedTop) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedTop) Examples() []string {
+	return nil
+}
+
+func (advancedTop) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedTop) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedTop) Init() error {
+	return nil
+}
+
+func (c advancedTop) Run(m *core.Message) (any, error, error) {
+	lines, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(lines) == 0 {
+		return render(m, "Nobody has answered a question yet.", nil)
+	}
+
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		embed := &dg.MessageEmbed{
+			Title:       "Trivia Leaderboard",
+			Description: strings.Join(lines, "\n"),
+		}
+		return embed, nil, nil
+	default:
+		return strings.Join(lines, " | "), nil, nil
+	}
+}
+
+func (advancedTop) core(m *core.Message) ([]string, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return nil, err
+	}
+
+	top, err := Top(here)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for i, s := range top {
+		name, err := nick.Name(s.Person, here)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, fmt.Sprintf("#%d %s: %d", i+1, name, s.Points))
+	}
+	return lines, nil
+}
+
+////////////////
+//            //
+// categories //
+//            //
+////////////////
+
+var AdvancedCategories = advancedCategories{}
+
+type advancedCategories struct{}
+
+func (c advancedCategories) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedCategories) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedCategories) Names() []string {
+	return []string{
+		"categories",
+		"category",
+		"cats",
+	}
+}
+
+func (advancedCategories) Description() string {
+	return "Show the available categories."
+}
+
+func (advancedCategories) UsageArgs() string {
+	return ""
+}
+
+func (c advancedCategories) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedCategories) Examples() []string {
+	return nil
+}
+
+func (advancedCategories) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedCategories) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedCategories) Init() error {
+	return nil
+}
+
+func (advancedCategories) Run(m *core.Message) (any, error, error) {
+	cs := Categories()
+	if len(cs) == 0 {
+		return render(m, fmt.Sprint(ErrNoQuestions), ErrNoQuestions)
+	}
+	return render(m, strings.Join(cs, ", "), nil)
+}
+
+/////////////
+//         //
+// timeout //
+//         //
+/////////////
+
+var AdvancedTimeout = advancedTimeout{}
+
+type advancedTimeout struct{}
+
+func (c advancedTimeout) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (advancedTimeout) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advancedTimeout) Names() []string {
+	return []string{
+		"timeout",
+		"time",
+	}
+}
+
+func (advancedTimeout) Description() string {
+	return "Show or set the number of seconds players have to answer."
+}
+
+func (advancedTimeout) UsageArgs() string {
+	return "[seconds]"
+}
+
+func (c advancedTimeout) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedTimeout) Examples() []string {
+	return nil
+}
+
+func (advancedTimeout) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedTimeout) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedTimeout) Init() error {
+	return nil
+}
+
+func (c advancedTimeout) Run(m *core.Message) (any, error, error) {
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedTimeout) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(m.Command.Args) == 0 {
+		timeout, err := TimeoutGet(here)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("Players have %d seconds to answer.", int(timeout.Seconds())), nil, nil
+	}
+
+	seconds, err := strconv.Atoi(m.Command.Args[0])
+	if err != nil {
+		return fmt.Sprint(ErrInvalidTimeout), ErrInvalidTimeout, nil
+	}
+	usrErr, err := TimeoutSet(here, time.Duration(seconds)*time.Second)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	return fmt.Sprintf("Players now have %d seconds to answer.", seconds), nil, nil
+}
@@ -0,0 +1,541 @@
+package trivia
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+
+	dg "github.com/bwmarrin/discordgo"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrActive         = errors.New("A game is already being played here.")
+	ErrNoQuestions    = errors.New("Couldn't find any questions, try a different category or difficulty.")
+	ErrInvalidRounds  = errors.New("Expected a number of rounds between 1 and 15.")
+	ErrInvalidTimeout = errors.New("Expected a number of seconds between 5 and 120.")
+)
+
+const (
+	DefaultRounds = 5
+	MaxRounds     = 15
+
+	minTimeout = 5 * time.Second
+	maxTimeout = 2 * time.Minute
+
+	// give some time for information to be processed by the players between
+	// rounds
+	interval = 5 * time.Second
+
+	// LeaderboardSize is the number of people shown in the leaderboard.
+	LeaderboardSize = 10
+
+	// every JSON file in this directory is loaded as a question pack
+	packsDir = "data/trivia"
+)
+
+var difficulties = []string{
+	"easy",
+	"medium",
+	"hard",
+}
+
+type Question struct {
+	Category   string   `json:"category"`
+	Difficulty string   `json:"difficulty"`
+	Question   string   `json:"question"`
+	Details    string   `json:"details"` // shown under the question
+	Answers    []string `json:"answers"` // any of them is accepted
+
+	// The following are only shown on frontends that support images.
+	Icon      string `json:"icon"`
+	Image     string `json:"image"`     // shown with the question
+	Thumbnail string `json:"thumbnail"` // shown with the answer
+}
+
+// Pack is a collection of questions, questions that don't specify a category
+// use the pack's name instead.
+type Pack struct {
+	Name      string     `json:"name"`
+	Questions []Question `json:"questions"`
+}
+
+type Score struct {
+	Person int64
+	Points int64
+}
+
+var (
+	packsLock sync.RWMutex
+	questions []Question
+)
+
+// LoadPacks reads all of the question packs in dir and returns their
+// questions. A missing directory is not an error.
+func LoadPacks(dir string) ([]Question, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var qs []Question
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		var p Pack
+		if err := json.Unmarshal(content, &p); err != nil {
+			return nil, fmt.Errorf("invalid trivia pack %s: %v", file, err)
+		}
+
+		for _, q := range p.Questions {
+			if q.Category == "" {
+				q.Category = p.Name
+			}
+			q.Difficulty = strings.ToLower(q.Difficulty)
+			qs = append(qs, q)
+		}
+	}
+
+	log.Debug().
+		Int("packs", len(files)).
+		Int("questions", len(qs)).
+		Msg("loaded trivia packs")
+
+	return qs, nil
+}
+
+// Questions returns the loaded questions that match the category and
+// difficulty, empty strings match everything.
+func Questions(category, difficulty string) []Question {
+	packsLock.RLock()
+	defer packsLock.RUnlock()
+
+	var qs []Question
+	for _, q := range questions {
+		if category != "" && !strings.EqualFold(q.Category, category) {
+			continue
+		}
+		if difficulty != "" && q.Difficulty != difficulty {
+			continue
+		}
+		qs = append(qs, q)
+	}
+	return qs
+}
+
+// Categories returns the categories of the loaded questions.
+func Categories() []string {
+	packsLock.RLock()
+	defer packsLock.RUnlock()
+
+	seen := map[string]struct{}{}
+	var cs []string
+	for _, q := range questions {
+		if _, ok := seen[q.Category]; ok {
+			continue
+		}
+		seen[q.Category] = struct{}{}
+		cs = append(cs, q.Category)
+	}
+	sort.Strings(cs)
+	return cs
+}
+
+// Random returns a function that can be used as a Game's Next, questions are
+// only repeated if there are more rounds than questions.
+func Random(qs []Question) func(round int) Question {
+	shuffled := make([]Question, len(qs))
+	copy(shuffled, qs)
+	rand.Seed(time.Now().UnixNano())
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return func(round int) Question {
+		return shuffled[(round-1)%len(shuffled)]
+	}
+}
+
+// ParseArgs parses the arguments of the form:
+// [rounds] [difficulty] [category...]
+// which can be given in any order.
+func ParseArgs(args []string) (int, string, string, error) {
+	rounds := DefaultRounds
+	var difficulty string
+	var category []string
+
+	for _, arg := range args {
+		if n, err := strconv.Atoi(arg); err == nil {
+			if n < 1 || n > MaxRounds {
+				return 0, "", "", ErrInvalidRounds
+			}
+			rounds = n
+			continue
+		}
+		if isDifficulty(arg) {
+			difficulty = strings.ToLower(arg)
+			continue
+		}
+		category = append(category, arg)
+	}
+
+	return rounds, difficulty, strings.Join(category, " "), nil
+}
+
+func isDifficulty(s string) bool {
+	for _, d := range difficulties {
+		if strings.EqualFold(s, d) {
+			return true
+		}
+	}
+	return false
+}
+
+func simplify(s string) string {
+	re := regexp.MustCompile(`[^\w]`)
+	return strings.ToLower(re.ReplaceAllString(s, ""))
+}
+
+func awaitAnswer(here int64, answers []string, timeout time.Duration) *core.Message {
+	return core.Await(timeout, func(m *core.Message) bool {
+		place, err := m.Here.ScopeExact()
+		if err != nil {
+			return false
+		}
+
+		if place != here {
+			return false
+		}
+
+		for _, a := range answers {
+			if simplify(a) == simplify(m.Raw) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+//////////
+//      //
+// game //
+//      //
+//////////
+
+// Game describes a single game, the questions can come from anywhere.
+type Game struct {
+	Name    string
+	Rounds  int
+	Timeout time.Duration
+
+	// Next returns the question for the specified round, rounds start from 1.
+	Next func(round int) Question
+
+	// The following only affect discord embeds.
+	Color     int
+	Thumbnail string // shown next to every question
+	Footer    string // shown under the final scores
+}
+
+var (
+	playingLock sync.Mutex
+
+	// the exact places where a game is currently being played
+	playing = map[int64]struct{}{}
+)
+
+func start(place int64) bool {
+	playingLock.Lock()
+	defer playingLock.Unlock()
+
+	if _, ok := playing[place]; ok {
+		return false
+	}
+	playing[place] = struct{}{}
+	return true
+}
+
+func stop(place int64) {
+	playingLock.Lock()
+	defer playingLock.Unlock()
+	delete(playing, place)
+}
+
+// send sends a message to where the game is being played without replying to
+// anyone.
+func send(m *core.Message, g Game, embed *dg.MessageEmbed, text string) {
+	var err error
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		if embed.Color == 0 {
+			embed.Color = g.Color
+		}
+		err = discord.SendEmbed(m.Here.ID(), embed)
+	default:
+		_, err = m.Client.Send(text, nil)
+	}
+	log.Debug().Err(err).Str("game", g.Name).Msg("sent trivia message")
+}
+
+// Play plays the game in the place the message came from, blocks until the
+// game is over. The first person to answer each question correctly gets a
+// point, points are kept in the logical place's leaderboard. Returns
+// ErrActive if a game is already being played in the same exact place.
+func Play(m *core.Message, g Game) (error, error) {
+	here, err := m.Here.ScopeExact()
+	if err != nil {
+		return nil, err
+	}
+	logical, err := m.Here.ScopeLogical()
+	if err != nil {
+		return nil, err
+	}
+
+	if !start(here) {
+		return ErrActive, nil
+	}
+	defer stop(here)
+
+	send(m, g, g.introEmbed(), g.introText())
+
+	scores := map[int64]int64{}
+	names := map[int64]string{}
+
+	for r := 1; r <= g.Rounds; r++ {
+		time.Sleep(interval)
+
+		q := g.Next(r)
+		send(m, g, g.questionEmbed(r, q), g.questionText(r, q))
+
+		var winner string
+		if answer := awaitAnswer(here, q.Answers, g.Timeout); answer != nil {
+			person, err := answer.Author.Scope()
+			if err != nil {
+				return nil, err
+			}
+			if err := dbScoreAdd(logical, person, 1); err != nil {
+				return nil, err
+			}
+			scores[person]++
+			names[person] = answer.Author.DisplayName()
+			winner = names[person]
+		}
+
+		last := r == g.Rounds
+		send(m, g, g.answerEmbed(r, q, winner, last), g.answerText(r, q, winner))
+	}
+
+	var final []Score
+	for person, points := range scores {
+		final = append(final, Score{person, points})
+	}
+	sort.Slice(final, func(i, j int) bool {
+		return final[i].Points > final[j].Points
+	})
+
+	send(m, g, g.scoresEmbed(final, names), g.scoresText(final, names))
+	return nil, nil
+}
+
+///////////////
+//           //
+// rendering //
+//           //
+///////////////
+
+func (g Game) introEmbed() *dg.MessageEmbed {
+	return &dg.MessageEmbed{
+		Title:       fmt.Sprintf("🔥 **%s** 🔥", g.Name),
+		Description: "Game starting in a few seconds!",
+	}
+}
+
+func (g Game) introText() string {
+	return fmt.Sprintf("%s starting in a few seconds! %d rounds, first one to answer correctly gets the point.", g.Name, g.Rounds)
+}
+
+func (g Game) questionEmbed(round int, q Question) *dg.MessageEmbed {
+	title := fmt.Sprintf("Round %d: %s", round, q.Question)
+	if q.Icon != "" {
+		title = q.Icon + " " + title
+	}
+
+	var desc strings.Builder
+	if q.Details != "" {
+		fmt.Fprintf(&desc, "%s\n\n", q.Details)
+	}
+	desc.WriteString("Enter your answer in the chat!\n")
+	fmt.Fprintf(&desc, "You have %d seconds.\n", int(g.Timeout.Seconds()))
+
+	embed := &dg.MessageEmbed{
+		Title:       title,
+		Description: desc.String(),
+		Image: &dg.MessageEmbedImage{
+			URL: q.Image,
+		},
+		Thumbnail: &dg.MessageEmbedThumbnail{
+			URL: g.Thumbnail,
+		},
+	}
+
+	if info := q.info(); info != "" {
+		embed.Footer = &dg.MessageEmbedFooter{Text: info}
+	}
+
+	return embed
+}
+
+func (g Game) questionText(round int, q Question) string {
+	s := fmt.Sprintf("Round %d", round)
+	if info := q.info(); info != "" {
+		s += " (" + info + ")"
+	}
+	s += ": " + q.Question
+	if q.Details != "" {
+		s += " " + q.Details
+	}
+	return fmt.Sprintf("%s [%ds]", s, int(g.Timeout.Seconds()))
+}
+
+// info returns the question's category and difficulty.
+func (q Question) info() string {
+	var info []string
+	if q.Category != "" {
+		info = append(info, q.Category)
+	}
+	if q.Difficulty != "" {
+		info = append(info, q.Difficulty)
+	}
+	return strings.Join(info, ", ")
+}
+
+func (g Game) answerEmbed(round int, q Question, winner string, last bool) *dg.MessageEmbed {
+	var title string
+	if winner == "" {
+		title = fmt.Sprintf("**Round %d: Nobody Answered**", round)
+	} else {
+		title = fmt.Sprintf("**Round %d: %s got the Answer!**", round, winner)
+	}
+
+	var desc strings.Builder
+	fmt.Fprintf(&desc, "The correct answer was: *%s*\n", strings.Join(q.Answers, " **or** "))
+
+	if winner != "" {
+		desc.WriteString("**1 point**\n")
+	}
+
+	if !last {
+		desc.WriteString("\nNext Question in a few seconds!\n")
+	}
+
+	return &dg.MessageEmbed{
+		Title:       title,
+		Description: desc.String(),
+		Thumbnail: &dg.MessageEmbedThumbnail{
+			URL: q.Thumbnail,
+		},
+	}
+}
+
+func (g Game) answerText(round int, q Question, winner string) string {
+	answer := strings.Join(q.Answers, " or ")
+	if winner == "" {
+		return fmt.Sprintf("Round %d: Nobody got it, the answer was: %s", round, answer)
+	}
+	return fmt.Sprintf("Round %d: %s got it! The answer was: %s", round, winner, answer)
+}
+
+func (g Game) scoresEmbed(scores []Score, names map[int64]string) *dg.MessageEmbed {
+	var desc string
+	var fields []*dg.MessageEmbedField
+
+	if len(scores) == 0 {
+		desc = "No one got any points."
+	} else {
+		desc = "__**Leaderboard**__\n"
+
+		var player, score strings.Builder
+		for _, s := range scores {
+			fmt.Fprintf(&player, "%s\n", names[s.Person])
+			fmt.Fprintf(&score, "%d\n", s.Points)
+		}
+
+		fields = []*dg.MessageEmbedField{
+			{
+				Name:   "Player",
+				Value:  player.String(),
+				Inline: true,
+			},
+			{
+				Name:   "Score",
+				Value:  score.String(),
+				Inline: true,
+			},
+		}
+	}
+
+	embed := &dg.MessageEmbed{
+		Title:       "**Game Over!**",
+		Description: desc,
+		Fields:      fields,
+	}
+
+	if g.Footer != "" {
+		embed.Footer = &dg.MessageEmbedFooter{Text: g.Footer}
+	}
+
+	return embed
+}
+
+func (g Game) scoresText(scores []Score, names map[int64]string) string {
+	if len(scores) == 0 {
+		return "Game over! No one got any points."
+	}
+	var ss []string
+	for _, s := range scores {
+		ss = append(ss, fmt.Sprintf("%s: %d", names[s.Person], s.Points))
+	}
+	return "Game over! " + strings.Join(ss, ", ")
+}
+
+//////////////
+//          //
+// settings //
+//          //
+//////////////
+
+// TimeoutGet returns the time players have to answer each question.
+func TimeoutGet(place int64) (time.Duration, error) {
+	seconds, err := core.DB.SettingPlaceGet("cmd_trivia_timeout", place)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds.(int64)) * time.Second, nil
+}
+
+// TimeoutSet sets the time players have to answer each question.
+func TimeoutSet(place int64, timeout time.Duration) (error, error) {
+	if timeout < minTimeout || timeout > maxTimeout {
+		return ErrInvalidTimeout, nil
+	}
+	return nil, core.DB.SettingPlaceSet("cmd_trivia_timeout", place, int64(timeout.Seconds()))
+}
+
+// Top returns the people with the most points in the specified place.
+func Top(place int64) ([]Score, error) {
+	return dbTop(place, LeaderboardSize)
+}
@@ -0,0 +1,69 @@
+package trivia
+
+import (
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/rs/zerolog/log"
+)
+
+func dbScoreAdd(place, person, points int64) error {
+	if err := core.DB.SettingsPersonGenerate(person, place); err != nil {
+		return err
+	}
+
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		UPDATE settings_person
+		SET cmd_trivia_points = cmd_trivia_points + $1
+		WHERE person = $2 and place = $3
+	`, points, person, place)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int64("person", person).
+		Int64("points", points).
+		Msg("added trivia points")
+
+	return err
+}
+
+func dbTop(place int64, limit int) ([]Score, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	rows, err := db.DB.Query(`
+		SELECT person, cmd_trivia_points
+		FROM settings_person
+		WHERE place = $1 and cmd_trivia_points > 0
+		ORDER BY cmd_trivia_points DESC, person
+		LIMIT $2
+	`, place, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var top []Score
+	for rows.Next() {
+		var s Score
+		if err := rows.Scan(&s.Person, &s.Points); err != nil {
+			return nil, err
+		}
+		top = append(top, s)
+	}
+
+	err = rows.Err()
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int("count", len(top)).
+		Msg("got trivia leaderboard")
+
+	return top, err
+}
@@ -0,0 +1,109 @@
+package trivia
+
+import (
+	"github.com/janitorjeff/jeff-bot/core"
+)
+
+var Normal = normal{}
+
+type normal struct{}
+
+func (normal) Type() core.CommandType {
+	return core.Normal
+}
+
+func (normal) Permitted(m *core.Message) bool {
+	return Advanced.Permitted(m)
+}
+
+func (normal) Names() []string {
+	return Advanced.Names()
+}
+
+func (normal) Description() string {
+	return AdvancedPlay.Description()
+}
+
+func (normal) UsageArgs() string {
+	return AdvancedPlay.UsageArgs()
+}
+
+func (normal) Category() core.CommandCategory {
+	return Advanced.Category()
+}
+
+func (normal) Examples() []string {
+	return AdvancedPlay.Examples()
+}
+
+func (normal) Parent() core.CommandStatic {
+	return nil
+}
+
+func (normal) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		NormalTop,
+	}
+}
+
+func (normal) Init() error {
+	return nil
+}
+
+func (normal) Run(m *core.Message) (any, error, error) {
+	return AdvancedPlay.Run(m)
+}
+
+/////////
+//     //
+// top //
+//     //
+/////////
+
+var NormalTop = normalTop{}
+
+type normalTop struct{}
+
+func (c normalTop) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c normalTop) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (normalTop) Names() []string {
+	return AdvancedTop.Names()
+}
+
+func (normalTop) Description() string {
+	return AdvancedTop.Description()
+}
+
+func (normalTop) UsageArgs() string {
+	return AdvancedTop.UsageArgs()
+}
+
+func (c normalTop) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (normalTop) Examples() []string {
+	return nil
+}
+
+func (normalTop) Parent() core.CommandStatic {
+	return Normal
+}
+
+func (normalTop) Children() core.CommandsStatic {
+	return nil
+}
+
+func (normalTop) Init() error {
+	return nil
+}
+
+func (normalTop) Run(m *core.Message) (any, error, error) {
+	return AdvancedTop.Run(m)
+}
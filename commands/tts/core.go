@@ -22,6 +22,7 @@ var Hooks = gosafe.Map[string, int]{}
 var (
 	ErrHookNotFound   = errors.New("Wasn't monitoring, what are you even trynna do??")
 	ErrPersonNotFound = errors.New("Person's voice has not been set.")
+	ErrInvalidVoice   = errors.New("invalid voice")
 )
 
 var Voices = []string{
@@ -112,7 +113,7 @@ func TTS(voice, text string) ([]byte, error) {
 	reqURL += "&req_text=" + url.QueryEscape(text)
 	reqURL += "&speaker_map_type=0&aid=1233"
 
-	client := &http.Client{}
+	client := core.APIClient("tiktok")
 	req, err := http.NewRequest("POST", reqURL, nil)
 	if err != nil {
 		return nil, err
@@ -232,6 +233,11 @@ func Start(sp core.AudioSpeaker, twitchUsername string) {
 			return
 		}
 
+		core.Overlay.Publish(here, core.OverlayTTS, map[string]string{
+			"author": m.Author.DisplayName(),
+			"text":   m.Raw,
+			"voice":  voice,
+		})
 		Play(sp, voice, m.Raw)
 	})
 	Hooks.Set(twitchUsername, id)
@@ -269,7 +275,7 @@ func VoiceSet(person, place int64, voice string) error {
 		}
 	}
 	if !exists {
-		return errors.New("invalid voice")
+		return ErrInvalidVoice
 	}
 	return core.DB.SettingPersonSet("cmd_tts_voice", person, place, voice)
 }
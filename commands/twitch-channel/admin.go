@@ -0,0 +1,273 @@
+package twitch_channel
+
+import (
+	"strings"
+
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+	"github.com/janitorjeff/jeff-bot/frontends/twitch"
+
+	dg "github.com/bwmarrin/discordgo"
+)
+
+var Admin = admin{}
+
+type admin struct{}
+
+func (admin) Type() core.CommandType {
+	return core.Admin
+}
+
+func (admin) Permitted(*core.Message) bool {
+	return true
+}
+
+func (admin) Names() []string {
+	return Advanced.Names()
+}
+
+func (admin) Description() string {
+	return "Manage the twitch channels the bot is in."
+}
+
+func (c admin) UsageArgs() string {
+	return c.Children().Usage()
+}
+
+func (admin) Category() core.CommandCategory {
+	return Advanced.Category()
+}
+
+func (admin) Examples() []string {
+	return nil
+}
+
+func (admin) Parent() core.CommandStatic {
+	return nil
+}
+
+func (admin) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		AdminJoin,
+		AdminPart,
+		AdminList,
+	}
+}
+
+func (admin) Init() error {
+	return nil
+}
+
+func (admin) Run(m *core.Message) (any, error, error) {
+	return m.Usage(), core.ErrMissingArgs, nil
+}
+
+//////////
+//      //
+// join //
+//      //
+//////////
+
+var AdminJoin = adminJoin{}
+
+type adminJoin struct{}
+
+func (c adminJoin) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c adminJoin) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (adminJoin) Names() []string {
+	return AdvancedJoin.Names()
+}
+
+func (adminJoin) Description() string {
+	return "Make the bot join a channel."
+}
+
+func (adminJoin) UsageArgs() string {
+	return "<channel>"
+}
+
+func (c adminJoin) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (adminJoin) Examples() []string {
+	return []string{
+		"janitorjeff",
+	}
+}
+
+func (adminJoin) Parent() core.CommandStatic {
+	return Admin
+}
+
+func (adminJoin) Children() core.CommandsStatic {
+	return nil
+}
+
+func (adminJoin) Init() error {
+	return nil
+}
+
+func (c adminJoin) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+
+	channel := m.Command.Args[0]
+	usrErr, err := twitch.Join(channel)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, joinErr(usrErr, channel), usrErr)
+}
+
+//////////
+//      //
+// part //
+//      //
+//////////
+
+var AdminPart = adminPart{}
+
+type adminPart struct{}
+
+func (c adminPart) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c adminPart) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (adminPart) Names() []string {
+	return AdvancedPart.Names()
+}
+
+func (adminPart) Description() string {
+	return "Make the bot leave a channel."
+}
+
+func (adminPart) UsageArgs() string {
+	return "<channel>"
+}
+
+func (c adminPart) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (adminPart) Examples() []string {
+	return []string{
+		"janitorjeff",
+	}
+}
+
+func (adminPart) Parent() core.CommandStatic {
+	return Admin
+}
+
+func (adminPart) Children() core.CommandsStatic {
+	return nil
+}
+
+func (adminPart) Init() error {
+	return nil
+}
+
+func (c adminPart) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+
+	channel := m.Command.Args[0]
+	usrErr, err := twitch.Part(channel)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, partErr(usrErr, channel), usrErr)
+}
+
+//////////
+//      //
+// list //
+//      //
+//////////
+
+var AdminList = adminList{}
+
+type adminList struct{}
+
+func (c adminList) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c adminList) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (adminList) Names() []string {
+	return core.AliasesList
+}
+
+func (adminList) Description() string {
+	return "List the channels the bot is in."
+}
+
+func (adminList) UsageArgs() string {
+	return ""
+}
+
+func (c adminList) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (adminList) Examples() []string {
+	return nil
+}
+
+func (adminList) Parent() core.CommandStatic {
+	return Admin
+}
+
+func (adminList) Children() core.CommandsStatic {
+	return nil
+}
+
+func (adminList) Init() error {
+	return nil
+}
+
+func (c adminList) Run(m *core.Message) (any, error, error) {
+	channels, err := twitch.Joined()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(channels) == 0 {
+		return render(m, "The bot is not in any channels.", nil)
+	}
+
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		for i := range channels {
+			channels[i] = "- " + discord.PlaceInBackticks(channels[i])
+		}
+		return render(m, strings.Join(channels, "\n"), nil)
+	default:
+		return render(m, strings.Join(channels, ", "), nil)
+	}
+}
+
+func render(m *core.Message, resp string, usrErr error) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return &dg.MessageEmbed{Description: resp}, usrErr, nil
+	default:
+		return resp, usrErr, nil
+	}
+}
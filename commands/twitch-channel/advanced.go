@@ -0,0 +1,206 @@
+package twitch_channel
+
+import (
+	"fmt"
+
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/twitch"
+)
+
+var Advanced = advanced{}
+
+type advanced struct{}
+
+func (advanced) Type() core.CommandType {
+	return core.Advanced
+}
+
+func (advanced) Permitted(m *core.Message) bool {
+	return m.Frontend.Type() == twitch.Frontend.Type()
+}
+
+func (advanced) Names() []string {
+	return []string{
+		"twitch",
+	}
+}
+
+func (advanced) Description() string {
+	return "Add the bot to your channel or remove it."
+}
+
+func (c advanced) UsageArgs() string {
+	return c.Children().Usage()
+}
+
+func (advanced) Category() core.CommandCategory {
+	return core.CommandCategoryOther
+}
+
+func (advanced) Examples() []string {
+	return nil
+}
+
+func (advanced) Parent() core.CommandStatic {
+	return nil
+}
+
+func (advanced) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		AdvancedJoin,
+		AdvancedPart,
+	}
+}
+
+func (advanced) Init() error {
+	return nil
+}
+
+func (advanced) Run(m *core.Message) (any, error, error) {
+	return m.Usage(), core.ErrMissingArgs, nil
+}
+
+//////////
+//      //
+// join //
+//      //
+//////////
+
+var AdvancedJoin = advancedJoin{}
+
+type advancedJoin struct{}
+
+func (c advancedJoin) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedJoin) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedJoin) Names() []string {
+	return []string{
+		"join",
+	}
+}
+
+func (advancedJoin) Description() string {
+	return "Add the bot to your channel."
+}
+
+func (advancedJoin) UsageArgs() string {
+	return ""
+}
+
+func (c advancedJoin) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedJoin) Examples() []string {
+	return nil
+}
+
+func (advancedJoin) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedJoin) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedJoin) Init() error {
+	return nil
+}
+
+func (c advancedJoin) Run(m *core.Message) (any, error, error) {
+	channel := m.Author.Name()
+	usrErr, err := twitch.Join(channel)
+	if err != nil {
+		return "", nil, err
+	}
+	return joinErr(usrErr, channel), usrErr, nil
+}
+
+func joinErr(usrErr error, channel string) string {
+	switch usrErr {
+	case nil:
+		return fmt.Sprintf("Joined channel %s.", channel)
+	case twitch.ErrNoResults:
+		return fmt.Sprintf("Channel %s doesn't exist.", channel)
+	default:
+		return fmt.Sprint(usrErr)
+	}
+}
+
+//////////
+//      //
+// part //
+//      //
+//////////
+
+var AdvancedPart = advancedPart{}
+
+type advancedPart struct{}
+
+func (c advancedPart) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedPart) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedPart) Names() []string {
+	return []string{
+		"part",
+		"leave",
+	}
+}
+
+func (advancedPart) Description() string {
+	return "Remove the bot from your channel."
+}
+
+func (advancedPart) UsageArgs() string {
+	return ""
+}
+
+func (c advancedPart) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedPart) Examples() []string {
+	return nil
+}
+
+func (advancedPart) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedPart) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedPart) Init() error {
+	return nil
+}
+
+func (c advancedPart) Run(m *core.Message) (any, error, error) {
+	channel := m.Author.Name()
+	usrErr, err := twitch.Part(channel)
+	if err != nil {
+		return "", nil, err
+	}
+	return partErr(usrErr, channel), usrErr, nil
+}
+
+func partErr(usrErr error, channel string) string {
+	switch usrErr {
+	case nil:
+		return fmt.Sprintf("Left channel %s.", channel)
+	case twitch.ErrNoResults:
+		return fmt.Sprintf("Channel %s doesn't exist.", channel)
+	default:
+		return fmt.Sprint(usrErr)
+	}
+}
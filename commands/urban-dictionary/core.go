@@ -5,12 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"net/http"
 	"net/url"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/janitorjeff/jeff-bot/core"
+
 	dg "github.com/bwmarrin/discordgo"
 )
 
@@ -22,6 +23,8 @@ const (
 
 var errNoResults = errors.New("No results found.")
 
+var client = core.APIClient("urban_dictionary")
+
 type definition struct {
 	Definition  string    `json:"definition"`
 	Permalink   string    `json:"permalink"`
@@ -50,7 +53,7 @@ func cleanLinks(s string) string {
 }
 
 func read(u string) (definition, error, error) {
-	resp, err := http.Get(u)
+	resp, err := client.Get(u)
 	if err != nil {
 		return definition{}, nil, err
 	}
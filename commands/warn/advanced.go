@@ -0,0 +1,844 @@
+package warn
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/janitorjeff/jeff-bot/commands/moderation"
+	"github.com/janitorjeff/jeff-bot/commands/nick"
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+
+	dg "github.com/bwmarrin/discordgo"
+)
+
+func render(m *core.Message, resp string, usrErr error) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return &dg.MessageEmbed{Description: resp}, usrErr, nil
+	default:
+		return resp, usrErr, nil
+	}
+}
+
+var Advanced = advanced{}
+
+type advanced struct{}
+
+func (advanced) Type() core.CommandType {
+	return core.Advanced
+}
+
+func (advanced) Permitted(m *core.Message) bool {
+	return m.Author.Mod()
+}
+
+func (advanced) Names() []string {
+	return []string{
+		"warn",
+		"warning",
+		"warnings",
+	}
+}
+
+func (advanced) Description() string {
+	return "Keep track of people's warnings."
+}
+
+func (c advanced) UsageArgs() string {
+	return c.Children().Usage()
+}
+
+func (advanced) Category() core.CommandCategory {
+	return core.CommandCategoryModerators
+}
+
+func (advanced) Examples() []string {
+	return nil
+}
+
+func (advanced) Parent() core.CommandStatic {
+	return nil
+}
+
+func (advanced) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		AdvancedAdd,
+		AdvancedList,
+		AdvancedDelete,
+		AdvancedClear,
+		AdvancedShow,
+		AdvancedThreshold,
+		AdvancedWindow,
+		AdvancedAction,
+		AdvancedTimeout,
+	}
+}
+
+func (advanced) Init() error {
+	return nil
+}
+
+func (advanced) Run(m *core.Message) (any, error, error) {
+	return m.Usage(), core.ErrMissingArgs, nil
+}
+
+/////////
+//     //
+// add //
+//     //
+/////////
+
+var AdvancedAdd = advancedAdd{}
+
+type advancedAdd struct{}
+
+func (c advancedAdd) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedAdd) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedAdd) Names() []string {
+	return core.AliasesAdd
+}
+
+func (advancedAdd) Description() string {
+	return "Warn someone."
+}
+
+func (advancedAdd) UsageArgs() string {
+	return "<person> <reason...>"
+}
+
+func (c advancedAdd) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedAdd) Examples() []string {
+	return nil
+}
+
+func (advancedAdd) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedAdd) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedAdd) Init() error {
+	return nil
+}
+
+func (c advancedAdd) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 2 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedAdd) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+	target := m.Command.Args[0]
+	person, err := nick.ParsePerson(m, here, target)
+	if err != nil {
+		return fmt.Sprint(moderation.ErrPersonNotFound), moderation.ErrPersonNotFound, nil
+	}
+
+	author, err := m.Author.Scope()
+	if err != nil {
+		return "", nil, err
+	}
+	if person == author {
+		return fmt.Sprint(moderation.ErrSelf), moderation.ErrSelf, nil
+	}
+
+	id, action, usrErr, err := Warn(m, person, author, m.RawArgs(1))
+	if err != nil {
+		return "", nil, err
+	}
+
+	resp := fmt.Sprintf("Warned %s, warning #%d.", target, id)
+	switch {
+	case usrErr != nil:
+		resp += " " + fmt.Sprint(usrErr)
+	case action == ActionTimeout:
+		resp += " They reached the warning threshold and were timed out."
+	case action == ActionBan:
+		resp += " They reached the warning threshold and were banned."
+	}
+	return resp, usrErr, nil
+}
+
+//////////
+//      //
+// list //
+//      //
+//////////
+
+var AdvancedList = advancedList{}
+
+type advancedList struct{}
+
+func (c advancedList) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedList) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedList) Names() []string {
+	return core.AliasesList
+}
+
+func (advancedList) Description() string {
+	return "List someone's warnings."
+}
+
+func (advancedList) UsageArgs() string {
+	return "<person>"
+}
+
+func (c advancedList) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedList) Examples() []string {
+	return nil
+}
+
+func (advancedList) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedList) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedList) Init() error {
+	return nil
+}
+
+func (c advancedList) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	warnings, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	if usrErr != nil {
+		return render(m, fmt.Sprint(usrErr), usrErr)
+	}
+	target := m.Command.Args[0]
+	if len(warnings) == 0 {
+		return render(m, fmt.Sprintf("%s has no warnings.", target), nil)
+	}
+
+	var lines []string
+	for _, w := range warnings {
+		line := fmt.Sprintf("#%d %s: %s", w.ID, w.Created.Format("2006-01-02"), w.Reason)
+		if w.Warner == Automod {
+			line += " (automod)"
+		}
+		lines = append(lines, line)
+	}
+
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		embed := &dg.MessageEmbed{
+			Title:       fmt.Sprintf("Warnings of %s", target),
+			Description: strings.Join(lines, "\n"),
+		}
+		return embed, nil, nil
+	default:
+		return fmt.Sprintf("Warnings of %s: %s", target, strings.Join(lines, " | ")), nil, nil
+	}
+}
+
+func (advancedList) core(m *core.Message) ([]Warning, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return nil, nil, err
+	}
+	person, err := nick.ParsePerson(m, here, m.Command.Args[0])
+	if err != nil {
+		return nil, moderation.ErrPersonNotFound, nil
+	}
+	warnings, err := List(here, person)
+	return warnings, nil, err
+}
+
+////////////
+//        //
+// delete //
+//        //
+////////////
+
+var AdvancedDelete = advancedDelete{}
+
+type advancedDelete struct{}
+
+func (c advancedDelete) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedDelete) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedDelete) Names() []string {
+	return core.AliasesDelete
+}
+
+func (advancedDelete) Description() string {
+	return "Delete a warning."
+}
+
+func (advancedDelete) UsageArgs() string {
+	return "<id>"
+}
+
+func (c advancedDelete) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedDelete) Examples() []string {
+	return nil
+}
+
+func (advancedDelete) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedDelete) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedDelete) Init() error {
+	return nil
+}
+
+func (c advancedDelete) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedDelete) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	id, usrErr := ParseID(m.Command.Args[0])
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+	usrErr, err = Delete(here, id)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	return fmt.Sprintf("Deleted warning #%d.", id), nil, nil
+}
+
+///////////
+//       //
+// clear //
+//       //
+///////////
+
+var AdvancedClear = advancedClear{}
+
+type advancedClear struct{}
+
+func (c advancedClear) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedClear) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedClear) Names() []string {
+	return []string{
+		"clear",
+	}
+}
+
+func (advancedClear) Description() string {
+	return "Delete all of someone's warnings."
+}
+
+func (advancedClear) UsageArgs() string {
+	return "<person>"
+}
+
+func (c advancedClear) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedClear) Examples() []string {
+	return nil
+}
+
+func (advancedClear) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedClear) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedClear) Init() error {
+	return nil
+}
+
+func (c advancedClear) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedClear) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+	target := m.Command.Args[0]
+	person, err := nick.ParsePerson(m, here, target)
+	if err != nil {
+		return fmt.Sprint(moderation.ErrPersonNotFound), moderation.ErrPersonNotFound, nil
+	}
+
+	n, err := Clear(here, person)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("Cleared %d warning(s) of %s.", n, target), nil, nil
+}
+
+//////////
+//      //
+// show //
+//      //
+//////////
+
+var AdvancedShow = advancedShow{}
+
+type advancedShow struct{}
+
+func (c advancedShow) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedShow) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedShow) Names() []string {
+	return core.AliasesShow
+}
+
+func (advancedShow) Description() string {
+	return "Show the warning threshold settings."
+}
+
+func (advancedShow) UsageArgs() string {
+	return ""
+}
+
+func (c advancedShow) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedShow) Examples() []string {
+	return nil
+}
+
+func (advancedShow) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedShow) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedShow) Init() error {
+	return nil
+}
+
+func (c advancedShow) Run(m *core.Message) (any, error, error) {
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedShow) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	s, err := SettingsGet(here)
+	if err != nil {
+		return "", nil, err
+	}
+	if s.Threshold == 0 {
+		return "Nothing happens automatically when someone is warned.", nil, nil
+	}
+
+	var action string
+	switch s.Action {
+	case ActionBan:
+		action = "banned"
+	default:
+		action = "timed out for " + moderation.FormatDuration(s.Timeout)
+	}
+	resp := fmt.Sprintf("Anyone with %d warnings within %s is %s.", s.Threshold, moderation.FormatDuration(s.Window), action)
+	return resp, nil, nil
+}
+
+///////////////
+//           //
+// threshold //
+//           //
+///////////////
+
+var AdvancedThreshold = advancedThreshold{}
+
+type advancedThreshold struct{}
+
+func (c advancedThreshold) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedThreshold) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedThreshold) Names() []string {
+	return []string{
+		"threshold",
+	}
+}
+
+func (advancedThreshold) Description() string {
+	return "Set the number of warnings that trigger the action."
+}
+
+func (advancedThreshold) UsageArgs() string {
+	return "<number | off>"
+}
+
+func (c advancedThreshold) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedThreshold) Examples() []string {
+	return nil
+}
+
+func (advancedThreshold) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedThreshold) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedThreshold) Init() error {
+	return nil
+}
+
+func (c advancedThreshold) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedThreshold) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var threshold int64
+	if arg := strings.ToLower(m.Command.Args[0]); arg != "off" {
+		if _, err := fmt.Sscan(arg, &threshold); err != nil || threshold <= 0 {
+			return fmt.Sprint(ErrInvalidThreshold), ErrInvalidThreshold, nil
+		}
+	}
+	if err := ThresholdSet(here, threshold); err != nil {
+		return "", nil, err
+	}
+	if threshold == 0 {
+		return "Nothing will happen automatically when someone is warned.", nil, nil
+	}
+	return fmt.Sprintf("The threshold has been set to %d warnings.", threshold), nil, nil
+}
+
+////////////
+//        //
+// window //
+//        //
+////////////
+
+var AdvancedWindow = advancedWindow{}
+
+type advancedWindow struct{}
+
+func (c advancedWindow) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedWindow) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedWindow) Names() []string {
+	return []string{
+		"window",
+	}
+}
+
+func (advancedWindow) Description() string {
+	return "Set the period within which warnings count towards the threshold."
+}
+
+func (advancedWindow) UsageArgs() string {
+	return "<duration>"
+}
+
+func (c advancedWindow) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedWindow) Examples() []string {
+	return nil
+}
+
+func (advancedWindow) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedWindow) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedWindow) Init() error {
+	return nil
+}
+
+func (c advancedWindow) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedWindow) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	dur, usrErr := moderation.ParseDuration(m.Command.Args[0])
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+	usrErr, err = WindowSet(here, dur)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	return fmt.Sprintf("The window has been set to %s.", moderation.FormatDuration(dur)), nil, nil
+}
+
+////////////
+//        //
+// action //
+//        //
+////////////
+
+var AdvancedAction = advancedAction{}
+
+type advancedAction struct{}
+
+func (c advancedAction) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedAction) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedAction) Names() []string {
+	return []string{
+		"action",
+	}
+}
+
+func (advancedAction) Description() string {
+	return "Set what happens when the threshold is reached."
+}
+
+func (advancedAction) UsageArgs() string {
+	return "<timeout | ban>"
+}
+
+func (c advancedAction) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedAction) Examples() []string {
+	return nil
+}
+
+func (advancedAction) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedAction) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedAction) Init() error {
+	return nil
+}
+
+func (c advancedAction) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedAction) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	action := Action(strings.ToLower(m.Command.Args[0]))
+	usrErr, err := ActionSet(here, action)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	return fmt.Sprintf("The action has been set to %s.", action), nil, nil
+}
+
+/////////////
+//         //
+// timeout //
+//         //
+/////////////
+
+var AdvancedTimeout = advancedTimeout{}
+
+type advancedTimeout struct{}
+
+func (c advancedTimeout) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c advancedTimeout) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (advancedTimeout) Names() []string {
+	return []string{
+		"timeout",
+	}
+}
+
+func (advancedTimeout) Description() string {
+	return "Set how long the timeout action lasts."
+}
+
+func (advancedTimeout) UsageArgs() string {
+	return "<duration>"
+}
+
+func (c advancedTimeout) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (advancedTimeout) Examples() []string {
+	return nil
+}
+
+func (advancedTimeout) Parent() core.CommandStatic {
+	return Advanced
+}
+
+func (advancedTimeout) Children() core.CommandsStatic {
+	return nil
+}
+
+func (advancedTimeout) Init() error {
+	return nil
+}
+
+func (c advancedTimeout) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+	resp, usrErr, err := c.core(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return render(m, resp, usrErr)
+}
+
+func (advancedTimeout) core(m *core.Message) (string, error, error) {
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return "", nil, err
+	}
+
+	dur, usrErr := moderation.ParseDuration(m.Command.Args[0])
+	if usrErr != nil {
+		return fmt.Sprint(usrErr), usrErr, nil
+	}
+	usrErr, err = TimeoutSet(here, dur)
+	if usrErr != nil || err != nil {
+		return fmt.Sprint(usrErr), usrErr, err
+	}
+	return fmt.Sprintf("The timeout has been set to %s.", moderation.FormatDuration(dur)), nil, nil
+}
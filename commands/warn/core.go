@@ -0,0 +1,181 @@
+package warn
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/commands/moderation"
+	"github.com/janitorjeff/jeff-bot/core"
+)
+
+var (
+	ErrNotFound         = errors.New("Couldn't find a warning with that ID.")
+	ErrInvalidID        = errors.New("Expected a warning ID, for example #3.")
+	ErrInvalidAction    = errors.New("Expected either timeout or ban.")
+	ErrInvalidThreshold = errors.New("Expected a positive number or off.")
+	ErrWindowTooShort   = errors.New("The window must be at least one minute long.")
+	ErrTimeoutTooShort  = errors.New("The timeout must be at least one second long.")
+)
+
+// Automod is used as the warner for warnings that were automatically added
+// by automod.
+const Automod int64 = -1
+
+// Action is what happens when someone reaches the warning threshold.
+type Action string
+
+const (
+	ActionNone    Action = ""
+	ActionTimeout Action = "timeout"
+	ActionBan     Action = "ban"
+)
+
+type Warning struct {
+	ID      int64
+	Place   int64
+	Person  int64
+	Warner  int64 // Automod if added by automod
+	Reason  string
+	Created time.Time
+}
+
+// Settings are a place's warning threshold settings.
+type Settings struct {
+	// Threshold is the number of warnings within Window that trigger Action,
+	// 0 means that nothing happens automatically.
+	Threshold int64
+	Window    time.Duration
+	Action    Action
+	Timeout   time.Duration
+}
+
+// ParseID parses a warning ID, which can optionally be prefixed with a #.
+func ParseID(s string) (int64, error) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(s, "#"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidID
+	}
+	return id, nil
+}
+
+// Warn adds a warning for the person in the place the message came from and
+// applies the place's threshold action if it was reached. Returns the new
+// warning's ID and the action that was applied, if any.
+func Warn(m *core.Message, person, warner int64, reason string) (int64, Action, error, error) {
+	place, err := m.Here.ScopeLogical()
+	if err != nil {
+		return -1, ActionNone, nil, err
+	}
+
+	id, err := dbAdd(place, person, warner, reason)
+	if err != nil {
+		return -1, ActionNone, nil, err
+	}
+
+	s, err := SettingsGet(place)
+	if err != nil {
+		return id, ActionNone, nil, err
+	}
+	if s.Threshold == 0 {
+		return id, ActionNone, nil, nil
+	}
+
+	count, err := Count(place, person, time.Now().Add(-s.Window))
+	if err != nil {
+		return id, ActionNone, nil, err
+	}
+	if int64(count) < s.Threshold {
+		return id, ActionNone, nil, nil
+	}
+
+	var usrErr error
+	switch s.Action {
+	case ActionBan:
+		usrErr, err = moderation.Ban(m, person, reason)
+	default:
+		usrErr, err = moderation.Timeout(m, person, s.Timeout, reason)
+	}
+	return id, s.Action, usrErr, err
+}
+
+// Count returns the number of warnings the person has received in the
+// specified place since the given time.
+func Count(place, person int64, since time.Time) (int, error) {
+	return dbCount(place, person, since, false)
+}
+
+// CountAutomod is the same as Count but only counts warnings added by
+// automod.
+func CountAutomod(place, person int64, since time.Time) (int, error) {
+	return dbCount(place, person, since, true)
+}
+
+// List returns all of the person's warnings in the specified place.
+func List(place, person int64) ([]Warning, error) {
+	return dbList(place, person)
+}
+
+// Delete deletes a single warning. Returns ErrNotFound if the warning doesn't
+// exist in the specified place.
+func Delete(place, id int64) (error, error) {
+	exists, err := dbExists(place, id)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return ErrNotFound, nil
+	}
+	return nil, dbDelete(place, id)
+}
+
+// Clear deletes all of the person's warnings in the specified place and
+// returns how many were deleted.
+func Clear(place, person int64) (int64, error) {
+	return dbClear(place, person)
+}
+
+//////////////
+//          //
+// settings //
+//          //
+//////////////
+
+// SettingsGet returns the threshold settings of the specified place.
+func SettingsGet(place int64) (Settings, error) {
+	return dbGetSettings(place)
+}
+
+// ThresholdSet sets the number of warnings that trigger the threshold action,
+// 0 turns it off.
+func ThresholdSet(place, threshold int64) error {
+	return core.DB.SettingPlaceSet("cmd_warn_threshold", place, threshold)
+}
+
+// WindowSet sets the amount of time within which the warnings must have been
+// received in order to count towards the threshold.
+func WindowSet(place int64, window time.Duration) (error, error) {
+	if window < time.Minute {
+		return ErrWindowTooShort, nil
+	}
+	return nil, core.DB.SettingPlaceSet("cmd_warn_window", place, int64(window.Seconds()))
+}
+
+// ActionSet sets the action that is applied when the threshold is reached.
+func ActionSet(place int64, action Action) (error, error) {
+	switch action {
+	case ActionTimeout, ActionBan:
+	default:
+		return ErrInvalidAction, nil
+	}
+	return nil, core.DB.SettingPlaceSet("cmd_warn_action", place, string(action))
+}
+
+// TimeoutSet sets how long the timeout action lasts.
+func TimeoutSet(place int64, dur time.Duration) (error, error) {
+	if dur < time.Second {
+		return ErrTimeoutTooShort, nil
+	}
+	return nil, core.DB.SettingPlaceSet("cmd_warn_timeout", place, int64(dur.Seconds()))
+}
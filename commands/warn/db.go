@@ -0,0 +1,222 @@
+package warn
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/rs/zerolog/log"
+)
+
+func dbAdd(place, person, warner int64, reason string) (int64, error) {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	var w sql.NullInt64
+	if warner != Automod {
+		w = sql.NullInt64{Int64: warner, Valid: true}
+	}
+
+	var id int64
+	err := db.DB.QueryRow(`
+		INSERT INTO cmd_warn_warnings(place, person, warner, reason, created)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, place, person, w, reason, time.Now().UTC().Unix()).Scan(&id)
+
+	log.Debug().
+		Err(err).
+		Int64("id", id).
+		Int64("place", place).
+		Int64("person", person).
+		Int64("warner", warner).
+		Str("reason", reason).
+		Msg("added warning")
+
+	return id, err
+}
+
+func dbCount(place, person int64, since time.Time, automod bool) (int, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	query := `
+		SELECT COUNT(*)
+		FROM cmd_warn_warnings
+		WHERE place = $1 and person = $2 and created >= $3
+	`
+	if automod {
+		query += " and warner IS NULL"
+	}
+
+	var count int
+	err := db.DB.QueryRow(query, place, person, since.UTC().Unix()).Scan(&count)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int64("person", person).
+		Time("since", since).
+		Bool("automod", automod).
+		Int("count", count).
+		Msg("counted warnings")
+
+	return count, err
+}
+
+func dbList(place, person int64) ([]Warning, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	rows, err := db.DB.Query(`
+		SELECT id, warner, reason, created
+		FROM cmd_warn_warnings
+		WHERE place = $1 and person = $2
+		ORDER BY id
+	`, place, person)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var warnings []Warning
+	for rows.Next() {
+		var warner sql.NullInt64
+		var created int64
+		w := Warning{
+			Place:  place,
+			Person: person,
+		}
+		if err := rows.Scan(&w.ID, &warner, &w.Reason, &created); err != nil {
+			return nil, err
+		}
+		w.Warner = Automod
+		if warner.Valid {
+			w.Warner = warner.Int64
+		}
+		w.Created = time.Unix(created, 0).UTC()
+		warnings = append(warnings, w)
+	}
+
+	err = rows.Err()
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int64("person", person).
+		Int("count", len(warnings)).
+		Msg("got warnings")
+
+	return warnings, err
+}
+
+func dbExists(place, id int64) (bool, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	var exists bool
+	row := db.DB.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM cmd_warn_warnings
+			WHERE place = $1 and id = $2
+			LIMIT 1
+		)
+	`, place, id)
+	err := row.Scan(&exists)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int64("id", id).
+		Bool("exists", exists).
+		Msg("checked if warning exists")
+
+	return exists, err
+}
+
+func dbDelete(place, id int64) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		DELETE FROM cmd_warn_warnings
+		WHERE place = $1 and id = $2
+	`, place, id)
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int64("id", id).
+		Msg("deleted warning")
+
+	return err
+}
+
+func dbClear(place, person int64) (int64, error) {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	res, err := db.DB.Exec(`
+		DELETE FROM cmd_warn_warnings
+		WHERE place = $1 and person = $2
+	`, place, person)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := res.RowsAffected()
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Int64("person", person).
+		Int64("deleted", n).
+		Msg("cleared warnings")
+
+	return n, err
+}
+
+func dbGetSettings(place int64) (Settings, error) {
+	var s Settings
+
+	// Make sure that the place settings are present
+	if err := core.DB.SettingsPlaceGenerate(place); err != nil {
+		return s, err
+	}
+
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	var window, timeout int64
+	var action string
+
+	err := db.DB.QueryRow(`
+		SELECT
+			cmd_warn_threshold,
+			cmd_warn_window,
+			cmd_warn_action,
+			cmd_warn_timeout
+		FROM settings_place
+		WHERE place = $1
+	`, place).Scan(&s.Threshold, &window, &action, &timeout)
+
+	s.Window = time.Duration(window) * time.Second
+	s.Action = Action(action)
+	s.Timeout = time.Duration(timeout) * time.Second
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Interface("settings", s).
+		Msg("got warning settings")
+
+	return s, err
+}
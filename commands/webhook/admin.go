@@ -0,0 +1,307 @@
+package webhook
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/janitorjeff/jeff-bot/core"
+	"github.com/janitorjeff/jeff-bot/frontends/discord"
+	wh "github.com/janitorjeff/jeff-bot/frontends/webhook"
+
+	dg "github.com/bwmarrin/discordgo"
+)
+
+func render(m *core.Message, resp string, usrErr error) (any, error, error) {
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		return &dg.MessageEmbed{Description: resp}, usrErr, nil
+	default:
+		return resp, usrErr, nil
+	}
+}
+
+var Admin = admin{}
+
+type admin struct{}
+
+func (admin) Type() core.CommandType {
+	return core.Admin
+}
+
+func (admin) Permitted(*core.Message) bool {
+	return true
+}
+
+func (admin) Names() []string {
+	return []string{
+		"webhook",
+	}
+}
+
+func (admin) Description() string {
+	return "Manage the clients that can run commands over HTTP."
+}
+
+func (c admin) UsageArgs() string {
+	return c.Children().Usage()
+}
+
+func (admin) Category() core.CommandCategory {
+	return core.CommandCategoryOther
+}
+
+func (admin) Examples() []string {
+	return nil
+}
+
+func (admin) Parent() core.CommandStatic {
+	return nil
+}
+
+func (admin) Children() core.CommandsStatic {
+	return core.CommandsStatic{
+		AdminAdd,
+		AdminDelete,
+		AdminList,
+	}
+}
+
+func (admin) Init() error {
+	return nil
+}
+
+func (admin) Run(m *core.Message) (any, error, error) {
+	return m.Usage(), core.ErrMissingArgs, nil
+}
+
+/////////
+//     //
+// add //
+//     //
+/////////
+
+var AdminAdd = adminAdd{}
+
+type adminAdd struct{}
+
+func (c adminAdd) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c adminAdd) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (adminAdd) Names() []string {
+	return core.AliasesAdd
+}
+
+func (adminAdd) Description() string {
+	return "Register a client, the token is sent in a DM."
+}
+
+func (adminAdd) UsageArgs() string {
+	return "<name> [callback url]"
+}
+
+func (c adminAdd) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (adminAdd) Examples() []string {
+	return []string{
+		"streamdeck",
+		"website https://example.com/jeff-callback",
+	}
+}
+
+func (adminAdd) Parent() core.CommandStatic {
+	return Admin
+}
+
+func (adminAdd) Children() core.CommandsStatic {
+	return nil
+}
+
+func (adminAdd) Init() error {
+	return nil
+}
+
+func (c adminAdd) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+
+	// anyone in chat could see the token
+	if m.Frontend.Type() != discord.Frontend.Type() {
+		return render(m, "Tokens can't be sent privately here.", nil)
+	}
+
+	name := m.Command.Args[0]
+	var callback string
+	if len(m.Command.Args) > 1 {
+		callback = m.Command.Args[1]
+	}
+
+	token, usrErr, err := wh.Register(name, callback)
+	if err != nil {
+		return nil, nil, err
+	}
+	if usrErr != nil {
+		return render(m, fmt.Sprint(usrErr), usrErr)
+	}
+
+	text := fmt.Sprintf("The token of client %s, send it in the Authorization header as `Bearer <token>`:\n||%s||", name, token)
+	if err := discord.DM(m.Author.ID(), text); err != nil {
+		return nil, nil, err
+	}
+	return render(m, fmt.Sprintf("Registered client %s, sent you its token in a DM.", name), nil)
+}
+
+////////////
+//        //
+// delete //
+//        //
+////////////
+
+var AdminDelete = adminDelete{}
+
+type adminDelete struct{}
+
+func (c adminDelete) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c adminDelete) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (adminDelete) Names() []string {
+	return core.AliasesDelete
+}
+
+func (adminDelete) Description() string {
+	return "Delete a client along with all of its people and places."
+}
+
+func (adminDelete) UsageArgs() string {
+	return "<name>"
+}
+
+func (c adminDelete) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (adminDelete) Examples() []string {
+	return []string{
+		"streamdeck",
+	}
+}
+
+func (adminDelete) Parent() core.CommandStatic {
+	return Admin
+}
+
+func (adminDelete) Children() core.CommandsStatic {
+	return nil
+}
+
+func (adminDelete) Init() error {
+	return nil
+}
+
+func (c adminDelete) Run(m *core.Message) (any, error, error) {
+	if len(m.Command.Args) < 1 {
+		return m.Usage(), core.ErrMissingArgs, nil
+	}
+
+	name := m.Command.Args[0]
+	usrErr, err := wh.Delete(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if usrErr != nil {
+		return render(m, fmt.Sprint(usrErr), usrErr)
+	}
+	return render(m, fmt.Sprintf("Deleted client %s.", name), nil)
+}
+
+//////////
+//      //
+// list //
+//      //
+//////////
+
+var AdminList = adminList{}
+
+type adminList struct{}
+
+func (c adminList) Type() core.CommandType {
+	return c.Parent().Type()
+}
+
+func (c adminList) Permitted(m *core.Message) bool {
+	return c.Parent().Permitted(m)
+}
+
+func (adminList) Names() []string {
+	return core.AliasesList
+}
+
+func (adminList) Description() string {
+	return "List the registered clients."
+}
+
+func (adminList) UsageArgs() string {
+	return ""
+}
+
+func (c adminList) Category() core.CommandCategory {
+	return c.Parent().Category()
+}
+
+func (adminList) Examples() []string {
+	return nil
+}
+
+func (adminList) Parent() core.CommandStatic {
+	return Admin
+}
+
+func (adminList) Children() core.CommandsStatic {
+	return nil
+}
+
+func (adminList) Init() error {
+	return nil
+}
+
+func (c adminList) Run(m *core.Message) (any, error, error) {
+	clients, err := wh.List()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(clients) == 0 {
+		return render(m, "No clients have been registered.", nil)
+	}
+
+	var lines []string
+	for _, cl := range clients {
+		line := cl.Name
+		if cl.Callback != "" {
+			line += " (" + cl.Callback + ")"
+		}
+		lines = append(lines, line)
+	}
+
+	switch m.Frontend.Type() {
+	case discord.Frontend.Type():
+		for i := range lines {
+			lines[i] = "- " + lines[i]
+		}
+		return render(m, strings.Join(lines, "\n"), nil)
+	default:
+		return render(m, strings.Join(lines, ", "), nil)
+	}
+}
@@ -4,12 +4,15 @@ import (
 	"encoding/json"
 	"errors"
 	"io/ioutil"
-	"net/http"
 	"net/url"
+
+	"github.com/janitorjeff/jeff-bot/core"
 )
 
 var errNoResult = errors.New("no results found")
 
+var client = core.APIClient("wikipedia")
+
 const queryURL = "https://en.wikipedia.org/w/api.php?" +
 	"action=query" +
 	"&format=json" +
@@ -32,7 +35,7 @@ type response struct {
 }
 
 func Search(query string) (page, error, error) {
-	resp, err := http.Get(queryURL + url.QueryEscape(query))
+	resp, err := client.Get(queryURL + url.QueryEscape(query))
 	if err != nil {
 		return page{}, nil, err
 	}
@@ -35,7 +35,7 @@ type Client struct {
 // New returns a new youtube client using the key set in core.YouTubeKey.
 func New() (*Client, error) {
 	client := &http.Client{
-		Transport: &transport.APIKey{Key: core.YouTubeKey},
+		Transport: core.APITransport("youtube", &transport.APIKey{Key: core.YouTubeKey}),
 	}
 
 	service, err := youtube.New(client)
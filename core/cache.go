@@ -1,32 +1,246 @@
 package core
 
 import (
+	"bytes"
+	"container/list"
+	"encoding/gob"
+	"sync"
+	"time"
+
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
 )
 
-var RDB *redis.Client
+// How long things stay cached. Everything that's cached is also invalidated
+// explicitly when it changes, the TTLs only exist so that anything that is
+// missed, for example changes made directly to the DB, doesn't stay stale
+// forever.
+const (
+	// Scopes only change if they're deleted, which rarely happens.
+	CacheScopeTTL = 24 * time.Hour
+	CacheTTL      = 10 * time.Minute
+)
+
+// Cacher is a key-value store with expiring keys.
+type Cacher interface {
+	// Get returns the key's value and whether or not it was found.
+	Get(key string) ([]byte, bool, error)
+	// Set sets the key's value, a ttl of 0 means that it never expires.
+	Set(key string, val []byte, ttl time.Duration) error
+	// Delete deletes the keys, keys that don't exist are ignored.
+	Delete(keys ...string) error
+}
+
+// Cache is the bot's cache, it is shared between instances only if it's
+// backed by redis.
+var Cache Cacher = NewMemoryCache(100000)
+
+func init() {
+	// The settings are returned as is from the DB driver, these are the only
+	// types that aren't registered by default.
+	gob.Register(time.Time{})
+}
+
+// cached is what is actually stored, the value is wrapped in a struct so that
+// interfaces and nil values can also be cached.
+type cached[T any] struct {
+	Val T
+}
+
+// CacheGet returns the key's value by looking it up in the cache, if it
+// doesn't exist then it fetches it using get and then caches it for ttl. The
+// key should be globally unique.
+func CacheGet[T any](key string, ttl time.Duration, get func() (T, error)) (T, error) {
+	slog := log.With().Str("key", key).Logger()
+
+	var c cached[T]
+
+	b, found, err := Cache.Get(key)
+	if err != nil {
+		return c.Val, err
+	}
+	if found {
+		if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&c); err == nil {
+			MetricCache.Inc("hit")
+			slog.Debug().Interface("val", c.Val).Msg("CACHE: found value")
+			return c.Val, nil
+		}
+		// the type of the value might have changed since it was cached, so
+		// it's just treated as a miss
+		slog.Debug().Err(err).Msg("CACHE: failed to decode value")
+	}
+	MetricCache.Inc("miss")
+
+	c.Val, err = get()
+	if err != nil {
+		return c.Val, err
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(c); err != nil {
+		return c.Val, err
+	}
+	err = Cache.Set(key, buf.Bytes(), ttl)
+	slog.Debug().Err(err).Interface("val", c.Val).Msg("CACHE: cached value")
+	return c.Val, err
+}
 
 // CacheScope returns the scope by looking it up in the cache, if it doesn't
 // exist then it fetches it from the DB using getScope and then caches it. The
 // key should be globally unique.
 func CacheScope(key string, getScope func() (int64, error)) (int64, error) {
-	slog := log.With().Str("key", key).Logger()
-
-	scope, err := RDB.Get(ctx, key).Int64()
-	if err != nil && err != redis.Nil {
+	scope, err := CacheGet(key, CacheScopeTTL, getScope)
+	if err != nil {
 		return -1, err
 	}
-	if err != redis.Nil {
-		slog.Debug().Int64("scope", scope).Msg("CACHE: found scope")
-		return scope, nil
+	return scope, nil
+}
+
+// CacheInvalidate deletes the keys from the cache. It should be called
+// whenever what the keys were caching changes, for example when a scope is
+// deleted.
+func CacheInvalidate(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	err := Cache.Delete(keys...)
+	log.Debug().Err(err).Strs("keys", keys).Msg("CACHE: invalidated keys")
+	return err
+}
+
+///////////
+//       //
+// redis //
+//       //
+///////////
+
+// RedisCache implements the Cacher interface using redis.
+type RedisCache struct {
+	RDB *redis.Client
+}
+
+func NewRedisCache(addr string) *RedisCache {
+	return &RedisCache{
+		RDB: redis.NewClient(&redis.Options{
+			Addr: addr,
+		}),
 	}
+}
 
-	scope, err = getScope()
+func (c *RedisCache) Get(key string) ([]byte, bool, error) {
+	b, err := c.RDB.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
 	if err != nil {
-		return -1, err
+		return nil, false, err
+	}
+	return b, true, nil
+}
+
+func (c *RedisCache) Set(key string, val []byte, ttl time.Duration) error {
+	return c.RDB.Set(ctx, key, val, ttl).Err()
+}
+
+func (c *RedisCache) Delete(keys ...string) error {
+	return c.RDB.Del(ctx, keys...).Err()
+}
+
+////////////
+//        //
+// memory //
+//        //
+////////////
+
+// MemoryCache implements the Cacher interface with an in-process LRU cache.
+// Expired keys are only removed once they're accessed or evicted.
+type MemoryCache struct {
+	lock  sync.Mutex
+	size  int
+	order *list.List
+	keys  map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key     string
+	val     []byte
+	expires time.Time
+}
+
+// NewMemoryCache returns a cache that holds at most size keys, once full the
+// least recently used key is evicted.
+func NewMemoryCache(size int) *MemoryCache {
+	if size <= 0 {
+		panic("cache size must be positive")
+	}
+	return &MemoryCache{
+		size:  size,
+		order: list.New(),
+		keys:  map[string]*list.Element{},
+	}
+}
+
+func (c *MemoryCache) Get(key string) ([]byte, bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	el, ok := c.keys[key]
+	if !ok {
+		return nil, false, nil
 	}
-	err = RDB.Set(ctx, key, scope, 0).Err()
-	slog.Debug().Err(err).Int64("scope", scope).Msg("CACHE: cached scope")
-	return scope, err
+
+	e := el.Value.(*memoryEntry)
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		c.remove(el)
+		return nil, false, nil
+	}
+
+	c.order.MoveToFront(el)
+	return e.val, true, nil
+}
+
+func (c *MemoryCache) Set(key string, val []byte, ttl time.Duration) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+
+	if el, ok := c.keys[key]; ok {
+		e := el.Value.(*memoryEntry)
+		e.val = val
+		e.expires = expires
+		c.order.MoveToFront(el)
+		return nil
+	}
+
+	c.keys[key] = c.order.PushFront(&memoryEntry{
+		key:     key,
+		val:     val,
+		expires: expires,
+	})
+
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+func (c *MemoryCache) Delete(keys ...string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, key := range keys {
+		if el, ok := c.keys[key]; ok {
+			c.remove(el)
+		}
+	}
+	return nil
+}
+
+func (c *MemoryCache) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.keys, el.Value.(*memoryEntry).key)
 }
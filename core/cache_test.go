@@ -0,0 +1,79 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/core"
+)
+
+func TestMemoryCache(t *testing.T) {
+	c := core.NewMemoryCache(2)
+
+	c.Set("a", []byte("1"), 0)
+	c.Set("b", []byte("2"), 0)
+	// a is now the most recently used, so b gets evicted
+	c.Get("a")
+	c.Set("c", []byte("3"), 0)
+
+	if _, found, _ := c.Get("b"); found {
+		t.Error("expected least recently used key to be evicted")
+	}
+	if v, found, _ := c.Get("a"); !found || string(v) != "1" {
+		t.Errorf("expected a to be 1, got %q (found %t)", v, found)
+	}
+
+	c.Set("d", []byte("4"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if _, found, _ := c.Get("d"); found {
+		t.Error("expected key to have expired")
+	}
+
+	c.Delete("a", "missing")
+	if _, found, _ := c.Get("a"); found {
+		t.Error("expected key to be deleted")
+	}
+}
+
+func TestCacheGet(t *testing.T) {
+	core.Cache = core.NewMemoryCache(10)
+
+	now := time.Now().UTC().Round(time.Second)
+	tests := []struct {
+		key string
+		val any
+	}{
+		{"int", int64(7)},
+		{"bool", false},
+		{"string", "UTC"},
+		{"time", now},
+		{"nil", nil},
+	}
+
+	for _, test := range tests {
+		calls := 0
+		get := func() (any, error) {
+			calls++
+			return test.val, nil
+		}
+
+		for i := 0; i < 2; i++ {
+			v, err := core.CacheGet(test.key, time.Minute, get)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", test.key, err)
+			}
+			if v != test.val {
+				t.Errorf("%s: expected %v (%T), got %v (%T)", test.key, test.val, test.val, v, v)
+			}
+		}
+		if calls != 1 {
+			t.Errorf("%s: expected value to be fetched once, was fetched %d times", test.key, calls)
+		}
+
+		core.CacheInvalidate(test.key)
+		core.CacheGet(test.key, time.Minute, get)
+		if calls != 2 {
+			t.Errorf("%s: expected value to be fetched again after invalidation", test.key)
+		}
+	}
+}
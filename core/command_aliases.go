@@ -0,0 +1,114 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Aliases:
+//
+// Places can give their own names to built-in commands, for example `!sr` can
+// be made to run `$audio play`. Same as with prefixes, the core only knows how
+// to find and expand a place's aliases, adding and deleting them is handled
+// externally by a command.
+//
+// An alias is expanded before the command is matched, so the command that it
+// stands for goes through the same checks as if it was typed out, meaning the
+// permissions and the type of the command are respected.
+
+type Alias struct {
+	// Trigger is what the alias is called with, it's used as is, without a
+	// prefix.
+	Trigger string
+
+	// Type is the type of the command, any of the place's prefixes of this
+	// type is used when expanding the alias.
+	Type CommandType
+
+	// Command is the command's path followed by any fixed arguments, without
+	// a prefix.
+	Command string
+}
+
+func aliasesKey(place int64) string {
+	return fmt.Sprintf("aliases_%d", place)
+}
+
+// PlaceAliasesInvalidate must be called whenever the place's aliases are
+// changed so that the cached ones aren't used.
+func PlaceAliasesInvalidate(place int64) error {
+	return CacheInvalidate(aliasesKey(place))
+}
+
+// PlaceAliases returns all of the place's aliases.
+func PlaceAliases(place int64) ([]Alias, error) {
+	return CacheGet(aliasesKey(place), CacheTTL, func() ([]Alias, error) {
+		return DB.AliasList(place)
+	})
+}
+
+// PlaceAlias returns the place's alias with the given trigger, if it exists.
+func PlaceAlias(place int64, trigger string) (Alias, bool, error) {
+	aliases, err := PlaceAliases(place)
+	if err != nil {
+		return Alias{}, false, err
+	}
+	for _, a := range aliases {
+		if a.Trigger == trigger {
+			return a, true, nil
+		}
+	}
+	return Alias{}, false, nil
+}
+
+// expandAlias sets the message's expanded text to the command that the alias
+// it begins with stands for, if any, followed by the arguments that come after
+// the alias exactly as they were typed.
+func (m *Message) expandAlias() error {
+	m.Expanded = ""
+
+	fields := fieldsSpace(m.Raw)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		return err
+	}
+
+	prefixes, _, err := PlacePrefixes(here)
+	if err != nil {
+		return err
+	}
+
+	// Triggers always begin with one of the place's prefixes, so most
+	// messages can be skipped without looking up the aliases.
+	trigger := strings.TrimSpace(fields[0])
+	if _, _, ok := MatchPrefix(prefixes, trigger); !ok {
+		return nil
+	}
+
+	alias, ok, err := PlaceAlias(here, trigger)
+	if err != nil || !ok {
+		return err
+	}
+
+	// If the place has no prefixes of the alias's type then commands of that
+	// type can't be used here, so neither can the alias.
+	prefix, ok := TypePrefix(prefixes, alias.Type)
+	if !ok {
+		return fmt.Errorf("no prefix of type %d for alias '%s'", alias.Type, alias.Trigger)
+	}
+
+	m.Expanded = strings.TrimSpace(prefix + alias.Command + " " + strings.Join(fields[1:], ""))
+
+	log.Debug().
+		Str("alias", alias.Trigger).
+		Str("text", m.Expanded).
+		Msg("expanded alias")
+
+	return nil
+}
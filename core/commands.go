@@ -105,14 +105,33 @@ func Format(cmd CommandStatic, prefix string) string {
 		args = " " + cmd.UsageArgs()
 	}
 
+	return fmt.Sprintf("%s%s%s", prefix, commandPath(cmd), args)
+}
+
+// commandPath returns the main names of the command and its parents, for
+// example "prefix add".
+func commandPath(cmd CommandStatic) string {
 	path := []string{}
 	for cmd.Parent() != nil {
 		path = append([]string{cmd.Names()[0]}, path...)
 		cmd = cmd.Parent()
 	}
 	path = append([]string{cmd.Names()[0]}, path...)
+	return strings.Join(path, " ")
+}
 
-	return fmt.Sprintf("%s%s%s", prefix, strings.Join(path, " "), args)
+// typeName returns the command type's name in lowercase, for example normal.
+func typeName(t CommandType) string {
+	switch t {
+	case Normal:
+		return "normal"
+	case Advanced:
+		return "advanced"
+	case Admin:
+		return "admin"
+	default:
+		return "unknown"
+	}
 }
 
 // CommandRuntime holds a command's runtime information.
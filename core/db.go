@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"sync"
+	"time"
 
 	_ "github.com/lib/pq"
 	"github.com/rs/zerolog/log"
@@ -63,6 +64,8 @@ func (db *SQLDB) Init(schema string) error {
 }
 
 func (_ *SQLDB) ScopeAdd(tx *sql.Tx, frontendID string, frontend int) (int64, error) {
+	defer MetricDB.Since(time.Now(), "ScopeAdd")
+
 	var id int64
 	err := tx.QueryRow(`
 		INSERT INTO scopes(frontend_id, frontend_type)
@@ -75,6 +78,8 @@ func (_ *SQLDB) ScopeAdd(tx *sql.Tx, frontendID string, frontend int) (int64, er
 
 // Returns the given scope's frontend specific ID
 func (db *SQLDB) ScopeID(scope int64) (string, error) {
+	defer MetricDB.Since(time.Now(), "ScopeID")
+
 	db.Lock.RLock()
 	defer db.Lock.RUnlock()
 
@@ -92,6 +97,8 @@ func (db *SQLDB) ScopeID(scope int64) (string, error) {
 
 // Returns then given scope's frontend id
 func (db *SQLDB) ScopeFrontend(scope int64) (int64, error) {
+	defer MetricDB.Since(time.Now(), "ScopeFrontend")
+
 	db.Lock.RLock()
 	defer db.Lock.RUnlock()
 
@@ -109,11 +116,13 @@ func (db *SQLDB) ScopeFrontend(scope int64) (int64, error) {
 
 // Returns the list of all prefixes for a specific scope.
 func (db *SQLDB) PrefixList(place int64) ([]Prefix, error) {
+	defer MetricDB.Since(time.Now(), "PrefixList")
+
 	db.Lock.RLock()
 	defer db.Lock.RUnlock()
 
 	rows, err := db.DB.Query(`
-		SELECT prefix, type
+		SELECT prefix, type, spaced
 		FROM prefixes
 		WHERE place = $1`, place)
 	if err != nil {
@@ -125,10 +134,11 @@ func (db *SQLDB) PrefixList(place int64) ([]Prefix, error) {
 	for rows.Next() {
 		var prefix string
 		var t CommandType
-		if err := rows.Scan(&prefix, &t); err != nil {
+		var spaced bool
+		if err := rows.Scan(&prefix, &t, &spaced); err != nil {
 			return nil, err
 		}
-		prefixes = append(prefixes, Prefix{Type: t, Prefix: prefix})
+		prefixes = append(prefixes, Prefix{Type: t, Prefix: prefix, Spaced: spaced})
 	}
 
 	err = rows.Err()
@@ -141,12 +151,52 @@ func (db *SQLDB) PrefixList(place int64) ([]Prefix, error) {
 	return prefixes, err
 }
 
+// Returns the list of all aliases for a specific place.
+func (db *SQLDB) AliasList(place int64) ([]Alias, error) {
+	defer MetricDB.Since(time.Now(), "AliasList")
+
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	rows, err := db.DB.Query(`
+		SELECT trigger, type, command
+		FROM aliases
+		WHERE place = $1`, place)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var aliases []Alias
+	for rows.Next() {
+		var a Alias
+		if err := rows.Scan(&a.Trigger, &a.Type, &a.Command); err != nil {
+			return nil, err
+		}
+		aliases = append(aliases, a)
+	}
+
+	err = rows.Err()
+
+	log.Debug().
+		Err(err).
+		Int64("place", place).
+		Interface("aliases", aliases).
+		Msg("got aliases")
+
+	return aliases, err
+}
+
 ////////////////////
 //                //
 // place settings //
 //                //
 ////////////////////
 
+func settingPlaceKey(col string, place int64) string {
+	return fmt.Sprintf("settings_place_%d_%s", place, col)
+}
+
 func (db *SQLDB) settingsPlaceExist(place int64) (bool, error) {
 	db.Lock.RLock()
 	defer db.Lock.RUnlock()
@@ -192,67 +242,60 @@ func (db *SQLDB) settingsPlaceGenerate(place int64) error {
 // SettingsPlaceGenerate will check if settings for the specified place exist
 // and if not will generate them.
 func (db *SQLDB) SettingsPlaceGenerate(place int64) error {
-	rdbKey := fmt.Sprintf("settings_place_%d", place)
+	defer MetricDB.Since(time.Now(), "SettingsPlaceGenerate")
 
-	if _, err := RDB.Get(ctx, rdbKey).Result(); err == nil {
-		log.Debug().Msg("CACHE: place settings already generated")
-		return nil
-	}
-
-	exists, err := db.settingsPlaceExist(place)
-	if err != nil {
-		return err
-	}
-	if exists {
-		err := RDB.Set(ctx, rdbKey, nil, 0).Err()
-		log.Debug().
-			Err(err).
-			Msg("CACHE: place settings already exist in db, caching")
-		return err
-	}
+	rdbKey := fmt.Sprintf("settings_place_%d", place)
 
-	err = db.settingsPlaceGenerate(place)
-	if err != nil {
-		return err
-	}
-	err = RDB.Set(ctx, rdbKey, nil, 0).Err()
-	log.Debug().Err(err).Msg("CACHE: generated place settings in db, caching")
+	// The settings only stop existing if the place's scope is deleted.
+	_, err := CacheGet(rdbKey, CacheScopeTTL, func() (bool, error) {
+		exists, err := db.settingsPlaceExist(place)
+		if err != nil || exists {
+			return exists, err
+		}
+		return true, db.settingsPlaceGenerate(place)
+	})
 	return err
 }
 
 // SettingPlaceGet returns the value of col in table for the specified place.
 func (db *SQLDB) SettingPlaceGet(col string, place int64) (any, error) {
+	defer MetricDB.Since(time.Now(), "SettingPlaceGet")
+
 	// Make sure that the place settings are present
 	if err := db.SettingsPlaceGenerate(place); err != nil {
 		return nil, err
 	}
 
-	db.Lock.RLock()
-	defer db.Lock.RUnlock()
+	return CacheGet(settingPlaceKey(col, place), CacheTTL, func() (any, error) {
+		db.Lock.RLock()
+		defer db.Lock.RUnlock()
 
-	var val any
+		var val any
 
-	query := fmt.Sprintf(`
-		SELECT %s
-		FROM settings_place
-		WHERE place = $1
-	`, col)
+		query := fmt.Sprintf(`
+			SELECT %s
+			FROM settings_place
+			WHERE place = $1
+		`, col)
 
-	row := db.DB.QueryRow(query, place)
+		row := db.DB.QueryRow(query, place)
 
-	err := row.Scan(&val)
+		err := row.Scan(&val)
 
-	log.Debug().
-		Err(err).
-		Int64("place", place).
-		Interface(col, val).
-		Msg("got value")
+		log.Debug().
+			Err(err).
+			Int64("place", place).
+			Interface(col, val).
+			Msg("got value")
 
-	return val, err
+		return val, err
+	})
 }
 
 // SettingPlaceSet sets the value of col in table for the specified place.
 func (db *SQLDB) SettingPlaceSet(col string, place int64, val any) error {
+	defer MetricDB.Since(time.Now(), "SettingPlaceSet")
+
 	// Make sure that the place settings are present
 	if err := db.SettingsPlaceGenerate(place); err != nil {
 		return err
@@ -275,7 +318,10 @@ func (db *SQLDB) SettingPlaceSet(col string, place int64, val any) error {
 		Interface(col, val).
 		Msg("changed setting")
 
-	return err
+	if err != nil {
+		return err
+	}
+	return CacheInvalidate(settingPlaceKey(col, place))
 }
 
 /////////////////////
@@ -284,6 +330,10 @@ func (db *SQLDB) SettingPlaceSet(col string, place int64, val any) error {
 //                 //
 /////////////////////
 
+func settingPersonKey(col string, person, place int64) string {
+	return fmt.Sprintf("settings_person_%d_%d_%s", person, place, col)
+}
+
 func (db *SQLDB) settingsPersonExist(person, place int64) (bool, error) {
 	db.Lock.RLock()
 	defer db.Lock.RUnlock()
@@ -331,70 +381,64 @@ func (db *SQLDB) settingsPersonGenerate(person, place int64) error {
 // SettingsPersonGenerate will check if settings for the specified person in the
 // specified place exist, and if not will generate them.
 func (db *SQLDB) SettingsPersonGenerate(person, place int64) error {
-	rdbKey := fmt.Sprintf("settings_person_%d_%d", person, place)
-
-	if _, err := RDB.Get(ctx, rdbKey).Result(); err == nil {
-		log.Debug().Msg("CACHE: person settings already generated")
-		return nil
-	}
+	defer MetricDB.Since(time.Now(), "SettingsPersonGenerate")
 
-	exists, err := db.settingsPersonExist(person, place)
-	if err != nil {
-		return err
-	}
-	if exists {
-		err := RDB.Set(ctx, rdbKey, nil, 0).Err()
-		log.Debug().
-			Err(err).
-			Msg("CACHE: person settings already exist in db, caching")
-		return err
-	}
+	rdbKey := fmt.Sprintf("settings_person_%d_%d", person, place)
 
-	err = db.settingsPersonGenerate(person, place)
-	if err != nil {
-		return err
-	}
-	err = RDB.Set(ctx, rdbKey, nil, 0).Err()
-	log.Debug().Err(err).Msg("CACHE: generated person settings in db, caching")
+	// The settings only stop existing if the person's or the place's scope
+	// is deleted.
+	_, err := CacheGet(rdbKey, CacheScopeTTL, func() (bool, error) {
+		exists, err := db.settingsPersonExist(person, place)
+		if err != nil || exists {
+			return exists, err
+		}
+		return true, db.settingsPersonGenerate(person, place)
+	})
 	return err
 }
 
 // SettingPersonGet returns the value of col in table for the specified person
 // in the specified place.
 func (db *SQLDB) SettingPersonGet(col string, person, place int64) (any, error) {
+	defer MetricDB.Since(time.Now(), "SettingPersonGet")
+
 	// Make sure that the person settings are present
 	if err := db.SettingsPersonGenerate(person, place); err != nil {
 		return nil, err
 	}
 
-	db.Lock.RLock()
-	defer db.Lock.RUnlock()
+	return CacheGet(settingPersonKey(col, person, place), CacheTTL, func() (any, error) {
+		db.Lock.RLock()
+		defer db.Lock.RUnlock()
 
-	var val any
+		var val any
 
-	query := fmt.Sprintf(`
-		SELECT %s
-		FROM settings_person
-		WHERE person = $1 and place = $2
-	`, col)
+		query := fmt.Sprintf(`
+			SELECT %s
+			FROM settings_person
+			WHERE person = $1 and place = $2
+		`, col)
 
-	row := db.DB.QueryRow(query, person, place)
+		row := db.DB.QueryRow(query, person, place)
 
-	err := row.Scan(&val)
+		err := row.Scan(&val)
 
-	log.Debug().
-		Err(err).
-		Int64("person", person).
-		Int64("place", place).
-		Interface(col, val).
-		Msg("got value")
+		log.Debug().
+			Err(err).
+			Int64("person", person).
+			Int64("place", place).
+			Interface(col, val).
+			Msg("got value")
 
-	return val, err
+		return val, err
+	})
 }
 
 // PlaceSettingSet sets the value of col in table for the specified person in
 // the specified place.
 func (db *SQLDB) SettingPersonSet(col string, person, place int64, val any) error {
+	defer MetricDB.Since(time.Now(), "SettingPersonSet")
+
 	// Make sure that the person settings are present
 	if err := db.SettingsPersonGenerate(person, place); err != nil {
 		return err
@@ -418,5 +462,8 @@ func (db *SQLDB) SettingPersonSet(col string, person, place int64, val any) erro
 		Interface(col, val).
 		Msg("changed setting")
 
-	return err
+	if err != nil {
+		return err
+	}
+	return CacheInvalidate(settingPersonKey(col, person, place))
 }
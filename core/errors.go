@@ -5,6 +5,6 @@ import (
 )
 
 var (
-	ErrMissingArgs = errors.New("not enough arguments provided")
+	ErrMissingArgs = NewError("core.missing_args")
 	ErrSilence     = errors.New("if this error is returned don't send any message")
 )
@@ -2,7 +2,6 @@ package core
 
 import (
 	"fmt"
-	"sync"
 )
 
 var Frontends Frontenders
@@ -13,10 +12,17 @@ type Frontender interface {
 	// Type returns the frontend type ID.
 	Type() FrontendType
 
+	// Name returns the frontend's name in lowercase, for example discord.
+	Name() string
+
 	// Init is responsible for starting up any frontend specific services and
-	// connecting to frontend. When it receives the stop signal then it should
-	// disconnect from everything.
-	Init(wgInit, wgStop *sync.WaitGroup, stop chan struct{})
+	// connecting to the frontend, it blocks until either the connection fails,
+	// in which case it returns the error and the supervisor calls it again
+	// after a while, or it receives the stop signal, in which case it
+	// disconnects from everything and returns nil. connected must be called
+	// with true once connected and, if the frontend reconnects by itself,
+	// with false when the connection is lost and true again once it's back.
+	Init(connected func(bool), stop chan struct{}) error
 
 	// CreateMessage returns a Message object based on the given arguments.
 	// Used to send messages that are not direct replies, e.g. reminders.
@@ -42,3 +48,34 @@ func (fs Frontenders) CreateMessage(person, place int64, msgID string) (*Message
 
 	return nil, fmt.Errorf("frontend type %d couldn't be matched", frontendType)
 }
+
+// Permissioner is implemented by frontends that can check a person's
+// permissions in a place without having a message from them, e.g. in order to
+// re-check the permissions of an API token.
+type Permissioner interface {
+	// Permissions returns whether the person is a moderator and whether they
+	// are an admin in the place, the same as Author's Mod and Admin would.
+	Permissions(person, place int64) (mod bool, admin bool, err error)
+}
+
+// Permissions returns whether the person is a moderator and whether they are
+// an admin in the place. It detects what the frontend is based on the place.
+// If the frontend can't check then neither is assumed.
+func (fs Frontenders) Permissions(person, place int64) (bool, bool, error) {
+	frontendType, err := DB.ScopeFrontend(place)
+	if err != nil {
+		return false, false, err
+	}
+
+	for _, f := range fs {
+		if f.Type() != FrontendType(frontendType) {
+			continue
+		}
+		if p, ok := f.(Permissioner); ok {
+			return p.Permissions(person, place)
+		}
+		return false, false, nil
+	}
+
+	return false, false, fmt.Errorf("frontend type %d couldn't be matched", frontendType)
+}
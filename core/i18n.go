@@ -0,0 +1,363 @@
+package core
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Localization:
+//
+// User facing strings are looked up by ID in message catalogs. Each package
+// keeps its own catalogs as JSON files named after the language they're in,
+// e.g. locales/en.json, and loads them on init. IDs are prefixed with the
+// package's name so that they're unique across packages.
+//
+// A catalog maps an ID either to a string or, if the string depends on a
+// number, to an object with the plural forms of the language, for example:
+//
+//	{
+//		"time.timers": {"one": "%d timer open.", "other": "%d timers open."}
+//	}
+//
+// The strings are formatted using fmt, so they can refer to the arguments by
+// index if a language needs them in a different order, e.g. %[2]s.
+//
+// The language is picked per place, and can be overridden per person. When a
+// string hasn't been translated to the person's language it falls back to the
+// place's language and finally to the default one. Command descriptions are
+// the exception, they fall back to the Description function which is always
+// in English.
+
+// DefaultLanguage is the language that every string is expected to exist in.
+const DefaultLanguage = "en"
+
+//go:embed locales
+var locales embed.FS
+
+// Translation is a translated string along with its plural forms, Other is
+// used if the form that is needed doesn't exist.
+type Translation struct {
+	One   string `json:"one"`
+	Few   string `json:"few"`
+	Many  string `json:"many"`
+	Other string `json:"other"`
+}
+
+// UnmarshalJSON also accepts a plain string, for strings with no plural forms.
+func (t *Translation) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*t = Translation{Other: s}
+		return nil
+	}
+
+	type translation Translation
+	return json.Unmarshal(b, (*translation)(t))
+}
+
+// form returns the plural form of the translation that should be used with n
+// in the specified language.
+func (t Translation) form(lang string, n int64) string {
+	var s string
+	switch pluralForm(lang, n) {
+	case "one":
+		s = t.One
+	case "few":
+		s = t.Few
+	case "many":
+		s = t.Many
+	}
+	if s == "" {
+		return t.Other
+	}
+	return s
+}
+
+// pluralForm returns the CLDR plural category of n for integers, only the
+// categories that Translation supports are returned.
+func pluralForm(lang string, n int64) string {
+	if n < 0 {
+		n = -n
+	}
+	mod10, mod100 := n%10, n%100
+
+	switch baseLanguage(lang) {
+	case "fr", "pt":
+		if n == 0 || n == 1 {
+			return "one"
+		}
+	case "ru", "uk":
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return "one"
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	case "pl":
+		switch {
+		case n == 1:
+			return "one"
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	default:
+		if n == 1 {
+			return "one"
+		}
+	}
+	return "other"
+}
+
+// NormalizeLanguage returns the language tag in the form that is used for the
+// catalogs' names, e.g. pt_BR becomes pt-br.
+func NormalizeLanguage(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
+
+// baseLanguage returns the language without its region, e.g. pt-br becomes pt.
+func baseLanguage(lang string) string {
+	base, _, _ := strings.Cut(lang, "-")
+	return base
+}
+
+type catalogs struct {
+	lock  sync.RWMutex
+	langs map[string]map[string]Translation
+}
+
+var Catalogs = catalogs{}
+
+// Add adds the translations to the language's catalog, replacing any that
+// already exist with the same ID.
+func (c *catalogs) Add(lang string, translations map[string]Translation) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	lang = NormalizeLanguage(lang)
+
+	if c.langs == nil {
+		c.langs = map[string]map[string]Translation{}
+	}
+	if c.langs[lang] == nil {
+		c.langs[lang] = map[string]Translation{}
+	}
+	for id, t := range translations {
+		c.langs[lang][id] = t
+	}
+}
+
+// Load adds every <language>.json catalog found in dir.
+func (c *catalogs) Load(fsys fs.FS, dir string) error {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		b, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+
+		var translations map[string]Translation
+		if err := json.Unmarshal(b, &translations); err != nil {
+			return fmt.Errorf("invalid catalog %s: %v", file, err)
+		}
+
+		c.Add(strings.TrimSuffix(path.Base(file), ".json"), translations)
+	}
+
+	return nil
+}
+
+// Exists returns true if there is a catalog for the language.
+func (c *catalogs) Exists(lang string) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	_, ok := c.langs[NormalizeLanguage(lang)]
+	return ok
+}
+
+// Languages returns the languages that have a catalog.
+func (c *catalogs) Languages() []string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	langs := make([]string, 0, len(c.langs))
+	for lang := range c.langs {
+		langs = append(langs, lang)
+	}
+	return langs
+}
+
+// lookup returns the translation from the first language that has one, along
+// with the language it's in.
+func (c *catalogs) lookup(langs []string, id string) (Translation, string, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	for _, lang := range langs {
+		if t, ok := c.langs[lang][id]; ok {
+			return t, lang, true
+		}
+	}
+	return Translation{}, "", false
+}
+
+// fallbacks returns the order in which the languages are tried, each language
+// is followed by its base language and the default language is always last.
+func fallbacks(langs ...string) []string {
+	var chain []string
+	seen := map[string]bool{}
+
+	add := func(lang string) {
+		if lang != "" && !seen[lang] {
+			seen[lang] = true
+			chain = append(chain, lang)
+		}
+	}
+
+	for _, lang := range langs {
+		lang = NormalizeLanguage(lang)
+		add(lang)
+		add(baseLanguage(lang))
+	}
+	add(DefaultLanguage)
+
+	return chain
+}
+
+func format(s string, args []any) string {
+	if len(args) == 0 {
+		return s
+	}
+	return fmt.Sprintf(s, args...)
+}
+
+// Translate returns the string with the given ID in the first of the
+// languages that it has been translated to, formatted with args. If it
+// hasn't been translated at all then the ID is returned.
+func Translate(langs []string, id string, args ...any) string {
+	t, _, ok := Catalogs.lookup(fallbacks(langs...), id)
+	if !ok {
+		log.Debug().Str("id", id).Strs("languages", langs).Msg("no translation found")
+		return id
+	}
+	return format(t.Other, args)
+}
+
+// TranslatePlural is the same as Translate, except that the plural form that
+// is used depends on n.
+func TranslatePlural(langs []string, id string, n int64, args ...any) string {
+	t, lang, ok := Catalogs.lookup(fallbacks(langs...), id)
+	if !ok {
+		log.Debug().Str("id", id).Strs("languages", langs).Msg("no translation found")
+		return id
+	}
+	return format(t.form(lang, n), args)
+}
+
+// Error is a user error that can be translated, its Error method returns the
+// message in the default language.
+type Error struct {
+	ID   string
+	Args []any
+}
+
+// NewError returns an error whose message is the string with the given ID.
+func NewError(id string, args ...any) error {
+	return &Error{ID: id, Args: args}
+}
+
+func (e *Error) Error() string {
+	return Translate(nil, e.ID, e.Args...)
+}
+
+// Languages returns the languages that the replies to the message should be
+// in, in order of preference. The author's language comes first, followed by
+// the place's. Languages that aren't set are skipped.
+func (m *Message) Languages() []string {
+	var langs []string
+
+	here, err := m.Here.ScopeLogical()
+	if err != nil {
+		log.Debug().Err(err).Msg("failed to get place, using default language")
+		return nil
+	}
+
+	if person, err := m.Author.Scope(); err == nil {
+		if lang, err := DB.SettingPersonGet("language", person, here); err == nil {
+			if lang, ok := lang.(string); ok {
+				langs = append(langs, lang)
+			}
+		}
+	}
+
+	if lang, err := DB.SettingPlaceGet("language", here); err == nil {
+		if lang, ok := lang.(string); ok {
+			langs = append(langs, lang)
+		}
+	}
+
+	return langs
+}
+
+// T returns the translation of the string with the given ID in the message's
+// language, formatted with args.
+func (m *Message) T(id string, args ...any) string {
+	return Translate(m.Languages(), id, args...)
+}
+
+// TPlural returns the translation of the string with the given ID in the
+// message's language, in the plural form that is needed for n.
+func (m *Message) TPlural(id string, n int64, args ...any) string {
+	return TranslatePlural(m.Languages(), id, n, args...)
+}
+
+// TError returns the error's message in the message's language, errors that
+// aren't translatable are returned as is.
+func (m *Message) TError(err error) string {
+	if e, ok := err.(*Error); ok {
+		return m.T(e.ID, e.Args...)
+	}
+	return fmt.Sprint(err)
+}
+
+// TDescription returns the command's description in the message's language,
+// falling back to the command's own description if it hasn't been
+// translated. The ID is the command's type and path followed by description,
+// e.g. advanced.time.timezone.set.description.
+func (m *Message) TDescription(cmd CommandStatic) string {
+	id := typeName(cmd.Type()) + "." + strings.ReplaceAll(commandPath(cmd), " ", ".") + ".description"
+
+	// The default language isn't added to the chain as the descriptions
+	// themselves are in it.
+	var chain []string
+	for _, lang := range fallbacks(m.Languages()...) {
+		if lang != DefaultLanguage {
+			chain = append(chain, lang)
+		}
+	}
+
+	if t, _, ok := Catalogs.lookup(chain, id); ok {
+		return t.Other
+	}
+	return cmd.Description()
+}
+
+func init() {
+	if err := Catalogs.Load(locales, "locales"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,81 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/janitorjeff/jeff-bot/core"
+)
+
+func TestTranslate(t *testing.T) {
+	core.Catalogs.Add("en", map[string]core.Translation{
+		"test.greeting": {Other: "Hello %s."},
+		"test.english":  {Other: "Only in English."},
+	})
+	core.Catalogs.Add("es", map[string]core.Translation{
+		"test.greeting": {Other: "Hola %s."},
+	})
+
+	tests := []struct {
+		langs    []string
+		id       string
+		expected string
+	}{
+		{nil, "test.greeting", "Hello jeff."},
+		{[]string{"es"}, "test.greeting", "Hola jeff."},
+		// person's language has no catalog, falls back to the place's
+		{[]string{"xx", "es"}, "test.greeting", "Hola jeff."},
+		// region falls back to the base language
+		{[]string{"ES_mx"}, "test.greeting", "Hola jeff."},
+		{[]string{"es"}, "test.english", "Only in English."},
+		{[]string{"es"}, "test.missing", "test.missing"},
+	}
+
+	for _, test := range tests {
+		var got string
+		if test.id == "test.greeting" {
+			got = core.Translate(test.langs, test.id, "jeff")
+		} else {
+			got = core.Translate(test.langs, test.id)
+		}
+		if got != test.expected {
+			t.Errorf("languages %v, id '%s': expected '%s', got '%s'", test.langs, test.id, test.expected, got)
+		}
+	}
+}
+
+func TestTranslatePlural(t *testing.T) {
+	core.Catalogs.Add("en", map[string]core.Translation{
+		"test.apples": {One: "%d apple", Other: "%d apples"},
+	})
+	core.Catalogs.Add("ru", map[string]core.Translation{
+		"test.apples": {One: "%d яблоко", Few: "%d яблока", Many: "%d яблок"},
+	})
+
+	tests := []struct {
+		lang     string
+		n        int64
+		expected string
+	}{
+		{"en", 1, "1 apple"},
+		{"en", 0, "0 apples"},
+		{"en", 2, "2 apples"},
+		{"ru", 1, "1 яблоко"},
+		{"ru", 21, "21 яблоко"},
+		{"ru", 3, "3 яблока"},
+		{"ru", 12, "12 яблок"},
+		{"ru", 25, "25 яблок"},
+	}
+
+	for _, test := range tests {
+		got := core.TranslatePlural([]string{test.lang}, "test.apples", test.n, test.n)
+		if got != test.expected {
+			t.Errorf("language %s, n = %d: expected '%s', got '%s'", test.lang, test.n, test.expected, got)
+		}
+	}
+}
+
+func TestErrorTranslation(t *testing.T) {
+	if core.ErrMissingArgs.Error() != "not enough arguments provided" {
+		t.Errorf("unexpected default language error message '%s'", core.ErrMissingArgs)
+	}
+}
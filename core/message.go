@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/rs/zerolog/log"
 )
@@ -126,8 +127,14 @@ type Here interface {
 }
 
 type Message struct {
-	ID       string
-	Raw      string
+	ID  string
+	Raw string
+
+	// Expanded is the text of the command that the alias the message begins
+	// with stands for, empty if it doesn't begin with one. The command is
+	// parsed from this instead of Raw, which is always kept as it was sent.
+	Expanded string
+
 	Frontend Frontender
 	Author   Author
 	Here     Here
@@ -136,14 +143,26 @@ type Message struct {
 	Command  *Command
 }
 
+// text returns the text that commands are parsed from.
+func (m *Message) text() string {
+	if m.Expanded != "" {
+		return m.Expanded
+	}
+	return m.Raw
+}
+
 func (m *Message) Fields() []string {
-	return strings.Fields(m.Raw)
+	return strings.Fields(m.text())
 }
 
 // Split text into fields that include all trailing whitespace. For example:
 // "example of    text" will be split into ["example ", "of    ", "text"]
 func (m *Message) FieldsSpace() []string {
-	text := strings.TrimSpace(m.Raw)
+	return fieldsSpace(m.text())
+}
+
+func fieldsSpace(text string) []string {
+	text = strings.TrimSpace(text)
 	re := regexp.MustCompile(`\S+\s*`)
 	fields := re.FindAllString(text, -1)
 
@@ -162,7 +181,10 @@ func (m *Message) RawArgs(n int) string {
 		panic("unexpected n")
 	}
 
-	fields := m.FieldsSpace()
+	// The prefix isn't necessarily attached to the command's name, e.g. the
+	// bot's mention, so it's removed first.
+	text := strings.TrimPrefix(strings.TrimSpace(m.text()), m.Command.Prefix)
+	fields := fieldsSpace(text)
 
 	// Skip over the command + the given offset
 	s := strings.Join(fields[len(m.Command.Path)+n:], "")
@@ -190,60 +212,44 @@ func (m *Message) Prefixes() ([]Prefix, bool, error) {
 	return PlacePrefixes(here)
 }
 
-func hasPrefix(prefixes []Prefix, s string) (Prefix, bool) {
-	for _, p := range prefixes {
-		// Example:
-		// !prefix add !prefix
-		// !prefixprefix ls // works
-		// !prefix ls // doesn't work
-		//
-		// This is because the rootCmdName "!prefix" in the third command gets
-		// matched as the prefix "!prefix" and not the prefix "!" with the
-		// command name "prefix". Which makes it so the actual command name is
-		// empty.
-		if p.Prefix == s {
-			continue
-		}
-
-		if strings.HasPrefix(s, p.Prefix) {
-			log.Debug().Interface("prefix", p).Msg("matched prefix")
-			return p, true
-		}
-	}
-
-	return Prefix{}, false
-}
-
-func (m *Message) matchPrefix(rootCmdName string) (Prefix, error) {
+// matchPrefix returns the prefix that the text begins with and the text that
+// follows it. The frontend's implicit prefixes are tried first, so that a
+// place's prefix can't stop the bot's mention from working, e.g. the prefix
+// `@` on twitch.
+func (m *Message) matchPrefix(text string) (Prefix, string, error) {
 	prefixes, _, err := m.Prefixes()
 	if err != nil {
-		return Prefix{}, err
+		return Prefix{}, "", err
 	}
+	prefixes = append(ImplicitPrefixes(m.Frontend), prefixes...)
 
-	if prefix, ok := hasPrefix(prefixes, rootCmdName); ok {
-		return prefix, nil
+	if prefix, rest, ok := MatchPrefix(prefixes, text); ok {
+		return prefix, rest, nil
 	}
 
-	log.Debug().Str("command", rootCmdName).Msg("failed to match prefix")
+	log.Debug().Str("text", text).Msg("failed to match prefix")
 
-	return Prefix{}, fmt.Errorf("failed to match prefix")
+	return Prefix{}, "", fmt.Errorf("failed to match prefix")
 }
 
 func (m *Message) CommandParse() (*Message, error) {
 	log.Debug().Str("text", m.Raw).Msg("starting command parsing")
 
-	args := m.Fields()
+	if err := m.expandAlias(); err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimSpace(m.text())
 
-	if len(args) == 0 {
+	if text == "" {
 		return nil, fmt.Errorf("Empty message")
 	}
 
-	rootCmdName := args[0]
-	prefix, err := m.matchPrefix(rootCmdName)
+	prefix, rest, err := m.matchPrefix(text)
 	if err != nil {
 		return nil, err
 	}
-	args[0] = strings.TrimPrefix(rootCmdName, prefix.Prefix)
+	args := strings.Fields(rest)
 
 	cmdStatic, index, err := Commands.Match(prefix.Type, m, args)
 	if err != nil {
@@ -258,9 +264,11 @@ func (m *Message) CommandParse() (*Message, error) {
 		Send()
 
 	cmdRuntime := CommandRuntime{
-		Path:   cmdName,
-		Args:   args,
-		Prefix: prefix.Prefix,
+		Path: cmdName,
+		Args: args,
+		// The prefix as it was used, which for spaced and pattern prefixes
+		// isn't the same as the prefix itself, e.g. the bot's mention.
+		Prefix: strings.TrimSuffix(text, rest),
 	}
 
 	m.Command = &Command{
@@ -282,13 +290,25 @@ func (m *Message) CommandRun() (*Message, error) {
 	}
 
 	resp, usrErr, err := m.Command.Run(m)
+
+	outcome := "ok"
+	switch {
+	case err == ErrSilence:
+		outcome = "silence"
+	case err != nil:
+		outcome = "error"
+	case usrErr != nil:
+		outcome = "user_error"
+	}
+	MetricCommands.Inc(commandPath(m.Command.CommandStatic), typeName(m.Command.Type()), m.Frontend.Name(), outcome)
+
 	if err == ErrSilence {
 		return nil, err
 	}
 	if err != nil {
 		// passing an empty error in order to get any error specific rendering
 		// that might be supported
-		m.Write("Something went wrong...", errors.New(""))
+		m.Write(m.T("core.something_went_wrong"), errors.New(""))
 		return nil, fmt.Errorf("failed to run command '%v': %v", m.Command.Path, err)
 	}
 
@@ -297,7 +317,9 @@ func (m *Message) CommandRun() (*Message, error) {
 
 func (m *Message) Hooks() {
 	for _, h := range Hooks.Get() {
+		start := time.Now()
 		h.Run(m)
+		MetricHooks.Since(start)
 	}
 }
 
@@ -0,0 +1,381 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// Metrics:
+//
+// A small implementation of prometheus' counters, gauges and histograms that
+// only supports what the bot needs. All of the registered metrics are exposed
+// at /metrics in the prometheus text format. All operations are thread safe.
+
+// The metrics that are collected by the core, packages can register their own
+// as well.
+var (
+	MetricCommands = NewCounter(
+		"jeff_commands_total",
+		"Number of commands run, the outcome is one of ok, user_error, error or silence.",
+		"path", "type", "frontend", "outcome",
+	)
+
+	MetricHooks = NewHistogram(
+		"jeff_hook_duration_seconds",
+		"Time it took for a hook to run on a message.",
+	)
+
+	MetricDB = NewHistogram(
+		"jeff_db_query_duration_seconds",
+		"Time it took for a database helper to run.",
+		"helper",
+	)
+
+	MetricCache = NewCounter(
+		"jeff_cache_requests_total",
+		"Number of cache lookups, the result is either hit or miss.",
+		"result",
+	)
+
+	MetricAPI = NewHistogram(
+		"jeff_external_api_duration_seconds",
+		"Time it took for a request to an external API to complete.",
+		"api",
+	)
+
+	MetricAPIFailures = NewCounter(
+		"jeff_external_api_failures_total",
+		"Number of requests to an external API that failed or got an error status.",
+		"api",
+	)
+)
+
+// The default histogram buckets, in seconds.
+var buckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type metric interface {
+	write(w io.Writer)
+}
+
+var metrics = struct {
+	lock sync.RWMutex
+	list []metric
+}{}
+
+func register(m metric) {
+	metrics.lock.Lock()
+	defer metrics.lock.Unlock()
+	metrics.list = append(metrics.list, m)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labelPairs returns the labels in the form {name="value",...}, extra is added
+// at the end as is.
+func labelPairs(names, values []string, extra string) string {
+	var pairs []string
+	for i, n := range names {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, n, labelEscaper.Replace(values[i])))
+	}
+	if extra != "" {
+		pairs = append(pairs, extra)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	default:
+		return fmt.Sprint(f)
+	}
+}
+
+// series are the values of a metric for each combination of label values.
+type series[T any] struct {
+	lock   sync.Mutex
+	values map[string]*T
+	labels map[string][]string
+}
+
+// get returns the value for the label values, creating it if needed. The
+// lock must be held.
+func (s *series[T]) get(values []string) *T {
+	key := strings.Join(values, "\xff")
+	if s.values == nil {
+		s.values = map[string]*T{}
+		s.labels = map[string][]string{}
+	}
+	if _, ok := s.values[key]; !ok {
+		s.values[key] = new(T)
+		s.labels[key] = values
+	}
+	return s.values[key]
+}
+
+// sorted returns the keys in order so that the output is stable. The lock
+// must be held.
+func (s *series[T]) sorted() []string {
+	keys := make([]string, 0, len(s.values))
+	for k := range s.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func checkLabels(name string, names, values []string) {
+	if len(names) != len(values) {
+		panic(fmt.Sprintf("metric %s expects %d label values, got %d", name, len(names), len(values)))
+	}
+}
+
+/////////////
+//         //
+// counter //
+//         //
+/////////////
+
+// Counter is a value that only goes up, for example the number of commands
+// that have been run.
+type Counter struct {
+	name   string
+	help   string
+	labels []string
+	series series[float64]
+}
+
+// NewCounter creates and registers a counter with the specified label names.
+func NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{name: name, help: help, labels: labels}
+	register(c)
+	return c
+}
+
+// Inc increments the counter that has the specified label values by 1.
+func (c *Counter) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add increments the counter that has the specified label values by v.
+func (c *Counter) Add(v float64, values ...string) {
+	checkLabels(c.name, c.labels, values)
+	c.series.lock.Lock()
+	defer c.series.lock.Unlock()
+	*c.series.get(values) += v
+}
+
+func (c *Counter) write(w io.Writer) {
+	c.series.lock.Lock()
+	defer c.series.lock.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+	for _, k := range c.series.sorted() {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, labelPairs(c.labels, c.series.labels[k], ""), formatFloat(*c.series.values[k]))
+	}
+}
+
+///////////
+//       //
+// gauge //
+//       //
+///////////
+
+// Gauge is a value that can go up and down, for example the number of audio
+// queues that are playing.
+type Gauge struct {
+	name string
+	help string
+	lock sync.Mutex
+	val  float64
+
+	// if set the value is calculated when the metrics are collected
+	f func() (float64, error)
+}
+
+// NewGauge creates and registers a gauge.
+func NewGauge(name, help string) *Gauge {
+	g := &Gauge{name: name, help: help}
+	register(g)
+	return g
+}
+
+// NewGaugeFunc creates and registers a gauge whose value is calculated by f
+// every time the metrics are collected. If f fails then the gauge is skipped.
+func NewGaugeFunc(name, help string, f func() (float64, error)) *Gauge {
+	g := &Gauge{name: name, help: help, f: f}
+	register(g)
+	return g
+}
+
+// Add adds v to the gauge's value, which can be negative.
+func (g *Gauge) Add(v float64) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.val += v
+}
+
+// Inc increments the gauge's value by 1.
+func (g *Gauge) Inc() {
+	g.Add(1)
+}
+
+// Dec decrements the gauge's value by 1.
+func (g *Gauge) Dec() {
+	g.Add(-1)
+}
+
+func (g *Gauge) write(w io.Writer) {
+	g.lock.Lock()
+	val := g.val
+	g.lock.Unlock()
+
+	if g.f != nil {
+		var err error
+		if val, err = g.f(); err != nil {
+			log.Debug().Err(err).Str("metric", g.name).Msg("failed to collect gauge")
+			return
+		}
+	}
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", g.name, g.help, g.name)
+	fmt.Fprintf(w, "%s %s\n", g.name, formatFloat(val))
+}
+
+///////////////
+//           //
+// histogram //
+//           //
+///////////////
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// Histogram keeps track of the distribution of some value, for example how
+// long database queries take.
+type Histogram struct {
+	name   string
+	help   string
+	labels []string
+	series series[histogram]
+}
+
+// NewHistogram creates and registers a histogram with the default buckets,
+// meant for durations in seconds, and the specified label names.
+func NewHistogram(name, help string, labels ...string) *Histogram {
+	h := &Histogram{name: name, help: help, labels: labels}
+	register(h)
+	return h
+}
+
+// Observe adds the value v to the histogram that has the specified label
+// values.
+func (h *Histogram) Observe(v float64, values ...string) {
+	checkLabels(h.name, h.labels, values)
+	h.series.lock.Lock()
+	defer h.series.lock.Unlock()
+
+	hist := h.series.get(values)
+	if hist.counts == nil {
+		hist.counts = make([]uint64, len(buckets))
+	}
+	for i, b := range buckets {
+		if v <= b {
+			hist.counts[i]++
+		}
+	}
+	hist.count++
+	hist.sum += v
+}
+
+// Since observes the number of seconds that have passed since start, meant to
+// be deferred.
+func (h *Histogram) Since(start time.Time, values ...string) {
+	h.Observe(time.Since(start).Seconds(), values...)
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.series.lock.Lock()
+	defer h.series.lock.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+	for _, k := range h.series.sorted() {
+		values := h.series.labels[k]
+		hist := h.series.values[k]
+		for i, b := range buckets {
+			le := fmt.Sprintf(`le="%s"`, formatFloat(b))
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, labelPairs(h.labels, values, le), hist.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, labelPairs(h.labels, values, `le="+Inf"`), hist.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, labelPairs(h.labels, values, ""), formatFloat(hist.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, labelPairs(h.labels, values, ""), hist.count)
+	}
+}
+
+///////////////////
+//               //
+// external APIs //
+//               //
+///////////////////
+
+type apiTransport struct {
+	api  string
+	base http.RoundTripper
+}
+
+func (t apiTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	MetricAPI.Since(start, t.api)
+	if err != nil || resp.StatusCode >= 400 {
+		MetricAPIFailures.Inc(t.api)
+	}
+	return resp, err
+}
+
+// APITransport wraps base so that the latency and failures of the requests
+// made to the external API are recorded. If base is nil then
+// http.DefaultTransport is used.
+func APITransport(api string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return apiTransport{api, base}
+}
+
+// APIClient returns an HTTP client that records the latency and failures of
+// the requests made to the external API.
+func APIClient(api string) *http.Client {
+	return &http.Client{Transport: APITransport(api, nil)}
+}
+
+func init() {
+	Gin.GET("/metrics", func(c *gin.Context) {
+		metrics.lock.RLock()
+		defer metrics.lock.RUnlock()
+
+		c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		c.Status(http.StatusOK)
+		for _, m := range metrics.list {
+			m.write(c.Writer)
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// The types of events that are sent to overlays.
+const (
+	OverlayTTS      = "tts"
+	OverlayAudio    = "audio"
+	OverlayPoll     = "poll"
+	OverlayCounter  = "counter"
+	OverlayGiveaway = "giveaway"
+	OverlayAlert    = "alert"
+)
+
+// OverlayEvent is what gets sent to the overlays, as JSON.
+type OverlayEvent struct {
+	Type string `json:"type"`
+	Data any    `json:"data"`
+}
+
+// Overlay delivers live events to the stream overlays that are connected to a
+// logical place. All operations are thread safe.
+var Overlay = overlay{}
+
+type overlay struct {
+	lock sync.RWMutex
+
+	// subscribers for each place, keyed by their id
+	subs map[int64]map[int]chan OverlayEvent
+
+	// Keeps track of the number of subscriptions, used as an ID.
+	total int
+
+	// return the events that bring a newly connected overlay up to date
+	initial []func(place int64) []OverlayEvent
+}
+
+// OnConnect registers a function that returns the events that a newly
+// connected overlay should receive first, for example the current values of
+// counters.
+func (o *overlay) OnConnect(f func(place int64) []OverlayEvent) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.initial = append(o.initial, f)
+}
+
+// Initial returns the events that a newly connected overlay should receive
+// first.
+func (o *overlay) Initial(place int64) []OverlayEvent {
+	o.lock.RLock()
+	fs := o.initial
+	o.lock.RUnlock()
+
+	var events []OverlayEvent
+	for _, f := range fs {
+		events = append(events, f(place)...)
+	}
+	return events
+}
+
+// Subscribe returns the subscription's id, which can be used to cancel it with
+// Unsubscribe, and the channel on which the place's events are received.
+func (o *overlay) Subscribe(place int64) (int, <-chan OverlayEvent) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if o.subs == nil {
+		o.subs = map[int64]map[int]chan OverlayEvent{}
+	}
+	if o.subs[place] == nil {
+		o.subs[place] = map[int]chan OverlayEvent{}
+	}
+
+	o.total++
+	// buffered so that a slow overlay doesn't block whoever is publishing
+	ch := make(chan OverlayEvent, 32)
+	o.subs[place][o.total] = ch
+
+	return o.total, ch
+}
+
+// Unsubscribe cancels the subscription and closes its channel. If the
+// subscription doesn't exist then nothing happens.
+func (o *overlay) Unsubscribe(place int64, id int) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	ch, ok := o.subs[place][id]
+	if !ok {
+		return
+	}
+	close(ch)
+	delete(o.subs[place], id)
+	if len(o.subs[place]) == 0 {
+		delete(o.subs, place)
+	}
+}
+
+// Publish sends the event to all of the overlays connected to the logical
+// place. Overlays that can't keep up miss the event.
+func (o *overlay) Publish(place int64, t string, data any) {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+
+	e := OverlayEvent{Type: t, Data: data}
+	for id, ch := range o.subs[place] {
+		select {
+		case ch <- e:
+		default:
+			log.Debug().Int64("place", place).Int("id", id).Msg("overlay is full, dropped event")
+		}
+	}
+}
+
+// PublishHere is the same as Publish but uses the logical place the message
+// came from.
+func (o *overlay) PublishHere(m *Message, t string, data any) {
+	place, err := m.Here.ScopeLogical()
+	if err != nil {
+		return
+	}
+	o.Publish(place, t, data)
+}
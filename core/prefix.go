@@ -2,8 +2,13 @@ package core
 
 import (
 	"fmt"
+	"regexp"
 	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/janitorjeff/gosafe"
 	"github.com/rs/zerolog/log"
 )
 
@@ -31,10 +36,136 @@ var Prefixes = prefixes{}
 // These are used to call normal, advanced and admin commands respectively. A
 // prefix has to be unique accross all types (in a specific scope), for example
 // the prefix `!` can't be used for both normal and advanced commands.
+//
+// On top of the place's prefixes, some frontends have implicit prefixes, which
+// are the bot's mention. These always work, no matter what the place's
+// prefixes are, so that it's not possible to get locked out of the bot by
+// deleting the prefixes or forgetting what they are.
 
 type Prefix struct {
 	Type   CommandType
 	Prefix string
+
+	// Spaced prefixes may be separated from the command by whitespace, for
+	// example the prefix `jeff,` can be used as `jeff, time` as well as
+	// `jeff,time`.
+	Spaced bool
+
+	// Pattern means that Prefix is a regular expression instead of literal
+	// text. It's matched at the start of the message.
+	Pattern bool
+}
+
+// The compiled regular expressions of pattern prefixes, so that they don't
+// have to be compiled for every message.
+var prefixPatterns = gosafe.Map[string, *regexp.Regexp]{}
+
+func (p Prefix) regexp() (*regexp.Regexp, error) {
+	if re, ok := prefixPatterns.Get(p.Prefix); ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(`^(?:` + p.Prefix + `)`)
+	if err != nil {
+		return nil, err
+	}
+	prefixPatterns.Set(p.Prefix, re)
+	return re, nil
+}
+
+// Match checks whether text begins with the prefix, if it does then the text
+// that follows the prefix is returned. A prefix that isn't followed by
+// anything doesn't match, and neither does one followed by whitespace unless
+// it's spaced.
+//
+// For example, if both `!` and `!prefix` are prefixes then `!prefix ls` must
+// be matched by `!` and not `!prefix`, otherwise the command name would be
+// empty.
+func (p Prefix) Match(text string) (string, bool) {
+	var rest string
+	if p.Pattern {
+		re, err := p.regexp()
+		if err != nil {
+			log.Debug().Err(err).Str("pattern", p.Prefix).Msg("invalid prefix pattern")
+			return "", false
+		}
+		loc := re.FindStringIndex(text)
+		if loc == nil {
+			return "", false
+		}
+		rest = text[loc[1]:]
+	} else {
+		if !strings.HasPrefix(text, p.Prefix) {
+			return "", false
+		}
+		rest = strings.TrimPrefix(text, p.Prefix)
+	}
+
+	if p.Spaced {
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+	}
+
+	if r, _ := utf8.DecodeRuneInString(rest); rest == "" || unicode.IsSpace(r) {
+		return "", false
+	}
+	return rest, true
+}
+
+// MatchPrefix returns the first of the prefixes that text begins with and the
+// text that follows it. The prefixes are expected to be in the order returned
+// by PlacePrefixes, so that longer prefixes are tried first.
+func MatchPrefix(prefixes []Prefix, text string) (Prefix, string, bool) {
+	for _, p := range prefixes {
+		if rest, ok := p.Match(text); ok {
+			log.Debug().Interface("prefix", p).Msg("matched prefix")
+			return p, rest, true
+		}
+	}
+	return Prefix{}, "", false
+}
+
+// TypePrefix returns the first of the prefixes that is of type t and can be
+// written out as is, so pattern prefixes are skipped. Returns false if there
+// is no such prefix.
+func TypePrefix(prefixes []Prefix, t CommandType) (string, bool) {
+	for _, p := range prefixes {
+		if p.Type == t && !p.Pattern {
+			return p.Prefix, true
+		}
+	}
+	return "", false
+}
+
+// MentionType is the type of commands that can be called by mentioning the
+// bot, see Mentioner.
+var MentionType = Normal
+
+// Mentioner is implemented by frontends on which the bot can be mentioned.
+// The mention works as an implicit prefix in every place of the frontend and
+// can't be deleted.
+type Mentioner interface {
+	// MentionPattern returns a regular expression that matches the bot's
+	// mention, or an empty string if it's not known, e.g. if not connected
+	// yet.
+	MentionPattern() string
+}
+
+// ImplicitPrefixes returns the prefixes that always work in the frontend,
+// regardless of the place's prefixes.
+func ImplicitPrefixes(f Frontender) []Prefix {
+	mentioner, ok := f.(Mentioner)
+	if !ok {
+		return nil
+	}
+	mention := mentioner.MentionPattern()
+	if mention == "" {
+		return nil
+	}
+	return []Prefix{{
+		Type:    MentionType,
+		Prefix:  mention,
+		Spaced:  true,
+		Pattern: true,
+	}}
 }
 
 type prefixes struct {
@@ -45,9 +176,9 @@ type prefixes struct {
 func (ps *prefixes) Add(t CommandType, p string) {
 	switch t {
 	case Admin:
-		ps.admin = append(ps.admin, Prefix{t, p})
+		ps.admin = append(ps.admin, Prefix{Type: t, Prefix: p})
 	case Normal, Advanced:
-		ps.others = append(ps.others, Prefix{t, p})
+		ps.others = append(ps.others, Prefix{Type: t, Prefix: p})
 	default:
 		panic(fmt.Sprintf("Unexpected prefix type: %d", t))
 	}
@@ -69,6 +200,16 @@ func (ps prefixes) Others() []Prefix {
 	return others
 }
 
+func prefixesKey(place int64) string {
+	return fmt.Sprintf("prefixes_%d", place)
+}
+
+// PlacePrefixesInvalidate must be called whenever the place's prefixes are
+// changed so that the cached ones aren't used.
+func PlacePrefixesInvalidate(place int64) error {
+	return CacheInvalidate(prefixesKey(place))
+}
+
 // Returns the given place's prefixes and also whether or not they were taken
 // from the database (if not then that means the default ones were used).
 func PlacePrefixes(place int64) ([]Prefix, bool, error) {
@@ -77,7 +218,9 @@ func PlacePrefixes(place int64) ([]Prefix, bool, error) {
 	// dropped because it added some unecessary complexity since we couldn't
 	// always trivially know whether a place was a DM or not.
 
-	prefixes, err := DB.PrefixList(place)
+	prefixes, err := CacheGet(prefixesKey(place), CacheTTL, func() ([]Prefix, error) {
+		return DB.PrefixList(place)
+	})
 	if err != nil {
 		return nil, false, err
 	}
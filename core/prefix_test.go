@@ -0,0 +1,57 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/janitorjeff/jeff-bot/core"
+)
+
+func TestPrefixMatch(t *testing.T) {
+	bang := core.Prefix{Prefix: "!"}
+	spaced := core.Prefix{Prefix: "jeff,", Spaced: true}
+	mention := core.Prefix{Prefix: `(?i)@janitorjeff\b[,:]?`, Spaced: true, Pattern: true}
+
+	tests := []struct {
+		prefix core.Prefix
+		text   string
+		rest   string
+		ok     bool
+	}{
+		{bang, "!time now", "time now", true},
+		{bang, "! time", "", false},
+		{bang, "!", "", false},
+		{bang, "time", "", false},
+		{spaced, "jeff, time", "time", true},
+		{spaced, "jeff,time", "time", true},
+		{spaced, "jeff,  ", "", false},
+		{mention, "@JanitorJeff time now", "time now", true},
+		{mention, "@janitorjeff, time", "time", true},
+		{mention, "hi @janitorjeff time", "", false},
+		{mention, "@janitorjeff", "", false},
+		{mention, "@JanitorJeffhelp", "", false},
+		{mention, "@janitorjeff_fan time", "", false},
+		{mention, "@janitorjeff:time", "time", true},
+	}
+
+	for _, test := range tests {
+		rest, ok := test.prefix.Match(test.text)
+		if rest != test.rest || ok != test.ok {
+			t.Errorf("prefix '%s', text '%s': expected ('%s', %t), got ('%s', %t)", test.prefix.Prefix, test.text, test.rest, test.ok, rest, ok)
+		}
+	}
+}
+
+func TestTypePrefix(t *testing.T) {
+	prefixes := []core.Prefix{
+		{Type: core.Normal, Prefix: `(?i)@janitorjeff\b[,:]?`, Spaced: true, Pattern: true},
+		{Type: core.Normal, Prefix: "!"},
+		{Type: core.Advanced, Prefix: "$"},
+	}
+
+	if prefix, ok := core.TypePrefix(prefixes, core.Normal); prefix != "!" || !ok {
+		t.Errorf("expected ('!', true), got ('%s', %t)", prefix, ok)
+	}
+	if prefix, ok := core.TypePrefix(prefixes, core.Admin); prefix != "" || ok {
+		t.Errorf("expected ('', false), got ('%s', %t)", prefix, ok)
+	}
+}
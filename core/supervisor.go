@@ -0,0 +1,215 @@
+package core
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// The states a frontend can be in.
+const (
+	// Connected and working normally.
+	FrontendConnected = "connected"
+
+	// The connection was lost or failed to be established and it is being
+	// retried.
+	FrontendDegraded = "degraded"
+
+	// Hasn't connected yet, or has failed to reconnect too many times in a
+	// row. It keeps getting retried.
+	FrontendDown = "down"
+)
+
+const (
+	backoffMin = time.Second
+	backoffMax = 5 * time.Minute
+
+	// the number of consecutive failures after which a frontend is down
+	downAfter = 5
+)
+
+var errStopped = errors.New("frontend stopped unexpectedly")
+
+// FrontendStatus is a frontend's current state as tracked by the supervisor.
+type FrontendStatus struct {
+	Name  string    `json:"name"`
+	State string    `json:"state"`
+	Since time.Time `json:"since"`
+
+	// Failures is the number of consecutive failed connection attempts.
+	Failures int `json:"failures"`
+
+	// Error is the error of the last failed attempt, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// Supervisor starts each frontend independently and keeps restarting the ones
+// that fail, with an exponential backoff, so that one platform being down
+// doesn't affect the others. All operations are thread safe.
+var Supervisor = supervisor{}
+
+type supervisor struct {
+	lock     sync.RWMutex
+	statuses map[string]*FrontendStatus
+}
+
+func (s *supervisor) set(name, state string, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	st := s.statuses[name]
+
+	switch {
+	case err != nil:
+		st.Failures++
+		st.Error = err.Error()
+		if st.Failures >= downAfter {
+			state = FrontendDown
+		}
+	case state == FrontendConnected:
+		st.Failures = 0
+		st.Error = ""
+	}
+
+	if st.State != state {
+		st.State = state
+		st.Since = time.Now().UTC()
+	}
+
+	log.Debug().Err(err).Str("frontend", name).Str("state", state).Msg("frontend state changed")
+}
+
+// Start starts all of the frontends, each one in its own goroutine. It
+// returns once each of them has either connected or failed its first attempt,
+// the ones that failed keep being retried in the background. Once stop is
+// closed the frontends are stopped and wgStop is done.
+func (s *supervisor) Start(fs Frontenders, stop chan struct{}, wgStop *sync.WaitGroup) {
+	s.lock.Lock()
+	s.statuses = map[string]*FrontendStatus{}
+	for _, f := range fs {
+		s.statuses[f.Name()] = &FrontendStatus{
+			Name:  f.Name(),
+			State: FrontendDown,
+			Since: time.Now().UTC(),
+		}
+	}
+	s.lock.Unlock()
+
+	wgFirst := new(sync.WaitGroup)
+	wgFirst.Add(len(fs))
+	wgStop.Add(len(fs))
+
+	for _, f := range fs {
+		go s.run(f, stop, wgFirst, wgStop)
+	}
+
+	wgFirst.Wait()
+}
+
+// run keeps the frontend running until stop is closed.
+func (s *supervisor) run(f Frontender, stop chan struct{}, wgFirst, wgStop *sync.WaitGroup) {
+	defer wgStop.Done()
+
+	var once sync.Once
+	first := func() {
+		once.Do(wgFirst.Done)
+	}
+	// in case it is stopped before the first attempt finishes
+	defer first()
+
+	backoff := backoffMin
+
+	for {
+		// set by the frontend's goroutines, so it's accessed atomically
+		var wasConnected int32
+
+		connected := func(ok bool) {
+			if ok {
+				atomic.StoreInt32(&wasConnected, 1)
+				s.set(f.Name(), FrontendConnected, nil)
+				first()
+			} else {
+				s.set(f.Name(), FrontendDegraded, nil)
+			}
+		}
+
+		err := f.Init(connected, stop)
+		first()
+
+		select {
+		case <-stop:
+			log.Debug().Str("frontend", f.Name()).Msg("frontend stopped")
+			return
+		default:
+		}
+
+		if err == nil {
+			err = errStopped
+		}
+		s.set(f.Name(), FrontendDegraded, err)
+
+		// the backoff only grows while connecting keeps failing
+		if atomic.LoadInt32(&wasConnected) == 1 {
+			backoff = backoffMin
+		}
+
+		log.Debug().Str("frontend", f.Name()).Dur("backoff", backoff).Msg("retrying frontend")
+		select {
+		case <-time.After(backoff):
+		case <-stop:
+			return
+		}
+
+		backoff *= 2
+		if backoff > backoffMax {
+			backoff = backoffMax
+		}
+	}
+}
+
+// Statuses returns the status of every frontend, sorted by name.
+func (s *supervisor) Statuses() []FrontendStatus {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var sts []FrontendStatus
+	for _, st := range s.statuses {
+		sts = append(sts, *st)
+	}
+	sort.Slice(sts, func(i, j int) bool {
+		return sts[i].Name < sts[j].Name
+	})
+	return sts
+}
+
+// Ready returns true if all of the frontends are connected.
+func (s *supervisor) Ready() bool {
+	for _, st := range s.Statuses() {
+		if st.State != FrontendConnected {
+			return false
+		}
+	}
+	return true
+}
+
+func init() {
+	// the process is alive as long as it can respond, a frontend being down
+	// is not a reason to restart it
+	Gin.GET("/healthz", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"frontends": Supervisor.Statuses()})
+	})
+
+	Gin.GET("/readyz", func(c *gin.Context) {
+		status := http.StatusOK
+		if !Supervisor.Ready() {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, gin.H{"frontends": Supervisor.Statuses()})
+	})
+}
@@ -2,7 +2,7 @@ package discord
 
 import (
 	"context"
-	"sync"
+	"fmt"
 
 	"github.com/janitorjeff/jeff-bot/core"
 
@@ -32,10 +32,38 @@ func (f *frontend) Type() core.FrontendType {
 	return Type
 }
 
-func (f *frontend) Init(wgInit, wgStop *sync.WaitGroup, stop chan struct{}) {
+func (f *frontend) Name() string {
+	return "discord"
+}
+
+// MentionPattern returns a regular expression that matches the bot's
+// mention, which works as a prefix everywhere.
+func (f *frontend) MentionPattern() string {
+	if Session == nil || Session.State.User == nil {
+		return ""
+	}
+	return fmt.Sprintf(`<@!?%s>[,:]?`, Session.State.User.ID)
+}
+
+// Permissions returns whether the person is a moderator and whether they are
+// an admin in the guild.
+func (f *frontend) Permissions(person, guild int64) (bool, bool, error) {
+	personID, err := core.DB.ScopeID(person)
+	if err != nil {
+		return false, false, err
+	}
+	guildID, err := core.DB.ScopeID(guild)
+	if err != nil {
+		return false, false, err
+	}
+	admin := isAdmin(guildID, personID)
+	return admin || isMod(guildID, personID), admin, nil
+}
+
+func (f *frontend) Init(connected func(bool), stop chan struct{}) error {
 	d, err := dg.New("Bot " + f.Token)
 	if err != nil {
-		log.Fatal().Err(err).Msg("failed to create discord client")
+		return err
 	}
 
 	d.AddHandler(messageCreate)
@@ -43,6 +71,10 @@ func (f *frontend) Init(wgInit, wgStop *sync.WaitGroup, stop chan struct{}) {
 	d.AddHandler(messageDelete)
 	d.AddHandler(interactionCreate)
 
+	// discordgo reconnects by itself, so these only report the state
+	d.AddHandler(func(*dg.Session, *dg.Connect) { connected(true) })
+	d.AddHandler(func(*dg.Session, *dg.Disconnect) { connected(false) })
+
 	// TODO: Specify only needed intents
 	d.Identify.Intents = dg.MakeIntent(dg.IntentsAll)
 
@@ -50,13 +82,12 @@ func (f *frontend) Init(wgInit, wgStop *sync.WaitGroup, stop chan struct{}) {
 
 	log.Debug().Msg("connecting to discord")
 	if err = d.Open(); err != nil {
-		log.Fatal().Err(err).Msg("failed to connect to discord")
-	} else {
-		log.Debug().Msg("connected to discord")
-		Session = d
+		return err
 	}
+	log.Debug().Msg("connected to discord")
+	Session = d
+	connected(true)
 
-	wgInit.Done()
 	<-stop
 
 	log.Debug().Msg("closing discord")
@@ -65,7 +96,7 @@ func (f *frontend) Init(wgInit, wgStop *sync.WaitGroup, stop chan struct{}) {
 	} else {
 		log.Debug().Msg("closed discord")
 	}
-	wgStop.Done()
+	return nil
 }
 
 func (f *frontend) CreateMessage(author, channel int64, msgID string) (*core.Message, error) {
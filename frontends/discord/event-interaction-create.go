@@ -28,6 +28,10 @@ func RegisterAppCommand(cmd *dg.ApplicationCommand) {
 }
 
 func interactionCreate(s *dg.Session, i *dg.InteractionCreate) {
+	if i.Type == dg.InteractionMessageComponent {
+		pagesScroll(s, i)
+		return
+	}
 	if i.Type != dg.InteractionApplicationCommand {
 		return
 	}
@@ -175,6 +179,37 @@ func (i *InteractionCreate) send(msg any, usrErr error) (*core.Message, error) {
 			},
 		}
 		return nil, Session.InteractionRespond(i.Interaction.Interaction, resp)
+
+	case Pages:
+		pages := msg.(Pages)
+		if err := pages.valid(); err != nil {
+			return nil, err
+		}
+		if len(pages.Embeds) == 1 {
+			return i.send(pages.Embeds[0], usrErr)
+		}
+
+		resp := &dg.InteractionResponse{
+			Type: dg.InteractionResponseChannelMessageWithSource,
+			Data: &dg.InteractionResponseData{
+				Embeds: []*dg.MessageEmbed{
+					pages.embed(usrErr),
+				},
+				Components: pages.components(),
+			},
+		}
+		if err := Session.InteractionRespond(i.Interaction.Interaction, resp); err != nil {
+			return nil, err
+		}
+
+		// the message's ID is only known once the response has been sent
+		sent, err := Session.InteractionResponse(i.Interaction.Interaction)
+		if err != nil {
+			return nil, err
+		}
+		pages.remember(sent.ID)
+		return nil, nil
+
 	default:
 		return nil, fmt.Errorf("Can't send discord message of type %v", t)
 	}
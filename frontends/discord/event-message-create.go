@@ -92,6 +92,8 @@ func (d *MessageCreate) send(msg any, usrErr error, ping bool) (*core.Message, e
 	case *dg.MessageEmbed:
 		embed := msg.(*dg.MessageEmbed)
 		return sendEmbed(d.Message.Message, embed, usrErr, ping)
+	case Pages:
+		return sendPages(d.Message.Message, msg.(Pages), usrErr, ping)
 	default:
 		return nil, fmt.Errorf("Can't send discord message of type %v", t)
 	}
@@ -98,6 +98,14 @@ func (d *MessageEdit) send(msg any, usrErr error, ping bool) (*core.Message, err
 		}
 		return editEmbed(d.Message.Message, embed, usrErr, id)
 
+	case Pages:
+		pages := msg.(Pages)
+		id, ok := replies.Get(d.Message.ID)
+		if !ok {
+			return sendPages(d.Message.Message, pages, usrErr, ping)
+		}
+		return editPages(d.Message.Message, pages, usrErr, id)
+
 	default:
 		return nil, fmt.Errorf("Can't send discord message of type %v", t)
 	}
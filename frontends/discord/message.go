@@ -56,6 +56,8 @@ func (d *Message) send(msg any, usrErr error, ping bool) (*core.Message, error)
 	case *dg.MessageEmbed:
 		embed := msg.(*dg.MessageEmbed)
 		return sendEmbed(d.Message, embed, usrErr, ping)
+	case Pages:
+		return sendPages(d.Message, msg.(Pages), usrErr, ping)
 	default:
 		return nil, fmt.Errorf("Can't send discord message of type %v", t)
 	}
@@ -0,0 +1,116 @@
+package discord
+
+import (
+	"time"
+
+	dg "github.com/bwmarrin/discordgo"
+)
+
+// MaxTimeout is the longest timeout discord allows.
+const MaxTimeout = 28 * 24 * time.Hour
+
+// highestRole returns the position of the member's highest role, or -1 if
+// they don't have any.
+func highestRole(guildID, userID string) (int, error) {
+	member, err := getMember(guildID, userID)
+	if err != nil {
+		return -1, err
+	}
+
+	highest := -1
+	for _, roleID := range member.Roles {
+		role, err := Session.State.Role(guildID, roleID)
+		if err != nil {
+			return -1, err
+		}
+		if role.Position > highest {
+			highest = role.Position
+		}
+	}
+	return highest, nil
+}
+
+// Outranks returns true if the user's highest role is strictly above the
+// target's, which is what discord requires for the user to be able to time
+// out or ban the target. The guild's owner outranks everyone.
+func Outranks(guildID, userID, targetID string) (bool, error) {
+	guild, err := Session.State.Guild(guildID)
+	if err != nil {
+		if guild, err = Session.Guild(guildID); err != nil {
+			return false, err
+		}
+	}
+	if guild.OwnerID == targetID {
+		return false, nil
+	}
+	if guild.OwnerID == userID {
+		return true, nil
+	}
+
+	user, err := highestRole(guildID, userID)
+	if err != nil {
+		return false, err
+	}
+	target, err := highestRole(guildID, targetID)
+	if err != nil {
+		return false, err
+	}
+	return user > target, nil
+}
+
+// Timeout times out the user in the specified guild for dur.
+func Timeout(guildID, userID string, dur time.Duration, reason string) error {
+	until := time.Now().Add(dur)
+	return Session.GuildMemberTimeout(guildID, userID, &until, dg.WithAuditLogReason(reason))
+}
+
+// Ban bans the user from the specified guild. No messages are deleted.
+func Ban(guildID, userID, reason string) error {
+	return Session.GuildBanCreateWithReason(guildID, userID, reason, 0)
+}
+
+// Unban removes the user's ban from the specified guild and also lifts any
+// active timeout.
+func Unban(guildID, userID string) error {
+	if err := Session.GuildMemberTimeout(guildID, userID, nil); err == nil {
+		return nil
+	}
+	return Session.GuildBanDelete(guildID, userID)
+}
+
+// DeleteMessage deletes a single message.
+func DeleteMessage(channelID, msgID string) error {
+	return Session.ChannelMessageDelete(channelID, msgID)
+}
+
+// DeleteUserMessages deletes the user's messages among the latest 100 in the
+// specified channel. Returns the number of messages that were deleted.
+func DeleteUserMessages(channelID, userID string) (int, error) {
+	msgs, err := Session.ChannelMessages(channelID, 100, "", "", "")
+	if err != nil {
+		return 0, err
+	}
+
+	// messages older than 2 weeks can't be bulk deleted
+	cutoff := time.Now().Add(-14 * 24 * time.Hour)
+
+	var ids []string
+	for _, msg := range msgs {
+		if msg.Author == nil || msg.Author.ID != userID {
+			continue
+		}
+		if msg.Timestamp.Before(cutoff) {
+			continue
+		}
+		ids = append(ids, msg.ID)
+	}
+
+	switch len(ids) {
+	case 0:
+		return 0, nil
+	case 1:
+		return 1, DeleteMessage(channelID, ids[0])
+	default:
+		return len(ids), Session.ChannelMessagesBulkDelete(channelID, ids)
+	}
+}
@@ -0,0 +1,157 @@
+package discord
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/core"
+
+	dg "github.com/bwmarrin/discordgo"
+	"github.com/janitorjeff/gosafe"
+	"github.com/rs/zerolog/log"
+)
+
+// Pages are a list of embeds of which only one is shown at a time, along with
+// buttons that can be used to scroll through them. Commands can return them
+// instead of a single embed when the content doesn't fit in one.
+type Pages struct {
+	Embeds []*dg.MessageEmbed
+
+	// Current is the index of the page that is being shown, the page that is
+	// shown first when sending.
+	Current int
+}
+
+const (
+	pagesPrev = "pages_prev"
+	pagesNext = "pages_next"
+
+	// How long the buttons keep working for after the pages are sent.
+	pagesTTL = 15 * time.Minute
+)
+
+// The pages that can be currently scrolled through, the key is the ID of the
+// message that they were sent in.
+var pagesSent = gosafe.Map[string, Pages]{}
+
+// embed returns the current page, with the page number in the footer.
+func (p Pages) embed(usrErr error) *dg.MessageEmbed {
+	embed := *p.Embeds[p.Current]
+	if embed.Footer == nil {
+		embed.Footer = &dg.MessageEmbedFooter{
+			Text: fmt.Sprintf("%d/%d", p.Current+1, len(p.Embeds)),
+		}
+	}
+	return embedColor(&embed, usrErr)
+}
+
+// components returns the buttons used to scroll to the previous and next
+// pages, a button is disabled if there is no page to scroll to.
+func (p Pages) components() []dg.MessageComponent {
+	return []dg.MessageComponent{
+		dg.ActionsRow{
+			Components: []dg.MessageComponent{
+				dg.Button{
+					Emoji:    dg.ComponentEmoji{Name: "⬅️"},
+					Style:    dg.SecondaryButton,
+					CustomID: pagesPrev,
+					Disabled: p.Current == 0,
+				},
+				dg.Button{
+					Emoji:    dg.ComponentEmoji{Name: "➡️"},
+					Style:    dg.SecondaryButton,
+					CustomID: pagesNext,
+					Disabled: p.Current == len(p.Embeds)-1,
+				},
+			},
+		},
+	}
+}
+
+// remember makes it possible to scroll through the pages sent in the message
+// with the given ID until pagesTTL has passed.
+func (p Pages) remember(id string) {
+	pagesSent.Set(id, p)
+	time.AfterFunc(pagesTTL, func() {
+		pagesSent.Delete(id)
+	})
+}
+
+func (p Pages) valid() error {
+	if p.Current < 0 || p.Current >= len(p.Embeds) {
+		return fmt.Errorf("page %d out of range, have %d", p.Current, len(p.Embeds))
+	}
+	return nil
+}
+
+func sendPages(m *dg.Message, p Pages, usrErr error, ping bool) (*core.Message, error) {
+	if err := p.valid(); err != nil {
+		return nil, err
+	}
+	if len(p.Embeds) == 1 {
+		return sendEmbed(m, p.Embeds[0], usrErr, ping)
+	}
+
+	resp, err := msgSend(m, "", p.embed(usrErr), p.components(), ping)
+	if err != nil {
+		return nil, err
+	}
+	p.remember(resp.ID)
+
+	return (&Message{Message: resp}).Parse()
+}
+
+func editPages(m *dg.Message, p Pages, usrErr error, id string) (*core.Message, error) {
+	if err := p.valid(); err != nil {
+		return nil, err
+	}
+	if len(p.Embeds) == 1 {
+		return editEmbed(m, p.Embeds[0], usrErr, id)
+	}
+
+	resp, err := msgEdit(m, id, "", p.embed(usrErr), p.components())
+	if err != nil {
+		return nil, err
+	}
+	p.remember(resp.ID)
+
+	return (&Message{Message: resp}).Parse()
+}
+
+// pagesScroll handles the presses of the buttons added by sendPages.
+func pagesScroll(s *dg.Session, i *dg.InteractionCreate) {
+	pagesSent.Lock()
+	p, ok := pagesSent.GetUnsafe(i.Message.ID)
+	if ok {
+		switch i.MessageComponentData().CustomID {
+		case pagesPrev:
+			if p.Current > 0 {
+				p.Current--
+			}
+		case pagesNext:
+			if p.Current < len(p.Embeds)-1 {
+				p.Current++
+			}
+		}
+		pagesSent.SetUnsafe(i.Message.ID, p)
+	}
+	pagesSent.Unlock()
+
+	data := &dg.InteractionResponseData{}
+	if ok {
+		data.Embeds = []*dg.MessageEmbed{p.embed(nil)}
+		data.Components = p.components()
+	} else {
+		// the pages have expired, so the buttons are removed
+		data.Embeds = i.Message.Embeds
+		data.Components = []dg.MessageComponent{}
+	}
+
+	resp := &dg.InteractionResponse{
+		Type: dg.InteractionResponseUpdateMessage,
+		Data: data,
+	}
+	if err := s.InteractionRespond(i.Interaction, resp); err != nil {
+		log.Debug().Err(err).Msg("failed to scroll pages")
+	}
+}
@@ -253,17 +253,25 @@ func parse(m *dg.Message) *core.Message {
 	return msg
 }
 
+// getMember returns the guild member from the state if it's there, otherwise
+// it's fetched.
+func getMember(guildID, userID string) (*dg.Member, error) {
+	member, err := Session.State.Member(guildID, userID)
+	if err == nil {
+		return member, nil
+	}
+	return Session.GuildMember(guildID, userID)
+}
+
 func memberHasPerms(guildID, userID string, perms int64) (bool, error) {
 	// if message is a DM
 	if guildID == "" {
 		return true, nil
 	}
 
-	member, err := Session.State.Member(guildID, userID)
+	member, err := getMember(guildID, userID)
 	if err != nil {
-		if member, err = Session.GuildMember(guildID, userID); err != nil {
-			return false, err
-		}
+		return false, err
 	}
 
 	for _, roleID := range member.Roles {
@@ -307,7 +315,7 @@ func getDisplayName(member *dg.Member, author *dg.User) string {
 	return displayName
 }
 
-func msgSend(m *dg.Message, text string, embed *dg.MessageEmbed, ping bool) (*dg.Message, error) {
+func msgSend(m *dg.Message, text string, embed *dg.MessageEmbed, components []dg.MessageComponent, ping bool) (*dg.Message, error) {
 	// TODO: Consider adding an option which allows one of these 3 values
 	// - no reply + no ping, just an embed
 	// - reply + no ping (default)
@@ -338,6 +346,7 @@ func msgSend(m *dg.Message, text string, embed *dg.MessageEmbed, ping bool) (*dg
 	reply := &dg.MessageSend{
 		Content:         text,
 		Embeds:          embeds,
+		Components:      components,
 		AllowedMentions: mentions,
 		Reference:       ref,
 	}
@@ -368,11 +377,11 @@ func sendText(m *dg.Message, text string, ping bool) (*core.Message, error) {
 	lenCnt := func(s string) int { return len(s) }
 
 	if lenLim > lenCnt(text) {
-		resp, err = msgSend(m, text, nil, ping)
+		resp, err = msgSend(m, text, nil, nil, ping)
 	} else {
 		parts := core.Split(text, lenCnt, lenLim)
 		for _, p := range parts {
-			resp, err = msgSend(m, p, nil, ping)
+			resp, err = msgSend(m, p, nil, nil, ping)
 		}
 	}
 
@@ -383,16 +392,15 @@ func sendText(m *dg.Message, text string, ping bool) (*core.Message, error) {
 }
 
 func sendEmbed(m *dg.Message, embed *dg.MessageEmbed, usrErr error, ping bool) (*core.Message, error) {
-	// TODO: implement message scrolling
 	embed = embedColor(embed, usrErr)
-	resp, err := msgSend(m, "", embed, ping)
+	resp, err := msgSend(m, "", embed, nil, ping)
 	if err != nil {
 		return nil, err
 	}
 	return (&Message{Message: resp}).Parse()
 }
 
-func msgEdit(m *dg.Message, id, text string, embed *dg.MessageEmbed) (*dg.Message, error) {
+func msgEdit(m *dg.Message, id, text string, embed *dg.MessageEmbed, components []dg.MessageComponent) (*dg.Message, error) {
 	var embeds []*dg.MessageEmbed
 	if embed != nil {
 		embeds = append(embeds, embed)
@@ -402,8 +410,9 @@ func msgEdit(m *dg.Message, id, text string, embed *dg.MessageEmbed) (*dg.Messag
 		ID:      id,
 		Channel: m.ChannelID,
 
-		Content: &text,
-		Embeds:  embeds,
+		Content:    &text,
+		Embeds:     embeds,
+		Components: components,
 		AllowedMentions: &dg.MessageAllowedMentions{
 			Parse: []dg.AllowedMentionType{}, // don't ping user
 		},
@@ -425,7 +434,7 @@ func msgEdit(m *dg.Message, id, text string, embed *dg.MessageEmbed) (*dg.Messag
 }
 
 func editText(m *dg.Message, id, text string) (*core.Message, error) {
-	resp, err := msgEdit(m, id, text, nil)
+	resp, err := msgEdit(m, id, text, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -434,13 +443,39 @@ func editText(m *dg.Message, id, text string) (*core.Message, error) {
 
 func editEmbed(m *dg.Message, embed *dg.MessageEmbed, usrErr error, id string) (*core.Message, error) {
 	embed = embedColor(embed, usrErr)
-	resp, err := msgEdit(m, id, "", embed)
+	resp, err := msgEdit(m, id, "", embed, nil)
 	if err != nil {
 		return nil, err
 	}
 	return (&Message{Message: resp}).Parse()
 }
 
+// SendEmbed sends an embed to the channel without replying to anyone, used for
+// messages that aren't direct responses to a command, e.g. game rounds.
+func SendEmbed(channelID string, embed *dg.MessageEmbed) error {
+	embed = embedColor(embed, nil)
+	_, err := Session.ChannelMessageSendEmbed(channelID, embed)
+	return err
+}
+
+// DM sends a direct message to the user.
+func DM(userID, text string) error {
+	ch, err := Session.UserChannelCreate(userID)
+	if err != nil {
+		return err
+	}
+	_, err = Session.ChannelMessageSend(ch.ID, text)
+	return err
+}
+
+// EditEmbed replaces the embed of a message that was sent by the bot, used for
+// messages that are updated in place, e.g. polls.
+func EditEmbed(channelID, msgID string, embed *dg.MessageEmbed) error {
+	embed = embedColor(embed, nil)
+	_, err := msgEdit(&dg.Message{ChannelID: channelID}, msgID, "", embed, nil)
+	return err
+}
+
 func embedColor(embed *dg.MessageEmbed, usrErr error) *dg.MessageEmbed {
 	if embed.Color != 0 {
 		return embed
@@ -4,9 +4,11 @@ import (
 	"github.com/janitorjeff/jeff-bot/core"
 	"github.com/janitorjeff/jeff-bot/frontends/discord"
 	"github.com/janitorjeff/jeff-bot/frontends/twitch"
+	"github.com/janitorjeff/jeff-bot/frontends/webhook"
 )
 
 var Frontends = core.Frontenders{
 	discord.Frontend,
 	twitch.Frontend,
+	webhook.Frontend,
 }
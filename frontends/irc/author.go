@@ -0,0 +1,90 @@
+package irc
+
+import (
+	"strings"
+
+	"github.com/janitorjeff/jeff-bot/core"
+)
+
+type Author struct {
+	Nick string
+	User string
+	Host string
+
+	// Account is the services account the author is logged into, empty if
+	// they aren't or if the server doesn't support the account-tag
+	// capability. Nicks can be used by anyone, so without an account the
+	// author is never trusted with mod or admin actions.
+	Account string
+
+	// Channel is the channel the message was sent in, empty for private
+	// messages.
+	Channel string
+}
+
+func (a Author) ID() string {
+	return fold(a.Nick)
+}
+
+func (a Author) Name() string {
+	return a.Nick
+}
+
+func (a Author) DisplayName() string {
+	return a.Nick
+}
+
+func (a Author) Mention() string {
+	return a.Nick + ":"
+}
+
+func (a Author) BotAdmin() bool {
+	return false
+}
+
+// modes returns the author's prefix modes in the channel.
+func (a Author) modes() string {
+	if ircClient == nil {
+		return ""
+	}
+	return ircClient.Modes(a.Channel, a.Nick)
+}
+
+// Admin returns true for channel operators and the ranks above them (owners
+// and admins on the networks that have them). In private messages the place
+// is the author's own account so they are always considered an admin.
+func (a Author) Admin() bool {
+	if a.Account == "" {
+		return false
+	}
+	if a.Channel == "" {
+		return true
+	}
+	return strings.ContainsAny(a.modes(), "qao")
+}
+
+// Mod returns true for admins and half-operators.
+func (a Author) Mod() bool {
+	if a.Account == "" {
+		return false
+	}
+	if a.Admin() {
+		return true
+	}
+	return strings.ContainsRune(a.modes(), 'h')
+}
+
+func (a Author) Subscriber() bool {
+	return false
+}
+
+// Scope returns the scope of the author's account if they are logged in,
+// otherwise that of their nick.
+func (a Author) Scope() (int64, error) {
+	if a.Account != "" {
+		return accountScope(a.Account)
+	}
+	return core.CacheScope(scopeKey(a.ID()), func() (int64, error) {
+		return dbAddUser(a.ID())
+	})
+}
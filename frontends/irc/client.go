@@ -0,0 +1,605 @@
+package irc
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/rs/zerolog/log"
+)
+
+// A minimal client that implements only the parts of RFC 1459/2812 and of the
+// IRCv3 capability negotiation that the bot needs.
+
+const (
+	// The maximum length of a line, including the trailing CRLF.
+	lineLimit = 512
+
+	// If nothing is received for this long, not even a PING, then the
+	// connection is considered dead.
+	readTimeout = 5 * time.Minute
+
+	// The longest hostname a server could prepend to the bot's messages when
+	// relaying them, since the bot can't know its own host for sure.
+	maxHostLen = 63
+)
+
+var errSASLFailed = errors.New("SASL authentication failed")
+
+// line is a parsed IRC message, named so that it doesn't get confused with
+// chat messages.
+type line struct {
+	Tags    map[string]string
+	Prefix  string
+	Command string
+	Params  []string
+}
+
+// Nick returns the nick part of the prefix, e.g. nick from nick!user@host.
+func (l *line) Nick() string {
+	nick, _, _ := strings.Cut(l.Prefix, "!")
+	return nick
+}
+
+// User returns the user and host parts of the prefix.
+func (l *line) UserHost() (string, string) {
+	_, uh, _ := strings.Cut(l.Prefix, "!")
+	user, host, _ := strings.Cut(uh, "@")
+	return user, host
+}
+
+// Param returns the i-th parameter or an empty string if it doesn't exist.
+func (l *line) Param(i int) string {
+	if i < len(l.Params) {
+		return l.Params[i]
+	}
+	return ""
+}
+
+func parseLine(raw string) (*line, error) {
+	l := &line{}
+	raw = strings.TrimRight(raw, "\r\n")
+
+	if strings.HasPrefix(raw, "@") {
+		var tags string
+		tags, raw, _ = strings.Cut(raw[1:], " ")
+		l.Tags = map[string]string{}
+		for _, t := range strings.Split(tags, ";") {
+			k, v, _ := strings.Cut(t, "=")
+			l.Tags[k] = v
+		}
+	}
+
+	raw = strings.TrimLeft(raw, " ")
+	if strings.HasPrefix(raw, ":") {
+		l.Prefix, raw, _ = strings.Cut(raw[1:], " ")
+	}
+
+	raw = strings.TrimLeft(raw, " ")
+	l.Command, raw, _ = strings.Cut(raw, " ")
+	if l.Command == "" {
+		return nil, fmt.Errorf("no command in line")
+	}
+	l.Command = strings.ToUpper(l.Command)
+
+	for raw != "" {
+		raw = strings.TrimLeft(raw, " ")
+		if strings.HasPrefix(raw, ":") {
+			l.Params = append(l.Params, raw[1:])
+			break
+		}
+		var p string
+		p, raw, _ = strings.Cut(raw, " ")
+		if p != "" {
+			l.Params = append(l.Params, p)
+		}
+	}
+
+	return l, nil
+}
+
+// fold returns the lowercase version of s according to the rfc1459 case
+// mapping, which is what most networks use, in which []\~ are the uppercase
+// versions of {}|^.
+func fold(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		case r == '[':
+			return '{'
+		case r == ']':
+			return '}'
+		case r == '\\':
+			return '|'
+		case r == '~':
+			return '^'
+		}
+		return r
+	}, s)
+}
+
+// isChannel returns true if target is a channel name instead of a nick.
+func isChannel(target string) bool {
+	return target != "" && strings.ContainsRune("#&+!", rune(target[0]))
+}
+
+type client struct {
+	conn net.Conn
+	cfg  *frontend
+
+	// protects writes so that lines from different goroutines don't get
+	// interleaved
+	writeLock sync.Mutex
+
+	lock sync.RWMutex
+	nick string
+	user string
+	host string
+
+	// the mode letters and their corresponding nick prefixes, e.g. o and @,
+	// in order of rank, set by the server's ISUPPORT PREFIX token
+	prefixModes   string
+	prefixSymbols string
+
+	// the type of each channel mode, from the ISUPPORT CHANMODES token, used
+	// to know which ones take parameters
+	chanModes [4]string
+
+	// the capabilities the server supports, collected from CAP LS
+	caps map[string]bool
+
+	// the prefix modes (e.g. "ov") each nick has in each channel, both are
+	// case folded
+	members map[string]map[string]string
+}
+
+func dial(f *frontend) (*client, error) {
+	var conn net.Conn
+	var err error
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if f.TLS {
+		host, _, _ := net.SplitHostPort(f.Server)
+		conn, err = tls.DialWithDialer(dialer, "tcp", f.Server, &tls.Config{
+			ServerName: host,
+			MinVersion: tls.VersionTLS12,
+		})
+	} else {
+		conn, err = dialer.Dial("tcp", f.Server)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &client{
+		conn:          conn,
+		cfg:           f,
+		nick:          f.Nick,
+		user:          f.Nick,
+		prefixModes:   "ov",
+		prefixSymbols: "@+",
+		chanModes:     [4]string{"beI", "k", "l", "imnpst"},
+		caps:          map[string]bool{},
+		members:       map[string]map[string]string{},
+	}
+	return c, nil
+}
+
+// send writes a single line, it's truncated if it's too long.
+func (c *client) send(format string, a ...any) error {
+	s := fmt.Sprintf(format, a...)
+	s = strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+	if len(s) > lineLimit-2 {
+		s = s[:lineLimit-2]
+	}
+
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(30 * time.Second))
+	_, err := c.conn.Write([]byte(s + "\r\n"))
+	return err
+}
+
+// Nick returns the bot's current nick.
+func (c *client) Nick() string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.nick
+}
+
+// Say sends the text to target, which is either a channel or a nick. The text
+// is split into as many lines as needed to fit in the line limit.
+func (c *client) Say(target, text string) error {
+	for _, p := range c.split(target, text) {
+		if err := c.send("PRIVMSG %s :%s", target, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// split splits text into parts that fit in a PRIVMSG to target. The limit
+// applies to the line the other clients receive, which also includes the
+// prefix the server adds, so the bot's own prefix is accounted for as well.
+func (c *client) split(target, text string) []string {
+	c.lock.RLock()
+	prefix := fmt.Sprintf(":%s!%s@", c.nick, c.user)
+	host := c.host
+	c.lock.RUnlock()
+
+	hostLen := len(host)
+	if hostLen == 0 {
+		hostLen = maxHostLen
+	}
+
+	overhead := len(prefix) + hostLen + len(" PRIVMSG  :\r\n") + len(target)
+	lenLim := lineLimit - overhead
+
+	text = strings.ReplaceAll(text, "\n", " ")
+	if lenLim > len(text) {
+		return []string{text}
+	}
+	byteCnt := func(s string) int { return len(s) }
+	return core.Split(text, byteCnt, lenLim)
+}
+
+// Join joins the channels, the ones that have a key must be passed as
+// channel:key.
+func (c *client) Join(channels ...string) error {
+	for _, ch := range channels {
+		name, key, _ := strings.Cut(ch, ":")
+		var err error
+		if key == "" {
+			err = c.send("JOIN %s", name)
+		} else {
+			err = c.send("JOIN %s %s", name, key)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Quit sends a QUIT and closes the connection.
+func (c *client) Quit() error {
+	c.send("QUIT :bye")
+	return c.conn.Close()
+}
+
+// Modes returns the prefix modes nick has in channel, e.g. "ov".
+func (c *client) Modes(channel, nick string) string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if m, ok := c.members[fold(channel)]; ok {
+		return m[fold(nick)]
+	}
+	return ""
+}
+
+func (c *client) setMode(channel, nick string, mode byte, add bool) {
+	ch, n := fold(channel), fold(nick)
+	if c.members[ch] == nil {
+		c.members[ch] = map[string]string{}
+	}
+	modes := strings.ReplaceAll(c.members[ch][n], string(mode), "")
+	if add {
+		modes += string(mode)
+	}
+	c.members[ch][n] = modes
+}
+
+// register starts the connection registration, the rest of it happens in
+// handle as the server's replies come in.
+func (c *client) register() error {
+	if err := c.send("CAP LS 302"); err != nil {
+		return err
+	}
+	if c.cfg.Password != "" {
+		if err := c.send("PASS %s", c.cfg.Password); err != nil {
+			return err
+		}
+	}
+	if err := c.send("NICK %s", c.cfg.Nick); err != nil {
+		return err
+	}
+	return c.send("USER %s 0 * :%s", c.cfg.Nick, c.cfg.Nick)
+}
+
+// Run registers the connection and handles the incoming lines until the
+// connection is closed or an error occurs. onConnect is called once the
+// registration is complete and onPrivMsg for every PRIVMSG that isn't a CTCP.
+func (c *client) Run(onConnect func(), onPrivMsg func(*line)) error {
+	if err := c.register(); err != nil {
+		return err
+	}
+
+	r := bufio.NewReaderSize(c.conn, lineLimit*16)
+	for {
+		c.conn.SetReadDeadline(time.Now().Add(readTimeout))
+		raw, err := r.ReadString('\n')
+		if err != nil {
+			return err
+		}
+
+		l, err := parseLine(raw)
+		if err != nil {
+			log.Debug().Err(err).Str("line", raw).Msg("failed to parse irc line")
+			continue
+		}
+
+		if err := c.handle(l, onConnect, onPrivMsg); err != nil {
+			return err
+		}
+	}
+}
+
+// the capabilities that are requested if the server supports them
+var wantedCaps = []string{"account-tag", "multi-prefix", "sasl"}
+
+func (c *client) handle(l *line, onConnect func(), onPrivMsg func(*line)) error {
+	switch l.Command {
+	case "PING":
+		return c.send("PONG :%s", l.Param(0))
+
+	case "ERROR":
+		return fmt.Errorf("server error: %s", l.Param(0))
+
+	case "CAP":
+		return c.handleCap(l)
+
+	case "AUTHENTICATE":
+		if l.Param(0) != "+" {
+			return nil
+		}
+		auth := c.cfg.SASLUser + "\x00" + c.cfg.SASLUser + "\x00" + c.cfg.SASLPassword
+		return c.send("AUTHENTICATE %s", base64.StdEncoding.EncodeToString([]byte(auth)))
+
+	case "903": // RPL_SASLSUCCESS
+		return c.send("CAP END")
+
+	case "902", "904", "905", "906": // the SASL failures
+		return errSASLFailed
+
+	case "001": // RPL_WELCOME
+		c.lock.Lock()
+		c.nick = l.Param(0)
+		c.lock.Unlock()
+
+		if c.cfg.NickServPassword != "" {
+			if err := c.send("PRIVMSG NickServ :IDENTIFY %s", c.cfg.NickServPassword); err != nil {
+				return err
+			}
+		}
+		// find out the user and host the server shows for the bot, needed to
+		// calculate how long messages can be
+		if err := c.send("WHOIS %s", l.Param(0)); err != nil {
+			return err
+		}
+		onConnect()
+
+	case "005": // RPL_ISUPPORT
+		c.handleISupport(l)
+
+	case "311": // RPL_WHOISUSER
+		if fold(l.Param(1)) == fold(c.Nick()) {
+			c.lock.Lock()
+			c.user, c.host = l.Param(2), l.Param(3)
+			c.lock.Unlock()
+		}
+
+	case "433": // ERR_NICKNAMEINUSE
+		// only relevant during registration, afterwards the nick is never
+		// changed by the bot
+		if l.Param(0) == "*" {
+			c.lock.Lock()
+			c.nick += "_"
+			nick := c.nick
+			c.lock.Unlock()
+			return c.send("NICK %s", nick)
+		}
+
+	case "353": // RPL_NAMREPLY
+		c.handleNames(l)
+
+	case "JOIN":
+		c.lock.Lock()
+		ch := fold(l.Param(0))
+		if fold(l.Nick()) == fold(c.nick) {
+			c.members[ch] = map[string]string{}
+		} else if c.members[ch] != nil {
+			c.members[ch][fold(l.Nick())] = ""
+		}
+		c.lock.Unlock()
+
+	case "PART":
+		c.removeMember(l.Param(0), l.Nick())
+
+	case "KICK":
+		c.removeMember(l.Param(0), l.Param(1))
+
+	case "QUIT":
+		c.lock.Lock()
+		for _, m := range c.members {
+			delete(m, fold(l.Nick()))
+		}
+		c.lock.Unlock()
+
+	case "NICK":
+		c.lock.Lock()
+		old, renamed := fold(l.Nick()), fold(l.Param(0))
+		if old == fold(c.nick) {
+			c.nick = l.Param(0)
+		}
+		for _, m := range c.members {
+			if modes, ok := m[old]; ok {
+				delete(m, old)
+				m[renamed] = modes
+			}
+		}
+		c.lock.Unlock()
+
+	case "MODE":
+		c.handleMode(l)
+
+	case "PRIVMSG":
+		// CTCP, e.g. ACTION or VERSION
+		if strings.HasPrefix(l.Param(1), "\x01") {
+			return nil
+		}
+		onPrivMsg(l)
+	}
+
+	return nil
+}
+
+func (c *client) handleCap(l *line) error {
+	switch strings.ToUpper(l.Param(1)) {
+	case "LS":
+		for _, cp := range strings.Fields(l.Param(len(l.Params) - 1)) {
+			name, _, _ := strings.Cut(cp, "=")
+			c.caps[name] = true
+		}
+
+		// multiline replies have a * before the caps on every line but the
+		// last one
+		if l.Param(2) == "*" {
+			return nil
+		}
+
+		var req []string
+		for _, cp := range wantedCaps {
+			if cp == "sasl" && c.cfg.SASLUser == "" {
+				continue
+			}
+			if c.caps[cp] {
+				req = append(req, cp)
+			}
+		}
+
+		if c.cfg.SASLUser != "" && !c.caps["sasl"] {
+			return errSASLFailed
+		}
+		if len(req) == 0 {
+			return c.send("CAP END")
+		}
+		return c.send("CAP REQ :%s", strings.Join(req, " "))
+
+	case "ACK":
+		for _, cp := range strings.Fields(l.Param(2)) {
+			if cp == "sasl" {
+				return c.send("AUTHENTICATE PLAIN")
+			}
+		}
+		return c.send("CAP END")
+
+	case "NAK":
+		if c.cfg.SASLUser != "" {
+			return errSASLFailed
+		}
+		return c.send("CAP END")
+	}
+	return nil
+}
+
+func (c *client) handleISupport(l *line) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// the first param is the nick and the last one is "are supported by this
+	// server"
+	for _, token := range l.Params[1 : len(l.Params)-1] {
+		k, v, _ := strings.Cut(token, "=")
+		switch k {
+		case "PREFIX":
+			// e.g. (qaohv)~&@%+
+			modes, symbols, ok := strings.Cut(strings.TrimPrefix(v, "("), ")")
+			if ok && len(modes) == len(symbols) {
+				c.prefixModes, c.prefixSymbols = modes, symbols
+			}
+		case "CHANMODES":
+			parts := strings.Split(v, ",")
+			for i := 0; i < len(parts) && i < len(c.chanModes); i++ {
+				c.chanModes[i] = parts[i]
+			}
+		}
+	}
+}
+
+func (c *client) handleNames(l *line) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	ch := fold(l.Param(2))
+	if c.members[ch] == nil {
+		c.members[ch] = map[string]string{}
+	}
+
+	for _, name := range strings.Fields(l.Param(3)) {
+		// with multi-prefix all of the prefixes are included, e.g. @+nick
+		var modes string
+		for len(name) > 0 {
+			i := strings.IndexByte(c.prefixSymbols, name[0])
+			if i == -1 {
+				break
+			}
+			modes += string(c.prefixModes[i])
+			name = name[1:]
+		}
+		c.members[ch][fold(name)] = modes
+	}
+}
+
+func (c *client) handleMode(l *line) {
+	ch := l.Param(0)
+	if !isChannel(ch) {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	add := true
+	arg := 2
+	for _, m := range []byte(l.Param(1)) {
+		switch {
+		case m == '+':
+			add = true
+		case m == '-':
+			add = false
+		case strings.IndexByte(c.prefixModes, m) != -1:
+			c.setMode(ch, l.Param(arg), m, add)
+			arg++
+		case strings.IndexByte(c.chanModes[0], m) != -1,
+			strings.IndexByte(c.chanModes[1], m) != -1:
+			arg++
+		case strings.IndexByte(c.chanModes[2], m) != -1:
+			if add {
+				arg++
+			}
+		}
+	}
+}
+
+func (c *client) removeMember(channel, nick string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if fold(nick) == fold(c.nick) {
+		delete(c.members, fold(channel))
+		return
+	}
+	if m, ok := c.members[fold(channel)]; ok {
+		delete(m, fold(nick))
+	}
+}
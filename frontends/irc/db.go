@@ -0,0 +1,109 @@
+package irc
+
+import (
+	"github.com/janitorjeff/jeff-bot/core"
+)
+
+// dbAddPlace returns the scope of the channel or, if id is a nick, of the
+// person as returned by dbAddPerson. Both expect case folded names.
+func dbAddPlace(id string) (int64, error) {
+	if isChannel(id) {
+		return dbAddChannel(id)
+	}
+	return dbAddPerson(id)
+}
+
+func dbAddChannel(channel string) (int64, error) {
+	return dbAdd("frontend_irc_channels", "channel", channel)
+}
+
+func dbAddUser(nick string) (int64, error) {
+	return dbAdd("frontend_irc_users", "nick", nick)
+}
+
+func dbAddAccount(account string) (int64, error) {
+	return dbAdd("frontend_irc_accounts", "account", account)
+}
+
+// dbAddPerson returns the scope of the account with that name if one has
+// been seen, otherwise that of the nick.
+func dbAddPerson(name string) (int64, error) {
+	scope, err := dbGetScope("frontend_irc_accounts", "account", name)
+	if err == nil {
+		return scope, nil
+	}
+	return dbAddUser(name)
+}
+
+// dbAdd returns the name's scope in the table, creating it if it doesn't
+// exist. The table and column are never user input.
+func dbAdd(table, column, name string) (int64, error) {
+	// if scope exists return it instead of re-adding it
+	scope, err := dbGetScope(table, column, name)
+	if err == nil {
+		return scope, nil
+	}
+
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return -1, err
+	}
+	defer tx.Rollback()
+
+	scope, err = db.ScopeAdd(tx, name, Type)
+	if err != nil {
+		return -1, err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO `+table+`(scope, `+column+`)
+		VALUES ($1, $2)`, scope, name)
+
+	if err != nil {
+		return -1, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return -1, err
+	}
+	// a previous scope of the name might still be cached
+	return scope, core.CacheInvalidate(scopeKey(name), accountScopeKey(name))
+}
+
+func dbGetScope(table, column, name string) (int64, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	row := db.DB.QueryRow(`
+		SELECT scope
+		FROM `+table+`
+		WHERE `+column+` = $1`, name)
+
+	var scope int64
+	err := row.Scan(&scope)
+	return scope, err
+}
+
+// dbGetName returns the channel's name or the user's account or nick,
+// depending on what the scope belongs to.
+func dbGetName(scope int64) (string, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	row := db.DB.QueryRow(`
+		SELECT channel FROM frontend_irc_channels WHERE scope = $1
+		UNION
+		SELECT nick FROM frontend_irc_users WHERE scope = $1
+		UNION
+		SELECT account FROM frontend_irc_accounts WHERE scope = $1`, scope)
+
+	var name string
+	err := row.Scan(&name)
+	return name, err
+}
@@ -0,0 +1,59 @@
+package irc
+
+import (
+	"github.com/janitorjeff/jeff-bot/core"
+)
+
+type Here struct {
+	// Target is either the channel or, for private messages, the author's
+	// nick.
+	Target string
+
+	// Account is the author's account in private messages, if they are
+	// logged in, in which case the place is the account instead of the nick.
+	Account string
+}
+
+func (h Here) ID() string {
+	return fold(h.Target)
+}
+
+func (h Here) Name() string {
+	return h.Target
+}
+
+func (h Here) Scope() (int64, error) {
+	if h.Account != "" {
+		return accountScope(h.Account)
+	}
+	return core.CacheScope(scopeKey(h.ID()), func() (int64, error) {
+		return dbAddPlace(h.ID())
+	})
+}
+
+// scopeKey is the cache key of the scope of the channel or nick, which are
+// both expected to be case folded.
+func scopeKey(name string) string {
+	return "frontend_irc_scope_" + name
+}
+
+// accountScopeKey is the same as scopeKey but for accounts, which can have
+// the same name as a nick.
+func accountScopeKey(account string) string {
+	return "frontend_irc_scope_account_" + account
+}
+
+func accountScope(account string) (int64, error) {
+	account = fold(account)
+	return core.CacheScope(accountScopeKey(account), func() (int64, error) {
+		return dbAddAccount(account)
+	})
+}
+
+func (h Here) ScopeExact() (int64, error) {
+	return h.Scope()
+}
+
+func (h Here) ScopeLogical() (int64, error) {
+	return h.Scope()
+}
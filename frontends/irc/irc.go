@@ -0,0 +1,292 @@
+package irc
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/rs/zerolog/log"
+)
+
+const Type = 1 << 2
+
+type frontend struct {
+	// Server is the address of the server in the form host:port.
+	Server string
+	TLS    bool
+	Nick   string
+
+	// Password is the server password, most networks don't use one.
+	Password string
+
+	// If set then SASL PLAIN is used to authenticate during registration,
+	// and the connection fails if the server doesn't support it.
+	SASLUser     string
+	SASLPassword string
+
+	// If set then the bot identifies with NickServ once connected, meant for
+	// networks that don't support SASL.
+	NickServPassword string
+
+	// Channels are the channels to join, the ones that need a key can be
+	// given as channel:key.
+	Channels []string
+}
+
+var Frontend = &frontend{}
+
+var ircClient *client
+
+// IRC implements the core.Messenger and core.AudioSpeaker interfaces.
+//
+// There are no user IDs on IRC, so people are identified by their case folded
+// account, if they are logged in, or nick and channels by their case folded
+// name. Private messages use the sender's scope as the place.
+type IRC struct {
+	client *client
+	line   *line
+}
+
+func onPrivMsg(l *line) {
+	i := &IRC{client: ircClient, line: l}
+	msg, err := i.Parse()
+	if err != nil {
+		log.Debug().Err(err).Send()
+		return
+	}
+
+	// the same connection is used to read everything, so slow commands
+	// shouldn't hold it up
+	go msg.Run()
+}
+
+func (f *frontend) Type() core.FrontendType {
+	return Type
+}
+
+func (f *frontend) Name() string {
+	return "irc"
+}
+
+func (f *frontend) Init(connected func(bool), stop chan struct{}) error {
+	log.Debug().Str("server", f.Server).Msg("connecting to irc")
+	c, err := dial(f)
+	if err != nil {
+		return err
+	}
+	ircClient = c
+
+	onConnect := func() {
+		log.Debug().Msg("connected to irc")
+		if err := c.Join(f.Channels...); err != nil {
+			log.Debug().Err(err).Msg("failed to join irc channels")
+		}
+		connected(true)
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- c.Run(onConnect, onPrivMsg)
+	}()
+
+	select {
+	case err := <-errs:
+		c.conn.Close()
+		return err
+	case <-stop:
+	}
+
+	log.Debug().Msg("closing irc")
+	if err := c.Quit(); err != nil {
+		log.Debug().Err(err).Msg("failed to close irc connection")
+	} else {
+		log.Debug().Msg("closed irc")
+	}
+	return nil
+}
+
+func (f *frontend) CreateMessage(person, place int64, msgID string) (*core.Message, error) {
+	nick, err := dbGetName(person)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := dbGetName(place)
+	if err != nil {
+		return nil, err
+	}
+	// private messages are addressed to the bot
+	if !isChannel(target) {
+		target = ircClient.Nick()
+	}
+
+	tags := map[string]string{"msgid": msgID}
+	// the person might be an account instead of a nick
+	if scope, err := dbGetScope("frontend_irc_accounts", "account", nick); err == nil && scope == person {
+		tags["account"] = nick
+	}
+
+	i := &IRC{
+		client: ircClient,
+		line: &line{
+			Tags:    tags,
+			Prefix:  nick,
+			Command: "PRIVMSG",
+			Params:  []string{target, ""},
+		},
+	}
+	return i.Parse()
+}
+
+///////////////
+//           //
+// Messenger //
+//           //
+///////////////
+
+func (i *IRC) Parse() (*core.Message, error) {
+	user, host := i.line.UserHost()
+	author := Author{
+		Nick:    i.line.Nick(),
+		User:    user,
+		Host:    host,
+		Account: i.line.Tags["account"],
+	}
+
+	here := Here{Target: i.line.Nick(), Account: author.Account}
+	if target := i.line.Param(0); isChannel(target) {
+		author.Channel = target
+		here = Here{Target: target}
+	}
+
+	msg := &core.Message{
+		ID:       i.line.Tags["msgid"],
+		Raw:      i.line.Param(1),
+		Frontend: Frontend,
+		Author:   author,
+		Here:     here,
+		Client:   i,
+		Speaker:  i,
+	}
+
+	return msg, nil
+}
+
+// validate returns an error if s can't be a nick or a channel name.
+func validate(s string) error {
+	if s == "" || strings.ContainsAny(s, " ,\x00\x07\r\n") {
+		return fmt.Errorf("'%s' is not a valid nick or channel", s)
+	}
+	return nil
+}
+
+func (i *IRC) PersonID(s, _ string) (string, error) {
+	// mentions are usually either nick: or @nick
+	s = strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(s, "@"), ":"), ",")
+	if isChannel(s) {
+		return "", fmt.Errorf("'%s' is a channel, not a nick", s)
+	}
+	if err := validate(s); err != nil {
+		return "", err
+	}
+	return fold(s), nil
+}
+
+func (i *IRC) PlaceID(s string) (string, error) {
+	s = strings.TrimPrefix(s, "@")
+	if err := validate(s); err != nil {
+		return "", err
+	}
+	return fold(s), nil
+}
+
+func (i *IRC) Person(id string) (int64, error) {
+	return dbAddPerson(id)
+}
+
+func (i *IRC) PlaceExact(id string) (int64, error) {
+	return dbAddPlace(id)
+}
+
+// There are no groups of channels on IRC, so the logical and the exact place
+// are always the same.
+func (i *IRC) PlaceLogical(id string) (int64, error) {
+	return dbAddPlace(id)
+}
+
+func (i *IRC) Usage(usage string) any {
+	return fmt.Sprintf("Usage: %s", usage)
+}
+
+// target returns where replies should be sent to, either the channel or, for
+// private messages, the author.
+func (i *IRC) target() string {
+	if target := i.line.Param(0); isChannel(target) {
+		return target
+	}
+	return i.line.Nick()
+}
+
+func (i *IRC) send(msg any, mention string) (*core.Message, error) {
+	var text string
+	switch t := msg.(type) {
+	case string:
+		text = msg.(string)
+	default:
+		return nil, fmt.Errorf("Can't send irc message of type %v", t)
+	}
+
+	return nil, i.client.Say(i.target(), mention+text)
+}
+
+func (i *IRC) Send(msg any, _ error) (*core.Message, error) {
+	return i.send(msg, "")
+}
+
+func (i *IRC) Ping(msg any, _ error) (*core.Message, error) {
+	return i.send(msg, i.line.Nick()+": ")
+}
+
+func (i *IRC) Write(msg any, usrErr error) (*core.Message, error) {
+	// no reason to ping someone in a private message
+	if !isChannel(i.line.Param(0)) {
+		return i.Send(msg, usrErr)
+	}
+	return i.Ping(msg, usrErr)
+}
+
+/////////////
+//         //
+// Speaker //
+//         //
+/////////////
+
+func (i *IRC) Enabled() bool {
+	return false
+}
+
+func (i *IRC) FrameRate() int {
+	return 0
+}
+
+func (i *IRC) Channels() int {
+	return 0
+}
+
+func (i *IRC) Join() error {
+	return nil
+}
+
+func (i *IRC) Say(io.Reader, *core.AudioState) error {
+	return nil
+}
+
+func (i *IRC) AuthorDeafened() (bool, error) {
+	return false, nil
+}
+
+func (i *IRC) AuthorConnected() (bool, error) {
+	return false, nil
+}
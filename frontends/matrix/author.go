@@ -0,0 +1,87 @@
+package matrix
+
+import (
+	"strings"
+
+	"github.com/janitorjeff/jeff-bot/core"
+)
+
+// Author implements the core.Author interface.
+type Author struct {
+	UserID string
+	RoomID string
+}
+
+func (a *Author) ID() string {
+	return a.UserID
+}
+
+// Name returns the localpart of the user ID, e.g. jeff for @jeff:matrix.org.
+func (a *Author) Name() string {
+	name, _, _ := strings.Cut(strings.TrimPrefix(a.UserID, "@"), ":")
+	return name
+}
+
+func (a *Author) DisplayName() string {
+	name, err := matrixClient.DisplayName(a.RoomID, a.UserID)
+	if err != nil || name == "" {
+		return a.Name()
+	}
+	return name
+}
+
+func (a *Author) Mention() string {
+	return a.DisplayName()
+}
+
+func (a *Author) BotAdmin() bool {
+	return false
+}
+
+// level returns the author's power level in the room and the level required
+// to ban people.
+func (a *Author) level() (int, int) {
+	pl, err := matrixClient.PowerLevels(a.RoomID)
+	if err != nil {
+		return 0, 50
+	}
+
+	level := pl.UsersDefault
+	if l, ok := pl.Users[a.UserID]; ok {
+		level = l
+	}
+
+	ban := 50
+	if pl.Ban != nil {
+		ban = *pl.Ban
+	}
+	return level, ban
+}
+
+// Admin returns true for people with a power level of 100, which is what
+// clients show as admin.
+func (a *Author) Admin() bool {
+	level, _ := a.level()
+	return level >= 100
+}
+
+// Mod returns true for people whose power level allows them to ban.
+func (a *Author) Mod() bool {
+	if a.Admin() {
+		return true
+	}
+	level, ban := a.level()
+	return level >= ban
+}
+
+func (a *Author) Subscriber() bool {
+	return false
+}
+
+func (a *Author) Scope() (int64, error) {
+	rdbKey := "frontend_matrix_scope_author_" + a.ID()
+
+	return core.CacheScope(rdbKey, func() (int64, error) {
+		return getUserScope(a.ID())
+	})
+}
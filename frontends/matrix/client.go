@@ -0,0 +1,329 @@
+package matrix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/rs/zerolog/log"
+)
+
+// A minimal client for the parts of the client-server API that the bot needs.
+
+// How long the homeserver is allowed to hold a sync request open.
+const syncTimeout = 30 * time.Second
+
+// Error is the error the homeserver returns, e.g. M_FORBIDDEN.
+type Error struct {
+	Status  int    `json:"-"`
+	ErrCode string `json:"errcode"`
+	Message string `json:"error"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("matrix: %d %s: %s", e.Status, e.ErrCode, e.Message)
+}
+
+type event struct {
+	Type     string          `json:"type"`
+	Sender   string          `json:"sender"`
+	EventID  string          `json:"event_id"`
+	StateKey *string         `json:"state_key,omitempty"`
+	Content  json.RawMessage `json:"content"`
+}
+
+type relatesTo struct {
+	RelType   string `json:"rel_type,omitempty"`
+	EventID   string `json:"event_id,omitempty"`
+	InReplyTo *struct {
+		EventID string `json:"event_id"`
+	} `json:"m.in_reply_to,omitempty"`
+}
+
+type mentions struct {
+	UserIDs []string `json:"user_ids,omitempty"`
+}
+
+type messageContent struct {
+	MsgType       string     `json:"msgtype"`
+	Body          string     `json:"body"`
+	Format        string     `json:"format,omitempty"`
+	FormattedBody string     `json:"formatted_body,omitempty"`
+	RelatesTo     *relatesTo `json:"m.relates_to,omitempty"`
+	Mentions      *mentions  `json:"m.mentions,omitempty"`
+}
+
+type powerLevels struct {
+	Ban          *int           `json:"ban"`
+	Users        map[string]int `json:"users"`
+	UsersDefault int            `json:"users_default"`
+}
+
+type syncResponse struct {
+	NextBatch string `json:"next_batch"`
+	Rooms     struct {
+		Join map[string]struct {
+			State struct {
+				Events []event `json:"events"`
+			} `json:"state"`
+			Timeline struct {
+				Events []event `json:"events"`
+			} `json:"timeline"`
+		} `json:"join"`
+		Invite map[string]json.RawMessage `json:"invite"`
+	} `json:"rooms"`
+}
+
+type client struct {
+	homeserver string
+	token      string
+	http       *http.Client
+
+	// the bot's own user ID
+	userID string
+
+	txn int64
+
+	// the power levels and the parent space of each room, the ones that
+	// change are dropped when their state event appears in a sync
+	lock   sync.RWMutex
+	levels map[string]*powerLevels
+	spaces map[string]string
+}
+
+func newClient(homeserver, token string) *client {
+	return &client{
+		homeserver: strings.TrimSuffix(homeserver, "/"),
+		token:      token,
+		http:       core.APIClient("matrix"),
+		levels:     map[string]*powerLevels{},
+		spaces:     map[string]string{},
+	}
+}
+
+// do sends a request to the client-server API, path is relative to
+// /_matrix/client/v3 and must already be escaped. If out is not nil then the
+// response is decoded into it.
+func (c *client) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.homeserver+"/_matrix/client/v3"+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		e := &Error{Status: resp.StatusCode}
+		json.NewDecoder(resp.Body).Decode(e)
+		return e
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *client) get(path string, out any) error {
+	return c.do(context.Background(), http.MethodGet, path, nil, out)
+}
+
+// WhoAmI returns the user ID the access token belongs to.
+func (c *client) WhoAmI() (string, error) {
+	var resp struct {
+		UserID string `json:"user_id"`
+	}
+	err := c.get("/account/whoami", &resp)
+	return resp.UserID, err
+}
+
+// Sync returns the events since the since token, or the latest state if it's
+// empty, in which case the timelines are kept short since old messages are
+// skipped anyway.
+func (c *client) Sync(ctx context.Context, since string) (*syncResponse, error) {
+	q := url.Values{}
+	if since == "" {
+		q.Set("filter", `{"room":{"timeline":{"limit":1}}}`)
+	} else {
+		q.Set("since", since)
+		q.Set("timeout", strconv.FormatInt(syncTimeout.Milliseconds(), 10))
+	}
+
+	var resp syncResponse
+	err := c.do(ctx, http.MethodGet, "/sync?"+q.Encode(), nil, &resp)
+	return &resp, err
+}
+
+// Join joins the room, used to accept invites.
+func (c *client) Join(roomID string) error {
+	return c.do(context.Background(), http.MethodPost, "/rooms/"+url.PathEscape(roomID)+"/join", struct{}{}, nil)
+}
+
+// Send sends a message event to the room and returns its ID.
+func (c *client) Send(roomID string, content *messageContent) (string, error) {
+	txn := strconv.FormatInt(time.Now().UnixNano(), 10) + "." + strconv.FormatInt(atomic.AddInt64(&c.txn, 1), 10)
+	path := fmt.Sprintf("/rooms/%s/send/m.room.message/%s", url.PathEscape(roomID), txn)
+
+	var resp struct {
+		EventID string `json:"event_id"`
+	}
+	err := c.do(context.Background(), http.MethodPut, path, content, &resp)
+	return resp.EventID, err
+}
+
+// State gets a single state event's content.
+func (c *client) State(roomID, eventType, stateKey string, out any) error {
+	path := fmt.Sprintf("/rooms/%s/state/%s/%s", url.PathEscape(roomID), url.PathEscape(eventType), url.PathEscape(stateKey))
+	return c.get(path, out)
+}
+
+// Event checks that the event exists and returns it.
+func (c *client) Event(roomID, eventID string) (*event, error) {
+	var e event
+	path := fmt.Sprintf("/rooms/%s/event/%s", url.PathEscape(roomID), url.PathEscape(eventID))
+	err := c.get(path, &e)
+	return &e, err
+}
+
+// ResolveAlias returns the ID of the room the alias points to.
+func (c *client) ResolveAlias(alias string) (string, error) {
+	var resp struct {
+		RoomID string `json:"room_id"`
+	}
+	err := c.get("/directory/room/"+url.PathEscape(alias), &resp)
+	return resp.RoomID, err
+}
+
+// Profile checks that the user exists and returns their display name.
+func (c *client) Profile(userID string) (string, error) {
+	var resp struct {
+		DisplayName string `json:"displayname"`
+	}
+	err := c.get("/profile/"+url.PathEscape(userID), &resp)
+	return resp.DisplayName, err
+}
+
+// DisplayName returns the user's display name in the room, falling back to
+// their global one.
+func (c *client) DisplayName(roomID, userID string) (string, error) {
+	var member struct {
+		DisplayName string `json:"displayname"`
+	}
+	if err := c.State(roomID, "m.room.member", userID, &member); err == nil && member.DisplayName != "" {
+		return member.DisplayName, nil
+	}
+	return c.Profile(userID)
+}
+
+// PowerLevels returns the room's power levels.
+func (c *client) PowerLevels(roomID string) (*powerLevels, error) {
+	c.lock.RLock()
+	pl, ok := c.levels[roomID]
+	c.lock.RUnlock()
+	if ok {
+		return pl, nil
+	}
+
+	pl = &powerLevels{}
+	if err := c.State(roomID, "m.room.power_levels", "", pl); err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	c.levels[roomID] = pl
+	c.lock.Unlock()
+	return pl, nil
+}
+
+// Space returns the ID of the space the room belongs to, or an empty string
+// if it isn't part of one. If there are multiple then the canonical one is
+// preferred.
+func (c *client) Space(roomID string) (string, error) {
+	c.lock.RLock()
+	space, ok := c.spaces[roomID]
+	c.lock.RUnlock()
+	if ok {
+		return space, nil
+	}
+
+	var state []event
+	if err := c.get("/rooms/"+url.PathEscape(roomID)+"/state", &state); err != nil {
+		return "", err
+	}
+
+	for _, e := range state {
+		if e.Type != "m.space.parent" || e.StateKey == nil {
+			continue
+		}
+		var parent struct {
+			Via       []string `json:"via"`
+			Canonical bool     `json:"canonical"`
+		}
+		if err := json.Unmarshal(e.Content, &parent); err != nil || len(parent.Via) == 0 {
+			// removed parents have empty content
+			continue
+		}
+		if space == "" || parent.Canonical {
+			space = *e.StateKey
+		}
+	}
+
+	c.lock.Lock()
+	c.spaces[roomID] = space
+	c.lock.Unlock()
+	return space, nil
+}
+
+// IsSpace returns true if the room is a space.
+func (c *client) IsSpace(roomID string) bool {
+	var create struct {
+		Type string `json:"type"`
+	}
+	if err := c.State(roomID, "m.room.create", "", &create); err != nil {
+		return false
+	}
+	return create.Type == "m.space"
+}
+
+// forget drops the cached state that the event changes.
+func (c *client) forget(roomID string, e event) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	switch e.Type {
+	case "m.room.power_levels":
+		delete(c.levels, roomID)
+	case "m.space.parent":
+		delete(c.spaces, roomID)
+		if err := core.CacheInvalidate(logicalKey(roomID)); err != nil {
+			log.Debug().Err(err).Str("room", roomID).Msg("failed to invalidate matrix room's scope")
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package matrix
+
+import (
+	"database/sql"
+
+	"github.com/janitorjeff/jeff-bot/core"
+)
+
+func dbAddSpaceScope(tx *sql.Tx, spaceID string) (int64, error) {
+	scope, err := core.DB.ScopeAdd(tx, spaceID, Type)
+	if err != nil {
+		return -1, err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO frontend_matrix_spaces (scope, space)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING;`, scope, spaceID)
+
+	if err != nil {
+		return -1, err
+	}
+
+	return scope, nil
+}
+
+func dbAddRoomScope(tx *sql.Tx, roomID string, spaceScope int64) (int64, error) {
+	scope, err := core.DB.ScopeAdd(tx, roomID, Type)
+	if err != nil {
+		return -1, err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO frontend_matrix_rooms (scope, room, space)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING;`, scope, roomID, spaceScope)
+
+	if err != nil {
+		return -1, err
+	}
+
+	return scope, nil
+}
+
+func dbAddUserScope(tx *sql.Tx, userID string) (int64, error) {
+	scope, err := core.DB.ScopeAdd(tx, userID, Type)
+	if err != nil {
+		return -1, err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO frontend_matrix_users (scope, uid)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING;`, scope, userID)
+
+	if err != nil {
+		return -1, err
+	}
+
+	return scope, nil
+}
+
+func dbGetSpaceScope(spaceID string) (int64, error) {
+	row := core.DB.DB.QueryRow(`
+		SELECT scope
+		FROM frontend_matrix_spaces
+		WHERE space = $1`, spaceID)
+
+	var scope int64
+	err := row.Scan(&scope)
+	return scope, err
+}
+
+func dbGetRoomScope(roomID string) (int64, error) {
+	row := core.DB.DB.QueryRow(`
+		SELECT scope
+		FROM frontend_matrix_rooms
+		WHERE room = $1`, roomID)
+
+	var scope int64
+	err := row.Scan(&scope)
+	return scope, err
+}
+
+func dbGetUserScope(userID string) (int64, error) {
+	row := core.DB.DB.QueryRow(`
+		SELECT scope
+		FROM frontend_matrix_users
+		WHERE uid = $1`, userID)
+
+	var scope int64
+	err := row.Scan(&scope)
+	return scope, err
+}
+
+func dbGetRoomID(scope int64) (string, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	row := db.DB.QueryRow(`
+		SELECT room
+		FROM frontend_matrix_rooms
+		WHERE scope = $1`, scope)
+
+	var roomID string
+	err := row.Scan(&roomID)
+	return roomID, err
+}
+
+func dbGetUserID(scope int64) (string, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	row := db.DB.QueryRow(`
+		SELECT uid
+		FROM frontend_matrix_users
+		WHERE scope = $1`, scope)
+
+	var userID string
+	err := row.Scan(&userID)
+	return userID, err
+}
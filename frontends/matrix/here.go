@@ -0,0 +1,44 @@
+package matrix
+
+import (
+	"github.com/janitorjeff/jeff-bot/core"
+)
+
+// Here implements the core.Here interface.
+type Here struct {
+	RoomID string
+}
+
+func (h *Here) ID() string {
+	return h.RoomID
+}
+
+func (h *Here) Name() string {
+	var name struct {
+		Name string `json:"name"`
+	}
+	if err := matrixClient.State(h.RoomID, "m.room.name", "", &name); err != nil || name.Name == "" {
+		return h.RoomID
+	}
+	return name.Name
+}
+
+func (h *Here) ScopeExact() (int64, error) {
+	rdbKey := "frontend_matrix_scope_here_exact_" + h.ID()
+
+	return core.CacheScope(rdbKey, func() (int64, error) {
+		return getPlaceExactScope(h.ID())
+	})
+}
+
+// logicalKey is the cache key of the room's logical scope, which changes if
+// the room is moved to a different space.
+func logicalKey(roomID string) string {
+	return "frontend_matrix_scope_here_logical_" + roomID
+}
+
+func (h *Here) ScopeLogical() (int64, error) {
+	return core.CacheScope(logicalKey(h.ID()), func() (int64, error) {
+		return getPlaceLogicalScope(h.ID())
+	})
+}
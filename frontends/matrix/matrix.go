@@ -0,0 +1,333 @@
+package matrix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/rs/zerolog/log"
+)
+
+const Type = 1 << 3
+
+type frontend struct {
+	// Homeserver is the homeserver's base URL, e.g. https://matrix.org.
+	Homeserver string
+
+	// Token is the bot account's access token.
+	Token string
+}
+
+var Frontend = &frontend{}
+
+var matrixClient *client
+
+// Matrix implements the core.Messenger and core.AudioSpeaker interfaces.
+type Matrix struct {
+	client  *client
+	roomID  string
+	event   *event
+	content *messageContent
+}
+
+func (f *frontend) Type() core.FrontendType {
+	return Type
+}
+
+func (f *frontend) Name() string {
+	return "matrix"
+}
+
+func (f *frontend) Init(connected func(bool), stop chan struct{}) error {
+	c := newClient(f.Homeserver, f.Token)
+
+	log.Debug().Str("homeserver", f.Homeserver).Msg("connecting to matrix")
+	userID, err := c.WhoAmI()
+	if err != nil {
+		return err
+	}
+	c.userID = userID
+	matrixClient = c
+
+	// the sync requests are long polls, so they have to be cancelled for the
+	// bot to stop in time
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	// the messages sent while the bot was away are skipped
+	var since string
+	for first := true; ; first = false {
+		resp, err := c.Sync(ctx, since)
+		if err != nil {
+			select {
+			case <-stop:
+				log.Debug().Msg("closed matrix")
+				return nil
+			default:
+				return err
+			}
+		}
+		since = resp.NextBatch
+
+		if first {
+			log.Debug().Str("user", userID).Msg("connected to matrix")
+			connected(true)
+		}
+		c.handle(resp, first)
+	}
+}
+
+// handle accepts any invites and runs the new messages. If skip is true then
+// the messages are ignored.
+func (c *client) handle(resp *syncResponse, skip bool) {
+	for roomID := range resp.Rooms.Invite {
+		if err := c.Join(roomID); err != nil {
+			log.Debug().Err(err).Str("room", roomID).Msg("failed to accept matrix invite")
+		}
+	}
+
+	for roomID, room := range resp.Rooms.Join {
+		for _, e := range room.State.Events {
+			c.forget(roomID, e)
+		}
+
+		for _, e := range room.Timeline.Events {
+			if e.StateKey != nil {
+				c.forget(roomID, e)
+				continue
+			}
+			if skip || e.Type != "m.room.message" || e.Sender == c.userID {
+				continue
+			}
+
+			var content messageContent
+			if err := json.Unmarshal(e.Content, &content); err != nil {
+				log.Debug().Err(err).Str("event", e.EventID).Msg("failed to parse matrix message")
+				continue
+			}
+			// edits are sent as new messages that replace the old ones
+			if content.MsgType != "m.text" || (content.RelatesTo != nil && content.RelatesTo.RelType == "m.replace") {
+				continue
+			}
+
+			e := e
+			m := &Matrix{client: c, roomID: roomID, event: &e, content: &content}
+			msg, err := m.Parse()
+			if err != nil {
+				log.Debug().Err(err).Send()
+				continue
+			}
+
+			// the same loop receives everything, so slow commands shouldn't
+			// hold it up
+			go msg.Run()
+		}
+	}
+}
+
+func (f *frontend) CreateMessage(person, place int64, msgID string) (*core.Message, error) {
+	userID, err := dbGetUserID(person)
+	if err != nil {
+		return nil, err
+	}
+
+	roomID, err := dbGetRoomID(place)
+	if err != nil {
+		return nil, err
+	}
+
+	// check if the event still exists (could have been redacted for example)
+	if msgID != "" {
+		if e, err := matrixClient.Event(roomID, msgID); err != nil || len(e.Content) <= 2 {
+			msgID = ""
+		}
+	}
+
+	m := &Matrix{
+		client: matrixClient,
+		roomID: roomID,
+		event: &event{
+			Type:    "m.room.message",
+			Sender:  userID,
+			EventID: msgID,
+		},
+		content: &messageContent{MsgType: "m.text"},
+	}
+	return m.Parse()
+}
+
+///////////////
+//           //
+// Messenger //
+//           //
+///////////////
+
+// stripReplyFallback removes the quote of the replied to message that older
+// clients include at the start of replies.
+func stripReplyFallback(c *messageContent) string {
+	if c.RelatesTo == nil || c.RelatesTo.InReplyTo == nil {
+		return c.Body
+	}
+
+	lines := strings.Split(c.Body, "\n")
+	i := 0
+	for i < len(lines) && strings.HasPrefix(lines[i], ">") {
+		i++
+	}
+	if i > 0 && i < len(lines) && lines[i] == "" {
+		i++
+	}
+	return strings.Join(lines[i:], "\n")
+}
+
+func (m *Matrix) Parse() (*core.Message, error) {
+	author := &Author{
+		UserID: m.event.Sender,
+		RoomID: m.roomID,
+	}
+
+	here := &Here{
+		RoomID: m.roomID,
+	}
+
+	msg := &core.Message{
+		ID:       m.event.EventID,
+		Raw:      stripReplyFallback(m.content),
+		Frontend: Frontend,
+		Author:   author,
+		Here:     here,
+		Client:   m,
+		Speaker:  m,
+	}
+
+	return msg, nil
+}
+
+// trimLink removes the matrix.to prefix that mentions in messages use.
+func trimLink(s string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(s, "https://matrix.to/#/"), "matrix.to/#/")
+}
+
+func (m *Matrix) PersonID(s, _ string) (string, error) {
+	s = strings.TrimSuffix(trimLink(s), ":")
+	if !strings.HasPrefix(s, "@") || !strings.Contains(s, ":") {
+		return "", fmt.Errorf("'%s' is not a valid user ID", s)
+	}
+	if _, err := m.client.Profile(s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+func (m *Matrix) PlaceID(s string) (string, error) {
+	s = trimLink(s)
+	switch {
+	case strings.HasPrefix(s, "#"):
+		return m.client.ResolveAlias(s)
+	case strings.HasPrefix(s, "!") && strings.Contains(s, ":"):
+		return s, nil
+	default:
+		return "", fmt.Errorf("'%s' is not a valid room ID or alias", s)
+	}
+}
+
+func (m *Matrix) Person(id string) (int64, error) {
+	return getUserScope(id)
+}
+
+func (m *Matrix) PlaceExact(id string) (int64, error) {
+	return getPlaceExactScope(id)
+}
+
+func (m *Matrix) PlaceLogical(id string) (int64, error) {
+	return getPlaceLogicalScope(id)
+}
+
+func (m *Matrix) Usage(usage string) any {
+	return fmt.Sprintf("Usage: %s", usage)
+}
+
+func (m *Matrix) send(msg any, reply bool) (*core.Message, error) {
+	var text string
+	switch t := msg.(type) {
+	case string:
+		text = msg.(string)
+	default:
+		return nil, fmt.Errorf("Can't send matrix message of type %v", t)
+	}
+
+	content := &messageContent{
+		MsgType: "m.text",
+		Body:    text,
+	}
+
+	// replies notify the person that is being replied to, so no explicit
+	// mention is needed
+	if reply && m.event.EventID != "" {
+		content.RelatesTo = &relatesTo{}
+		content.RelatesTo.InReplyTo = &struct {
+			EventID string `json:"event_id"`
+		}{m.event.EventID}
+		content.Mentions = &mentions{UserIDs: []string{m.event.Sender}}
+	}
+
+	_, err := m.client.Send(m.roomID, content)
+	return nil, err
+}
+
+func (m *Matrix) Send(msg any, _ error) (*core.Message, error) {
+	return m.send(msg, false)
+}
+
+func (m *Matrix) Ping(msg any, _ error) (*core.Message, error) {
+	return m.send(msg, true)
+}
+
+func (m *Matrix) Write(msg any, usrErr error) (*core.Message, error) {
+	return m.Ping(msg, usrErr)
+}
+
+/////////////
+//         //
+// Speaker //
+//         //
+/////////////
+
+func (m *Matrix) Enabled() bool {
+	return false
+}
+
+func (m *Matrix) FrameRate() int {
+	return 0
+}
+
+func (m *Matrix) Channels() int {
+	return 0
+}
+
+func (m *Matrix) Join() error {
+	return nil
+}
+
+func (m *Matrix) Say(io.Reader, *core.AudioState) error {
+	return nil
+}
+
+func (m *Matrix) AuthorDeafened() (bool, error) {
+	return false, nil
+}
+
+func (m *Matrix) AuthorConnected() (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,161 @@
+package matrix
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/janitorjeff/jeff-bot/internal/testkit/matrixtest"
+)
+
+const (
+	space = "!space:localhost"
+	room  = "!room:localhost"
+	dm    = "!dm:localhost"
+	admin = "@admin:localhost"
+	mod   = "@mod:localhost"
+	user  = "@user:localhost"
+)
+
+func newHomeserver(t *testing.T) *matrixtest.Homeserver {
+	hs := matrixtest.NewHomeserver("@jeff:localhost")
+	t.Cleanup(hs.Close)
+
+	hs.AddSpace(space)
+	hs.AddRoom(room, space)
+	hs.AddRoom(dm, "")
+	hs.SetPowerLevel(room, admin, 100)
+	hs.SetPowerLevel(room, mod, 50)
+
+	matrixClient = newClient(hs.URL, hs.Token)
+	return hs
+}
+
+// syncMessages returns the messages received since the since token and
+// advances it.
+func syncMessages(t *testing.T, since *string) []*Matrix {
+	resp, err := matrixClient.Sync(context.Background(), *since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	*since = resp.NextBatch
+
+	var msgs []*Matrix
+	for roomID, r := range resp.Rooms.Join {
+		for _, e := range r.Timeline.Events {
+			if e.Type != "m.room.message" {
+				continue
+			}
+			e := e
+			m := &Matrix{client: matrixClient, roomID: roomID, event: &e, content: &messageContent{}}
+			if err := json.Unmarshal(e.Content, m.content); err != nil {
+				t.Fatal(err)
+			}
+			msgs = append(msgs, m)
+		}
+	}
+	return msgs
+}
+
+func TestMessages(t *testing.T) {
+	hs := newHomeserver(t)
+
+	var since string
+	hs.Message(room, user, "old message", "")
+	syncMessages(t, &since)
+
+	replied := hs.Message(room, user, "!first", "")
+	id := hs.Message(room, admin, "> <@user:localhost> !first\n\n!help", replied)
+
+	msgs := syncMessages(t, &since)
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(msgs))
+	}
+
+	msg, err := msgs[1].Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Raw != "!help" {
+		t.Errorf("expected the reply fallback to be stripped, got %q", msg.Raw)
+	}
+	if msg.ID != id {
+		t.Errorf("expected message id %s, got %s", id, msg.ID)
+	}
+
+	if _, err := msg.Write("hi", nil); err != nil {
+		t.Fatal(err)
+	}
+	sent := hs.Sent()
+	if len(sent) != 1 {
+		t.Fatalf("expected 1 sent message, got %d", len(sent))
+	}
+	rel, _ := sent[0].Content["m.relates_to"].(map[string]any)
+	reply, _ := rel["m.in_reply_to"].(map[string]any)
+	if reply["event_id"] != id {
+		t.Errorf("expected a reply to %s, got %v", id, sent[0].Content)
+	}
+}
+
+func TestPowerLevels(t *testing.T) {
+	newHomeserver(t)
+
+	tests := []struct {
+		user  string
+		admin bool
+		mod   bool
+	}{
+		{admin, true, true},
+		{mod, false, true},
+		{user, false, false},
+	}
+
+	for _, test := range tests {
+		a := &Author{UserID: test.user, RoomID: room}
+		if a.Admin() != test.admin {
+			t.Errorf("%s: expected admin to be %v", test.user, test.admin)
+		}
+		if a.Mod() != test.mod {
+			t.Errorf("%s: expected mod to be %v", test.user, test.mod)
+		}
+	}
+}
+
+func TestSpaces(t *testing.T) {
+	newHomeserver(t)
+
+	tests := []struct {
+		id    string
+		room  string
+		space string
+	}{
+		{room, room, space},
+		{dm, dm, ""},
+		{space, "", space},
+	}
+
+	for _, test := range tests {
+		r, s, err := getRoomSpaceIDs(test.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r != test.room || s != test.space {
+			t.Errorf("%s: expected room %q and space %q, got %q and %q", test.id, test.room, test.space, r, s)
+		}
+	}
+}
+
+func TestInvites(t *testing.T) {
+	hs := newHomeserver(t)
+
+	hs.Invite("!new:localhost")
+	resp, err := matrixClient.Sync(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrixClient.handle(resp, true)
+
+	if !hs.Joined("!new:localhost") {
+		t.Error("expected the invite to be accepted")
+	}
+}
@@ -0,0 +1,119 @@
+package matrix
+
+import (
+	"database/sql"
+
+	"github.com/janitorjeff/jeff-bot/core"
+)
+
+// getRoomSpaceIDs returns the room and the space that id refers to. If id is
+// a space then the room is empty, if it's a room that isn't part of a space
+// then the space is empty.
+func getRoomSpaceIDs(id string) (string, string, error) {
+	if matrixClient.IsSpace(id) {
+		return "", id, nil
+	}
+	space, err := matrixClient.Space(id)
+	if err != nil {
+		return "", "", err
+	}
+	return id, space, nil
+}
+
+func getSpaceScope(tx *sql.Tx, id string) (int64, error) {
+	if space, err := dbGetSpaceScope(id); err == nil {
+		return space, nil
+	}
+	return dbAddSpaceScope(tx, id)
+}
+
+func getRoomScope(tx *sql.Tx, id string, space int64) (int64, error) {
+	if room, err := dbGetRoomScope(id); err == nil {
+		return room, nil
+	}
+	return dbAddRoomScope(tx, id, space)
+}
+
+// getPlaceScopes returns both the space's and the room's scopes, creating
+// them if needed. Rooms that aren't part of a space belong to the special
+// empty space, the same way discord DMs belong to the empty guild. If id is
+// a space then the room's scope is -1.
+func getPlaceScopes(id string) (int64, int64, string, error) {
+	roomID, spaceID, err := getRoomSpaceIDs(id)
+	if err != nil {
+		return -1, -1, "", err
+	}
+
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return -1, -1, "", err
+	}
+	defer tx.Rollback()
+
+	space, err := getSpaceScope(tx, spaceID)
+	if err != nil {
+		return -1, -1, "", err
+	}
+
+	if roomID == "" {
+		return space, -1, spaceID, tx.Commit()
+	}
+
+	room, err := getRoomScope(tx, roomID, space)
+	if err != nil {
+		return -1, -1, "", err
+	}
+
+	return space, room, spaceID, tx.Commit()
+}
+
+func getPlaceExactScope(id string) (int64, error) {
+	space, room, _, err := getPlaceScopes(id)
+	if err != nil {
+		return -1, err
+	}
+	if room == -1 {
+		return space, nil
+	}
+	return room, nil
+}
+
+// getPlaceLogicalScope returns the space's scope, unless the room isn't part
+// of one in which case the room's scope is returned instead.
+func getPlaceLogicalScope(id string) (int64, error) {
+	space, room, spaceID, err := getPlaceScopes(id)
+	if err != nil {
+		return -1, err
+	}
+	if spaceID == "" {
+		return room, nil
+	}
+	return space, nil
+}
+
+func getUserScope(id string) (int64, error) {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	if user, err := dbGetUserScope(id); err == nil {
+		return user, nil
+	}
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return -1, err
+	}
+	defer tx.Rollback()
+
+	user, err := dbAddUserScope(tx, id)
+	if err != nil {
+		return -1, err
+	}
+
+	return user, tx.Commit()
+}
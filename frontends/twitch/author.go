@@ -54,9 +54,7 @@ func (a Author) Subscriber() bool {
 }
 
 func (a Author) Scope() (int64, error) {
-	rdbKey := "frontend_twitch_scope_" + a.ID()
-
-	return core.CacheScope(rdbKey, func() (int64, error) {
+	return core.CacheScope(scopeKey(a.ID()), func() (int64, error) {
 		return dbAddChannel(a.ID(), a.User, nil)
 	})
 }
@@ -0,0 +1,129 @@
+package twitch
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrAlreadyJoined = errors.New("The bot is already in that channel.")
+	ErrNotJoined     = errors.New("The bot is not in that channel.")
+)
+
+// channel returns the channel's ID, its scope and its name as twitch reports
+// it. The name is case insensitive and may be prefixed with an @.
+func channel(name string) (string, int64, string, error) {
+	name = strings.ToLower(strings.TrimPrefix(name, "@"))
+
+	h, err := HelixApp()
+	if err != nil {
+		return "", -1, "", err
+	}
+
+	id, err := h.GetUserID(name)
+	if err != nil {
+		return "", -1, "", err
+	}
+
+	scope, err := dbAddChannelSimple(id, name)
+	return id, scope, name, err
+}
+
+// Join makes the bot join the specified channel and saves it in the database
+// so that it is re-joined the next time the bot starts up. Returns
+// ErrAlreadyJoined if the bot is already in the channel and ErrNoResults if no
+// such channel exists.
+func Join(name string) (error, error) {
+	_, scope, name, err := channel(name)
+	if err == ErrNoResults {
+		return ErrNoResults, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	joined, err := dbGetJoined(scope)
+	if err != nil {
+		return nil, err
+	}
+	if joined {
+		return ErrAlreadyJoined, nil
+	}
+
+	if err := dbSetJoined(scope, true); err != nil {
+		return nil, err
+	}
+
+	twitchIrcClient.Join(name)
+	log.Debug().Str("channel", name).Msg("joined twitch channel")
+
+	return nil, nil
+}
+
+// Part makes the bot leave the specified channel and removes it from the list
+// of channels that are joined on start up. Returns ErrNotJoined if the bot is
+// not in the channel and ErrNoResults if no such channel exists.
+func Part(name string) (error, error) {
+	_, scope, name, err := channel(name)
+	if err == ErrNoResults {
+		return ErrNoResults, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	joined, err := dbGetJoined(scope)
+	if err != nil {
+		return nil, err
+	}
+	if !joined {
+		return ErrNotJoined, nil
+	}
+
+	if err := dbSetJoined(scope, false); err != nil {
+		return nil, err
+	}
+
+	twitchIrcClient.Depart(name)
+	log.Debug().Str("channel", name).Msg("parted twitch channel")
+
+	return nil, nil
+}
+
+// Joined returns the names of all the channels the bot is in.
+func Joined() ([]string, error) {
+	return dbListJoined()
+}
+
+// ChannelName returns the name of the channel or user with the specified
+// scope.
+func ChannelName(scope int64) (string, error) {
+	_, name, err := dbGetChannel(scope)
+	return name, err
+}
+
+// seed saves the channels that were passed through the config as joined
+// channels. Only channels that have never been joined or parted are seeded, so
+// that a channel that was parted isn't joined again on the next start up.
+func seed(channels []string) {
+	for _, name := range channels {
+		if name == "" {
+			continue
+		}
+
+		_, scope, name, err := channel(name)
+		if err != nil {
+			log.Debug().Err(err).Str("channel", name).Msg("failed to seed channel")
+			continue
+		}
+
+		seeded, err := dbSeedJoined(scope)
+		log.Debug().
+			Err(err).
+			Bool("seeded", seeded).
+			Str("channel", name).
+			Msg("seeded channel")
+	}
+}
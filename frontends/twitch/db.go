@@ -4,6 +4,7 @@ import (
 	"github.com/janitorjeff/jeff-bot/core"
 
 	tirc "github.com/gempir/go-twitch-irc/v4"
+	"github.com/rs/zerolog/log"
 )
 
 func dbAddChannelSimple(uid, uname string) (int64, error) {
@@ -56,7 +57,11 @@ func dbAddChannel(id string, u tirc.User, h *Helix) (int64, error) {
 		return -1, err
 	}
 
-	return scope, tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return -1, err
+	}
+	// a previous scope of the channel might still be cached
+	return scope, core.CacheInvalidate(scopeKey(channelID))
 }
 
 func dbGetChannelScope(channelID string) (int64, error) {
@@ -139,3 +144,89 @@ func dbGetetUserRefreshToken(accessToken string) (string, error) {
 	err := row.Scan(&refreshToken)
 	return refreshToken, err
 }
+
+func dbSetJoined(scope int64, joined bool) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		UPDATE frontend_twitch_channels
+		SET joined = $1
+		WHERE scope = $2`, joined, scope)
+
+	log.Debug().
+		Err(err).
+		Int64("scope", scope).
+		Bool("joined", joined).
+		Msg("changed channel's joined state")
+
+	return err
+}
+
+// dbSeedJoined marks the channel as joined only if it has never been joined
+// or parted before, returns whether it was marked.
+func dbSeedJoined(scope int64) (bool, error) {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	res, err := db.DB.Exec(`
+		UPDATE frontend_twitch_channels
+		SET joined = TRUE
+		WHERE scope = $1 AND joined IS NULL`, scope)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func dbGetJoined(scope int64) (bool, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	row := db.DB.QueryRow(`
+		SELECT COALESCE(joined, FALSE)
+		FROM frontend_twitch_channels
+		WHERE scope = $1`, scope)
+
+	var joined bool
+	err := row.Scan(&joined)
+	return joined, err
+}
+
+func dbListJoined() ([]string, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	rows, err := db.DB.Query(`
+		SELECT channel_name
+		FROM frontend_twitch_channels
+		WHERE joined = $1`, true)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var channels []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		channels = append(channels, name)
+	}
+
+	err = rows.Err()
+
+	log.Debug().
+		Err(err).
+		Strs("channels", channels).
+		Msg("got joined channels")
+
+	return channels, err
+}
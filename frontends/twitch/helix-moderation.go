@@ -0,0 +1,216 @@
+package twitch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nicklaw5/helix"
+	"github.com/rs/zerolog/log"
+)
+
+// The version of the helix library that is used doesn't support any of the
+// moderation endpoints, so we make the requests ourselves. Since the
+// broadcaster's token is used, the broadcaster is also the moderator.
+
+const helixURL = "https://api.twitch.tv/helix"
+
+// MaxTimeout is the longest timeout twitch allows.
+const MaxTimeout = 14 * 24 * time.Hour
+
+var (
+	ErrReconnectRequired = errors.New("This channel's broadcaster must reconnect their twitch account to the bot to allow this.")
+	ErrAlreadyBanned     = errors.New("That person is already banned.")
+	ErrNotBanned         = errors.New("That person isn't banned.")
+	ErrCannotBan         = errors.New("That person can't be banned or timed out, for example because they are a moderator.")
+)
+
+// moderationError returns the user error that corresponds to an unsuccessful
+// response if it's an expected one, e.g. trying to ban someone twice.
+func moderationError(resp helix.ResponseCommon) error {
+	msg := strings.ToLower(resp.ErrorMessage)
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		// refreshing the token doesn't add any scopes, the broadcaster
+		// has to connect again so that the new ones are granted
+		if strings.Contains(msg, "missing scope") {
+			return ErrReconnectRequired
+		}
+	case http.StatusBadRequest:
+		switch {
+		case strings.Contains(msg, "already banned"):
+			return ErrAlreadyBanned
+		case strings.Contains(msg, "is not banned"):
+			return ErrNotBanned
+		case strings.Contains(msg, "may not be banned"):
+			return ErrCannotBan
+		}
+	}
+	return nil
+}
+
+// request makes the request, if out isn't nil then a successful response's
+// body is decoded into it.
+func (h *Helix) request(method, path string, query url.Values, body, out any) (helix.ResponseCommon, error) {
+	var resp helix.ResponseCommon
+
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return resp, err
+		}
+	}
+
+	u := fmt.Sprintf("%s%s?%s", helixURL, path, query.Encode())
+	req, err := http.NewRequest(method, u, &buf)
+	if err != nil {
+		return resp, err
+	}
+	req.Header.Set("Client-Id", ClientID)
+	req.Header.Set("Authorization", "Bearer "+h.c.GetUserAccessToken())
+	req.Header.Set("Content-Type", "application/json")
+
+	r, err := httpClient.Do(req)
+	if err != nil {
+		return resp, err
+	}
+	defer r.Body.Close()
+
+	resp.StatusCode = r.StatusCode
+	resp.Header = r.Header
+
+	if r.StatusCode < 200 || r.StatusCode >= 300 {
+		json.NewDecoder(r.Body).Decode(&resp)
+	} else if out != nil {
+		if err := json.NewDecoder(r.Body).Decode(out); err != nil {
+			return resp, err
+		}
+	}
+
+	log.Debug().
+		Str("method", method).
+		Str("path", path).
+		Int("status", r.StatusCode).
+		Str("error", resp.ErrorMessage).
+		Msg("made helix moderation request")
+
+	return resp, nil
+}
+
+// moderate makes a request that requires the broadcaster's user access token.
+// Returns ErrUserTokenRequired as a user error if the broadcaster hasn't
+// connected their account and ErrReconnectRequired if the token doesn't work
+// even after being refreshed.
+func (h *Helix) moderate(method, path string, query url.Values, body any) (error, error) {
+	return h.moderateRetry(method, path, query, body, nil, true)
+}
+
+// moderateRetry is the same as moderate but decodes a successful response
+// into out, if it isn't nil. The token is only refreshed if retry is true.
+func (h *Helix) moderateRetry(method, path string, query url.Values, body, out any, retry bool) (error, error) {
+	if h.c.GetUserAccessToken() == "" {
+		return ErrUserTokenRequired, nil
+	}
+
+	resp, err := h.request(method, path, query, body, out)
+	if err == nil {
+		if usrErr := moderationError(resp); usrErr != nil {
+			return usrErr, nil
+		}
+	}
+	err = checkErrors(err, resp, 1)
+
+	switch err {
+	case ErrRetry:
+		if !retry {
+			return ErrReconnectRequired, nil
+		}
+		if err := h.refreshToken(); err != nil {
+			return nil, err
+		}
+		return h.moderateRetry(method, path, query, body, out, false)
+
+	default:
+		return nil, err
+	}
+}
+
+func moderationQuery(broadcasterID string) url.Values {
+	q := url.Values{}
+	q.Set("broadcaster_id", broadcasterID)
+	q.Set("moderator_id", broadcasterID)
+	return q
+}
+
+// IsModerator returns whether the user is one of the broadcaster's moderators.
+// Returns ErrUserTokenRequired as a user error if the broadcaster hasn't
+// connected their account, since only they can see the list.
+func (h *Helix) IsModerator(broadcasterID, userID string) (bool, error, error) {
+	q := url.Values{}
+	q.Set("broadcaster_id", broadcasterID)
+	q.Set("user_id", userID)
+
+	var out struct {
+		Data []struct {
+			UserID string `json:"user_id"`
+		} `json:"data"`
+	}
+	usrErr, err := h.moderateRetry(http.MethodGet, "/moderation/moderators", q, nil, &out, true)
+	if usrErr != nil || err != nil {
+		return false, usrErr, err
+	}
+	// an empty list just means that the user isn't a moderator
+	return len(out.Data) != 0, nil, nil
+}
+
+// BanUser bans the user from the broadcaster's chat. If dur is zero then the
+// ban is permanent, otherwise the user is timed out for that long.
+func (h *Helix) BanUser(broadcasterID, userID string, dur time.Duration, reason string) (error, error) {
+	data := map[string]any{
+		"user_id": userID,
+		"reason":  reason,
+	}
+	if dur != 0 {
+		data["duration"] = int(dur.Seconds())
+	}
+	body := map[string]any{"data": data}
+	return h.moderate(http.MethodPost, "/moderation/bans", moderationQuery(broadcasterID), body)
+}
+
+// UnbanUser removes the user's ban or timeout.
+func (h *Helix) UnbanUser(broadcasterID, userID string) (error, error) {
+	q := moderationQuery(broadcasterID)
+	q.Set("user_id", userID)
+	return h.moderate(http.MethodDelete, "/moderation/bans", q, nil)
+}
+
+// DeleteChatMessages deletes the specified message, if msgID is empty then all
+// the messages in the broadcaster's chat are deleted.
+func (h *Helix) DeleteChatMessages(broadcasterID, msgID string) (error, error) {
+	q := moderationQuery(broadcasterID)
+	if msgID != "" {
+		q.Set("message_id", msgID)
+	}
+	return h.moderate(http.MethodDelete, "/moderation/chat", q, nil)
+}
+
+// ChatSettings holds the chat settings that can be changed. Only non-nil
+// fields are sent.
+type ChatSettings struct {
+	SlowMode             *bool `json:"slow_mode,omitempty"`
+	SlowModeWaitTime     *int  `json:"slow_mode_wait_time,omitempty"`
+	FollowerMode         *bool `json:"follower_mode,omitempty"`
+	FollowerModeDuration *int  `json:"follower_mode_duration,omitempty"`
+	EmoteMode            *bool `json:"emote_mode,omitempty"`
+}
+
+// UpdateChatSettings changes the broadcaster's chat settings.
+func (h *Helix) UpdateChatSettings(broadcasterID string, settings ChatSettings) (error, error) {
+	return h.moderate(http.MethodPatch, "/chat/settings", moderationQuery(broadcasterID), settings)
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/janitorjeff/jeff-bot/core"
+
 	"github.com/janitorjeff/gosafe"
 	"github.com/nicklaw5/helix"
 	"github.com/rs/zerolog/log"
@@ -18,10 +20,15 @@ var (
 
 var appAccessToken = gosafe.Value[string]{}
 
+// httpClient is used for all requests to helix so that they are recorded in
+// the metrics.
+var httpClient = core.APIClient("helix")
+
 func generateAppAccessToken() error {
 	client, err := helix.NewClient(&helix.Options{
 		ClientID:     ClientID,
 		ClientSecret: ClientSecret,
+		HTTPClient:   httpClient,
 	})
 	if err != nil {
 		return err
@@ -40,6 +47,7 @@ func refreshUserAccessToken(accessToken string) (string, error) {
 	client, err := helix.NewClient(&helix.Options{
 		ClientID:     ClientID,
 		ClientSecret: ClientSecret,
+		HTTPClient:   httpClient,
 	})
 	if err != nil {
 		return "", err
@@ -75,6 +83,42 @@ type Helix struct {
 	c *helix.Client
 }
 
+// HelixApp returns a Helix client that uses the app access token. Can be used
+// for requests that don't require any special permissions from a broadcaster.
+func HelixApp() (*Helix, error) {
+	h, err := helix.NewClient(&helix.Options{
+		ClientID:   ClientID,
+		HTTPClient: httpClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	h.SetAppAccessToken(appAccessToken.Get())
+	return &Helix{h}, nil
+}
+
+// HelixChannel returns a Helix client that uses the user access token of the
+// specified channel's broadcaster, if they have connected their account to the
+// bot. Otherwise the app access token is used.
+func HelixChannel(channelID string) (*Helix, error) {
+	h, err := helix.NewClient(&helix.Options{
+		ClientID:   ClientID,
+		HTTPClient: httpClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	userAccessToken, err := dbGetUserAccessToken(channelID)
+	if err == nil {
+		h.SetUserAccessToken(userAccessToken)
+	} else {
+		h.SetAppAccessToken(appAccessToken.Get())
+	}
+
+	return &Helix{h}, nil
+}
+
 // func HelixInit(token string) (*Helix, error) {
 // 	h, err := helix.NewClient(&helix.Options{
 // 		ClientID:        ClientID,
@@ -18,13 +18,17 @@ func (h Here) Name() string {
 }
 
 func (h Here) Scope() (int64, error) {
-	rdbKey := "frontend_twitch_scope_" + h.ID()
-
-	return core.CacheScope(rdbKey, func() (int64, error) {
+	return core.CacheScope(scopeKey(h.ID()), func() (int64, error) {
 		return dbAddChannelSimple(h.ID(), h.Name())
 	})
 }
 
+// scopeKey is the cache key of the scope of the channel with the given ID,
+// people are also channels.
+func scopeKey(id string) string {
+	return "frontend_twitch_scope_" + id
+}
+
 func (h Here) ScopeExact() (int64, error) {
 	return h.Scope()
 }
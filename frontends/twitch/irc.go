@@ -4,15 +4,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"regexp"
 	"strconv"
 	"strings"
-	"sync"
 	"unicode/utf8"
 
 	"github.com/janitorjeff/jeff-bot/core"
 
 	tirc "github.com/gempir/go-twitch-irc/v4"
-	"github.com/nicklaw5/helix"
 	"github.com/rs/zerolog/log"
 )
 
@@ -53,39 +52,98 @@ func (f *frontend) Type() core.FrontendType {
 	return Type
 }
 
-func (f *frontend) Init(wgInit, wgStop *sync.WaitGroup, stop chan struct{}) {
-	twitchIrcClient = tirc.NewClient(f.Nick, f.OAuth)
+func (f *frontend) Name() string {
+	return "twitch"
+}
 
-	twitchIrcClient.OnPrivateMessage(onPrivateMessage)
+// MentionPattern returns a regular expression that matches the bot's
+// mention, which works as a prefix everywhere. Names are case insensitive
+// on twitch, and the word boundary stops longer names that begin with the
+// bot's from matching.
+func (f *frontend) MentionPattern() string {
+	if f.Nick == "" {
+		return ""
+	}
+	return `(?i)@` + regexp.QuoteMeta(f.Nick) + `\b[,:]?`
+}
 
-	twitchIrcClient.Join(f.Channels...)
+// Permissions returns whether the person is a moderator and whether they are
+// the broadcaster in the channel. Moderators can only be checked if the
+// broadcaster has connected their account, otherwise nobody else is
+// considered one.
+func (f *frontend) Permissions(person, channel int64) (bool, bool, error) {
+	if person == channel {
+		return true, true, nil
+	}
 
-	log.Debug().Msg("connecting to twitch irc")
-	var err error
-	// FIXME: Race condition??? (err doesn't have time to be set)
-	go func() {
-		err = twitchIrcClient.Connect()
-	}()
+	personID, _, err := dbGetChannel(person)
 	if err != nil {
-		log.Fatal().Err(err).Msg("failed to connect to twitch irc")
-	} else {
-		log.Debug().Msg("connected to twitch irc")
+		return false, false, err
+	}
+	channelID, _, err := dbGetChannel(channel)
+	if err != nil {
+		return false, false, err
 	}
 
+	h, err := HelixChannel(channelID)
+	if err != nil {
+		return false, false, err
+	}
+	mod, usrErr, err := h.IsModerator(channelID, personID)
+	if usrErr != nil {
+		return false, false, nil
+	}
+	return mod, false, err
+}
+
+func (f *frontend) Init(connected func(bool), stop chan struct{}) error {
+	client := tirc.NewClient(f.Nick, f.OAuth)
+
+	client.OnPrivateMessage(onPrivateMessage)
+	client.OnConnect(func() {
+		log.Debug().Msg("connected to twitch irc")
+		connected(true)
+	})
+	// twitch asks clients to reconnect every now and then, the client does
+	// so by itself and OnConnect gets called again once it's done
+	client.OnReconnectMessage(func(tirc.ReconnectMessage) {
+		connected(false)
+	})
+
+	twitchIrcClient = client
+
 	if err := generateAppAccessToken(); err != nil {
-		panic(err)
+		return err
+	}
+
+	// The channels passed through the config are only used to seed the
+	// database, the list of channels to join is always read from there.
+	seed(f.Channels)
+	channels, err := Joined()
+	if err != nil {
+		return err
 	}
+	client.Join(channels...)
+
+	log.Debug().Msg("connecting to twitch irc")
+	errs := make(chan error, 1)
+	go func() {
+		errs <- client.Connect()
+	}()
 
-	wgInit.Done()
-	<-stop
+	select {
+	case err := <-errs:
+		return err
+	case <-stop:
+	}
 
 	log.Debug().Msg("closing twitch irc")
-	if err = twitchIrcClient.Disconnect(); err != nil {
+	if err := client.Disconnect(); err != nil {
 		log.Debug().Err(err).Msg("failed to close twitch irc connection")
 	} else {
 		log.Debug().Msg("closed twitch irc")
 	}
-	wgStop.Done()
+	return nil
 }
 
 func (f *frontend) CreateMessage(person, place int64, _ string) (*core.Message, error) {
@@ -118,21 +176,16 @@ func (f *frontend) CreateMessage(person, place int64, _ string) (*core.Message,
 var twitchIrcClient *tirc.Client
 
 func (t *Twitch) Helix() (*Helix, error) {
-	h, err := helix.NewClient(&helix.Options{
-		ClientID: ClientID,
-	})
-	if err != nil {
-		return nil, err
-	}
+	return HelixChannel(t.message.RoomID)
+}
 
-	userAccessToken, err := dbGetUserAccessToken(t.message.RoomID)
-	if err == nil {
-		h.SetUserAccessToken(userAccessToken)
-	} else {
-		h.SetAppAccessToken(appAccessToken.Get())
+// Emotes returns the number of emotes used in the message.
+func (t *Twitch) Emotes() int {
+	var count int
+	for _, e := range t.message.Emotes {
+		count += e.Count
 	}
-
-	return &Helix{h}, nil
+	return count
 }
 
 ///////////////
@@ -16,6 +16,36 @@ func NewState() (string, error) {
 	return states.New()
 }
 
+// logins are the states of the authorizations that are only used to find out
+// who the user is, their access token is not saved.
+var logins = &core.States{}
+
+// OnLogin is called with the user's channel scope once they log in using the
+// URL returned by LoginURL, it is responsible for writing the response.
+var OnLogin func(c *gin.Context, scope int64)
+
+// LoginURL returns the address at which a user can log in with their twitch
+// account, no extra permissions are requested.
+func LoginURL() (string, error) {
+	c, err := helix.NewClient(&helix.Options{
+		ClientID:    ClientID,
+		RedirectURI: "https://" + core.VirtualHost + "/twitch/callback",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	state, err := logins.New()
+	if err != nil {
+		return "", err
+	}
+
+	return c.GetAuthorizationURL(&helix.AuthorizationURLParams{
+		ResponseType: "code",
+		State:        state,
+	}), nil
+}
+
 func init() {
 	callback := "/twitch/callback"
 
@@ -33,12 +63,15 @@ func init() {
 		}
 		state := statesQuery[0]
 
-		if !states.In(state) {
+		login := logins.In(state)
+
+		if !login && !states.In(state) {
 			log.Debug().Str("state", state).Msg("got unexpected state")
 			fail(c)
 			return
 		}
 		states.Delete(state)
+		logins.Delete(state)
 
 		codes, ok := q["code"]
 		if !ok || len(codes) == 0 {
@@ -51,6 +84,7 @@ func init() {
 			ClientID:     ClientID,
 			ClientSecret: ClientSecret,
 			RedirectURI:  "https://" + core.VirtualHost + callback,
+			HTTPClient:   httpClient,
 		})
 		if err != nil {
 			log.Debug().Err(err).Send()
@@ -88,6 +122,15 @@ func init() {
 			return
 		}
 
+		if login {
+			if OnLogin == nil {
+				fail(c)
+				return
+			}
+			OnLogin(c, scope)
+			return
+		}
+
 		err = dbSetUserAccessToken(scope, accessToken, refreshToken)
 		if err != nil {
 			log.Debug().Err(err).Send()
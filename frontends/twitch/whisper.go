@@ -0,0 +1,68 @@
+package twitch
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/janitorjeff/gosafe"
+	"github.com/nicklaw5/helix"
+)
+
+var ErrWhisperFailed = errors.New("Couldn't whisper them, they might have whispers from strangers blocked.")
+
+// botID is the bot's user ID, it's looked up the first time it's needed.
+var botID = gosafe.Value[string]{}
+
+// helixBot returns a Helix client that uses the bot's own token, the same one
+// that is used to connect to chat.
+func helixBot() (*Helix, error) {
+	h, err := helix.NewClient(&helix.Options{
+		ClientID:   ClientID,
+		HTTPClient: httpClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	h.SetUserAccessToken(strings.TrimPrefix(Frontend.OAuth, "oauth:"))
+	return &Helix{h}, nil
+}
+
+// Whisper sends a whisper from the bot to the user. The bot's token needs the
+// user:manage:whispers scope. Returns ErrWhisperFailed as a user error if
+// twitch refuses to deliver it, since that depends on the user's settings.
+func Whisper(userID, text string) (error, error) {
+	id := botID.Get()
+	if id == "" {
+		h, err := HelixApp()
+		if err != nil {
+			return nil, err
+		}
+		if id, err = h.GetUserID(Frontend.Nick); err != nil {
+			return nil, err
+		}
+		botID.Set(id)
+	}
+
+	h, err := helixBot()
+	if err != nil {
+		return nil, err
+	}
+
+	q := url.Values{}
+	q.Set("from_user_id", id)
+	q.Set("to_user_id", userID)
+	body := map[string]string{"message": text}
+
+	resp, err := h.request(http.MethodPost, "/whispers", q, body, nil)
+	if err != nil {
+		return nil, err
+	}
+	// the bot's token can't be refreshed, so a 401 is treated the same as
+	// the user not accepting whispers
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+		return ErrWhisperFailed, nil
+	}
+	return nil, checkErrors(nil, resp, 1)
+}
@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"fmt"
+
+	"github.com/janitorjeff/jeff-bot/core"
+)
+
+// Author implements the core.Author interface. Clients are trusted, so the
+// permissions are whatever the client says they are.
+type Author struct {
+	Client int64
+
+	PersonID     string
+	Username     string
+	Display      string
+	IsAdmin      bool
+	IsMod        bool
+	IsSubscriber bool
+}
+
+func (a *Author) ID() string {
+	return a.PersonID
+}
+
+func (a *Author) Name() string {
+	if a.Username == "" {
+		return a.PersonID
+	}
+	return a.Username
+}
+
+func (a *Author) DisplayName() string {
+	if a.Display == "" {
+		return a.Name()
+	}
+	return a.Display
+}
+
+func (a *Author) Mention() string {
+	return "@" + a.DisplayName()
+}
+
+func (a *Author) BotAdmin() bool {
+	return false
+}
+
+func (a *Author) Admin() bool {
+	return a.IsAdmin
+}
+
+func (a *Author) Mod() bool {
+	return a.IsAdmin || a.IsMod
+}
+
+func (a *Author) Subscriber() bool {
+	return a.IsSubscriber
+}
+
+func (a *Author) Scope() (int64, error) {
+	return core.CacheScope(personKey(a.Client, a.ID()), func() (int64, error) {
+		return dbAddPerson(a.Client, a.ID())
+	})
+}
+
+func personKey(client int64, id string) string {
+	return fmt.Sprintf("frontend_webhook_scope_person_%d_%s", client, id)
+}
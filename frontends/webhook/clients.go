@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/core"
+)
+
+var (
+	ErrInvalidToken    = errors.New("invalid token")
+	ErrClientExists    = errors.New("a client with that name already exists")
+	ErrClientNotFound  = errors.New("no client with that name exists")
+	ErrInvalidCallback = errors.New("the callback must be a valid http or https URL")
+)
+
+// Client is an integration that is allowed to run commands. Each client has
+// its own people and places, so the IDs they use can't clash with another
+// client's.
+type Client struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Callback string `json:"callback,omitempty"`
+	Created  int64  `json:"created"`
+
+	// the token's hash, which is also the key used to sign the callbacks
+	hash string
+}
+
+// Tokens are saved hashed so that a leaked database doesn't leak them as
+// well, since they are long and random a plain sha256 is enough.
+func hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Register adds a new client and returns its token, which can't be retrieved
+// later. Callback can be empty, in which case any replies that aren't direct
+// responses to a request, e.g. reminders, are dropped.
+func Register(name, callback string) (string, error, error) {
+	if callback != "" && !core.IsValidURL(callback) {
+		return "", ErrInvalidCallback, nil
+	}
+	if _, err := dbGetClientByName(name); err == nil {
+		return "", ErrClientExists, nil
+	} else if err != sql.ErrNoRows {
+		return "", nil, err
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	return token, nil, dbAddClient(name, hash(token), callback, time.Now().UTC().Unix())
+}
+
+// Authenticate returns the client the token belongs to, returns
+// ErrInvalidToken if it doesn't exist.
+func Authenticate(token string) (Client, error, error) {
+	c, err := dbGetClientByHash(hash(token))
+	if err == sql.ErrNoRows {
+		return Client{}, ErrInvalidToken, nil
+	}
+	if err != nil {
+		return Client{}, nil, err
+	}
+	return c, nil, nil
+}
+
+// Delete deletes the client along with its people and places.
+func Delete(name string) (error, error) {
+	n, err := dbDeleteClient(name)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return ErrClientNotFound, nil
+	}
+	return nil, nil
+}
+
+// List returns all of the clients, sorted by name.
+func List() ([]Client, error) {
+	return dbListClients()
+}
+
+// SetCallback changes the client's callback URL, an empty one disables it.
+func SetCallback(client int64, callback string) (error, error) {
+	if callback != "" && !core.IsValidURL(callback) {
+		return ErrInvalidCallback, nil
+	}
+	return nil, dbSetCallback(client, callback)
+}
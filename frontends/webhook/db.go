@@ -0,0 +1,278 @@
+package webhook
+
+import (
+	"fmt"
+
+	"github.com/janitorjeff/jeff-bot/core"
+)
+
+/////////////
+//         //
+// clients //
+//         //
+/////////////
+
+func dbAddClient(name, hash, callback string, created int64) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		INSERT INTO frontend_webhook_clients(name, hash, callback, created)
+		VALUES ($1, $2, $3, $4)`, name, hash, callback, created)
+	return err
+}
+
+func scanClient(row interface{ Scan(...any) error }) (Client, error) {
+	var c Client
+	err := row.Scan(&c.ID, &c.Name, &c.hash, &c.Callback, &c.Created)
+	return c, err
+}
+
+func dbGetClientByHash(hash string) (Client, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	row := db.DB.QueryRow(`
+		SELECT id, name, hash, callback, created
+		FROM frontend_webhook_clients
+		WHERE hash = $1`, hash)
+	return scanClient(row)
+}
+
+func dbGetClientByName(name string) (Client, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	row := db.DB.QueryRow(`
+		SELECT id, name, hash, callback, created
+		FROM frontend_webhook_clients
+		WHERE name = $1`, name)
+	return scanClient(row)
+}
+
+func dbGetClient(id int64) (Client, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	row := db.DB.QueryRow(`
+		SELECT id, name, hash, callback, created
+		FROM frontend_webhook_clients
+		WHERE id = $1`, id)
+	return scanClient(row)
+}
+
+func dbListClients() ([]Client, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	rows, err := db.DB.Query(`
+		SELECT id, name, hash, callback, created
+		FROM frontend_webhook_clients
+		ORDER BY name ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var clients []Client
+	for rows.Next() {
+		c, err := scanClient(rows)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, c)
+	}
+	return clients, rows.Err()
+}
+
+// dbDeleteClient deletes the client and the scopes of its people and places,
+// which in turn deletes everything else that references them. The cached
+// scopes are invalidated so that they get re-created if the client is
+// registered again.
+func dbDeleteClient(name string) (int64, error) {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT c.id, 'person', p.person
+		FROM frontend_webhook_clients c
+		JOIN frontend_webhook_people p ON p.client = c.id
+		WHERE c.name = $1
+		UNION ALL
+		SELECT c.id, 'place', p.place
+		FROM frontend_webhook_clients c
+		JOIN frontend_webhook_places p ON p.client = c.id
+		WHERE c.name = $1`, name)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var client int64
+		var kind, id string
+		if err := rows.Scan(&client, &kind, &id); err != nil {
+			return 0, err
+		}
+		if kind == "person" {
+			keys = append(keys, personKey(client, id))
+		} else {
+			keys = append(keys, placeKey(client, id))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM scopes
+		WHERE id IN (
+			SELECT scope FROM frontend_webhook_people WHERE client = (SELECT id FROM frontend_webhook_clients WHERE name = $1)
+			UNION
+			SELECT scope FROM frontend_webhook_places WHERE client = (SELECT id FROM frontend_webhook_clients WHERE name = $1)
+		)`, name)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := tx.Exec(`
+		DELETE FROM frontend_webhook_clients
+		WHERE name = $1`, name)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, core.CacheInvalidate(keys...)
+}
+
+func dbSetCallback(client int64, callback string) error {
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	_, err := db.DB.Exec(`
+		UPDATE frontend_webhook_clients
+		SET callback = $1
+		WHERE id = $2`, callback, client)
+	return err
+}
+
+////////////
+//        //
+// scopes //
+//        //
+////////////
+
+// The tables and columns passed to these are never user input.
+
+// dbAddScope returns the scope of the client's person or place, creating it
+// if it doesn't exist.
+func dbAddScope(table, column string, client int64, id string) (int64, error) {
+	if scope, err := dbGetScope(table, column, client, id); err == nil {
+		return scope, nil
+	}
+
+	db := core.DB
+	db.Lock.Lock()
+	defer db.Lock.Unlock()
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return -1, err
+	}
+	defer tx.Rollback()
+
+	// the id alone isn't unique across clients
+	scope, err := db.ScopeAdd(tx, fmt.Sprintf("%d:%s", client, id), Type)
+	if err != nil {
+		return -1, err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO `+table+`(scope, client, `+column+`)
+		VALUES ($1, $2, $3)`, scope, client, id)
+	if err != nil {
+		return -1, err
+	}
+
+	return scope, tx.Commit()
+}
+
+func dbGetScope(table, column string, client int64, id string) (int64, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	row := db.DB.QueryRow(`
+		SELECT scope
+		FROM `+table+`
+		WHERE client = $1 AND `+column+` = $2`, client, id)
+
+	var scope int64
+	err := row.Scan(&scope)
+	return scope, err
+}
+
+// dbGetID returns the client and the ID of the person or place that the scope
+// belongs to.
+func dbGetID(table, column string, scope int64) (int64, string, error) {
+	db := core.DB
+	db.Lock.RLock()
+	defer db.Lock.RUnlock()
+
+	row := db.DB.QueryRow(`
+		SELECT client, `+column+`
+		FROM `+table+`
+		WHERE scope = $1`, scope)
+
+	var client int64
+	var id string
+	err := row.Scan(&client, &id)
+	return client, id, err
+}
+
+func dbAddPerson(client int64, id string) (int64, error) {
+	return dbAddScope("frontend_webhook_people", "person", client, id)
+}
+
+func dbAddPlace(client int64, id string) (int64, error) {
+	return dbAddScope("frontend_webhook_places", "place", client, id)
+}
+
+func dbPersonExists(client int64, id string) bool {
+	_, err := dbGetScope("frontend_webhook_people", "person", client, id)
+	return err == nil
+}
+
+func dbPlaceExists(client int64, id string) bool {
+	_, err := dbGetScope("frontend_webhook_places", "place", client, id)
+	return err == nil
+}
+
+func dbGetPerson(scope int64) (int64, string, error) {
+	return dbGetID("frontend_webhook_people", "person", scope)
+}
+
+func dbGetPlace(scope int64) (int64, string, error) {
+	return dbGetID("frontend_webhook_places", "place", scope)
+}
@@ -0,0 +1,45 @@
+package webhook
+
+import (
+	"fmt"
+
+	"github.com/janitorjeff/jeff-bot/core"
+)
+
+// Here implements the core.Here interface.
+type Here struct {
+	Client int64
+
+	PlaceID   string
+	PlaceName string
+}
+
+func (h *Here) ID() string {
+	return h.PlaceID
+}
+
+func (h *Here) Name() string {
+	if h.PlaceName == "" {
+		return h.PlaceID
+	}
+	return h.PlaceName
+}
+
+func (h *Here) Scope() (int64, error) {
+	return core.CacheScope(placeKey(h.Client, h.ID()), func() (int64, error) {
+		return dbAddPlace(h.Client, h.ID())
+	})
+}
+
+func placeKey(client int64, id string) string {
+	return fmt.Sprintf("frontend_webhook_scope_place_%d_%s", client, id)
+}
+
+// Places have no hierarchy, so the exact and logical scopes are the same.
+func (h *Here) ScopeExact() (int64, error) {
+	return h.Scope()
+}
+
+func (h *Here) ScopeLogical() (int64, error) {
+	return h.Scope()
+}
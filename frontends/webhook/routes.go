@@ -0,0 +1,174 @@
+package webhook
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// The outcomes of a message, returned in the status field of the response.
+const (
+	StatusOK          = "ok"
+	StatusUserError   = "user_error"
+	StatusError       = "error"
+	StatusSilence     = "silence"
+	StatusNotACommand = "not_a_command"
+)
+
+type messageRequest struct {
+	ID     string `json:"id"`
+	Text   string `json:"text" binding:"required"`
+	Author struct {
+		ID          string `json:"id" binding:"required"`
+		Name        string `json:"name"`
+		DisplayName string `json:"display_name"`
+		Admin       bool   `json:"admin"`
+		Mod         bool   `json:"mod"`
+		Subscriber  bool   `json:"subscriber"`
+	} `json:"author" binding:"required"`
+	Place struct {
+		ID   string `json:"id" binding:"required"`
+		Name string `json:"name"`
+	} `json:"place" binding:"required"`
+}
+
+type messageResponse struct {
+	Status  string  `json:"status"`
+	Command *string `json:"command"`
+	Replies []Reply `json:"replies"`
+}
+
+func fail(c *gin.Context, err error) {
+	log.Error().Err(err).Str("path", c.FullPath()).Msg("webhook request failed")
+	c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "something went wrong"})
+}
+
+func client(c *gin.Context) Client {
+	return c.MustGet("client").(Client)
+}
+
+// authenticate only lets requests from registered clients through.
+func authenticate(c *gin.Context) {
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	cl, usrErr, err := Authenticate(token)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if usrErr != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": usrErr.Error()})
+		return
+	}
+	c.Set("client", cl)
+}
+
+// postMessage runs the message as if it was sent in chat and returns all of
+// the replies, including the ones from hooks.
+func postMessage(c *gin.Context) {
+	var req messageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		return
+	}
+
+	cl := client(c)
+	replies := []Reply{}
+	w := &Webhook{
+		client: cl,
+		id:     req.ID,
+		text:   req.Text,
+		author: &Author{
+			Client:       cl.ID,
+			PersonID:     req.Author.ID,
+			Username:     req.Author.Name,
+			Display:      req.Author.DisplayName,
+			IsAdmin:      req.Author.Admin,
+			IsMod:        req.Author.Mod,
+			IsSubscriber: req.Author.Subscriber,
+		},
+		here: &Here{
+			Client:    cl.ID,
+			PlaceID:   req.Place.ID,
+			PlaceName: req.Place.Name,
+		},
+		replies: &replies,
+	}
+
+	msg, err := w.Parse()
+	if err != nil {
+		fail(c, err)
+		return
+	}
+
+	msg.Hooks()
+	_, err = msg.CommandRun()
+
+	resp := messageResponse{Status: StatusOK}
+	switch {
+	case msg.Command == nil:
+		resp.Status = StatusNotACommand
+	case err == core.ErrSilence:
+		resp.Status = StatusSilence
+	case err != nil:
+		resp.Status = StatusError
+		log.Debug().Err(err).Str("client", cl.Name).Msg("webhook command failed")
+	}
+
+	if msg.Command != nil {
+		path := strings.Join(msg.Command.Path, " ")
+		resp.Command = &path
+	}
+
+	// anything sent from now on, e.g. by a timer, is delivered to the
+	// callback since the response has already been put together
+	w.lock.Lock()
+	resp.Replies = replies
+	w.replies = nil
+	w.lock.Unlock()
+
+	if resp.Status == StatusOK {
+		for _, r := range resp.Replies {
+			if r.Error {
+				resp.Status = StatusUserError
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func getClient(c *gin.Context) {
+	c.JSON(http.StatusOK, client(c))
+}
+
+func putCallback(c *gin.Context) {
+	var req struct {
+		Callback string `json:"callback"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		return
+	}
+
+	usrErr, err := SetCallback(client(c).ID, req.Callback)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if usrErr != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": usrErr.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func init() {
+	v1 := core.Gin.Group("/webhook/v1", authenticate)
+	v1.GET("/client", getClient)
+	v1.PUT("/callback", putCallback)
+	v1.POST("/messages", postMessage)
+}
@@ -0,0 +1,273 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/janitorjeff/jeff-bot/core"
+
+	"github.com/rs/zerolog/log"
+)
+
+const Type = 1 << 4
+
+// How long a client's callback has to respond.
+const callbackTimeout = 10 * time.Second
+
+// SignatureHeader is the header in which the callbacks' signature is sent. It
+// is the hex encoded HMAC-SHA256 of the body, the key being the hex encoded
+// SHA256 of the client's token, prefixed with sha256=.
+const SignatureHeader = "X-Jeff-Signature"
+
+var httpClient = core.APIClient("webhook")
+
+type frontend struct{}
+
+var Frontend = &frontend{}
+
+// Reply is a message the bot sends back.
+type Reply struct {
+	Text string `json:"text"`
+
+	// Error is true if the reply is an error message, e.g. a usage message
+	// because of missing arguments.
+	Error bool `json:"error"`
+
+	// Mention is true if the reply is meant to mention the author.
+	Mention bool `json:"mention"`
+}
+
+// Webhook implements the core.Messenger and core.AudioSpeaker interfaces.
+type Webhook struct {
+	client Client
+	id     string
+	text   string
+	author *Author
+	here   *Here
+
+	// the replies are collected here if the message came from a request so
+	// that they can be returned in its response, otherwise they are sent to
+	// the client's callback
+	lock    sync.Mutex
+	replies *[]Reply
+}
+
+func (f *frontend) Type() core.FrontendType {
+	return Type
+}
+
+func (f *frontend) Name() string {
+	return "webhook"
+}
+
+// Init has nothing to connect to, the routes are always served.
+func (f *frontend) Init(connected func(bool), stop chan struct{}) error {
+	connected(true)
+	<-stop
+	return nil
+}
+
+func (f *frontend) CreateMessage(person, place int64, msgID string) (*core.Message, error) {
+	client, personID, err := dbGetPerson(person)
+	if err != nil {
+		return nil, err
+	}
+
+	placeClient, placeID, err := dbGetPlace(place)
+	if err != nil {
+		return nil, err
+	}
+	if client != placeClient {
+		return nil, fmt.Errorf("person %d and place %d belong to different clients", person, place)
+	}
+
+	c, err := dbGetClient(client)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Webhook{
+		client: c,
+		id:     msgID,
+		author: &Author{Client: client, PersonID: personID},
+		here:   &Here{Client: client, PlaceID: placeID},
+	}
+	return w.Parse()
+}
+
+// deliver sends a reply that isn't part of a request's response to the
+// client's callback. If the client has no callback then it's dropped.
+func (w *Webhook) deliver(r Reply) error {
+	if w.client.Callback == "" {
+		log.Debug().Str("client", w.client.Name).Msg("no webhook callback, dropping reply")
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"message_id": w.id,
+		"person":     w.author.ID(),
+		"place":      w.here.ID(),
+		"reply":      r,
+	})
+	if err != nil {
+		return err
+	}
+
+	mac := hmac.New(sha256.New, []byte(w.client.hash))
+	mac.Write(body)
+
+	ctx, cancel := context.WithTimeout(context.Background(), callbackTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.client.Callback, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook callback of client %s returned status %d", w.client.Name, resp.StatusCode)
+	}
+	return nil
+}
+
+///////////////
+//           //
+// Messenger //
+//           //
+///////////////
+
+func (w *Webhook) Parse() (*core.Message, error) {
+	msg := &core.Message{
+		ID:       w.id,
+		Raw:      w.text,
+		Frontend: Frontend,
+		Author:   w.author,
+		Here:     w.here,
+		Client:   w,
+		Speaker:  w,
+	}
+	return msg, nil
+}
+
+// People and places only exist once a client has sent a message from them,
+// the IDs are whatever the client uses.
+
+func (w *Webhook) PersonID(s, _ string) (string, error) {
+	s = strings.TrimPrefix(s, "@")
+	if !dbPersonExists(w.client.ID, s) {
+		return "", fmt.Errorf("person '%s' doesn't exist", s)
+	}
+	return s, nil
+}
+
+func (w *Webhook) PlaceID(s string) (string, error) {
+	if !dbPlaceExists(w.client.ID, s) {
+		return "", fmt.Errorf("place '%s' doesn't exist", s)
+	}
+	return s, nil
+}
+
+func (w *Webhook) Person(id string) (int64, error) {
+	return dbAddPerson(w.client.ID, id)
+}
+
+func (w *Webhook) PlaceExact(id string) (int64, error) {
+	return dbAddPlace(w.client.ID, id)
+}
+
+func (w *Webhook) PlaceLogical(id string) (int64, error) {
+	return dbAddPlace(w.client.ID, id)
+}
+
+func (w *Webhook) Usage(usage string) any {
+	return fmt.Sprintf("Usage: %s", usage)
+}
+
+func (w *Webhook) send(msg any, usrErr error, mention bool) (*core.Message, error) {
+	var text string
+	switch t := msg.(type) {
+	case string:
+		text = msg.(string)
+	default:
+		return nil, fmt.Errorf("Can't send webhook message of type %v", t)
+	}
+
+	r := Reply{
+		Text:    text,
+		Error:   usrErr != nil,
+		Mention: mention,
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.replies != nil {
+		*w.replies = append(*w.replies, r)
+		return nil, nil
+	}
+	return nil, w.deliver(r)
+}
+
+func (w *Webhook) Send(msg any, usrErr error) (*core.Message, error) {
+	return w.send(msg, usrErr, false)
+}
+
+func (w *Webhook) Ping(msg any, usrErr error) (*core.Message, error) {
+	return w.send(msg, usrErr, true)
+}
+
+func (w *Webhook) Write(msg any, usrErr error) (*core.Message, error) {
+	return w.Ping(msg, usrErr)
+}
+
+/////////////
+//         //
+// Speaker //
+//         //
+/////////////
+
+func (w *Webhook) Enabled() bool {
+	return false
+}
+
+func (w *Webhook) FrameRate() int {
+	return 0
+}
+
+func (w *Webhook) Channels() int {
+	return 0
+}
+
+func (w *Webhook) Join() error {
+	return nil
+}
+
+func (w *Webhook) Say(io.Reader, *core.AudioState) error {
+	return nil
+}
+
+func (w *Webhook) AuthorDeafened() (bool, error) {
+	return false, nil
+}
+
+func (w *Webhook) AuthorConnected() (bool, error) {
+	return false, nil
+}
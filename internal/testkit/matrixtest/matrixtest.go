@@ -0,0 +1,289 @@
+// Package matrixtest provides a matrix homeserver for tests. It is separate
+// from testkit so that using it doesn't require a database.
+package matrixtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Homeserver is a stand-in homeserver that implements the parts of the
+// client-server API that the matrix frontend uses, so that it can be tested
+// without running a real one. Messages are queued with Message and the ones
+// the bot sends can be inspected with Sent.
+type Homeserver struct {
+	*httptest.Server
+
+	UserID string
+	Token  string
+
+	lock    sync.Mutex
+	nextID  int
+	events  []matrixEvent
+	rooms   map[string]*matrixRoom
+	invites []string
+	sent    []SentMessage
+}
+
+// SentMessage is a message the bot sent.
+type SentMessage struct {
+	RoomID  string
+	Content map[string]any
+}
+
+type matrixEvent struct {
+	RoomID string
+	Event  map[string]any
+}
+
+type matrixRoom struct {
+	SpaceID string
+	Name    string
+	Levels  map[string]int
+	Joined  bool
+	IsSpace bool
+}
+
+// NewHomeserver starts a homeserver on which the bot is logged in as
+// userID with the access token "token".
+func NewHomeserver(userID string) *Homeserver {
+	hs := &Homeserver{
+		UserID: userID,
+		Token:  "token",
+		rooms:  map[string]*matrixRoom{},
+	}
+	hs.Server = httptest.NewServer(http.HandlerFunc(hs.serve))
+	return hs
+}
+
+// AddSpace adds a space.
+func (hs *Homeserver) AddSpace(spaceID string) {
+	hs.lock.Lock()
+	defer hs.lock.Unlock()
+	hs.rooms[spaceID] = &matrixRoom{IsSpace: true, Levels: map[string]int{}}
+}
+
+// AddRoom adds a room that the bot has joined, spaceID can be empty if it
+// isn't part of a space.
+func (hs *Homeserver) AddRoom(roomID, spaceID string) {
+	hs.lock.Lock()
+	defer hs.lock.Unlock()
+	hs.rooms[roomID] = &matrixRoom{SpaceID: spaceID, Levels: map[string]int{}, Joined: true}
+}
+
+// SetPowerLevel sets the user's power level in the room.
+func (hs *Homeserver) SetPowerLevel(roomID, userID string, level int) {
+	hs.lock.Lock()
+	defer hs.lock.Unlock()
+	hs.rooms[roomID].Levels[userID] = level
+	hs.push(roomID, map[string]any{
+		"type":      "m.room.power_levels",
+		"state_key": "",
+		"sender":    userID,
+		"content":   map[string]any{"users": hs.rooms[roomID].Levels},
+	})
+}
+
+// Invite invites the bot to a room that gets added once it joins.
+func (hs *Homeserver) Invite(roomID string) {
+	hs.lock.Lock()
+	defer hs.lock.Unlock()
+	hs.rooms[roomID] = &matrixRoom{Levels: map[string]int{}}
+	hs.invites = append(hs.invites, roomID)
+}
+
+// Message queues a text message from sender in the room and returns its ID.
+// If replyTo is not empty then the message is a reply to that event.
+func (hs *Homeserver) Message(roomID, sender, body, replyTo string) string {
+	hs.lock.Lock()
+	defer hs.lock.Unlock()
+
+	content := map[string]any{"msgtype": "m.text", "body": body}
+	if replyTo != "" {
+		content["m.relates_to"] = map[string]any{
+			"m.in_reply_to": map[string]any{"event_id": replyTo},
+		}
+	}
+	return hs.push(roomID, map[string]any{
+		"type":    "m.room.message",
+		"sender":  sender,
+		"content": content,
+	})
+}
+
+// Sent returns the messages the bot has sent so far.
+func (hs *Homeserver) Sent() []SentMessage {
+	hs.lock.Lock()
+	defer hs.lock.Unlock()
+	return append([]SentMessage{}, hs.sent...)
+}
+
+// Joined returns true if the bot is in the room.
+func (hs *Homeserver) Joined(roomID string) bool {
+	hs.lock.Lock()
+	defer hs.lock.Unlock()
+	r, ok := hs.rooms[roomID]
+	return ok && r.Joined
+}
+
+// push adds an event and returns its ID, the lock must be held.
+func (hs *Homeserver) push(roomID string, e map[string]any) string {
+	hs.nextID++
+	id := fmt.Sprintf("$event%d", hs.nextID)
+	e["event_id"] = id
+	hs.events = append(hs.events, matrixEvent{roomID, e})
+	return id
+}
+
+func (hs *Homeserver) error(w http.ResponseWriter, status int, code, msg string) {
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"errcode": code, "error": msg})
+}
+
+func (hs *Homeserver) serve(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+hs.Token {
+		hs.error(w, http.StatusUnauthorized, "M_UNKNOWN_TOKEN", "unknown token")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.EscapedPath(), "/_matrix/client/v3")
+	var parts []string
+	for _, p := range strings.Split(strings.Trim(path, "/"), "/") {
+		p, _ = url.PathUnescape(p)
+		parts = append(parts, p)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case path == "/account/whoami":
+		json.NewEncoder(w).Encode(map[string]string{"user_id": hs.UserID})
+	case path == "/sync":
+		hs.sync(w, r)
+	case parts[0] == "profile":
+		json.NewEncoder(w).Encode(map[string]string{"displayname": strings.TrimPrefix(parts[1], "@")})
+	case parts[0] == "directory":
+		hs.error(w, http.StatusNotFound, "M_NOT_FOUND", "no such alias")
+	case parts[0] == "rooms" && len(parts) >= 3:
+		hs.room(w, r, parts[1], parts[2:])
+	default:
+		hs.error(w, http.StatusNotFound, "M_UNRECOGNIZED", "unrecognized request")
+	}
+}
+
+func (hs *Homeserver) sync(w http.ResponseWriter, r *http.Request) {
+	since, _ := strconv.Atoi(r.URL.Query().Get("since"))
+	timeout, _ := strconv.Atoi(r.URL.Query().Get("timeout"))
+	deadline := time.Now().Add(time.Duration(timeout) * time.Millisecond)
+
+	for {
+		hs.lock.Lock()
+		if len(hs.events) > since || len(hs.invites) > 0 || !time.Now().Before(deadline) {
+			break
+		}
+		hs.lock.Unlock()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	defer hs.lock.Unlock()
+
+	join := map[string]any{}
+	for _, e := range hs.events[since:] {
+		room, ok := join[e.RoomID].(map[string]any)
+		if !ok {
+			room = map[string]any{"timeline": map[string]any{"events": []any{}}}
+			join[e.RoomID] = room
+		}
+		tl := room["timeline"].(map[string]any)
+		tl["events"] = append(tl["events"].([]any), e.Event)
+	}
+
+	invite := map[string]any{}
+	for _, id := range hs.invites {
+		invite[id] = map[string]any{}
+	}
+	hs.invites = nil
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"next_batch": strconv.Itoa(len(hs.events)),
+		"rooms":      map[string]any{"join": join, "invite": invite},
+	})
+}
+
+func (hs *Homeserver) room(w http.ResponseWriter, r *http.Request, roomID string, rest []string) {
+	hs.lock.Lock()
+	defer hs.lock.Unlock()
+
+	room, ok := hs.rooms[roomID]
+	if !ok {
+		hs.error(w, http.StatusNotFound, "M_NOT_FOUND", "no such room")
+		return
+	}
+
+	switch {
+	case rest[0] == "join":
+		room.Joined = true
+		json.NewEncoder(w).Encode(map[string]string{"room_id": roomID})
+
+	case rest[0] == "send" && len(rest) == 3:
+		var content map[string]any
+		json.NewDecoder(r.Body).Decode(&content)
+		hs.sent = append(hs.sent, SentMessage{roomID, content})
+		hs.nextID++
+		json.NewEncoder(w).Encode(map[string]string{"event_id": fmt.Sprintf("$event%d", hs.nextID)})
+
+	case rest[0] == "event" && len(rest) == 2:
+		for _, e := range hs.events {
+			if e.RoomID == roomID && e.Event["event_id"] == rest[1] {
+				json.NewEncoder(w).Encode(e.Event)
+				return
+			}
+		}
+		hs.error(w, http.StatusNotFound, "M_NOT_FOUND", "no such event")
+
+	case rest[0] == "state" && len(rest) == 1:
+		state := []any{}
+		if room.SpaceID != "" {
+			state = append(state, map[string]any{
+				"type":      "m.space.parent",
+				"state_key": room.SpaceID,
+				"content":   map[string]any{"via": []string{"localhost"}, "canonical": true},
+			})
+		}
+		json.NewEncoder(w).Encode(state)
+
+	case rest[0] == "state" && len(rest) >= 2:
+		switch rest[1] {
+		case "m.room.power_levels":
+			json.NewEncoder(w).Encode(map[string]any{"users": room.Levels})
+		case "m.room.create":
+			content := map[string]any{}
+			if room.IsSpace {
+				content["type"] = "m.space"
+			}
+			json.NewEncoder(w).Encode(content)
+		case "m.room.name":
+			if room.Name == "" {
+				hs.error(w, http.StatusNotFound, "M_NOT_FOUND", "no name")
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"name": room.Name})
+		default:
+			hs.error(w, http.StatusNotFound, "M_NOT_FOUND", "no such state")
+		}
+
+	default:
+		hs.error(w, http.StatusNotFound, "M_UNRECOGNIZED", "unrecognized request")
+	}
+}
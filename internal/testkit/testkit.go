@@ -14,7 +14,6 @@ import (
 
 	dg "github.com/bwmarrin/discordgo"
 	_ "github.com/lib/pq"
-	"github.com/redis/go-redis/v9"
 )
 
 type TestDB struct {
@@ -75,9 +74,9 @@ func NewTestDB() *TestDB {
 	if err := db.Init(string(schema)); err != nil {
 		log.Fatalf("failed to init schema: %v\n", err)
 	}
-	core.RDB = redis.NewClient(&redis.Options{
-		Addr: readVar("REDIS_ADDR"),
-	})
+	// Every test DB starts counting scopes from the beginning, so a cache
+	// that outlives it, like redis, would return other tests' values.
+	core.Cache = core.NewMemoryCache(1000)
 
 	core.DB = db
 	return &TestDB{db}
@@ -17,9 +17,10 @@ import (
 	"github.com/janitorjeff/jeff-bot/core"
 	"github.com/janitorjeff/jeff-bot/frontends"
 	"github.com/janitorjeff/jeff-bot/frontends/discord"
+	"github.com/janitorjeff/jeff-bot/frontends/irc"
+	"github.com/janitorjeff/jeff-bot/frontends/matrix"
 	"github.com/janitorjeff/jeff-bot/frontends/twitch"
 
-	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
@@ -54,24 +55,19 @@ func readVar(name string) string {
 }
 
 func connect(stop chan struct{}, wgStop *sync.WaitGroup) {
-	// TODO: Handle inability to connect to a specific platform more gracefully,
-	// in case something is down
-
-	wgInit := new(sync.WaitGroup)
-	wgInit.Add(len(frontends.Frontends))
-	wgStop.Add(len(frontends.Frontends))
-
 	twitch.Frontend.Nick = "JanitorJeff"
 	twitch.Frontend.OAuth = readVar("TWITCH_OAUTH")
-	twitch.Frontend.Channels = strings.Split(readVar("TWITCH_CHANNELS"), ",")
+	// Channels are saved in the database once joined, so this is only needed
+	// to seed it.
+	if channels, ok := os.LookupEnv("TWITCH_CHANNELS"); ok {
+		twitch.Frontend.Channels = strings.Split(channels, ",")
+	}
 
 	discord.Frontend.Token = readVar("DISCORD_TOKEN")
 
-	for _, f := range frontends.Frontends {
-		go f.Init(wgInit, wgStop, stop)
-	}
-
-	wgInit.Wait()
+	// Each frontend is connected to independently and retried if it fails,
+	// so one platform being down doesn't take the rest of the bot with it.
+	core.Supervisor.Start(frontends.Frontends, stop, wgStop)
 }
 
 func main() {
@@ -92,10 +88,36 @@ func main() {
 	defer db.Close()
 	defer log.Debug().Msg("closing db")
 
-	log.Debug().Msg("connecting to redis")
-	core.RDB = redis.NewClient(&redis.Options{
-		Addr: readVar("REDIS_ADDR"),
-	})
+	// Without redis the cache isn't shared, so it should only be skipped if
+	// a single instance is running.
+	if addr, ok := os.LookupEnv("REDIS_ADDR"); ok {
+		log.Debug().Msg("connecting to redis")
+		core.Cache = core.NewRedisCache(addr)
+	} else {
+		log.Debug().Msg("no redis address given, using in-memory cache")
+	}
+
+	// Unlike the rest, there's no single IRC network or matrix homeserver, so
+	// they're only used if one is given.
+	if server, ok := os.LookupEnv("IRC_SERVER"); ok {
+		irc.Frontend.Server = server
+		irc.Frontend.TLS = os.Getenv("IRC_TLS") == "true"
+		irc.Frontend.Nick = readVar("IRC_NICK")
+		irc.Frontend.Password = os.Getenv("IRC_PASSWORD")
+		irc.Frontend.SASLUser = os.Getenv("IRC_SASL_USER")
+		irc.Frontend.SASLPassword = os.Getenv("IRC_SASL_PASSWORD")
+		irc.Frontend.NickServPassword = os.Getenv("IRC_NICKSERV_PASSWORD")
+		if channels, ok := os.LookupEnv("IRC_CHANNELS"); ok {
+			irc.Frontend.Channels = strings.Split(channels, ",")
+		}
+		frontends.Frontends = append(frontends.Frontends, irc.Frontend)
+	}
+
+	if homeserver, ok := os.LookupEnv("MATRIX_HOMESERVER"); ok {
+		matrix.Frontend.Homeserver = homeserver
+		matrix.Frontend.Token = readVar("MATRIX_TOKEN")
+		frontends.Frontends = append(frontends.Frontends, matrix.Frontend)
+	}
 
 	core.Frontends = frontends.Frontends
 	core.Commands = &commands.Commands
@@ -116,6 +138,12 @@ func main() {
 	core.Prefixes.Add(core.Normal, "!")
 	core.Prefixes.Add(core.Advanced, "$")
 
+	// Mentioning the bot always works as a prefix, by default for normal
+	// commands.
+	if os.Getenv("MENTION_ADVANCED") == "true" {
+		core.MentionType = core.Advanced
+	}
+
 	discord.Admins = []string{"155662023743635456"}
 	twitch.ClientID = readVar("TWITCH_CLIENT_ID")
 	twitch.ClientSecret = readVar("TWITCH_CLIENT_SECRET")
@@ -15,21 +15,87 @@ CREATE TABLE IF NOT EXISTS prefixes (
 	place BIGINT NOT NULL,
 	prefix VARCHAR(20) NOT NULL,
 	type INTEGER NOT NULL,
+	spaced BOOLEAN NOT NULL DEFAULT FALSE, -- allows whitespace after the prefix
 	UNIQUE(place, prefix),
 	FOREIGN KEY (place) REFERENCES scopes(id) ON DELETE CASCADE
 );
 
+-- Columns added after the table was first created, CREATE TABLE IF NOT EXISTS
+-- doesn't add them to existing databases.
+ALTER TABLE prefixes ADD COLUMN IF NOT EXISTS spaced BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- The command is saved without a prefix, the type decides which of the place's
+-- prefixes is used when the alias is expanded.
+CREATE TABLE IF NOT EXISTS aliases (
+	id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+	place BIGINT NOT NULL,
+	trigger VARCHAR(255) NOT NULL,
+	type INTEGER NOT NULL,
+	command VARCHAR(255) NOT NULL,
+	UNIQUE(place, trigger),
+	FOREIGN KEY (place) REFERENCES scopes(id) ON DELETE CASCADE
+);
+
 CREATE TABLE IF NOT EXISTS settings_place (
 	place BIGINT PRIMARY KEY,
 	FOREIGN KEY (place) REFERENCES scopes(id) ON DELETE CASCADE,
 
+	language VARCHAR(255), -- null means the default language
+
 	cmd_tts_subonly BOOLEAN NOT NULL DEFAULT FALSE,
 
 	cmd_god_reply_on BOOL NOT NULL DEFAULT FALSE,
 	cmd_god_reply_interval INTEGER NOT NULL DEFAULT 1800, -- in seconds
-	cmd_god_reply_last INTEGER NOT NULL DEFAULT 0 -- unix timestamp of last reply
+	cmd_god_reply_last INTEGER NOT NULL DEFAULT 0, -- unix timestamp of last reply
+
+	cmd_automod_on BOOLEAN NOT NULL DEFAULT FALSE,
+	cmd_automod_links BOOLEAN NOT NULL DEFAULT FALSE,
+	cmd_automod_caps INTEGER NOT NULL DEFAULT 0, -- max percentage, 0 means off
+	cmd_automod_emotes INTEGER NOT NULL DEFAULT 0, -- max count, 0 means off
+	cmd_automod_symbols INTEGER NOT NULL DEFAULT 0, -- max percentage, 0 means off
+	cmd_automod_repeat INTEGER NOT NULL DEFAULT 0, -- max count, 0 means off
+	cmd_automod_actions VARCHAR(255) NOT NULL DEFAULT 'warn delete timeout',
+	cmd_automod_timeout INTEGER NOT NULL DEFAULT 600, -- in seconds
+	cmd_automod_exempt_mods BOOLEAN NOT NULL DEFAULT TRUE,
+	cmd_automod_exempt_subs BOOLEAN NOT NULL DEFAULT FALSE,
+
+	cmd_warn_threshold INTEGER NOT NULL DEFAULT 3, -- 0 means off
+	cmd_warn_window INTEGER NOT NULL DEFAULT 86400, -- in seconds
+	cmd_warn_action VARCHAR(255) NOT NULL DEFAULT 'timeout',
+	cmd_warn_timeout INTEGER NOT NULL DEFAULT 600, -- in seconds
+
+	cmd_points_chat INTEGER NOT NULL DEFAULT 1, -- given at most once per minute
+	cmd_points_watch INTEGER NOT NULL DEFAULT 10, -- given every 5 minutes
+
+	cmd_trivia_timeout INTEGER NOT NULL DEFAULT 15, -- in seconds
+
+	cmd_overlay_token VARCHAR(255) NOT NULL DEFAULT '', -- empty until one is generated
+
+	cmd_help_suggest BOOLEAN NOT NULL DEFAULT FALSE
 );
 
+ALTER TABLE settings_place
+	ADD COLUMN IF NOT EXISTS language VARCHAR(255),
+	ADD COLUMN IF NOT EXISTS cmd_automod_on BOOLEAN NOT NULL DEFAULT FALSE,
+	ADD COLUMN IF NOT EXISTS cmd_automod_links BOOLEAN NOT NULL DEFAULT FALSE,
+	ADD COLUMN IF NOT EXISTS cmd_automod_caps INTEGER NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS cmd_automod_emotes INTEGER NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS cmd_automod_symbols INTEGER NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS cmd_automod_repeat INTEGER NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS cmd_automod_actions VARCHAR(255) NOT NULL DEFAULT 'warn delete timeout',
+	ADD COLUMN IF NOT EXISTS cmd_automod_timeout INTEGER NOT NULL DEFAULT 600,
+	ADD COLUMN IF NOT EXISTS cmd_automod_exempt_mods BOOLEAN NOT NULL DEFAULT TRUE,
+	ADD COLUMN IF NOT EXISTS cmd_automod_exempt_subs BOOLEAN NOT NULL DEFAULT FALSE,
+	ADD COLUMN IF NOT EXISTS cmd_warn_threshold INTEGER NOT NULL DEFAULT 3,
+	ADD COLUMN IF NOT EXISTS cmd_warn_window INTEGER NOT NULL DEFAULT 86400,
+	ADD COLUMN IF NOT EXISTS cmd_warn_action VARCHAR(255) NOT NULL DEFAULT 'timeout',
+	ADD COLUMN IF NOT EXISTS cmd_warn_timeout INTEGER NOT NULL DEFAULT 600,
+	ADD COLUMN IF NOT EXISTS cmd_points_chat INTEGER NOT NULL DEFAULT 1,
+	ADD COLUMN IF NOT EXISTS cmd_points_watch INTEGER NOT NULL DEFAULT 10,
+	ADD COLUMN IF NOT EXISTS cmd_trivia_timeout INTEGER NOT NULL DEFAULT 15,
+	ADD COLUMN IF NOT EXISTS cmd_overlay_token VARCHAR(255) NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS cmd_help_suggest BOOLEAN NOT NULL DEFAULT FALSE;
+
 CREATE TABLE IF NOT EXISTS settings_person (
 	person BIGINT NOT NULL,
 	place BIGINT NOT NULL,
@@ -37,11 +103,23 @@ CREATE TABLE IF NOT EXISTS settings_person (
 	FOREIGN KEY (person) REFERENCES scopes(id) ON DELETE CASCADE,
 	FOREIGN KEY (place) REFERENCES scopes(id) ON DELETE CASCADE,
 
+	language VARCHAR(255), -- null means the place's language
+
 	cmd_nick_nick VARCHAR(255),
 	UNIQUE(place, cmd_nick_nick),
 
 	cmd_time_tz VARCHAR(255) NOT NULL DEFAULT 'UTC',
 
+	cmd_points_points BIGINT NOT NULL DEFAULT 0,
+
+	cmd_rps_wins INTEGER NOT NULL DEFAULT 0,
+	cmd_rps_losses INTEGER NOT NULL DEFAULT 0,
+	cmd_rps_draws INTEGER NOT NULL DEFAULT 0,
+	cmd_rps_streak INTEGER NOT NULL DEFAULT 0, -- current win streak
+	cmd_rps_best INTEGER NOT NULL DEFAULT 0, -- best win streak
+
+	cmd_trivia_points BIGINT NOT NULL DEFAULT 0,
+
 	cmd_tts_voice VARCHAR(255) NOT NULL DEFAULT (ARRAY[
 		-- DISNEY VOICES
 		'en_us_ghostface',       -- Ghost Face
@@ -98,6 +176,16 @@ CREATE TABLE IF NOT EXISTS settings_person (
 	])[floor(random() * 41 + 1)]
 );
 
+ALTER TABLE settings_person
+	ADD COLUMN IF NOT EXISTS language VARCHAR(255),
+	ADD COLUMN IF NOT EXISTS cmd_points_points BIGINT NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS cmd_rps_wins INTEGER NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS cmd_rps_losses INTEGER NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS cmd_rps_draws INTEGER NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS cmd_rps_streak INTEGER NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS cmd_rps_best INTEGER NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS cmd_trivia_points BIGINT NOT NULL DEFAULT 0;
+
 CREATE INDEX IF NOT EXISTS settings_person_index_person_place ON settings_person (person, place);
 CREATE INDEX IF NOT EXISTS settings_person_index_nick ON settings_person (cmd_nick_nick);
 
@@ -127,6 +215,60 @@ CREATE TABLE IF NOT EXISTS frontend_discord_users (
 	FOREIGN KEY (scope) REFERENCES scopes(id) ON DELETE CASCADE
 );
 
+-------------------
+--               --
+-- Frontend: IRC --
+--               --
+-------------------
+
+-- Names are case folded, there are no IDs on IRC
+CREATE TABLE IF NOT EXISTS frontend_irc_channels (
+	scope BIGINT PRIMARY KEY,
+	channel VARCHAR(255) NOT NULL UNIQUE,
+	FOREIGN KEY (scope) REFERENCES scopes(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS frontend_irc_users (
+	scope BIGINT PRIMARY KEY,
+	nick VARCHAR(255) NOT NULL UNIQUE,
+	FOREIGN KEY (scope) REFERENCES scopes(id) ON DELETE CASCADE
+);
+
+-- Services accounts, from the IRCv3 account tag, kept apart from the nicks
+-- since anyone can use a nick but only its owner can log into an account
+CREATE TABLE IF NOT EXISTS frontend_irc_accounts (
+	scope BIGINT PRIMARY KEY,
+	account VARCHAR(255) NOT NULL UNIQUE,
+	FOREIGN KEY (scope) REFERENCES scopes(id) ON DELETE CASCADE
+);
+
+----------------------
+--                  --
+-- Frontend: Matrix --
+--                  --
+----------------------
+
+-- Rooms that aren't part of a space belong to the space with an empty ID
+CREATE TABLE IF NOT EXISTS frontend_matrix_spaces (
+	scope BIGINT PRIMARY KEY,
+	space VARCHAR(255) NOT NULL UNIQUE,
+	FOREIGN KEY (scope) REFERENCES scopes(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS frontend_matrix_rooms (
+	scope BIGINT PRIMARY KEY,
+	room VARCHAR(255) NOT NULL UNIQUE,
+	space BIGINT NOT NULL,
+	FOREIGN KEY (scope) REFERENCES scopes(id) ON DELETE CASCADE,
+	FOREIGN KEY (space) REFERENCES frontend_matrix_spaces(scope) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS frontend_matrix_users (
+	scope BIGINT PRIMARY KEY,
+	uid VARCHAR(255) NOT NULL UNIQUE,
+	FOREIGN KEY (scope) REFERENCES scopes(id) ON DELETE CASCADE
+);
+
 ----------------------
 --                  --
 -- Frontend: Twitch --
@@ -139,9 +281,105 @@ CREATE TABLE IF NOT EXISTS frontend_twitch_channels (
 	channel_name VARCHAR(255) NOT NULL,
 	access_token VARCHAR(255),
 	refresh_token VARCHAR(255),
+	joined BOOLEAN, -- whether the bot is in the channel, null if it was never joined or parted
 	FOREIGN KEY (scope) REFERENCES scopes(id) ON DELETE CASCADE
 );
 
+ALTER TABLE frontend_twitch_channels ADD COLUMN IF NOT EXISTS joined BOOLEAN;
+ALTER TABLE frontend_twitch_channels ALTER COLUMN joined DROP NOT NULL, ALTER COLUMN joined DROP DEFAULT;
+
+-----------------------
+--                   --
+-- Frontend: Webhook --
+--                   --
+-----------------------
+
+CREATE TABLE IF NOT EXISTS frontend_webhook_clients (
+	id SERIAL PRIMARY KEY,
+	name VARCHAR(255) NOT NULL UNIQUE,
+	hash VARCHAR(64) NOT NULL UNIQUE, -- sha256 of the token
+	callback VARCHAR(2048) NOT NULL DEFAULT '',
+	created BIGINT NOT NULL
+);
+
+-- The IDs are chosen by the clients, so they are only unique per client
+CREATE TABLE IF NOT EXISTS frontend_webhook_people (
+	scope BIGINT PRIMARY KEY,
+	client INTEGER NOT NULL,
+	person VARCHAR(255) NOT NULL,
+	UNIQUE(client, person),
+	FOREIGN KEY (scope) REFERENCES scopes(id) ON DELETE CASCADE,
+	FOREIGN KEY (client) REFERENCES frontend_webhook_clients(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS frontend_webhook_places (
+	scope BIGINT PRIMARY KEY,
+	client INTEGER NOT NULL,
+	place VARCHAR(255) NOT NULL,
+	UNIQUE(client, place),
+	FOREIGN KEY (scope) REFERENCES scopes(id) ON DELETE CASCADE,
+	FOREIGN KEY (client) REFERENCES frontend_webhook_clients(id) ON DELETE CASCADE
+);
+
+------------------
+--              --
+-- Command: API --
+--              --
+------------------
+
+CREATE TABLE IF NOT EXISTS cmd_api_tokens (
+	id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+	hash VARCHAR(64) NOT NULL UNIQUE, -- hex encoded sha256 of the token
+	person BIGINT NOT NULL,
+	place BIGINT NOT NULL,
+	created BIGINT NOT NULL, -- unix timestamp
+	FOREIGN KEY (person) REFERENCES scopes(id) ON DELETE CASCADE,
+	FOREIGN KEY (place) REFERENCES scopes(id) ON DELETE CASCADE
+);
+-- permissions are checked every time a token is used instead
+ALTER TABLE cmd_api_tokens DROP COLUMN IF EXISTS mod, DROP COLUMN IF EXISTS admin;
+
+----------------------
+--                  --
+-- Command: Automod --
+--                  --
+----------------------
+
+CREATE TABLE IF NOT EXISTS cmd_automod_allowlist (
+	id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+	place BIGINT NOT NULL,
+	domain VARCHAR(255) NOT NULL,
+	UNIQUE(place, domain),
+	FOREIGN KEY (place) REFERENCES scopes(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS cmd_automod_phrases (
+	id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+	place BIGINT NOT NULL,
+	phrase VARCHAR(255) NOT NULL,
+	UNIQUE(place, phrase),
+	FOREIGN KEY (place) REFERENCES scopes(id) ON DELETE CASCADE
+);
+
+----------------------
+--                  --
+-- Command: Counter --
+--                  --
+----------------------
+
+CREATE TABLE IF NOT EXISTS cmd_counter_counters (
+	id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+	place BIGINT NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	value BIGINT NOT NULL,
+	restricted BOOLEAN NOT NULL, -- if true only mods can change the value from chat
+	creator BIGINT NOT NULL,
+	created BIGINT NOT NULL, -- unix timestamp
+	UNIQUE(place, name),
+	FOREIGN KEY (place) REFERENCES scopes(id) ON DELETE CASCADE,
+	FOREIGN KEY (creator) REFERENCES scopes(id) ON DELETE CASCADE
+);
+
 ------------------------------
 --                          --
 -- Command: Custom Commands --
@@ -166,6 +404,155 @@ CREATE TABLE IF NOT EXISTS cmd_customcommand_commands (
 	FOREIGN KEY (deleter) REFERENCES scopes(id) ON DELETE CASCADE
 );
 
+-----------------------
+--                   --
+-- Command: Giveaway --
+--                   --
+-----------------------
+
+CREATE TABLE IF NOT EXISTS cmd_giveaway_giveaways (
+	id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+
+	place BIGINT NOT NULL, -- the exact place, entries are only accepted there
+	creator BIGINT NOT NULL,
+	keyword VARCHAR(255) NOT NULL,
+	eligible VARCHAR(255) NOT NULL, -- everyone, subs, mods or followers
+	follow_age BIGINT NOT NULL, -- seconds, only used for followers
+	ends BIGINT NOT NULL, -- unix timestamp of when entries close
+	active BOOLEAN NOT NULL, -- whether entries are still accepted
+	created BIGINT NOT NULL,
+
+	FOREIGN KEY (place) REFERENCES scopes(id) ON DELETE CASCADE,
+	FOREIGN KEY (creator) REFERENCES scopes(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS cmd_giveaway_entries (
+	giveaway BIGINT NOT NULL,
+	person BIGINT NOT NULL,
+	entered BIGINT NOT NULL,
+
+	UNIQUE(giveaway, person), -- one entry per person
+	FOREIGN KEY (giveaway) REFERENCES cmd_giveaway_giveaways(id) ON DELETE CASCADE,
+	FOREIGN KEY (person) REFERENCES scopes(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS cmd_giveaway_winners (
+	id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+
+	giveaway BIGINT NOT NULL,
+	person BIGINT NOT NULL,
+	drawn BIGINT NOT NULL,
+	rerolled BOOLEAN NOT NULL, -- the win was taken back and someone else was drawn
+
+	UNIQUE(giveaway, person),
+	FOREIGN KEY (giveaway) REFERENCES cmd_giveaway_giveaways(id) ON DELETE CASCADE,
+	FOREIGN KEY (person) REFERENCES scopes(id) ON DELETE CASCADE
+);
+
+---------------------
+--                 --
+-- Command: Points --
+--                 --
+---------------------
+
+CREATE TABLE IF NOT EXISTS cmd_points_transactions (
+	id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+	place BIGINT NOT NULL,
+	person BIGINT NOT NULL,
+	amount BIGINT NOT NULL, -- negative if points were taken
+	reason VARCHAR(255) NOT NULL,
+	created BIGINT NOT NULL,
+	FOREIGN KEY (place) REFERENCES scopes(id) ON DELETE CASCADE,
+	FOREIGN KEY (person) REFERENCES scopes(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS cmd_points_transactions_index_place_person ON cmd_points_transactions (place, person);
+
+-------------------
+--               --
+-- Command: Poll --
+--               --
+-------------------
+
+CREATE TABLE IF NOT EXISTS cmd_poll_polls (
+	id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+
+	place BIGINT NOT NULL, -- the exact place, votes are only counted there
+	creator BIGINT NOT NULL,
+	question VARCHAR(255) NOT NULL,
+	msg_id VARCHAR(255) NOT NULL, -- the poll's message, edited in place on discord
+	ends BIGINT NOT NULL, -- unix timestamp
+	active BOOLEAN NOT NULL,
+
+	FOREIGN KEY (place) REFERENCES scopes(id) ON DELETE CASCADE,
+	FOREIGN KEY (creator) REFERENCES scopes(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS cmd_poll_options (
+	poll BIGINT NOT NULL,
+	number INTEGER NOT NULL, -- what people type in order to vote, starts from 1
+	option VARCHAR(255) NOT NULL,
+
+	UNIQUE(poll, number),
+	FOREIGN KEY (poll) REFERENCES cmd_poll_polls(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS cmd_poll_votes (
+	poll BIGINT NOT NULL,
+	person BIGINT NOT NULL,
+	number INTEGER NOT NULL,
+
+	UNIQUE(poll, person), -- one vote per person
+	FOREIGN KEY (poll) REFERENCES cmd_poll_polls(id) ON DELETE CASCADE,
+	FOREIGN KEY (person) REFERENCES scopes(id) ON DELETE CASCADE
+);
+
+--------------------
+--                --
+-- Command: Quote --
+--                --
+--------------------
+
+CREATE TABLE IF NOT EXISTS cmd_quote_quotes (
+	id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+
+	place BIGINT NOT NULL,
+	number INTEGER NOT NULL, -- the quote's number in the place, kept across edits
+	quote VARCHAR(500) NOT NULL,
+	quoted BIGINT NOT NULL,
+	quoted_name VARCHAR(255) NOT NULL,
+	game VARCHAR(255) NOT NULL, -- twitch game at the time of quoting, empty if none
+	said BIGINT NOT NULL, -- unix timestamp of when the quote was first added
+	quoter BIGINT NOT NULL,
+	active BOOLEAN NOT NULL,
+
+	creator BIGINT NOT NULL,
+	created BIGINT NOT NULL,
+	deleter BIGINT,
+	deleted BIGINT,
+
+	FOREIGN KEY (place) REFERENCES scopes(id) ON DELETE CASCADE,
+	FOREIGN KEY (quoted) REFERENCES scopes(id) ON DELETE CASCADE,
+	FOREIGN KEY (quoter) REFERENCES scopes(id) ON DELETE CASCADE,
+	FOREIGN KEY (creator) REFERENCES scopes(id) ON DELETE CASCADE,
+	FOREIGN KEY (deleter) REFERENCES scopes(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS cmd_quote_quotes_index_place_number ON cmd_quote_quotes (place, number);
+
+---------------------
+--                 --
+-- Command: Random --
+--                 --
+---------------------
+
+CREATE TABLE IF NOT EXISTS cmd_random_answers (
+	id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+	place BIGINT NOT NULL,
+	answer VARCHAR(255) NOT NULL, -- a custom 8-ball answer
+	FOREIGN KEY (place) REFERENCES scopes(id) ON DELETE CASCADE
+);
+
 -------------------
 --               --
 -- Command: Time --
@@ -182,3 +569,23 @@ CREATE TABLE IF NOT EXISTS cmd_time_reminders (
 	FOREIGN KEY (person) REFERENCES scopes(id) ON DELETE CASCADE,
 	FOREIGN KEY (place) REFERENCES scopes(id) ON DELETE CASCADE
 );
+
+-------------------
+--               --
+-- Command: Warn --
+--               --
+-------------------
+
+CREATE TABLE IF NOT EXISTS cmd_warn_warnings (
+	id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+	place BIGINT NOT NULL,
+	person BIGINT NOT NULL,
+	warner BIGINT, -- NULL if added by automod
+	reason VARCHAR(255) NOT NULL,
+	created BIGINT NOT NULL,
+	FOREIGN KEY (place) REFERENCES scopes(id) ON DELETE CASCADE,
+	FOREIGN KEY (person) REFERENCES scopes(id) ON DELETE CASCADE,
+	FOREIGN KEY (warner) REFERENCES scopes(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS cmd_warn_warnings_index_place_person ON cmd_warn_warnings (place, person);